@@ -2,6 +2,10 @@ package vidgo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/feitianbubu/vidgo/adapters"
 )
@@ -18,24 +22,9 @@ func (w *adapterWrapper) Name() string {
 
 // CreateGeneration creates a new video generation task
 func (w *adapterWrapper) CreateGeneration(ctx context.Context, req *GenerationRequest) (*GenerationResponse, error) {
-	adapterReq := &adapters.GenerationRequest{
-		Prompt:         req.Prompt,
-		Image:          req.Image,
-		Style:          req.Style,
-		Duration:       req.Duration,
-		FPS:            req.FPS,
-		Width:          req.Width,
-		Height:         req.Height,
-		ResponseFormat: adapters.ResponseFormat(req.ResponseFormat),
-		QualityLevel:   adapters.QualityLevel(req.QualityLevel),
-		Seed:           req.Seed,
-		Model:          req.Model,
-		Metadata:       req.Metadata,
-	}
-
-	resp, err := w.provider.CreateGeneration(ctx, adapterReq)
+	resp, err := w.provider.CreateGeneration(ctx, toAdapterRequest(req))
 	if err != nil {
-		return nil, err
+		return nil, w.providerError(err)
 	}
 
 	return &GenerationResponse{
@@ -48,14 +37,79 @@ func (w *adapterWrapper) CreateGeneration(ctx context.Context, req *GenerationRe
 func (w *adapterWrapper) GetGeneration(ctx context.Context, taskID string) (*TaskResult, error) {
 	result, err := w.provider.GetGeneration(ctx, taskID)
 	if err != nil {
-		return nil, err
+		return nil, w.providerError(err)
+	}
+
+	return fromAdapterResult(ProviderType(strings.ToLower(w.Name())), result), nil
+}
+
+// providerError converts a provider's TaskNotFoundError and APIError to the
+// root types, so HTTPStatus, IsRetryableError and errors.Is see them
+func (w *adapterWrapper) providerError(err error) error {
+	var notFound *adapters.TaskNotFoundError
+	if errors.As(err, &notFound) {
+		return &TaskNotFoundError{
+			TaskID:   notFound.TaskID,
+			Provider: w.Name(),
+			Expired:  notFound.Expired,
+			Message:  notFound.Message,
+		}
+	}
+	var apiErr *adapters.APIError
+	if errors.As(err, &apiErr) {
+		return &APIError{
+			Code:         apiErr.StatusCode,
+			Message:      apiErr.Message,
+			Provider:     w.Name(),
+			ProviderCode: apiErr.Code,
+		}
+	}
+	return err
+}
+
+// resultAssets lists the assets of a result, filling in expiries from signed URLs
+func resultAssets(result *adapters.TaskResult) []ResultAsset {
+	assets := append([]ResultAsset(nil), result.Assets...)
+	if len(assets) == 0 {
+		for _, video := range resultVideos(result) {
+			assets = append(assets, ResultAsset{URL: video.URL, Kind: AssetVideo})
+		}
+	}
+	for i := range assets {
+		if assets[i].ExpiresAt != nil {
+			continue
+		}
+		if expires, ok := adapters.URLExpiry(assets[i].URL); ok {
+			assets[i].ExpiresAt = &expires
+		}
+	}
+	return assets
+}
+
+// resultVideos lists the videos of a result, reporting URL as the only video
+// when the provider returned none
+func resultVideos(result *adapters.TaskResult) []VideoOutput {
+	if len(result.Videos) > 0 {
+		return append([]VideoOutput(nil), result.Videos...)
+	}
+	if result.URL == "" {
+		return nil
+	}
+	video := VideoOutput{URL: result.URL, Format: result.Format}
+	if result.Metadata != nil {
+		video.Duration = result.Metadata.Duration
 	}
+	return []VideoOutput{video}
+}
 
+// fromAdapterResult converts an adapters task result, applying response transforms
+func fromAdapterResult(provider ProviderType, result *adapters.TaskResult) *TaskResult {
 	mainResult := &TaskResult{
-		TaskID: result.TaskID,
-		Status: TaskStatus(result.Status),
-		URL:    result.URL,
-		Format: result.Format,
+		TaskID:       result.TaskID,
+		Status:       TaskStatus(result.Status),
+		URL:          result.URL,
+		Format:       result.Format,
+		ClientTaskID: result.ClientTaskID,
 	}
 
 	if result.Metadata != nil {
@@ -76,7 +130,161 @@ func (w *adapterWrapper) GetGeneration(ctx context.Context, taskID string) (*Tas
 		}
 	}
 
-	return mainResult, nil
+	mainResult.Assets = resultAssets(result)
+	mainResult.Videos = resultVideos(result)
+	if result.Cancellation != nil {
+		cancellation := *result.Cancellation
+		mainResult.Cancellation = &cancellation
+	}
+	if result.Usage != nil {
+		usage := *result.Usage
+		mainResult.Usage = &usage
+	}
+
+	transformResponse(provider, result.RawResponse, mainResult)
+
+	// Callers read Error.Message of failed tasks, even when the provider gave no reason
+	if mainResult.Status == TaskStatusFailed {
+		if mainResult.Error == nil {
+			mainResult.Error = &TaskError{Code: http.StatusInternalServerError}
+		}
+		if mainResult.Error.Message == "" {
+			mainResult.Error.Message = "task failed"
+		}
+	}
+
+	return mainResult
+}
+
+// ExtendGeneration extends a generated video if the provider supports it
+func (w *adapterWrapper) ExtendGeneration(ctx context.Context, req *ExtendRequest) (*GenerationResponse, error) {
+	extender, ok := w.provider.(adapters.Extender)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support video extension", ErrUnsupportedOperation, w.Name())
+	}
+
+	resp, err := extender.ExtendGeneration(ctx, req)
+	if err != nil {
+		return nil, w.providerError(err)
+	}
+
+	return &GenerationResponse{
+		TaskID: resp.TaskID,
+		Status: TaskStatus(resp.Status),
+	}, nil
+}
+
+// CreateLipSync lip-syncs a video if the provider supports it
+func (w *adapterWrapper) CreateLipSync(ctx context.Context, req *LipSyncRequest) (*GenerationResponse, error) {
+	syncer, ok := w.provider.(adapters.LipSyncer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support lip-sync", ErrUnsupportedOperation, w.Name())
+	}
+
+	resp, err := syncer.CreateLipSync(ctx, req)
+	if err != nil {
+		return nil, w.providerError(err)
+	}
+
+	return &GenerationResponse{
+		TaskID: resp.TaskID,
+		Status: TaskStatus(resp.Status),
+	}, nil
+}
+
+// CreateEffect submits a video effect task if the provider supports it
+func (w *adapterWrapper) CreateEffect(ctx context.Context, req *EffectRequest) (*GenerationResponse, error) {
+	generator, ok := w.provider.(adapters.EffectGenerator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support video effects", ErrUnsupportedOperation, w.Name())
+	}
+
+	resp, err := generator.CreateEffect(ctx, req)
+	if err != nil {
+		return nil, w.providerError(err)
+	}
+
+	return &GenerationResponse{
+		TaskID: resp.TaskID,
+		Status: TaskStatus(resp.Status),
+	}, nil
+}
+
+// SupportedEffects lists the provider's video effects for a model
+func (w *adapterWrapper) SupportedEffects(model string) []string {
+	if generator, ok := w.provider.(adapters.EffectGenerator); ok {
+		return generator.SupportedEffects(model)
+	}
+	return nil
+}
+
+// GetQuota returns the account quota if the provider reports it
+func (w *adapterWrapper) GetQuota(ctx context.Context) (*Quota, error) {
+	info, ok := w.provider.(adapters.AccountInfoProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not report account quota", ErrUnsupportedOperation, w.Name())
+	}
+	quota, err := info.GetQuota(ctx)
+	if err != nil {
+		return nil, w.providerError(err)
+	}
+	return quota, nil
+}
+
+// GetGenerationByClientID looks up a task by the ClientTaskID it was submitted with
+func (w *adapterWrapper) GetGenerationByClientID(ctx context.Context, clientTaskID string) (*TaskResult, error) {
+	lookup, ok := w.provider.(adapters.ClientTaskLookup)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support client task IDs", ErrUnsupportedOperation, w.Name())
+	}
+	result, err := lookup.GetGenerationByClientID(ctx, clientTaskID)
+	if err != nil {
+		return nil, w.providerError(err)
+	}
+	return fromAdapterResult(ProviderType(strings.ToLower(w.Name())), result), nil
+}
+
+// CancelGeneration cancels a task if the provider supports it
+func (w *adapterWrapper) CancelGeneration(ctx context.Context, taskID string) error {
+	canceler, ok := w.provider.(adapters.Canceler)
+	if !ok {
+		return fmt.Errorf("%w: %s does not support canceling tasks", ErrUnsupportedOperation, w.Name())
+	}
+	if err := canceler.CancelGeneration(ctx, taskID); err != nil {
+		return w.providerError(err)
+	}
+	return nil
+}
+
+// GetGenerations fetches many tasks through the provider's batch lookup
+func (w *adapterWrapper) GetGenerations(ctx context.Context, taskIDs []string) (map[string]*TaskResult, error) {
+	batch, ok := w.provider.(adapters.BatchTaskGetter)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support batch task lookups", ErrUnsupportedOperation, w.Name())
+	}
+	found, err := batch.GetGenerations(ctx, taskIDs)
+	if err != nil {
+		return nil, w.providerError(err)
+	}
+
+	provider := ProviderType(strings.ToLower(w.Name()))
+	results := make(map[string]*TaskResult, len(found))
+	for taskID, result := range found {
+		results[taskID] = fromAdapterResult(provider, result)
+	}
+	return results, nil
+}
+
+// inheritTaskState hands the per-task state of the provider being replaced by a
+// reload to this one
+func (w *adapterWrapper) inheritTaskState(old Provider) {
+	previous, ok := old.(*adapterWrapper)
+	if !ok {
+		return
+	}
+	if inheritor, ok := w.provider.(adapters.TaskStateInheritor); ok {
+		inheritor.InheritTaskState(previous.provider)
+	}
 }
 
 // SupportedModels returns a list of supported models for this provider
@@ -86,11 +294,45 @@ func (w *adapterWrapper) SupportedModels() []string {
 
 // ValidateRequest validates if the request is compatible with this provider
 func (w *adapterWrapper) ValidateRequest(req *GenerationRequest) error {
+	return w.provider.ValidateRequest(toAdapterRequest(req))
+}
 
-	adapterReq := &adapters.GenerationRequest{
+// ValidateCredentials validates a per-request API key if the provider supports it
+func (w *adapterWrapper) ValidateCredentials(apiKey string) error {
+	if validator, ok := w.provider.(adapters.CredentialValidator); ok {
+		return validator.ValidateCredentials(apiKey)
+	}
+	return nil
+}
+
+// Warm warms up the provider if it supports it
+func (w *adapterWrapper) Warm(ctx context.Context) error {
+	if warmer, ok := w.provider.(adapters.Warmer); ok {
+		return warmer.Warm(ctx)
+	}
+	return nil
+}
+
+// PeerFeatures returns feature hints from a downstream vidgo-based service, if the provider reports them
+func (w *adapterWrapper) PeerFeatures() adapters.Features {
+	if negotiator, ok := w.provider.(adapters.FeatureNegotiator); ok {
+		return negotiator.PeerFeatures()
+	}
+	return nil
+}
+
+// toAdapterRequest converts a GenerationRequest to the adapters request type
+func toAdapterRequest(req *GenerationRequest) *adapters.GenerationRequest {
+	return &adapters.GenerationRequest{
 		Prompt:         req.Prompt,
+		NegativePrompt: req.NegativePrompt,
 		Image:          req.Image,
+		ImageTail:      req.ImageTail,
+		Images:         req.Images,
+		Keyframes:      req.Keyframes,
+		ImageBytes:     req.ImageBytes,
 		Style:          req.Style,
+		Mode:           req.Mode,
 		Duration:       req.Duration,
 		FPS:            req.FPS,
 		Width:          req.Width,
@@ -98,9 +340,30 @@ func (w *adapterWrapper) ValidateRequest(req *GenerationRequest) error {
 		ResponseFormat: adapters.ResponseFormat(req.ResponseFormat),
 		QualityLevel:   adapters.QualityLevel(req.QualityLevel),
 		Seed:           req.Seed,
+		GuidanceScale:  req.GuidanceScale,
 		Model:          req.Model,
+		CameraControl:  req.CameraControl,
+		MotionBrush:    req.MotionBrush,
+		CallbackURL:    req.CallbackURL,
+		ClientTaskID:   req.ClientTaskID,
+		Watermark:      req.Watermark,
 		Metadata:       req.Metadata,
 	}
+}
 
-	return w.provider.ValidateRequest(adapterReq)
+// Capabilities returns the provider capabilities, or nil if the provider does not publish them
+func (w *adapterWrapper) Capabilities() *adapters.Capabilities {
+	if provider, ok := w.provider.(adapters.CapabilitiesProvider); ok {
+		return provider.Capabilities()
+	}
+	return nil
+}
+
+// EffectiveConfig returns the provider configuration with defaults applied, or
+// nil if the provider does not report it
+func (w *adapterWrapper) EffectiveConfig() *adapters.ProviderConfig {
+	if provider, ok := w.provider.(adapters.ConfigReporter); ok {
+		return provider.EffectiveConfig()
+	}
+	return nil
 }
@@ -0,0 +1,31 @@
+package adapters
+
+import (
+	"context"
+	"time"
+)
+
+// ResourcePack is a prepaid credit package on a provider account
+type ResourcePack struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type,omitempty"` // e.g. Kling "decreasing_total" or "constant_period"
+	Total       float64   `json:"total"`
+	Remaining   float64   `json:"remaining"`
+	Status      string    `json:"status"` // e.g. Kling "online", "expired", "runOut"
+	EffectiveAt time.Time `json:"effective_at,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+}
+
+// Quota is the remaining balance of a provider account
+type Quota struct {
+	// Remaining sums the remaining credits of the usable packs
+	Remaining   float64                `json:"remaining"`
+	Packs       []ResourcePack         `json:"packs"`
+	RawResponse map[string]interface{} `json:"raw_response,omitempty"`
+}
+
+// AccountInfoProvider is implemented by providers that can report their account quota
+type AccountInfoProvider interface {
+	GetQuota(ctx context.Context) (*Quota, error)
+}
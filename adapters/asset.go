@@ -0,0 +1,93 @@
+package adapters
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Asset kinds
+const (
+	AssetVideo     = "video"
+	AssetThumbnail = "thumbnail" // Cover image published by the provider
+)
+
+// ResultAsset is one output file of a task
+type ResultAsset struct {
+	URL  string `json:"url"`
+	Kind string `json:"kind,omitempty"` // AssetVideo or AssetThumbnail
+
+	// ExpiresAt is when the URL stops working, from the provider's retention
+	// policy or the URL signature; nil when unknown
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// IsExpired reports whether the asset URL has expired at now. Assets with an
+// unknown expiry are never reported as expired.
+func (a ResultAsset) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// TTL returns how long the asset URL stays valid after now, or false when the
+// expiry is unknown
+func (a ResultAsset) TTL(now time.Time) (time.Duration, bool) {
+	if a.ExpiresAt == nil {
+		return 0, false
+	}
+	if ttl := a.ExpiresAt.Sub(now); ttl > 0 {
+		return ttl, true
+	}
+	return 0, true
+}
+
+// URLExpiry reads the expiry of a pre-signed URL from its query parameters. It
+// understands S3/GCS/TOS V4 signatures (X-Amz-Date and X-Amz-Expires and their
+// X-Goog and X-Tos equivalents), V2-style Expires Unix timestamps (S3, OSS,
+// CloudFront), Azure SAS se and Tencent COS q-sign-time.
+func URLExpiry(rawURL string) (time.Time, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return time.Time{}, false
+	}
+	// Parsed by hand because url.ParseQuery rejects the ";" in COS signatures
+	query := make(url.Values)
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		key, err1 := url.QueryUnescape(key)
+		value, err2 := url.QueryUnescape(value)
+		if err1 == nil && err2 == nil {
+			query.Add(strings.ToLower(key), value)
+		}
+	}
+
+	for _, prefix := range []string{"x-amz-", "x-goog-", "x-tos-"} {
+		date, expires := query.Get(prefix+"date"), query.Get(prefix+"expires")
+		if date == "" || expires == "" {
+			continue
+		}
+		signed, err := time.Parse("20060102T150405Z", date)
+		seconds, err2 := strconv.ParseInt(expires, 10, 64)
+		if err == nil && err2 == nil {
+			return signed.Add(time.Duration(seconds) * time.Second), true
+		}
+	}
+	if expires := query.Get("expires"); expires != "" {
+		if seconds, err := strconv.ParseInt(expires, 10, 64); err == nil {
+			return time.Unix(seconds, 0), true
+		}
+	}
+	if se := query.Get("se"); se != "" && query.Get("sig") != "" {
+		if expires, err := time.Parse(time.RFC3339, se); err == nil {
+			return expires, true
+		}
+	}
+	if signTime := query.Get("q-sign-time"); signTime != "" {
+		if _, end, ok := strings.Cut(signTime, ";"); ok {
+			if seconds, err := strconv.ParseInt(end, 10, 64); err == nil {
+				return time.Unix(seconds, 0), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
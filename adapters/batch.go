@@ -0,0 +1,11 @@
+package adapters
+
+import "context"
+
+// BatchTaskGetter is implemented by providers that can fetch the status of many
+// tasks in a few requests, e.g. by listing recent tasks
+type BatchTaskGetter interface {
+	// GetGenerations returns the results it found by task ID. Tasks missing from
+	// the map are left for the caller to fetch one by one.
+	GetGenerations(ctx context.Context, taskIDs []string) (map[string]*TaskResult, error)
+}
@@ -0,0 +1,18 @@
+package adapters
+
+import "fmt"
+
+// ValidateCallbackURL checks GenerationRequest.CallbackURL; providers that cannot
+// notify on completion pass supported=false and reject the field
+func ValidateCallbackURL(provider, callbackURL string, supported bool) error {
+	if callbackURL == "" {
+		return nil
+	}
+	if !supported {
+		return fmt.Errorf("%s does not support callback URLs", provider)
+	}
+	if err := ValidateHTTPURL(callbackURL); err != nil {
+		return fmt.Errorf("callback URL %w", err)
+	}
+	return nil
+}
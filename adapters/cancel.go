@@ -0,0 +1,46 @@
+package adapters
+
+import (
+	"context"
+	"time"
+)
+
+// CancellationReason says why a task was canceled
+type CancellationReason string
+
+// Cancellation reasons
+const (
+	CancellationUser       CancellationReason = "user"       // The end user aborted the task
+	CancellationBudget     CancellationReason = "budget"     // A budget or quota guard stopped it
+	CancellationTimeout    CancellationReason = "timeout"    // A system deadline passed
+	CancellationExpired    CancellationReason = "expired"    // The task was no longer wanted when it would have run
+	CancellationProvider   CancellationReason = "provider"   // The provider canceled it
+	CancellationSuperseded CancellationReason = "superseded" // Another task made it unnecessary, e.g. it lost a Race
+)
+
+// UserInitiated reports whether the end user canceled the task, as opposed to
+// the system or the provider
+func (r CancellationReason) UserInitiated() bool {
+	return r == CancellationUser
+}
+
+// Valid reports whether r is one of the known reasons
+func (r CancellationReason) Valid() bool {
+	switch r {
+	case CancellationUser, CancellationBudget, CancellationTimeout, CancellationExpired, CancellationProvider, CancellationSuperseded:
+		return true
+	}
+	return false
+}
+
+// Cancellation records why and when a task was canceled
+type Cancellation struct {
+	Reason     CancellationReason `json:"reason"`
+	Message    string             `json:"message,omitempty"`
+	CanceledAt time.Time          `json:"canceled_at"` // Zero when the provider does not report it
+}
+
+// Canceler is implemented by providers that can cancel unfinished tasks
+type Canceler interface {
+	CancelGeneration(ctx context.Context, taskID string) error
+}
@@ -0,0 +1,114 @@
+package adapters
+
+import "github.com/feitianbubu/vidgo/models"
+
+// ImageConstraints describes the input images a provider accepts
+type ImageConstraints struct {
+	MinWidth       int      `json:"min_width,omitempty"`
+	MinHeight      int      `json:"min_height,omitempty"`
+	MaxWidth       int      `json:"max_width,omitempty"`
+	MaxHeight      int      `json:"max_height,omitempty"`
+	MaxBytes       int64    `json:"max_bytes,omitempty"`
+	MinAspectRatio float64  `json:"min_aspect_ratio,omitempty"` // width/height
+	MaxAspectRatio float64  `json:"max_aspect_ratio,omitempty"` // width/height
+	Formats        []string `json:"formats,omitempty"`          // As reported by image.DecodeConfig, e.g. "jpeg", "png"
+}
+
+// DurationConstraints describes the clip lengths a provider generates
+type DurationConstraints struct {
+	Durations     []float64 `json:"durations,omitempty"`      // Clip lengths one task accepts, in seconds
+	ExtendSeconds float64   `json:"extend_seconds,omitempty"` // Footage one extension adds, 0 when extension is unsupported
+	MaxExtended   float64   `json:"max_extended,omitempty"`   // Longest video extensions may build, 0 for no limit
+}
+
+// Combination is one valid choice of model, mode, duration and aspect ratio
+type Combination struct {
+	Model       string  `json:"model"`
+	Mode        string  `json:"mode,omitempty"` // ModeStandard or ModePro, empty for providers without modes
+	Duration    float64 `json:"duration"`
+	AspectRatio string  `json:"aspect_ratio,omitempty"` // e.g. "16:9", empty when it follows the input image
+}
+
+// CapabilityMatrix lists the valid combinations of a provider
+type CapabilityMatrix []Combination
+
+// Capabilities describes what a provider supports
+type Capabilities struct {
+	Image        *ImageConstraints    `json:"image,omitempty"`
+	Duration     *DurationConstraints `json:"duration,omitempty"`
+	Combinations CapabilityMatrix     `json:"combinations,omitempty"`
+}
+
+// Matrix returns the valid combinations of model, mode, duration and aspect ratio,
+// or nil if the provider does not publish them
+func (c *Capabilities) Matrix() CapabilityMatrix {
+	if c == nil {
+		return nil
+	}
+	return c.Combinations
+}
+
+// Filter returns the combinations matching the set fields of want; zero fields
+// match anything, e.g. Filter(Combination{Model: "kling-v1", Mode: ModePro})
+func (m CapabilityMatrix) Filter(want Combination) CapabilityMatrix {
+	var matched CapabilityMatrix
+	for _, c := range m {
+		if (want.Model == "" || c.Model == want.Model) &&
+			(want.Mode == "" || c.Mode == want.Mode) &&
+			(want.Duration == 0 || c.Duration == want.Duration) &&
+			(want.AspectRatio == "" || c.AspectRatio == want.AspectRatio) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// Allows reports whether the matrix contains a combination matching want, see
+// Filter. An empty matrix allows everything.
+func (m CapabilityMatrix) Allows(want Combination) bool {
+	return len(m) == 0 || len(m.Filter(want)) > 0
+}
+
+// NewCapabilityMatrix returns every combination of the given values. Empty
+// modes or aspect ratios produce combinations with that field empty; exclude,
+// when set, drops combinations the provider rejects.
+func NewCapabilityMatrix(models, modes []string, durations []float64, aspectRatios []string, exclude func(Combination) bool) CapabilityMatrix {
+	if len(modes) == 0 {
+		modes = []string{""}
+	}
+	if len(aspectRatios) == 0 {
+		aspectRatios = []string{""}
+	}
+
+	var matrix CapabilityMatrix
+	for _, model := range models {
+		for _, mode := range modes {
+			for _, duration := range durations {
+				for _, ratio := range aspectRatios {
+					c := Combination{Model: model, Mode: mode, Duration: duration, AspectRatio: ratio}
+					if exclude == nil || !exclude(c) {
+						matrix = append(matrix, c)
+					}
+				}
+			}
+		}
+	}
+	return matrix
+}
+
+// CatalogMatrix returns the combinations of the models in a catalog, honoring
+// durations narrowed per mode
+func CatalogMatrix(catalog models.Catalog) CapabilityMatrix {
+	var matrix CapabilityMatrix
+	for _, model := range catalog {
+		model := model
+		matrix = append(matrix, NewCapabilityMatrix([]string{model.Name}, model.Modes, model.Durations, model.AspectRatios,
+			func(c Combination) bool { return !model.SupportsDuration(c.Mode, c.Duration) })...)
+	}
+	return matrix
+}
+
+// CapabilitiesProvider is implemented by providers that publish their capabilities
+type CapabilitiesProvider interface {
+	Capabilities() *Capabilities
+}
@@ -0,0 +1,63 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// ExchangeRecorder observes the HTTP exchanges of provider calls, e.g. to save
+// request/response examples. Bodies are passed unmodified; recorders must not
+// keep req or resp beyond the call.
+type ExchangeRecorder interface {
+	RecordExchange(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte)
+}
+
+type recorderKey struct{}
+
+// WithExchangeRecorder returns a context whose provider HTTP exchanges are
+// passed to recorder, see HTTPClient
+func WithExchangeRecorder(ctx context.Context, recorder ExchangeRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// ExchangeRecorderFromContext returns the recorder stored in ctx, or nil if there is none
+func ExchangeRecorderFromContext(ctx context.Context) ExchangeRecorder {
+	recorder, _ := ctx.Value(recorderKey{}).(ExchangeRecorder)
+	return recorder
+}
+
+// recordingTransport passes completed exchanges to a recorder
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder ExchangeRecorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	t.recorder.RecordExchange(req, reqBody, resp, respBody)
+	return resp, nil
+}
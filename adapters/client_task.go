@@ -0,0 +1,22 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+)
+
+// ClientTaskLookup is implemented by providers that can find a task by the
+// GenerationRequest.ClientTaskID it was submitted with, e.g. after a restart
+// lost the provider task ID
+type ClientTaskLookup interface {
+	GetGenerationByClientID(ctx context.Context, clientTaskID string) (*TaskResult, error)
+}
+
+// ValidateClientTaskID checks GenerationRequest.ClientTaskID; providers that
+// cannot look tasks up by it pass supported=false and reject the field
+func ValidateClientTaskID(provider, clientTaskID string, supported bool) error {
+	if clientTaskID != "" && !supported {
+		return fmt.Errorf("%s does not support client task IDs", provider)
+	}
+	return nil
+}
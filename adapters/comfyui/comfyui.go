@@ -0,0 +1,649 @@
+package comfyui
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Provider implements the adapters.Provider interface for a self-hosted ComfyUI server.
+// Each generation queues a copy of the configured workflow (API format) in which
+// {{placeholder}} strings are replaced by request values.
+type Provider struct {
+	config     *adapters.ProviderConfig
+	client     *http.Client
+	baseURL    string
+	apiKey     string
+	workflow   map[string]interface{}
+	outputNode string
+	outputDir  string
+	clientID   string
+}
+
+// PromptRequest represents ComfyUI's /prompt request format
+type PromptRequest struct {
+	Prompt   map[string]interface{} `json:"prompt"`
+	ClientID string                 `json:"client_id,omitempty"`
+}
+
+// PromptResponse represents ComfyUI's /prompt response
+type PromptResponse struct {
+	PromptID   string                 `json:"prompt_id"`
+	Number     int                    `json:"number"`
+	NodeErrors map[string]interface{} `json:"node_errors,omitempty"`
+	Error      *PromptError           `json:"error,omitempty"`
+}
+
+// PromptError represents a workflow validation error
+type PromptError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+}
+
+// HistoryEntry represents a finished or running prompt in /history/{prompt_id}
+type HistoryEntry struct {
+	Outputs map[string]map[string]json.RawMessage `json:"outputs"`
+	Status  struct {
+		StatusStr string            `json:"status_str"`
+		Completed bool              `json:"completed"`
+		Messages  []json.RawMessage `json:"messages"`
+	} `json:"status"`
+}
+
+// OutputFile is a file written by an output node
+type OutputFile struct {
+	Filename  string `json:"filename"`
+	Subfolder string `json:"subfolder"`
+	Type      string `json:"type"`
+}
+
+// placeholderPattern matches {{name}} in workflow inputs
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// videoExtensions are preferred over other output files
+var videoExtensions = map[string]bool{".mp4": true, ".webm": true, ".mov": true, ".mkv": true, ".gif": true, ".webp": true}
+
+// extraSchema lists the Extra keys ComfyUI understands
+var extraSchema = adapters.ExtraSchema{
+	{Name: "workflow", Description: "Workflow in API format (JSON)", Validate: func(value string) error {
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("must be valid JSON")
+		}
+		return nil
+	}},
+	{Name: "workflow_file", Description: "Path to a workflow in API format"},
+	{Name: "output_node", Description: "ID of the node whose output is returned"},
+	{Name: "output_dir", Description: "ComfyUI output directory, to return local paths instead of /view URLs"},
+}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
+func init() {
+	adapters.Register("comfyui", New)
+}
+
+// New creates a new ComfyUI provider instance. The workflow is read from
+// Extra["workflow"] (inline JSON) or Extra["workflow_file"]. Extra["output_node"]
+// selects the node whose output is returned, and Extra["output_dir"] returns local
+// file paths under ComfyUI's output directory instead of /view URLs.
+func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("invalid configuration")
+	}
+
+	if err := extraSchema.Validate("ComfyUI", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
+	workflowJSON := []byte(config.Extra["workflow"])
+	if file := config.Extra["workflow_file"]; len(workflowJSON) == 0 && file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read workflow file: %w", err)
+		}
+		workflowJSON = data
+	}
+	if len(workflowJSON) == 0 {
+		return nil, fmt.Errorf("a workflow is required for ComfyUI (Extra[\"workflow\"] or Extra[\"workflow_file\"])")
+	}
+
+	var workflow map[string]interface{}
+	if err := json.Unmarshal(workflowJSON, &workflow); err != nil {
+		return nil, fmt.Errorf("invalid workflow JSON: %w", err)
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "http://127.0.0.1:8188"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Provider{
+		config:     config,
+		client:     &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     strings.TrimSpace(config.APIKey),
+		workflow:   workflow,
+		outputNode: config.Extra["output_node"],
+		outputDir:  config.Extra["output_dir"],
+		clientID:   fmt.Sprintf("vidgo-%d", time.Now().UnixNano()),
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "ComfyUI"
+}
+
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
+// SupportedModels returns no fixed models; the request model is available to the workflow as {{model}}
+func (p *Provider) SupportedModels() []string {
+	return []string{}
+}
+
+// Warm opens a connection to the ComfyUI server
+func (p *Provider) Warm(ctx context.Context) error {
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
+}
+
+// ValidateRequest checks that the workflow can be filled from the request
+func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
+	if err := adapters.ValidateImages("ComfyUI", req.Images, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateKeyframes("ComfyUI", req.Keyframes, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("ComfyUI", req.CallbackURL, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("ComfyUI", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateWatermark("ComfyUI", req.Watermark); err != nil {
+		return err
+	}
+
+	hasImage := req.Image != "" || len(req.ImageBytes) > 0
+	for _, name := range workflowPlaceholders(p.workflow) {
+		switch name {
+		case "image":
+			if !hasImage {
+				return fmt.Errorf("the ComfyUI workflow requires an image")
+			}
+		case "prompt", "negative_prompt", "seed", "width", "height", "duration", "fps", "frames", "model":
+		default:
+			return fmt.Errorf("unknown workflow placeholder: {{%s}}", name)
+		}
+	}
+	return nil
+}
+
+// CreateGeneration queues the filled-in workflow
+func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
+	values, err := p.placeholderValues(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := fillPlaceholders(p.workflow, values).(map[string]interface{})
+	if err := applyInputs(workflow, req.Metadata); err != nil {
+		return nil, err
+	}
+
+	body := adapters.TransformRequest("comfyui", &PromptRequest{Prompt: workflow, ClientID: p.clientID})
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	respBody, status, err := p.do(ctx, "POST", "/prompt", "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+
+	var promptResp PromptResponse
+	json.Unmarshal(respBody, &promptResp)
+	if status >= 400 || promptResp.PromptID == "" {
+		message := string(respBody)
+		if promptResp.Error != nil {
+			message = promptResp.Error.Message
+			if promptResp.Error.Details != "" {
+				message += ": " + promptResp.Error.Details
+			}
+		}
+		if status < 400 {
+			status = http.StatusBadRequest
+		}
+		return nil, adapters.NewAPIError(status, "", message)
+	}
+
+	return &adapters.GenerationResponse{
+		TaskID: promptResp.PromptID,
+		Status: adapters.TaskStatusQueued,
+	}, nil
+}
+
+// GetGeneration reads the prompt history, falling back to the queue while it is not finished
+func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	body, status, err := p.do(ctx, "GET", "/history/"+url.PathEscape(taskID), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status >= 400 {
+		return nil, adapters.NewAPIError(status, "", string(body))
+	}
+
+	var history map[string]*HistoryEntry
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	entry, ok := history[taskID]
+	if !ok {
+		return p.queuedResult(ctx, taskID)
+	}
+
+	result := &adapters.TaskResult{TaskID: taskID, RawResponse: body}
+	switch {
+	case entry.Status.StatusStr == "error":
+		result.Status = adapters.TaskStatusFailed
+		result.Error = &adapters.TaskError{Code: http.StatusInternalServerError, Message: executionError(entry)}
+	case entry.Status.Completed:
+		file, ok := p.outputFile(entry)
+		if !ok {
+			result.Status = adapters.TaskStatusFailed
+			result.Error = &adapters.TaskError{Code: http.StatusInternalServerError, Message: "workflow produced no output file"}
+			break
+		}
+		result.Status = adapters.TaskStatusSucceeded
+		result.URL = p.outputLocation(file)
+		result.Format = strings.TrimPrefix(strings.ToLower(path.Ext(file.Filename)), ".")
+	default:
+		result.Status = adapters.TaskStatusProcessing
+	}
+
+	return result, nil
+}
+
+// queuedResult reports whether a prompt without history is running or waiting
+func (p *Provider) queuedResult(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	body, status, err := p.do(ctx, "GET", "/queue", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status >= 400 {
+		return nil, adapters.NewAPIError(status, "", string(body))
+	}
+
+	var queue struct {
+		Running [][]interface{} `json:"queue_running"`
+		Pending [][]interface{} `json:"queue_pending"`
+	}
+	if err := json.Unmarshal(body, &queue); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := &adapters.TaskResult{TaskID: taskID, Status: adapters.TaskStatusQueued, RawResponse: body}
+	for _, item := range queue.Running {
+		// Queue items are [number, prompt_id, prompt, extra_data, outputs_to_execute]
+		if len(item) > 1 && item[1] == taskID {
+			result.Status = adapters.TaskStatusProcessing
+		}
+	}
+	return result, nil
+}
+
+// placeholderValues collects the values substituted into the workflow
+func (p *Provider) placeholderValues(ctx context.Context, req *adapters.GenerationRequest) (map[string]interface{}, error) {
+	fps := req.FPS
+	if fps <= 0 {
+		fps = 24
+	}
+	seed := rand.Int63n(1 << 48)
+	if req.Seed != nil {
+		seed = int64(*req.Seed)
+	}
+
+	values := map[string]interface{}{
+		"prompt":   req.Prompt,
+		"seed":     seed,
+		"width":    req.Width,
+		"height":   req.Height,
+		"duration": req.Duration,
+		"fps":      fps,
+		"frames":   int(req.Duration*float64(fps)) + 1,
+		"model":    req.Model,
+
+		"negative_prompt": adapters.NegativePrompt(req),
+	}
+
+	if req.Image != "" || len(req.ImageBytes) > 0 {
+		name, err := p.uploadImage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		values["image"] = name
+	}
+
+	return values, nil
+}
+
+// uploadImage stores the request image in ComfyUI's input directory and returns the
+// name to use in LoadImage nodes. A plain string that is not a URL or Base64 is
+// taken as the name of an image already in the input directory.
+func (p *Provider) uploadImage(ctx context.Context, req *adapters.GenerationRequest) (string, error) {
+	data := req.ImageBytes
+	if decoded, _, ok := adapters.DecodeDataURI(req.Image); ok {
+		data = decoded
+	} else if strings.HasPrefix(req.Image, "http://") || strings.HasPrefix(req.Image, "https://") {
+		fetched, err := p.fetchImage(ctx, req.Image)
+		if err != nil {
+			return "", err
+		}
+		data = fetched
+	} else if decoded, err := base64.StdEncoding.DecodeString(req.Image); req.Image != "" && err == nil {
+		data = decoded
+	} else if req.Image != "" {
+		return req.Image, nil
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	// 按内容命名，同一图片重复上传时覆盖自身而不会覆盖其他请求的图片
+	sum := sha256.Sum256(data)
+	part, err := writer.CreateFormFile("image", "vidgo-"+hex.EncodeToString(sum[:16])+imageExtension(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	part.Write(data)
+	writer.WriteField("type", "input")
+	writer.WriteField("overwrite", "true")
+	writer.Close()
+
+	body, status, err := p.do(ctx, "POST", "/upload/image", writer.FormDataContentType(), &buf)
+	if err != nil {
+		return "", err
+	}
+	if status >= 400 {
+		return "", fmt.Errorf("failed to upload image: %w", adapters.NewAPIError(status, "", string(body)))
+	}
+
+	var uploaded struct {
+		Name      string `json:"name"`
+		Subfolder string `json:"subfolder"`
+	}
+	if err := json.Unmarshal(body, &uploaded); err != nil || uploaded.Name == "" {
+		return "", fmt.Errorf("failed to upload image: invalid response: %s", string(body))
+	}
+	if uploaded.Subfolder != "" {
+		return uploaded.Subfolder + "/" + uploaded.Name, nil
+	}
+	return uploaded.Name, nil
+}
+
+// maxFetchedImageSize caps the images fetchImage downloads
+const maxFetchedImageSize = 20 << 20
+
+// fetchImage downloads an image URL so it can be uploaded to ComfyUI
+func (p *Provider) fetchImage(ctx context.Context, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch image: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchedImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	if len(data) > maxFetchedImageSize {
+		return nil, fmt.Errorf("failed to fetch image: larger than %d bytes", maxFetchedImageSize)
+	}
+	return data, nil
+}
+
+// imageExtension guesses a file extension for uploaded image data
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".png"
+	}
+}
+
+// outputFile picks the output of the configured node, preferring video files.
+// Nodes are visited in ID order so the choice is stable.
+func (p *Provider) outputFile(entry *HistoryEntry) (OutputFile, bool) {
+	nodeIDs := make([]string, 0, len(entry.Outputs))
+	for nodeID := range entry.Outputs {
+		if p.outputNode == "" || nodeID == p.outputNode {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	sort.Strings(nodeIDs)
+
+	var fallback *OutputFile
+	for _, nodeID := range nodeIDs {
+		// VideoHelperSuite reports videos under "gifs"
+		for _, key := range []string{"gifs", "videos", "images"} {
+			var files []OutputFile
+			if json.Unmarshal(entry.Outputs[nodeID][key], &files) != nil {
+				continue
+			}
+			for i := range files {
+				if videoExtensions[strings.ToLower(path.Ext(files[i].Filename))] {
+					return files[i], true
+				}
+				if fallback == nil && files[i].Filename != "" {
+					fallback = &files[i]
+				}
+			}
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return OutputFile{}, false
+}
+
+// outputLocation returns the local path of an output file when Extra["output_dir"]
+// is set, otherwise its /view URL
+func (p *Provider) outputLocation(file OutputFile) string {
+	if p.outputDir != "" {
+		return filepath.Join(p.outputDir, file.Subfolder, file.Filename)
+	}
+
+	query := url.Values{}
+	query.Set("filename", file.Filename)
+	query.Set("subfolder", file.Subfolder)
+	query.Set("type", file.Type)
+	return p.baseURL + "/view?" + query.Encode()
+}
+
+// executionError extracts the exception message of a failed prompt
+func executionError(entry *HistoryEntry) string {
+	for _, raw := range entry.Status.Messages {
+		// Messages are [event, data] pairs
+		var message []json.RawMessage
+		if json.Unmarshal(raw, &message) != nil || len(message) != 2 {
+			continue
+		}
+		var event string
+		json.Unmarshal(message[0], &event)
+		if event != "execution_error" {
+			continue
+		}
+		var data struct {
+			NodeType         string `json:"node_type"`
+			ExceptionMessage string `json:"exception_message"`
+		}
+		json.Unmarshal(message[1], &data)
+		return strings.TrimSpace(data.NodeType + ": " + data.ExceptionMessage)
+	}
+	return "workflow execution failed"
+}
+
+// workflowPlaceholders lists the placeholder names used in a workflow
+func workflowPlaceholders(value interface{}) []string {
+	var names []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			names = append(names, workflowPlaceholders(item)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, workflowPlaceholders(item)...)
+		}
+	case string:
+		for _, match := range placeholderPattern.FindAllStringSubmatch(v, -1) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// fillPlaceholders returns a copy of value with placeholders replaced. A string that
+// is exactly one placeholder takes the value's type, so "{{seed}}" becomes a number.
+func fillPlaceholders(value interface{}, values map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		filled := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			filled[k] = fillPlaceholders(item, values)
+		}
+		return filled
+	case []interface{}:
+		filled := make([]interface{}, len(v))
+		for i, item := range v {
+			filled[i] = fillPlaceholders(item, values)
+		}
+		return filled
+	case string:
+		if match := placeholderPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			if replacement, ok := values[match[1]]; ok {
+				return replacement
+			}
+		}
+		return placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			if replacement, ok := values[name]; ok {
+				return fmt.Sprint(replacement)
+			}
+			return placeholder
+		})
+	default:
+		return value
+	}
+}
+
+// applyInputs sets metadata["inputs"] entries of the form "node_id.input" on the workflow
+func applyInputs(workflow map[string]interface{}, metadata map[string]interface{}) error {
+	inputs, ok := metadata["inputs"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for key, value := range inputs {
+		nodeID, input, ok := strings.Cut(key, ".")
+		node, found := workflow[nodeID].(map[string]interface{})
+		if !ok || !found {
+			return fmt.Errorf("invalid workflow input %q", key)
+		}
+		nodeInputs, _ := node["inputs"].(map[string]interface{})
+		if nodeInputs == nil {
+			nodeInputs = make(map[string]interface{})
+			node["inputs"] = nodeInputs
+		}
+		nodeInputs[input] = value
+	}
+	return nil
+}
+
+// do performs a request against the ComfyUI server. The API key, if any, is sent
+// as a Bearer token for servers behind an authenticating proxy.
+func (p *Provider) do(ctx context.Context, method, path, contentType string, body io.Reader) ([]byte, int, error) {
+	baseURL, apiKey := p.baseURL, p.apiKey
+	if overrides := adapters.RequestOverridesFromContext(ctx); overrides != nil {
+		if overrides.BaseURL != "" {
+			baseURL = strings.TrimRight(overrides.BaseURL, "/")
+		}
+		if overrides.APIKey != "" {
+			apiKey = overrides.APIKey
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
+
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return respBody, resp.StatusCode, nil
+}
@@ -0,0 +1,24 @@
+package adapters
+
+import "context"
+
+// EffectRequest applies a preset video effect, such as "hug" or "squish", to images
+type EffectRequest struct {
+	Effect string `json:"effect"`
+	Model  string `json:"model,omitempty"`
+	Mode   string `json:"mode,omitempty"`
+
+	// Images holds one image for single-subject effects, two for two-person effects
+	Images   []string `json:"images"`
+	Duration int      `json:"duration,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// EffectGenerator is implemented by providers that offer preset video effects.
+// The returned task is polled with GetGeneration like any other.
+type EffectGenerator interface {
+	CreateEffect(ctx context.Context, req *EffectRequest) (*GenerationResponse, error)
+	// SupportedEffects lists the effects available for a model
+	SupportedEffects(model string) []string
+}
@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Endpoint template placeholders
+const (
+	PlaceholderVersion  = "{version}"
+	PlaceholderTaskType = "{task_type}"
+)
+
+// DefaultEndpointKey is the ProviderConfig.Endpoints key applied to every task type
+const DefaultEndpointKey = "*"
+
+var placeholderPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// ValidateEndpointTemplate checks that a path template is absolute and only uses known placeholders.
+// Templates shared by all task types must contain {task_type}.
+func ValidateEndpointTemplate(template string, shared bool) error {
+	if !strings.HasPrefix(template, "/") {
+		return fmt.Errorf("endpoint template %q must start with '/'", template)
+	}
+
+	for _, placeholder := range placeholderPattern.FindAllString(template, -1) {
+		if placeholder != PlaceholderVersion && placeholder != PlaceholderTaskType {
+			return fmt.Errorf("endpoint template %q uses unknown placeholder %s", template, placeholder)
+		}
+	}
+
+	if shared && !strings.Contains(template, PlaceholderTaskType) {
+		return fmt.Errorf("endpoint template %q must contain %s", template, PlaceholderTaskType)
+	}
+	return nil
+}
+
+// ExpandEndpoint substitutes the version and task type placeholders in a path template
+func ExpandEndpoint(template, version, taskType string) string {
+	return strings.NewReplacer(PlaceholderVersion, version, PlaceholderTaskType, taskType).Replace(template)
+}
+
+// ResolveEndpoints builds the path for each task type from the default template and config overrides
+func ResolveEndpoints(defaultTemplate, version string, taskTypes []string, overrides map[string]string) (map[string]string, error) {
+	template := defaultTemplate
+	if shared, ok := overrides[DefaultEndpointKey]; ok {
+		if err := ValidateEndpointTemplate(shared, true); err != nil {
+			return nil, err
+		}
+		template = shared
+	}
+
+	endpoints := make(map[string]string, len(taskTypes))
+	for _, taskType := range taskTypes {
+		taskTemplate := template
+		if override, ok := overrides[taskType]; ok {
+			if err := ValidateEndpointTemplate(override, false); err != nil {
+				return nil, err
+			}
+			taskTemplate = override
+		}
+		endpoints[taskType] = ExpandEndpoint(taskTemplate, version, taskType)
+	}
+
+	for key := range overrides {
+		if key == DefaultEndpointKey {
+			continue
+		}
+		if _, ok := endpoints[key]; !ok {
+			return nil, fmt.Errorf("unknown task type in endpoint overrides: %s", key)
+		}
+	}
+
+	return endpoints, nil
+}
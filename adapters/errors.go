@@ -0,0 +1,34 @@
+package adapters
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// APIError is an error response of a provider API. StatusCode is the HTTP
+// status, or the closest one for providers that report errors in the body of a
+// 200 response; Code is the provider's own error code, if any.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" && e.Code != fmt.Sprint(e.StatusCode) {
+		return fmt.Sprintf("API error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// NewAPIError builds an APIError for an HTTP error response, falling back to
+// the status text when the provider gave no message
+func NewAPIError(statusCode int, code, message string) *APIError {
+	if statusCode == 0 {
+		statusCode = http.StatusBadGateway
+	}
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &APIError{StatusCode: statusCode, Code: code, Message: message}
+}
@@ -0,0 +1,18 @@
+package adapters
+
+import "context"
+
+// ExtendRequest continues a generated video with more footage
+type ExtendRequest struct {
+	TaskID         string                 `json:"task_id,omitempty"`  // Succeeded task whose video is extended, used when VideoID is empty
+	VideoID        string                 `json:"video_id,omitempty"` // Provider video ID
+	Prompt         string                 `json:"prompt,omitempty"`
+	NegativePrompt string                 `json:"negative_prompt,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Extender is implemented by providers that can extend generated videos. The
+// returned task is polled with GetGeneration like any other.
+type Extender interface {
+	ExtendGeneration(ctx context.Context, req *ExtendRequest) (*GenerationResponse, error)
+}
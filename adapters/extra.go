@@ -0,0 +1,112 @@
+package adapters
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExtraKey describes a ProviderConfig.Extra key understood by a provider
+type ExtraKey struct {
+	Name        string
+	Description string
+
+	// Validate checks a non-empty value, nil accepts any value
+	Validate func(value string) error
+}
+
+// ExtraSchema lists the Extra keys a provider understands
+type ExtraSchema []ExtraKey
+
+// Validate rejects Extra keys outside the schema, unless allowUnknown is set, and
+// values that fail their key's validation
+func (s ExtraSchema) Validate(provider string, extra map[string]string, allowUnknown bool) error {
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key, ok := s.lookup(name)
+		if !ok {
+			if allowUnknown {
+				continue
+			}
+			if suggestion := s.closest(name); suggestion != "" {
+				return fmt.Errorf("unknown %s Extra key %q (did you mean %q?)", provider, name, suggestion)
+			}
+			return fmt.Errorf("unknown %s Extra key %q", provider, name)
+		}
+
+		value := extra[name]
+		if value != "" && key.Validate != nil {
+			if err := key.Validate(value); err != nil {
+				return fmt.Errorf("invalid %s Extra[%q]: %w", provider, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// lookup finds a key by name
+func (s ExtraSchema) lookup(name string) (ExtraKey, bool) {
+	for _, key := range s {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return ExtraKey{}, false
+}
+
+// closest returns the key name within two edits of name, if any
+func (s ExtraSchema) closest(name string) string {
+	best, bestDistance := "", 3
+	for _, key := range s {
+		if d := editDistance(strings.ToLower(name), key.Name); d < bestDistance {
+			best, bestDistance = key.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+// ValidateHTTPURL is an ExtraKey validator for absolute http(s) URLs
+func ValidateHTTPURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL")
+	}
+	return nil
+}
+
+// ValidateDuration is an ExtraKey validator for non-negative durations such as "5m"
+func ValidateDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return fmt.Errorf("must be a non-negative duration such as \"5m\"")
+	}
+	return nil
+}
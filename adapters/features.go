@@ -0,0 +1,158 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SDKVersion is the vidgo version advertised to downstream services
+const SDKVersion = "1.0"
+
+// FeaturesHeader carries feature negotiation between vidgo-based clients, gateways
+// and relays, as comma-separated names with optional values: "sdk=1.0, callback"
+const FeaturesHeader = "X-Vidgo-Features"
+
+// ViaHeader lists the vidgo gateways a request was relayed through, oldest
+// first and comma-separated, so chained gateways can detect loops
+const ViaHeader = "X-Vidgo-Via"
+
+// Well-known feature names
+const (
+	FeatureSDK         = "sdk"         // SDK version of the sender
+	FeatureCallback    = "callback"    // Task completion callbacks are supported
+	FeaturePassthrough = "passthrough" // Request bodies are forwarded unchanged
+)
+
+// Features is a set of negotiated features; flags have an empty value
+type Features map[string]string
+
+// ParseFeatures parses an X-Vidgo-Features header value. Unknown names are kept
+// so newer peers can add features without breaking older ones.
+func ParseFeatures(header string) Features {
+	features := Features{}
+	for _, item := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(item), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			features[name] = strings.TrimSpace(value)
+		}
+	}
+	return features
+}
+
+// Has reports whether a feature is present
+func (f Features) Has(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+// String formats the features as a header value, in name order
+func (f Features) String() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = name
+		if f[name] != "" {
+			items[i] += "=" + f[name]
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+type featuresKey struct{}
+
+// WithFeatures returns a context whose requests advertise additional features,
+// e.g. a gateway announcing callback support to a downstream relay
+func WithFeatures(ctx context.Context, features Features) context.Context {
+	return context.WithValue(ctx, featuresKey{}, features)
+}
+
+// FeaturesFromContext returns the features stored in ctx, or nil if there are none
+func FeaturesFromContext(ctx context.Context) Features {
+	features, _ := ctx.Value(featuresKey{}).(Features)
+	return features
+}
+
+type viaKey struct{}
+
+// WithVia returns a context whose requests carry the relay hops, see ViaHeader
+func WithVia(ctx context.Context, hops []string) context.Context {
+	return context.WithValue(ctx, viaKey{}, hops)
+}
+
+// ViaFromContext returns the relay hops stored in ctx, or nil if there are none
+func ViaFromContext(ctx context.Context) []string {
+	hops, _ := ctx.Value(viaKey{}).([]string)
+	return hops
+}
+
+// ParseVia parses an X-Vidgo-Via header value
+func ParseVia(header string) []string {
+	var hops []string
+	for _, hop := range strings.Split(header, ",") {
+		if hop = strings.TrimSpace(hop); hop != "" {
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// SetFeatureHeader advertises the SDK version and the context features on req,
+// and the relay hops of the context, if any
+func SetFeatureHeader(ctx context.Context, req *http.Request) {
+	features := Features{FeatureSDK: SDKVersion}
+	for name, value := range FeaturesFromContext(ctx) {
+		features[name] = value
+	}
+	req.Header.Set(FeaturesHeader, features.String())
+	if hops := ViaFromContext(ctx); len(hops) > 0 {
+		req.Header.Set(ViaHeader, strings.Join(hops, ", "))
+	}
+}
+
+// PeerFeatures records the feature hints a downstream vidgo-based service sends
+// back in its responses. It is safe for concurrent use.
+type PeerFeatures struct {
+	mu       sync.RWMutex
+	features Features
+}
+
+// Observe records the hints in a response header, if any
+func (p *PeerFeatures) Observe(header http.Header) {
+	values := header.Values(FeaturesHeader)
+	if len(values) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.features = ParseFeatures(strings.Join(values, ","))
+}
+
+// Features returns a copy of the last observed hints, or nil if none were seen
+func (p *PeerFeatures) Features() Features {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.features == nil {
+		return nil
+	}
+	features := make(Features, len(p.features))
+	for name, value := range p.features {
+		features[name] = value
+	}
+	return features
+}
+
+// FeatureNegotiator is implemented by providers that report downstream feature hints
+type FeatureNegotiator interface {
+	PeerFeatures() Features
+}
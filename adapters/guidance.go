@@ -0,0 +1,14 @@
+package adapters
+
+// ValidGuidanceScale reports whether scale is unset or within [0, 1]
+func ValidGuidanceScale(scale *float64) bool {
+	return scale == nil || (*scale >= 0 && *scale <= 1)
+}
+
+// GuidanceScale returns the requested guidance scale, or def when unset
+func GuidanceScale(req *GenerationRequest, def float64) float64 {
+	if req.GuidanceScale != nil {
+		return *req.GuidanceScale
+	}
+	return def
+}
@@ -0,0 +1,66 @@
+package adapters
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// IsDataURI reports whether s is a data URI (data:[<mediatype>][;base64],<data>)
+func IsDataURI(s string) bool {
+	return strings.HasPrefix(s, "data:")
+}
+
+// DecodeDataURI decodes a data URI, returning its bytes and media type
+func DecodeDataURI(s string) ([]byte, string, bool) {
+	if !IsDataURI(s) {
+		return nil, "", false
+	}
+
+	header, payload, ok := strings.Cut(strings.TrimPrefix(s, "data:"), ",")
+	if !ok {
+		return nil, "", false
+	}
+
+	mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+	if isBase64 {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, "", false
+		}
+		return data, mediaType, true
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, "", false
+	}
+	return []byte(data), mediaType, true
+}
+
+// ImageBase64 returns the request image as plain Base64 (no data URI prefix) if it
+// was supplied as bytes or a data URI, or "" when Image is a URL or already Base64.
+func ImageBase64(req *GenerationRequest) string {
+	if len(req.ImageBytes) > 0 {
+		return base64.StdEncoding.EncodeToString(req.ImageBytes)
+	}
+	if data, _, ok := DecodeDataURI(req.Image); ok {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	return ""
+}
+
+// ValidateImages rejects more than maxImages reference images; providers without
+// multi-image support pass 0
+func ValidateImages(provider string, images []string, maxImages int) error {
+	switch {
+	case len(images) == 0:
+		return nil
+	case maxImages == 0:
+		return fmt.Errorf("%s does not support multiple reference images", provider)
+	case len(images) > maxImages:
+		return fmt.Errorf("%s supports at most %d reference images, got %d", provider, maxImages, len(images))
+	}
+	return nil
+}
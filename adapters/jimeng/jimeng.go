@@ -1,25 +1,178 @@
 package jimeng
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/models"
 )
 
 // Provider implements the adapters.Provider interface for Jimeng video generation
 type Provider struct {
-	config *adapters.ProviderConfig
+	config    *adapters.ProviderConfig
+	client    *http.Client
+	baseURL   string
+	accessKey string
+	secretKey string
 }
 
-// New creates a new Jimeng provider instance
+const (
+	apiVersion   = "2022-08-31"
+	actionSubmit = "CVSync2AsyncSubmitTask"
+	actionResult = "CVSync2AsyncGetResult"
+
+	// codeSuccess is the Volcengine visual API success code
+	codeSuccess = 10000
+)
+
+// reqKeyPair holds the text-to-video and image-to-video req_key of a resolution tier
+type reqKeyPair struct{ t2v, i2v string }
+
+// modelSpec describes a model's resolution tiers. When sendResolution is set, one
+// req_key serves every tier and the tier is sent in the resolution field.
+type modelSpec struct {
+	tiers          map[string]reqKeyPair
+	sendResolution bool
+}
+
+// Resolution tiers, ordered from lowest to highest
+const (
+	Resolution480p  = "480p"
+	Resolution720p  = "720p"
+	Resolution1080p = "1080p"
+)
+
+var resolutionTiers = []string{Resolution480p, Resolution720p, Resolution1080p}
+
+var (
+	v30Keys      = reqKeyPair{t2v: "jimeng_t2v_v30", i2v: "jimeng_i2v_first_v30"}
+	v30HDKeys    = reqKeyPair{t2v: "jimeng_t2v_v30_1080p", i2v: "jimeng_i2v_first_v30_1080p"}
+	v30ProKeys   = reqKeyPair{t2v: "jimeng_ti2v_v30_pro", i2v: "jimeng_ti2v_v30_pro"}
+	seedanceLite = modelSpec{tiers: map[string]reqKeyPair{Resolution720p: v30Keys, Resolution1080p: v30HDKeys}}
+)
+
+// statusForCode maps a Volcengine visual API error code to an HTTP status. The
+// codes embed one, e.g. 50429 for too many requests and 50500 for an internal error.
+func statusForCode(code int) int {
+	if status := code - 50000; status >= 400 && status < 600 {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// modelSpecs maps a model to the req_keys of its resolution tiers
+var modelSpecs = map[string]modelSpec{
+	"jimeng-v1": {tiers: map[string]reqKeyPair{
+		Resolution720p: {t2v: "jimeng_vgfm_t2v_l20", i2v: "jimeng_vgfm_i2v_l20"},
+	}},
+	"jimeng-v2":         seedanceLite,
+	"seedance-1.0-lite": seedanceLite,
+	"seedance-1.0-pro": {sendResolution: true, tiers: map[string]reqKeyPair{
+		Resolution480p: v30ProKeys, Resolution720p: v30ProKeys, Resolution1080p: v30ProKeys,
+	}},
+}
+
+// catalog describes the Jimeng models; its aspect ratios are the text-to-video
+// ratios getAspectRatio produces
+var catalog = models.List("jimeng")
+
+var supportedModels = catalog.Names()
+
+// JimengSubmitRequest represents Jimeng's submit task request format
+type JimengSubmitRequest struct {
+	ReqKey      string    `json:"req_key"`
+	Prompt      string    `json:"prompt,omitempty"`
+	ImageURLs   []string  `json:"image_urls,omitempty"`
+	ImageBase64 []string  `json:"binary_data_base64,omitempty"`
+	Seed        int       `json:"seed"`
+	AspectRatio string    `json:"aspect_ratio,omitempty"`
+	Frames      int       `json:"frames,omitempty"`
+	Resolution  string    `json:"resolution,omitempty"`
+	LogoInfo    *LogoInfo `json:"logo_info,omitempty"`
+}
+
+// LogoInfo controls the visible "AI生成" mark added to Jimeng videos
+type LogoInfo struct {
+	AddLogo bool `json:"add_logo"`
+}
+
+// JimengResultRequest represents Jimeng's get result request format
+type JimengResultRequest struct {
+	ReqKey string `json:"req_key"`
+	TaskID string `json:"task_id"`
+}
+
+// JimengResponse represents Jimeng's response envelope
+type JimengResponse struct {
+	Code      int        `json:"code"`
+	Message   string     `json:"message"`
+	RequestID string     `json:"request_id"`
+	Data      JimengData `json:"data"`
+}
+
+// JimengData represents the data field of a Jimeng response
+type JimengData struct {
+	TaskID   string `json:"task_id"`
+	Status   string `json:"status,omitempty"`
+	VideoURL string `json:"video_url,omitempty"`
+}
+
+// extraSchema lists the Extra keys Jimeng understands; it has none
+var extraSchema = adapters.ExtraSchema{}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
+func init() {
+	adapters.Register("jimeng", New)
+}
+
+// New creates a new Jimeng provider instance.
+// Credentials are read from APIKey/SecretKey, or from APIKey in 'access_key,secret_key' format.
 func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
 	if config == nil {
 		return nil, fmt.Errorf("invalid configuration")
 	}
 
+	if err := extraSchema.Validate("Jimeng", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
+	accessKey, secretKey := strings.TrimSpace(config.APIKey), strings.TrimSpace(config.SecretKey)
+	if secretKey == "" {
+		keyParts := strings.Split(config.APIKey, ",")
+		if len(keyParts) != 2 {
+			return nil, fmt.Errorf("invalid API key format for Jimeng, expected SecretKey or 'access_key,secret_key'")
+		}
+		accessKey, secretKey = strings.TrimSpace(keyParts[0]), strings.TrimSpace(keyParts[1])
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://visual.volcengineapi.com"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
 	return &Provider{
-		config: config,
+		config:    config,
+		client:    &http.Client{Timeout: timeout},
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		accessKey: accessKey,
+		secretKey: secretKey,
 	}, nil
 }
 
@@ -28,25 +181,330 @@ func (p *Provider) Name() string {
 	return "Jimeng"
 }
 
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
 // SupportedModels returns supported models
 func (p *Provider) SupportedModels() []string {
-	return []string{"jimeng-v1", "jimeng-v2"}
+	return append([]string{}, supportedModels...)
+}
+
+// Capabilities returns the Jimeng input constraints
+func (p *Provider) Capabilities() *adapters.Capabilities {
+	return &adapters.Capabilities{
+		Image: &adapters.ImageConstraints{
+			MaxWidth:       4096,
+			MaxHeight:      4096,
+			MaxBytes:       4_700_000,
+			MinAspectRatio: 1.0 / 3,
+			MaxAspectRatio: 3,
+			Formats:        []string{"jpeg", "png"},
+		},
+		Duration:     &adapters.DurationConstraints{Durations: catalog.Durations()},
+		Combinations: adapters.CatalogMatrix(catalog),
+	}
+}
+
+// Warm opens a connection to the Volcengine visual API. Requests are signed locally per call.
+func (p *Provider) Warm(ctx context.Context) error {
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
 }
 
 // ValidateRequest validates the request for Jimeng
 func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
-	// TODO: Implement Jimeng-specific validation
-	return nil
+	if adapters.NegativePrompt(req) != "" {
+		return fmt.Errorf("Jimeng does not support negative prompts")
+	}
+	if err := adapters.ValidateImages("Jimeng", req.Images, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateKeyframes("Jimeng", req.Keyframes, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Jimeng", req.CallbackURL, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("Jimeng", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateWatermark("Jimeng", req.Watermark, adapters.WatermarkNone, adapters.WatermarkVisible); err != nil {
+		return err
+	}
+
+	model := modelName(req)
+	info, ok := catalog.Lookup(model)
+	if !ok {
+		return fmt.Errorf("unsupported model: %s", model)
+	}
+	if !info.SupportsDuration("", req.Duration) {
+		return fmt.Errorf("Jimeng %s only supports %s duration", model, models.FormatDurations(info.Durations))
+	}
+
+	_, err := resolveResolution(req)
+	return err
 }
 
 // CreateGeneration creates a video generation task
 func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
-	// TODO: Implement Jimeng API integration
-	return nil, fmt.Errorf("Jimeng provider not yet implemented")
+	jimengReq, err := p.convertToJimengRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var jimengResp JimengResponse
+	if _, err := p.call(ctx, actionSubmit, adapters.TransformRequest("jimeng", jimengReq), &jimengResp); err != nil {
+		return nil, err
+	}
+
+	// task_id 只在提交时知道 req_key，编码进任务ID以便查询时还原
+	return &adapters.GenerationResponse{
+		TaskID: jimengReq.ReqKey + ":" + jimengResp.Data.TaskID,
+		Status: adapters.TaskStatusQueued,
+	}, nil
 }
 
 // GetGeneration retrieves the task status
 func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
-	// TODO: Implement Jimeng API integration
-	return nil, fmt.Errorf("Jimeng provider not yet implemented")
+	reqKey, jimengTaskID, ok := strings.Cut(taskID, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid Jimeng task ID: %s", taskID)
+	}
+
+	var jimengResp JimengResponse
+	body, err := p.call(ctx, actionResult, &JimengResultRequest{ReqKey: reqKey, TaskID: jimengTaskID}, &jimengResp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &adapters.TaskResult{
+		TaskID:      taskID,
+		Status:      p.convertStatus(jimengResp.Data.Status),
+		RawResponse: body,
+	}
+
+	if jimengResp.Data.VideoURL != "" {
+		result.URL = jimengResp.Data.VideoURL
+		result.Format = "mp4"
+	}
+
+	if jimengResp.Data.Status == "not_found" || jimengResp.Data.Status == "expired" {
+		result.Error = &adapters.TaskError{
+			Code:    http.StatusNotFound,
+			Message: "task " + jimengResp.Data.Status,
+		}
+	}
+
+	return result, nil
+}
+
+// convertToJimengRequest converts standard request to Jimeng format
+func (p *Provider) convertToJimengRequest(req *adapters.GenerationRequest) (*JimengSubmitRequest, error) {
+	resolution, err := resolveResolution(req)
+	if err != nil {
+		return nil, err
+	}
+	spec := modelSpecs[modelName(req)]
+	keys := spec.tiers[resolution]
+
+	jimengReq := &JimengSubmitRequest{
+		ReqKey:      keys.t2v,
+		Prompt:      req.Prompt,
+		Seed:        -1,
+		AspectRatio: p.getAspectRatio(req.Width, req.Height),
+	}
+
+	if b64 := adapters.ImageBase64(req); b64 != "" {
+		jimengReq.ReqKey = keys.i2v
+		jimengReq.ImageBase64 = []string{b64}
+		jimengReq.AspectRatio = ""
+	} else if req.Image != "" {
+		jimengReq.ReqKey = keys.i2v
+		jimengReq.ImageURLs = []string{req.Image}
+		jimengReq.AspectRatio = ""
+	}
+
+	if spec.sendResolution {
+		jimengReq.Resolution = resolution
+	}
+
+	if req.Seed != nil {
+		jimengReq.Seed = *req.Seed
+	}
+
+	// 24fps：5秒为121帧，10秒为241帧
+	if req.Duration == 10.0 {
+		jimengReq.Frames = 241
+	} else {
+		jimengReq.Frames = 121
+	}
+
+	if req.Watermark != adapters.WatermarkDefault {
+		jimengReq.LogoInfo = &LogoInfo{AddLogo: req.Watermark == adapters.WatermarkVisible}
+	}
+
+	if req.Metadata != nil {
+		if reqKey, ok := req.Metadata["req_key"].(string); ok && reqKey != "" {
+			jimengReq.ReqKey = reqKey
+		}
+	}
+
+	return jimengReq, nil
+}
+
+// modelName returns the request model, defaulting to jimeng-v1
+func modelName(req *adapters.GenerationRequest) string {
+	if req.Model == "" {
+		return "jimeng-v1"
+	}
+	return req.Model
+}
+
+// resolveResolution picks the resolution tier for a request. An explicit
+// metadata.resolution must be available for the model; a tier derived from
+// QualityLevel or Width/Height falls back to the closest available lower tier.
+func resolveResolution(req *adapters.GenerationRequest) (string, error) {
+	model := modelName(req)
+	info, ok := catalog.Lookup(model)
+	if !ok {
+		return "", fmt.Errorf("unsupported model: %s", model)
+	}
+	spec := modelSpecs[model]
+
+	if resolution, ok := req.Metadata["resolution"].(string); ok && resolution != "" {
+		resolution = strings.ToLower(resolution)
+		if !info.SupportsResolution(resolution) {
+			return "", fmt.Errorf("model %s does not support %s resolution", model, resolution)
+		}
+		return resolution, nil
+	}
+
+	// 按质量等级或短边推断档位，不可用时降到最接近的较低档位
+	wanted := 0
+	switch req.QualityLevel {
+	case adapters.QualityLevelLow:
+		return lowestTier(spec), nil
+	case adapters.QualityLevelStandard:
+		wanted = 1
+	case adapters.QualityLevelHigh:
+		wanted = 2
+	default:
+		shortSide := req.Width
+		if req.Height < shortSide {
+			shortSide = req.Height
+		}
+		switch {
+		case shortSide > 720:
+			wanted = 2
+		case shortSide > 480:
+			wanted = 1
+		}
+	}
+	for i := wanted; i >= 0; i-- {
+		if _, ok := spec.tiers[resolutionTiers[i]]; ok {
+			return resolutionTiers[i], nil
+		}
+	}
+	return lowestTier(spec), nil
+}
+
+// lowestTier returns the lowest resolution tier available for a model
+func lowestTier(spec modelSpec) string {
+	for _, tier := range resolutionTiers {
+		if _, ok := spec.tiers[tier]; ok {
+			return tier
+		}
+	}
+	return ""
+}
+
+// getAspectRatio determines aspect ratio from width and height
+func (p *Provider) getAspectRatio(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return "16:9"
+	}
+	ratio := float64(width) / float64(height)
+
+	switch {
+	case ratio > 2.0:
+		return "21:9"
+	case ratio > 1.5:
+		return "16:9"
+	case ratio > 1.1:
+		return "4:3"
+	case ratio < 0.6:
+		return "9:16"
+	case ratio < 0.9:
+		return "3:4"
+	default:
+		return "1:1"
+	}
+}
+
+// convertStatus converts Jimeng status to standard status
+func (p *Provider) convertStatus(status string) adapters.TaskStatus {
+	switch status {
+	case "in_queue":
+		return adapters.TaskStatusQueued
+	case "generating":
+		return adapters.TaskStatusProcessing
+	case "done":
+		return adapters.TaskStatusSucceeded
+	case "not_found", "expired":
+		return adapters.TaskStatusFailed
+	default:
+		return adapters.TaskStatusQueued
+	}
+}
+
+// call invokes a Volcengine visual API action, returning the raw response body
+func (p *Provider) call(ctx context.Context, action string, body interface{}, out *JimengResponse) ([]byte, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/?Action=%s&Version=%s", p.baseURL, action, apiVersion)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
+	signRequest(req, jsonBody, p.accessKey, p.secretKey, time.Now())
+
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, adapters.NewAPIError(resp.StatusCode, "", "")
+		}
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if out.Code != codeSuccess {
+		status := resp.StatusCode
+		if status < 400 {
+			status = statusForCode(out.Code)
+		}
+		return nil, adapters.NewAPIError(status, strconv.Itoa(out.Code), out.Message)
+	}
+
+	return respBody, nil
 }
@@ -0,0 +1,32 @@
+package jimeng
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSignRequestKnownAnswer(t *testing.T) {
+	body := []byte(`{"req_key":"jimeng_vgfm_t2v_l20","prompt":"A cat"}`)
+	req, err := http.NewRequest("POST", "https://visual.volcengineapi.com/?Action=CVSync2AsyncSubmitTask&Version=2022-08-31", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	signRequest(req, body, "test_access_key", "test_secret_key", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if got := req.Header.Get("X-Date"); got != "20250101T000000Z" {
+		t.Errorf("Expected X-Date 20250101T000000Z, got %s", got)
+	}
+	if got := req.Header.Get("X-Content-Sha256"); got != "5ee57bdcc93f554ebddea79ae73bd22edda3a10d4088b7f54928a5db5959294b" {
+		t.Errorf("Unexpected X-Content-Sha256: %s", got)
+	}
+	expected := "HMAC-SHA256 Credential=test_access_key/20250101/cn-north-1/cv/request, " +
+		"SignedHeaders=content-type;host;x-content-sha256;x-date, " +
+		"Signature=91cc4d147a68371d16ef8650f8f86106d50d2c7cdfc4420d3dc98759d3c97f7e"
+	if got := req.Header.Get("Authorization"); got != expected {
+		t.Errorf("Expected Authorization %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,43 @@
+package adapters
+
+import "fmt"
+
+// Keyframe is an image the video shows at a point in time
+type Keyframe struct {
+	Image     string  `json:"image"`      // URL, Base64 or data URI
+	AtSeconds float64 `json:"at_seconds"` // Offset from the start of the video
+}
+
+// ValidateKeyframes rejects more than maxKeyframes keyframes; providers without
+// keyframe support pass 0
+func ValidateKeyframes(provider string, keyframes []Keyframe, maxKeyframes int) error {
+	switch {
+	case len(keyframes) == 0:
+		return nil
+	case maxKeyframes == 0:
+		return fmt.Errorf("%s does not support keyframes", provider)
+	case len(keyframes) > maxKeyframes:
+		return fmt.Errorf("%s supports at most %d keyframes, got %d", provider, maxKeyframes, len(keyframes))
+	}
+	return nil
+}
+
+// EdgeKeyframes maps keyframes onto the start and end frame for providers that
+// only condition on those two. Keyframes must sit at 0s or at the request
+// duration; either frame may be left empty.
+func EdgeKeyframes(provider string, req *GenerationRequest) (first, last string, err error) {
+	if err := ValidateKeyframes(provider, req.Keyframes, 2); err != nil {
+		return "", "", err
+	}
+	for _, keyframe := range req.Keyframes {
+		switch {
+		case keyframe.AtSeconds == 0 && first == "":
+			first = keyframe.Image
+		case keyframe.AtSeconds >= req.Duration && last == "":
+			last = keyframe.Image
+		default:
+			return "", "", fmt.Errorf("%s only supports keyframes at 0s and %gs, got %gs", provider, req.Duration, keyframe.AtSeconds)
+		}
+	}
+	return first, last, nil
+}
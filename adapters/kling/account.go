@@ -0,0 +1,93 @@
+package kling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// accountCostsPath is the Kling resource pack endpoint
+const accountCostsPath = "/account/costs"
+
+// quotaWindow is how far back resource packs are listed
+const quotaWindow = 365 * 24 * time.Hour
+
+// KlingAccountResponse represents Kling's resource pack response
+type KlingAccountResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Code      int    `json:"code"`
+		Msg       string `json:"msg"`
+		PackInfos []struct {
+			ResourcePackName  string  `json:"resource_pack_name"`
+			ResourcePackID    string  `json:"resource_pack_id"`
+			ResourcePackType  string  `json:"resource_pack_type"`
+			TotalQuantity     float64 `json:"total_quantity"`
+			RemainingQuantity float64 `json:"remaining_quantity"`
+			EffectiveTime     int64   `json:"effective_time"`
+			InvalidTime       int64   `json:"invalid_time"`
+			Status            string  `json:"status"`
+		} `json:"resource_pack_subscribe_infos"`
+	} `json:"data"`
+}
+
+// GetQuota lists the account's resource packs of the past year
+func (p *Provider) GetQuota(ctx context.Context) (*adapters.Quota, error) {
+	baseURL, token, err := p.resolveAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	url := fmt.Sprintf("%s%s?start_time=%d&end_time=%d", baseURL, accountCostsPath, now.Add(-quotaWindow).UnixMilli(), now.UnixMilli())
+	resp, err := p.makeRequest(ctx, "GET", url, token, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var klingResp KlingAccountResponse
+	if err := json.Unmarshal(body, &klingResp); err != nil {
+		return nil, decodeError(resp.StatusCode, err)
+	}
+	if klingResp.Code != 0 {
+		return nil, apiError(resp.StatusCode, klingResp.Code, klingResp.Message)
+	}
+	if klingResp.Data.Code != 0 {
+		return nil, apiError(resp.StatusCode, klingResp.Data.Code, klingResp.Data.Msg)
+	}
+
+	quota := &adapters.Quota{Packs: []adapters.ResourcePack{}}
+	json.Unmarshal(body, &quota.RawResponse)
+	for _, info := range klingResp.Data.PackInfos {
+		pack := adapters.ResourcePack{
+			ID:        info.ResourcePackID,
+			Name:      info.ResourcePackName,
+			Type:      info.ResourcePackType,
+			Total:     info.TotalQuantity,
+			Remaining: info.RemainingQuantity,
+			Status:    info.Status,
+		}
+		if info.EffectiveTime > 0 {
+			pack.EffectiveAt = time.UnixMilli(info.EffectiveTime)
+		}
+		if info.InvalidTime > 0 {
+			pack.ExpiresAt = time.UnixMilli(info.InvalidTime)
+		}
+		if pack.Status == "online" {
+			quota.Remaining += pack.Remaining
+		}
+		quota.Packs = append(quota.Packs, pack)
+	}
+	return quota, nil
+}
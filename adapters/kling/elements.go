@@ -0,0 +1,217 @@
+package kling
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/models"
+)
+
+// MaxElementImages is the maximum number of subject images accepted by the elements API
+const MaxElementImages = 4
+
+// maxElementImageBytes is the maximum decoded size of a Base64 subject image (10MB)
+const maxElementImageBytes = 10 * 1024 * 1024
+
+// ElementsRequest represents a multi-image-to-video ("elements") request.
+// Up to MaxElementImages subject images are combined into one video.
+type ElementsRequest struct {
+	Images         []string
+	Prompt         string
+	NegativePrompt string
+	Model          string
+	Mode           string
+	Duration       float64
+	AspectRatio    string
+	CallbackURL    string
+	ClientTaskID   string
+}
+
+// KlingElementsRequest represents Kling's multi-image2video request format
+type KlingElementsRequest struct {
+	ModelName      string             `json:"model_name,omitempty"`
+	ImageList      []KlingElementItem `json:"image_list"`
+	Prompt         string             `json:"prompt,omitempty"`
+	NegativePrompt string             `json:"negative_prompt,omitempty"`
+	Mode           string             `json:"mode,omitempty"`
+	Duration       string             `json:"duration,omitempty"`
+	AspectRatio    string             `json:"aspect_ratio,omitempty"`
+	CallbackURL    string             `json:"callback_url,omitempty"`
+	ExternalTaskID string             `json:"external_task_id,omitempty"`
+}
+
+// KlingElementItem represents a single subject image
+type KlingElementItem struct {
+	Image string `json:"image"`
+}
+
+// CreateElementsGeneration creates a multi-image-to-video task
+func (p *Provider) CreateElementsGeneration(ctx context.Context, req *ElementsRequest) (*adapters.GenerationResponse, error) {
+	if err := validateElements(req); err != nil {
+		return nil, err
+	}
+
+	baseURL, token, err := p.resolveAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	klingReq := convertToKlingElementsRequest(req)
+
+	url := baseURL + p.endpoints[taskTypeMultiImage2Video]
+	resp, err := p.makeRequest(ctx, "POST", url, token, adapters.TransformRequest("kling", klingReq))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var klingResp KlingGenerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&klingResp); err != nil {
+		return nil, decodeError(resp.StatusCode, err)
+	}
+
+	if klingResp.Code != 0 {
+		return nil, apiError(resp.StatusCode, klingResp.Code, klingResp.Message)
+	}
+
+	p.tasks.setTaskType(klingResp.Data.TaskID, taskTypeMultiImage2Video)
+	if req.ClientTaskID != "" {
+		p.tasks.setClientTask(req.ClientTaskID, klingResp.Data.TaskID)
+	}
+
+	return &adapters.GenerationResponse{
+		TaskID: klingResp.Data.TaskID,
+		Status: adapters.TaskStatusQueued,
+	}, nil
+}
+
+// GetElementsGeneration retrieves the status of a multi-image-to-video task
+func (p *Provider) GetElementsGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	p.tasks.setTaskType(taskID, taskTypeMultiImage2Video)
+	return p.GetGeneration(ctx, taskID)
+}
+
+// validateElements validates image count, image size and model/mode gating
+func validateElements(req *ElementsRequest) error {
+	if len(req.Images) == 0 {
+		return fmt.Errorf("elements request requires at least one image")
+	}
+	if err := adapters.ValidateImages("Kling", req.Images, MaxElementImages); err != nil {
+		return err
+	}
+	if err := adapters.ValidateNegativePrompt("Kling", req.NegativePrompt, MaxNegativePromptLength); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Kling", req.CallbackURL, true); err != nil {
+		return err
+	}
+
+	for i, image := range req.Images {
+		if image == "" {
+			return fmt.Errorf("elements image %d is empty", i)
+		}
+		if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+			continue
+		}
+		if base64.StdEncoding.DecodedLen(len(image)) > maxElementImageBytes {
+			return fmt.Errorf("elements image %d exceeds 10MB", i)
+		}
+	}
+
+	model := req.Model
+	if model == "" {
+		model = "kling-v1-6"
+	}
+	info, ok := catalog.Lookup(model)
+	if !ok || !info.Supports(models.FeatureElements) {
+		return fmt.Errorf("model %s does not support elements", model)
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = "std"
+	}
+	if !info.SupportsMode(mode) {
+		return fmt.Errorf("model %s does not support elements in %s mode", model, mode)
+	}
+	return nil
+}
+
+// convertToKlingElementsRequest converts an elements request to Kling format
+func convertToKlingElementsRequest(req *ElementsRequest) *KlingElementsRequest {
+	klingReq := &KlingElementsRequest{
+		ModelName:      req.Model,
+		Prompt:         req.Prompt,
+		NegativePrompt: req.NegativePrompt,
+		Mode:           req.Mode,
+		AspectRatio:    req.AspectRatio,
+		CallbackURL:    req.CallbackURL,
+		ExternalTaskID: req.ClientTaskID,
+	}
+
+	if klingReq.ModelName == "" {
+		klingReq.ModelName = "kling-v1-6"
+	}
+	if klingReq.Mode == "" {
+		klingReq.Mode = "std"
+	}
+	if req.Duration == 10.0 {
+		klingReq.Duration = "10"
+	} else {
+		klingReq.Duration = "5"
+	}
+
+	for _, image := range req.Images {
+		klingReq.ImageList = append(klingReq.ImageList, KlingElementItem{Image: base64Image(image)})
+	}
+
+	return klingReq
+}
+
+// elementImages returns the request's reference images, falling back to
+// metadata["image_list"], or nil if absent
+func elementImages(req *adapters.GenerationRequest) []string {
+	if len(req.Images) > 0 {
+		return req.Images
+	}
+	if req.Metadata == nil {
+		return nil
+	}
+
+	switch list := req.Metadata["image_list"].(type) {
+	case []string:
+		return list
+	case []interface{}:
+		images := make([]string, 0, len(list))
+		for _, item := range list {
+			if image, ok := item.(string); ok {
+				images = append(images, image)
+			}
+		}
+		return images
+	default:
+		return nil
+	}
+}
+
+// toElementsRequest builds an elements request from a generic request and its subject images
+func (p *Provider) toElementsRequest(req *adapters.GenerationRequest, images []string) *ElementsRequest {
+	elementsReq := &ElementsRequest{
+		Images:       images,
+		Prompt:       req.Prompt,
+		Model:        req.Model,
+		Mode:         adapters.ResolveMode(req),
+		Duration:     req.Duration,
+		AspectRatio:  p.getAspectRatio(req.Width, req.Height),
+		CallbackURL:  req.CallbackURL,
+		ClientTaskID: req.ClientTaskID,
+	}
+
+	elementsReq.NegativePrompt = adapters.NegativePrompt(req)
+
+	return elementsReq
+}
@@ -0,0 +1,64 @@
+package kling
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Kling business error codes that change how an error is handled
+const (
+	codeAccountArrears   = 1101 // Account in arrears
+	codeResourceDepleted = 1102 // Resource pack depleted or expired
+)
+
+// apiError builds the error of a Kling response with a non-zero code. Kling
+// reports depleted balances as 429, which are not worth retrying, so they are
+// mapped to 402.
+func apiError(statusCode, code int, message string) error {
+	switch {
+	case code == codeAccountArrears || code == codeResourceDepleted:
+		statusCode = http.StatusPaymentRequired
+	case statusCode < 400:
+		statusCode = statusForCode(code)
+	}
+	var codeText string
+	if code != 0 {
+		codeText = strconv.Itoa(code)
+	}
+	return adapters.NewAPIError(statusCode, codeText, message)
+}
+
+// statusForCode maps a Kling error code to the HTTP status Kling documents for it
+func statusForCode(code int) int {
+	switch {
+	case code >= 1000 && code < 1100:
+		return http.StatusUnauthorized
+	case code == 1103:
+		return http.StatusForbidden
+	case code >= 1100 && code < 1200:
+		return http.StatusTooManyRequests
+	case code == codeResourceNotFound:
+		return http.StatusNotFound
+	case code >= 1200 && code < 1302:
+		return http.StatusBadRequest
+	case code >= 1302 && code < 1400:
+		return http.StatusTooManyRequests
+	case code == 5001:
+		return http.StatusServiceUnavailable
+	case code == 5002:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// decodeError reports an undecodable response: an HTTP error status, e.g. an
+// HTML page from a gateway, becomes an APIError
+func decodeError(statusCode int, err error) error {
+	if statusCode >= 400 {
+		return apiError(statusCode, 0, "")
+	}
+	return fmt.Errorf("failed to decode response: %w", err)
+}
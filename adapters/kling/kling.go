@@ -3,7 +3,9 @@ package kling
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 	"time"
 
 	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/models"
 	"github.com/golang-jwt/jwt"
 )
 
@@ -22,27 +25,68 @@ type Provider struct {
 	baseURL   string
 	accessKey string
 	secretKey string
+
+	// endpoints holds the resolved endpoint path per task type
+	endpoints map[string]string
+
+	// tasks remembers the task type and ClientTaskID of submitted tasks
+	tasks *taskState
+
+	// peer holds feature hints from a vidgo-based relay at baseURL
+	peer adapters.PeerFeatures
+
+	// tokens caches signed JWTs per key pair
+	tokens *tokenCache
 }
 
-// KlingGenerationRequest represents Kling-specific request format
+// Kling API versions accepted in ProviderConfig.APIVersion
+const (
+	// APIVersionLegacy targets old proxies that only understand the "model" field
+	APIVersionLegacy = "legacy"
+	// APIVersionV1 targets the current official API that uses "model_name"
+	APIVersionV1 = "v1"
+)
+
+// Kling task types, used as the {task_type} endpoint placeholder
+const (
+	taskTypeText2Video       = "text2video"
+	taskTypeImage2Video      = "image2video"
+	taskTypeMultiImage2Video = "multi-image2video"
+	taskTypeVideoExtend      = "video-extend"
+	taskTypeLipSync          = "lip-sync"
+	taskTypeEffects          = "effects"
+)
+
+// MaxNegativePromptLength is the longest negative prompt Kling accepts, in characters
+const MaxNegativePromptLength = 2500
+
+// DefaultCfgScale is the cfg_scale sent when GenerationRequest.GuidanceScale is nil
+const DefaultCfgScale = 0.5
+
+// defaultEndpointTemplate is the official Kling endpoint path layout
+const defaultEndpointTemplate = "/{version}/videos/{task_type}"
+
+var taskTypes = []string{taskTypeText2Video, taskTypeImage2Video, taskTypeMultiImage2Video, taskTypeVideoExtend, taskTypeLipSync, taskTypeEffects}
+
+// KlingGenerationRequest represents Kling-specific request format, shared by the
+// text2video and image2video endpoints
 type KlingGenerationRequest struct {
-	Prompt       string  `json:"prompt,omitempty"`
-	Image        string  `json:"image,omitempty"`
-	Mode         string  `json:"mode,omitempty"`
-	Duration     string  `json:"duration,omitempty"`
-	AspectRatio  string  `json:"aspect_ratio,omitempty"`
-	CameraMoving *string `json:"camera_moving,omitempty"`
-	Model        string  `json:"model,omitempty"`
-	ModelName    string  `json:"model_name,omitempty"`
-	CfgScale     float64 `json:"cfg_scale,omitempty"`
-	StaticMask   string  `json:"static_mask,omitempty"`
-	DynamicMasks []struct {
-		Mask         string `json:"mask"`
-		Trajectories []struct {
-			X int `json:"x"`
-			Y int `json:"y"`
-		} `json:"trajectories"`
-	} `json:"dynamic_masks,omitempty"`
+	Prompt         string                  `json:"prompt,omitempty"`
+	NegativePrompt string                  `json:"negative_prompt,omitempty"`
+	Image          string                  `json:"image,omitempty"`
+	ImageTail      string                  `json:"image_tail,omitempty"`
+	Mode           string                  `json:"mode,omitempty"`
+	Duration       string                  `json:"duration,omitempty"`
+	AspectRatio    string                  `json:"aspect_ratio,omitempty"`
+	CameraMoving   *string                 `json:"camera_moving,omitempty"`
+	CameraControl  *adapters.CameraControl `json:"camera_control,omitempty"`
+	Model          string                  `json:"model,omitempty"`
+	ModelName      string                  `json:"model_name,omitempty"`
+	CfgScale       float64                 `json:"cfg_scale"`
+	StaticMask     string                  `json:"static_mask,omitempty"`
+	DynamicMasks   []adapters.DynamicMask  `json:"dynamic_masks,omitempty"`
+	CallbackURL    string                  `json:"callback_url,omitempty"`
+	ExternalTaskID string                  `json:"external_task_id,omitempty"`
 }
 
 // KlingGenerationResponse represents Kling's response format
@@ -63,13 +107,27 @@ type KlingTaskResponse struct {
 	Data    KlingTaskResult `json:"data"`
 }
 
+// KlingTaskResult is the task object returned by status queries and posted to
+// callback_url. The official API names the ID and status task_id and task_status.
 type KlingTaskResult struct {
 	ID         string               `json:"id"`
 	Status     string               `json:"status"`
+	TaskID     string               `json:"task_id,omitempty"`
+	TaskStatus string               `json:"task_status,omitempty"`
+	StatusMsg  string               `json:"task_status_msg,omitempty"` // Failure reason
 	CreatedAt  int64                `json:"created_at"`
 	UpdatedAt  int64                `json:"updated_at"`
 	Task       KlingTaskDetails     `json:"task"`
+	TaskInfo   *KlingTaskInfo       `json:"task_info,omitempty"`
 	TaskResult *KlingTaskResultData `json:"task_result,omitempty"`
+
+	// FinalUnitDeduction is the number of account units the task consumed
+	FinalUnitDeduction string `json:"final_unit_deduction,omitempty"`
+}
+
+// KlingTaskInfo holds the task parameters echoed back by Kling
+type KlingTaskInfo struct {
+	ExternalTaskID string `json:"external_task_id,omitempty"`
 }
 
 type KlingTaskDetails struct {
@@ -83,15 +141,36 @@ type KlingTaskResultData struct {
 }
 
 type KlingVideo struct {
-	ID       string `json:"id"`
-	URL      string `json:"url"`
-	Duration string `json:"duration"`
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	WatermarkURL string `json:"watermark_url,omitempty"`
+	Duration     string `json:"duration"`
+}
+
+// catalog describes the Kling models, see package models
+var catalog = models.List("kling")
+
+var supportedModels = catalog.Names()
+
+// defaultModel is submitted when the request names no model
+const defaultModel = "kling-v2-master"
+
+// matrix lists the valid Kling combinations; the aspect ratios are those
+// getAspectRatio produces
+var matrix = adapters.CatalogMatrix(catalog)
+
+// extraSchema lists the Extra keys Kling understands
+var extraSchema = adapters.ExtraSchema{
+	{Name: "token_refresh_margin", Description: "How long before expiry a cached JWT is re-signed, default 5m", Validate: adapters.ValidateDuration},
+}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
 }
 
-var supportedModels = []string{
-	"kling-v1",
-	"kling-v1-6",
-	"kling-v2-master",
+func init() {
+	adapters.Register("kling", New)
 }
 
 // New creates a new Kling provider instance
@@ -100,6 +179,10 @@ func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
 		return nil, fmt.Errorf("invalid configuration")
 	}
 
+	if err := extraSchema.Validate("Kling", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
 	keyParts := strings.Split(config.APIKey, ",")
 	if len(keyParts) != 2 {
 		return nil, fmt.Errorf("invalid API key format for Kling, expected 'access_key,secret_key'")
@@ -115,12 +198,31 @@ func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
 		timeout = 30 * time.Second
 	}
 
+	switch config.APIVersion {
+	case "", APIVersionLegacy, APIVersionV1:
+	default:
+		return nil, fmt.Errorf("unsupported Kling API version: %s", config.APIVersion)
+	}
+
+	endpoints, err := adapters.ResolveEndpoints(defaultEndpointTemplate, "v1", taskTypes, config.Endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kling endpoints: %w", err)
+	}
+
+	margin := defaultTokenRefreshMargin
+	if value := config.Extra["token_refresh_margin"]; value != "" {
+		margin, _ = time.ParseDuration(value)
+	}
+
 	return &Provider{
 		config:    config,
 		client:    &http.Client{Timeout: timeout},
 		baseURL:   baseURL,
 		accessKey: strings.TrimSpace(keyParts[0]),
 		secretKey: strings.TrimSpace(keyParts[1]),
+		endpoints: endpoints,
+		tasks:     &taskState{},
+		tokens:    &tokenCache{margin: margin},
 	}, nil
 }
 
@@ -129,44 +231,198 @@ func (p *Provider) Name() string {
 	return "Kling"
 }
 
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
 // SupportedModels returns supported models
 func (p *Provider) SupportedModels() []string {
 	return append([]string{}, supportedModels...)
 }
 
+// Capabilities returns the Kling input constraints
+func (p *Provider) Capabilities() *adapters.Capabilities {
+	return &adapters.Capabilities{
+		Image: &adapters.ImageConstraints{
+			MinWidth:       300,
+			MinHeight:      300,
+			MaxBytes:       10 * 1024 * 1024,
+			MinAspectRatio: 1 / 2.5,
+			MaxAspectRatio: 2.5,
+			Formats:        []string{"jpeg", "png"},
+		},
+		Duration: &adapters.DurationConstraints{
+			Durations:     catalog.Durations(),
+			ExtendSeconds: 4.5,
+			MaxExtended:   180,
+		},
+		Combinations: append(adapters.CapabilityMatrix{}, matrix...),
+	}
+}
+
+// Warm opens a connection to the Kling API. JWTs are signed locally, so there is no token to fetch.
+func (p *Provider) Warm(ctx context.Context) error {
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
+}
+
 // ValidateRequest validates the request for Kling
 func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
-	if req.Model != "" {
-		found := false
-		for _, model := range supportedModels {
-			if model == req.Model {
-				found = true
-				break
-			}
+	model := req.Model
+	if model == "" {
+		model = defaultModel
+	}
+	info, ok := catalog.Lookup(model)
+	if !ok {
+		return fmt.Errorf("unsupported model: %s", req.Model)
+	}
+
+	if err := adapters.ValidateNegativePrompt("Kling", adapters.NegativePrompt(req), MaxNegativePromptLength); err != nil {
+		return err
+	}
+
+	mode := adapters.ResolveMode(req)
+	if !adapters.ValidMode(mode) {
+		return fmt.Errorf("Kling mode must be std or pro, got %s", mode)
+	}
+	if mode == "" {
+		mode = adapters.ModeStandard
+	}
+	if !info.SupportsDuration(mode, req.Duration) {
+		return fmt.Errorf("Kling %s only supports %s duration in %s mode", model, models.FormatDurations(info.DurationsFor(mode)), mode)
+	}
+
+	if !adapters.ValidGuidanceScale(req.GuidanceScale) {
+		return fmt.Errorf("Kling cfg_scale must be between 0 and 1, got %g", *req.GuidanceScale)
+	}
+
+	if err := adapters.ValidateCallbackURL("Kling", req.CallbackURL, true); err != nil {
+		return err
+	}
+
+	// API生成的视频本身不带水印，不支持添加
+	if err := adapters.ValidateWatermark("Kling", req.Watermark, adapters.WatermarkNone); err != nil {
+		return err
+	}
+
+	if images := elementImages(req); images != nil {
+		return validateElements(p.toElementsRequest(req, images))
+	}
+
+	first, last, err := adapters.EdgeKeyframes("Kling", req)
+	if err != nil {
+		return err
+	}
+
+	if control := cameraControl(req); control != nil {
+		if !info.Supports(models.FeatureCameraControl) {
+			return fmt.Errorf("Kling %s does not support camera control", model)
 		}
-		if !found {
-			return fmt.Errorf("unsupported model: %s", req.Model)
+		if err := control.Validate(); err != nil {
+			return err
 		}
 	}
 
-	if req.Duration != 5.0 && req.Duration != 10.0 {
-		return fmt.Errorf("Kling only supports 5s or 10s duration")
+	// 尾帧、运动笔刷和镜头控制三选一
+	if (req.ImageTail != "" || last != "") && (req.MotionBrush != nil || cameraControl(req) != nil) {
+		return fmt.Errorf("Kling image_tail cannot be combined with motion brush or camera control")
+	}
+
+	if req.MotionBrush != nil {
+		if !info.Supports(models.FeatureMotionBrush) {
+			return fmt.Errorf("Kling %s does not support motion brush", model)
+		}
+		if req.Image == "" && len(req.ImageBytes) == 0 && first == "" {
+			return fmt.Errorf("Kling motion brush requires an input image")
+		}
+		if err := validateMotionBrush(req.MotionBrush); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// Motion brush limits of the Kling image2video API
+const (
+	maxDynamicMasks     = 6
+	minTrajectoryPoints = 2
+	maxTrajectoryPoints = 77
+)
+
+// validateMotionBrush checks that masks are set and trajectory counts are within limits
+func validateMotionBrush(brush *adapters.MotionBrush) error {
+	if brush.StaticMask == "" && len(brush.DynamicMasks) == 0 {
+		return fmt.Errorf("motion brush requires a static mask or dynamic masks")
+	}
+	if len(brush.DynamicMasks) > maxDynamicMasks {
+		return fmt.Errorf("Kling supports at most %d dynamic masks, got %d", maxDynamicMasks, len(brush.DynamicMasks))
+	}
+	for i, mask := range brush.DynamicMasks {
+		if mask.Mask == "" {
+			return fmt.Errorf("dynamic mask %d is empty", i)
+		}
+		if n := len(mask.Trajectories); n < minTrajectoryPoints || n > maxTrajectoryPoints {
+			return fmt.Errorf("dynamic mask %d needs %d to %d trajectory points, got %d", i, minTrajectoryPoints, maxTrajectoryPoints, n)
+		}
+	}
+	return nil
+}
+
+// base64Image strips the data URI prefix from an image or mask, since Kling
+// accepts URLs or plain Base64
+func base64Image(image string) string {
+	if data, _, ok := adapters.DecodeDataURI(image); ok {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	return image
+}
+
+// cameraControl returns the typed camera control, falling back to a raw
+// metadata["camera_control"] map
+func cameraControl(req *adapters.GenerationRequest) *adapters.CameraControl {
+	if req.CameraControl != nil {
+		return req.CameraControl
+	}
+	raw, ok := req.Metadata["camera_control"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var control adapters.CameraControl
+	if err := json.Unmarshal(data, &control); err != nil {
+		return nil
+	}
+	return &control
+}
+
 // CreateGeneration creates a video generation task
 func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
+	if images := elementImages(req); images != nil {
+		return p.CreateElementsGeneration(ctx, p.toElementsRequest(req, images))
+	}
+
 	klingReq := p.convertToKlingRequest(req)
 
-	token, err := p.createJWTToken()
+	baseURL, token, err := p.resolveAuth(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JWT token: %w", err)
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/v1/videos/image2video", p.baseURL)
-	resp, err := p.makeRequest(ctx, "POST", url, token, klingReq)
+	// 没有首帧和尾帧时走文生视频接口
+	taskType := taskTypeImage2Video
+	if klingReq.Image == "" && klingReq.ImageTail == "" {
+		taskType = taskTypeText2Video
+	}
+
+	url := baseURL + p.endpoints[taskType]
+	resp, err := p.makeRequest(ctx, "POST", url, token, adapters.TransformRequest("kling", klingReq))
 	if err != nil {
 		return nil, err
 	}
@@ -174,11 +430,16 @@ func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.Generatio
 
 	var klingResp KlingGenerationResponse
 	if err := json.NewDecoder(resp.Body).Decode(&klingResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, decodeError(resp.StatusCode, err)
 	}
 
 	if klingResp.Code != 0 {
-		return nil, fmt.Errorf("API error %d: %s", klingResp.Code, klingResp.Message)
+		return nil, apiError(resp.StatusCode, klingResp.Code, klingResp.Message)
+	}
+
+	p.tasks.setTaskType(klingResp.Data.TaskID, taskType)
+	if req.ClientTaskID != "" {
+		p.tasks.setClientTask(req.ClientTaskID, klingResp.Data.TaskID)
 	}
 
 	return &adapters.GenerationResponse{
@@ -187,32 +448,101 @@ func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.Generatio
 	}, nil
 }
 
-// GetGeneration retrieves the task status
+// GetGeneration retrieves the task status. Tasks submitted by another process are
+// looked up as image2video first, then as text2video.
 func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
-	token, err := p.createJWTToken()
+	if taskType, ok := p.tasks.taskType(taskID); ok {
+		return p.fetchTask(ctx, taskType, taskID)
+	}
+
+	result, err := p.fetchTask(ctx, taskTypeImage2Video, taskID)
+	var notFound *adapters.TaskNotFoundError
+	if !errors.As(err, &notFound) {
+		return result, err
+	}
+
+	result, err = p.fetchTask(ctx, taskTypeText2Video, taskID)
+	if err == nil {
+		p.tasks.setTaskType(taskID, taskTypeText2Video)
+	}
+	return result, err
+}
+
+// GetGenerationByClientID retrieves a task by the external_task_id it was submitted
+// with. Kling accepts it in place of the task ID on the status path; tasks
+// submitted by another process are looked up on every generation endpoint.
+func (p *Provider) GetGenerationByClientID(ctx context.Context, clientTaskID string) (*adapters.TaskResult, error) {
+	if taskID, ok := p.tasks.taskID(clientTaskID); ok {
+		return p.GetGeneration(ctx, taskID)
+	}
+
+	var err error
+	for _, taskType := range []string{taskTypeImage2Video, taskTypeText2Video, taskTypeMultiImage2Video} {
+		var result *adapters.TaskResult
+		result, err = p.fetchTask(ctx, taskType, clientTaskID)
+		var notFound *adapters.TaskNotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.tasks.setTaskType(result.TaskID, taskType)
+		p.tasks.setClientTask(clientTaskID, result.TaskID)
+		return result, nil
+	}
+	return nil, err
+}
+
+// fetchTask queries a task on the status path of its task type
+func (p *Provider) fetchTask(ctx context.Context, taskType, taskID string) (*adapters.TaskResult, error) {
+	baseURL, token, err := p.resolveAuth(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JWT token: %w", err)
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/v1/videos/image2video/%s", p.baseURL, taskID)
+	url := fmt.Sprintf("%s%s/%s", baseURL, p.endpoints[taskType], taskID)
 	resp, err := p.makeRequest(ctx, "GET", url, token, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
 	var klingResp KlingTaskResponse
-	if err := json.NewDecoder(resp.Body).Decode(&klingResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(body, &klingResp); err != nil && resp.StatusCode != http.StatusNotFound {
+		return nil, decodeError(resp.StatusCode, err)
+	}
+
+	// 任务记录约30天后过期，之后查询返回404或资源不存在
+	if resp.StatusCode == http.StatusNotFound || klingResp.Code == codeResourceNotFound {
+		message := klingResp.Message
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, &adapters.TaskNotFoundError{
+			TaskID:  taskID,
+			Expired: strings.Contains(strings.ToLower(message), "expire"),
+			Message: message,
+		}
 	}
 
 	if klingResp.Code != 0 {
-		return nil, fmt.Errorf("API error %d: %s", klingResp.Code, klingResp.Message)
+		return nil, apiError(resp.StatusCode, klingResp.Code, klingResp.Message)
 	}
 
-	return p.convertToTaskResult(&klingResp.Data), nil
+	result := p.convertToTaskResult(&klingResp.Data)
+	result.RawResponse = body
+	return result, nil
 }
 
+// codeResourceNotFound is Kling's error code for a task or resource that does not exist
+const codeResourceNotFound = 1203
+
 // convertToKlingRequest converts standard request to Kling format
 func (p *Provider) convertToKlingRequest(req *adapters.GenerationRequest) *KlingGenerationRequest {
 	klingReq := &KlingGenerationRequest{
@@ -222,12 +552,15 @@ func (p *Provider) convertToKlingRequest(req *adapters.GenerationRequest) *Kling
 		Model:     req.Model,
 	}
 
-	// mode取自metadata的mode，如果没取到默认为std
-	klingReq.Mode = "std" // 默认为std
-	if req.Metadata != nil {
-		if mode, ok := req.Metadata["mode"].(string); ok && mode != "" {
-			klingReq.Mode = mode
-		}
+	// 可灵接受URL或不带前缀的Base64
+	if b64 := adapters.ImageBase64(req); b64 != "" {
+		klingReq.Image = b64
+	}
+
+	// mode取自Mode（兼容metadata的mode，高画质默认为pro），否则默认为std
+	klingReq.Mode = adapters.ResolveMode(req)
+	if klingReq.Mode == "" {
+		klingReq.Mode = adapters.ModeStandard
 	}
 
 	if req.Duration == 10.0 {
@@ -239,13 +572,40 @@ func (p *Provider) convertToKlingRequest(req *adapters.GenerationRequest) *Kling
 	aspectRatio := p.getAspectRatio(req.Width, req.Height)
 	klingReq.AspectRatio = aspectRatio
 
+	klingReq.NegativePrompt = adapters.NegativePrompt(req)
+	klingReq.ImageTail = base64Image(req.ImageTail)
+	if first, last, err := adapters.EdgeKeyframes("Kling", req); err == nil && len(req.Keyframes) > 0 {
+		klingReq.Image = base64Image(first)
+		klingReq.ImageTail = base64Image(last)
+	}
+	klingReq.CameraControl = cameraControl(req)
+	klingReq.CallbackURL = req.CallbackURL
+	klingReq.ExternalTaskID = req.ClientTaskID
+
+	if brush := req.MotionBrush; brush != nil {
+		klingReq.StaticMask = base64Image(brush.StaticMask)
+		for _, mask := range brush.DynamicMasks {
+			klingReq.DynamicMasks = append(klingReq.DynamicMasks, adapters.DynamicMask{
+				Mask:         base64Image(mask.Mask),
+				Trajectories: mask.Trajectories,
+			})
+		}
+	}
+
 	if req.Model == "" {
-		klingReq.Model = "kling-v2-master"
-		klingReq.ModelName = "kling-v2-master"
+		klingReq.Model = defaultModel
+		klingReq.ModelName = defaultModel
 	}
 
-	// 设置默认的cfg_scale
-	klingReq.CfgScale = 0.5
+	klingReq.CfgScale = adapters.GuidanceScale(req, DefaultCfgScale)
+
+	// 按API版本保留对应的模型字段，未指定版本时两者都发送
+	switch p.config.APIVersion {
+	case APIVersionLegacy:
+		klingReq.ModelName = ""
+	case APIVersionV1:
+		klingReq.Model = ""
+	}
 
 	return klingReq
 }
@@ -266,12 +626,37 @@ func (p *Provider) getAspectRatio(width, height int) string {
 
 // convertToTaskResult converts Kling task result to standard format
 func (p *Provider) convertToTaskResult(data *KlingTaskResult) *adapters.TaskResult {
+	taskID, status := data.ID, data.Status
+	if taskID == "" {
+		taskID = data.TaskID
+	}
+	if status == "" {
+		status = data.TaskStatus
+	}
+
 	result := &adapters.TaskResult{
-		TaskID: data.ID,
-		Status: p.convertStatus(data.Status),
+		TaskID: taskID,
+		Status: p.convertStatus(status),
+	}
+	if data.TaskInfo != nil {
+		result.ClientTaskID = data.TaskInfo.ExternalTaskID
+	}
+	if result.Status == adapters.TaskStatusFailed {
+		result.Error = &adapters.TaskError{Code: http.StatusInternalServerError, Message: data.StatusMsg}
 	}
 
 	if data.TaskResult != nil && len(data.TaskResult.Videos) > 0 {
+		for _, video := range data.TaskResult.Videos {
+			duration, _ := strconv.ParseFloat(video.Duration, 64)
+			result.Videos = append(result.Videos, adapters.VideoOutput{
+				ID:           video.ID,
+				URL:          video.URL,
+				Duration:     duration,
+				Format:       "mp4",
+				WatermarkURL: video.WatermarkURL,
+			})
+		}
+
 		video := data.TaskResult.Videos[0]
 		result.URL = video.URL
 		result.Format = "mp4"
@@ -283,10 +668,68 @@ func (p *Provider) convertToTaskResult(data *KlingTaskResult) *adapters.TaskResu
 			}
 		}
 	}
+	result.Usage = taskUsage(data, result)
 
 	return result
 }
 
+// taskUsage reports the units a task consumed and, once it succeeded, the
+// seconds of video it was billed for
+func taskUsage(data *KlingTaskResult, result *adapters.TaskResult) *adapters.Usage {
+	var usage adapters.Usage
+	usage.Credits, _ = strconv.ParseFloat(data.FinalUnitDeduction, 64)
+	if result.Status == adapters.TaskStatusSucceeded {
+		for _, video := range result.Videos {
+			usage.BilledSeconds += video.Duration
+		}
+	}
+	if usage == (adapters.Usage{}) {
+		return nil
+	}
+	return &usage
+}
+
+// ParseCallback converts the task object Kling posts to callback_url into a task result
+func ParseCallback(body []byte) (*adapters.TaskResult, error) {
+	var data KlingTaskResult
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode callback: %w", err)
+	}
+	if data.ID == "" && data.TaskID == "" {
+		return nil, fmt.Errorf("callback has no task_id")
+	}
+
+	result := (&Provider{}).convertToTaskResult(&data)
+	result.RawResponse = body
+	return result, nil
+}
+
+// CallbackID identifies a callback delivery for deduplication. Kling does not
+// send delivery IDs, so it combines the task ID, status and update time, and
+// returns the update time as well.
+func CallbackID(body []byte) (string, time.Time, error) {
+	var data KlingTaskResult
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode callback: %w", err)
+	}
+	taskID, status := data.TaskID, data.TaskStatus
+	if taskID == "" {
+		taskID = data.ID
+	}
+	if status == "" {
+		status = data.Status
+	}
+	if taskID == "" {
+		return "", time.Time{}, fmt.Errorf("callback has no task_id")
+	}
+
+	var updatedAt time.Time
+	if data.UpdatedAt > 0 {
+		updatedAt = time.UnixMilli(data.UpdatedAt)
+	}
+	return fmt.Sprintf("%s:%s:%d", taskID, status, data.UpdatedAt), updatedAt, nil
+}
+
 // convertStatus converts Kling status to standard status
 func (p *Provider) convertStatus(status string) adapters.TaskStatus {
 	switch status {
@@ -303,17 +746,81 @@ func (p *Provider) convertStatus(status string) adapters.TaskStatus {
 	}
 }
 
+// resolveAuth returns the base URL and JWT token for a request, honoring per-request overrides
+func (p *Provider) resolveAuth(ctx context.Context) (string, string, error) {
+	baseURL := p.baseURL
+	accessKey, secretKey := p.accessKey, p.secretKey
+
+	if overrides := adapters.RequestOverridesFromContext(ctx); overrides != nil {
+		if overrides.BaseURL != "" {
+			baseURL = overrides.BaseURL
+		}
+		if overrides.APIKey != "" {
+			var err error
+			accessKey, secretKey, err = parseAPIKey(overrides.APIKey)
+			if err != nil {
+				return "", "", err
+			}
+		}
+	}
+
+	// 令牌缓存复用，临近过期前重新签名，长时间轮询不会用到过期的令牌
+	token, err := p.tokens.get(accessKey, secretKey, adapters.TokenTTL(ctx, defaultTokenTTL))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create JWT token: %w", err)
+	}
+	return baseURL, token, nil
+}
+
+// InheritTaskState shares the task state of another Kling provider, so tasks
+// submitted before a configuration reload, including those the old provider is
+// still submitting, are polled on the right path. It must be called before p is used.
+func (p *Provider) InheritTaskState(from adapters.Provider) {
+	if previous, ok := from.(*Provider); ok {
+		p.tasks = previous.tasks
+	}
+}
+
+// ValidateCredentials validates a per-request API key in 'access_key,secret_key' format
+func (p *Provider) ValidateCredentials(apiKey string) error {
+	_, _, err := parseAPIKey(apiKey)
+	return err
+}
+
+// parseAPIKey splits a Kling API key into access key and secret key
+func parseAPIKey(apiKey string) (string, string, error) {
+	keyParts := strings.Split(apiKey, ",")
+	if len(keyParts) != 2 {
+		return "", "", fmt.Errorf("invalid API key format for Kling, expected 'access_key,secret_key'")
+	}
+
+	accessKey := strings.TrimSpace(keyParts[0])
+	secretKey := strings.TrimSpace(keyParts[1])
+	if accessKey == "" || secretKey == "" {
+		return "", "", fmt.Errorf("access key and secret key are required")
+	}
+	return accessKey, secretKey, nil
+}
+
 // createJWTToken creates JWT token for Kling API with proper JWT signature
 func (p *Provider) createJWTToken() (string, error) {
+	return p.tokens.get(p.accessKey, p.secretKey, defaultTokenTTL)
+}
+
+// defaultTokenTTL is the JWT lifetime when the client does not bound it
+const defaultTokenTTL = 30 * time.Minute
+
+// signJWTToken signs a Kling JWT token with the given access and secret keys
+func signJWTToken(accessKey, secretKey string, ttl time.Duration) (string, error) {
 	now := time.Now().Unix()
 	claims := jwt.MapClaims{
-		"iss": p.accessKey,
-		"exp": now + 1800, // 30分钟
-		"nbf": now - 5,    // 提前5秒生效
+		"iss": accessKey,
+		"exp": now + int64(ttl/time.Second),
+		"nbf": now - 5, // 提前5秒生效
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token.Header["typ"] = "JWT"
-	tokenString, err := token.SignedString([]byte(p.secretKey))
+	tokenString, err := token.SignedString([]byte(secretKey))
 	if err != nil {
 		return "", err
 	}
@@ -339,11 +846,20 @@ func (p *Provider) makeRequest(ctx context.Context, method, url, token string, b
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
 
-	resp, err := p.client.Do(req)
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
+	p.peer.Observe(resp.Header)
 
 	return resp, nil
 }
+
+// PeerFeatures returns the feature hints sent by a vidgo-based relay, or nil
+// when the server is Kling itself
+func (p *Provider) PeerFeatures() adapters.Features {
+	return p.peer.Features()
+}
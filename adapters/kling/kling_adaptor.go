@@ -15,39 +15,23 @@ import (
 )
 
 // TaskAdaptorError represents an error in task processing
-type TaskAdaptorError struct {
-	StatusCode int    `json:"status_code"`
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	LocalError bool   `json:"local_error"`
-}
-
-func (e *TaskAdaptorError) Error() string {
-	return e.Message
-}
+//
+// Deprecated: use vidgo.TaskAdaptorError. Run cmd/vidgo-migrate to rewrite usages.
+type TaskAdaptorError = adapters.TaskAdaptorError
 
 // TaskRelayInfo contains information needed for task relay
-type TaskRelayInfo struct {
-	ChannelType int
-	BaseUrl     string
-	ApiKey      string
-	Action      string
-}
+//
+// Deprecated: use vidgo.TaskRelayInfo. Run cmd/vidgo-migrate to rewrite usages.
+type TaskRelayInfo = adapters.TaskRelayInfo
 
 // VidgoSubmitReq represents a video generation request
-// For Kling: metadata.image or metadata.image_tail is required (cannot both be empty)
-// Optional metadata fields: image_tail, camera_moving
-type VidgoSubmitReq struct {
-	Prompt   string                 `json:"prompt"`             // Required: 文本描述
-	Model    string                 `json:"model,omitempty"`    // Optional: 模型名称
-	Mode     string                 `json:"mode,omitempty"`     // Optional: 模式 "std" or "pro", defaults to "std"
-	Image    string                 `json:"image,omitempty"`    // Optional: 图像URL，用于图生视频
-	Size     string                 `json:"size,omitempty"`     // Optional: 画面尺寸，用于推断aspect_ratio
-	Duration int                    `json:"duration,omitempty"` // Optional: 视频时长（秒），5或10，默认5
-	Metadata map[string]interface{} `json:"metadata,omitempty"` // Optional: 额外的元数据
-}
+//
+// Deprecated: use vidgo.VidgoSubmitReq. Run cmd/vidgo-migrate to rewrite usages.
+type VidgoSubmitReq = adapters.VidgoSubmitReq
 
 // TaskResponse represents a generic task response
+//
+// Deprecated: use vidgo.TaskResponse. Run cmd/vidgo-migrate to rewrite usages.
 type TaskResponse[T any] struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
@@ -59,12 +43,24 @@ func (t *TaskResponse[T]) IsSuccess() bool {
 }
 
 // KlingAdaptor implements TaskAdaptorInterface for Kling video generation
+//
+// Deprecated: use vidgo.TaskAdaptor. Run cmd/vidgo-migrate to rewrite usages.
 type KlingAdaptor struct {
 	ChannelType int
 	provider    *Provider // Use the existing Provider implementation
+
+	// CompatHTTPResponse makes DoRequest and FetchTask wrap typed results in a
+	// synthesized *http.Response, for callers still driving the HTTP-shaped
+	// workflow. Without it those methods fail; use Submit and Fetch instead.
+	CompatHTTPResponse bool
 }
 
+// errHTTPShimDisabled is returned by the HTTP-shaped methods unless CompatHTTPResponse is set
+var errHTTPShimDisabled = fmt.Errorf("KlingAdaptor does not perform HTTP requests; use Submit/Fetch or set CompatHTTPResponse")
+
 // NewKlingAdaptor creates a new KlingAdaptor instance
+//
+// Deprecated: use vidgo.NewTaskAdaptor. Run cmd/vidgo-migrate to rewrite usages.
 func NewKlingAdaptor() *KlingAdaptor {
 	return &KlingAdaptor{}
 }
@@ -127,40 +123,7 @@ func (k *KlingAdaptor) ValidateRequestAndSetAction(requestBody []byte, action st
 
 // convertToGenerationRequest converts VidgoSubmitReq to adapters.GenerationRequest
 func (k *KlingAdaptor) convertToGenerationRequest(req *VidgoSubmitReq) *adapters.GenerationRequest {
-	generationReq := &adapters.GenerationRequest{
-		Prompt:   req.Prompt,
-		Model:    req.Model, // modelName取自vidgo的model
-		Image:    req.Image, // image取自vidgo的image
-		Duration: float64(req.Duration),
-		Metadata: req.Metadata, // 传递metadata用于获取mode
-	}
-
-	// Extract width and height from size
-	if req.Size != "" {
-		switch req.Size {
-		case "1024x1024":
-			generationReq.Width, generationReq.Height = 1024, 1024
-		case "512x512":
-			generationReq.Width, generationReq.Height = 512, 512
-		case "1280x720":
-			generationReq.Width, generationReq.Height = 1280, 720
-		case "1920x1080":
-			generationReq.Width, generationReq.Height = 1920, 1080
-		case "720x1280":
-			generationReq.Width, generationReq.Height = 720, 1280
-		case "1080x1920":
-			generationReq.Width, generationReq.Height = 1080, 1920
-		}
-	}
-
-	// Extract image from metadata
-	if req.Metadata != nil {
-		if image, ok := req.Metadata["image"].(string); ok && image != "" {
-			generationReq.Image = image
-		}
-	}
-
-	return generationReq
+	return req.GenerationRequest("kling")
 }
 
 // BuildRequestURL builds the request URL for Kling video generation API
@@ -203,10 +166,47 @@ func (k *KlingAdaptor) BuildRequestBody(vidgoRequest *VidgoSubmitReq) ([]byte, e
 	return data, nil
 }
 
-// DoRequest performs video generation using the provider (bypasses HTTP)
-func (k *KlingAdaptor) DoRequest(url string, headers map[string]string, requestBody []byte) (*http.Response, error) {
+// Submit creates a generation task through the provider and returns the typed
+// result. Errors are *TaskAdaptorError.
+func (k *KlingAdaptor) Submit(ctx context.Context, vidgoRequest *VidgoSubmitReq) (*adapters.GenerationResponse, error) {
 	if k.provider == nil {
-		return nil, fmt.Errorf("provider not initialized")
+		return nil, errProviderNotInitialized()
+	}
+
+	generationResp, err := k.provider.CreateGeneration(ctx, k.convertToGenerationRequest(vidgoRequest))
+	if err != nil {
+		return nil, adapters.NewTaskAdaptorError("kling", err)
+	}
+	return generationResp, nil
+}
+
+// Fetch returns the typed status of a task through the provider. Errors are *TaskAdaptorError.
+func (k *KlingAdaptor) Fetch(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	if k.provider == nil {
+		return nil, errProviderNotInitialized()
+	}
+
+	taskResult, err := k.provider.GetGeneration(ctx, taskID)
+	if err != nil {
+		return nil, adapters.NewTaskAdaptorError("kling", err)
+	}
+	return taskResult, nil
+}
+
+// errProviderNotInitialized is returned when Init was not called or got an invalid API key
+func errProviderNotInitialized() *TaskAdaptorError {
+	return &TaskAdaptorError{
+		StatusCode: 500,
+		Code:       "provider_not_initialized",
+		Message:    "provider not initialized",
+		LocalError: true,
+	}
+}
+
+// DoRequest wraps Submit in a synthesized HTTP response. It requires CompatHTTPResponse.
+func (k *KlingAdaptor) DoRequest(url string, headers map[string]string, requestBody []byte) (*http.Response, error) {
+	if !k.CompatHTTPResponse {
+		return nil, errHTTPShimDisabled
 	}
 
 	// Parse the request body to VidgoSubmitReq
@@ -216,11 +216,9 @@ func (k *KlingAdaptor) DoRequest(url string, headers map[string]string, requestB
 		return nil, fmt.Errorf("failed to parse request: %w", err)
 	}
 
-	// Convert to GenerationRequest and call provider
-	generationReq := k.convertToGenerationRequest(&vidgoRequest)
-	generationResp, err := k.provider.CreateGeneration(context.Background(), generationReq)
+	generationResp, err := k.Submit(context.Background(), &vidgoRequest)
 	if err != nil {
-		return nil, fmt.Errorf("video generation failed: %w", err)
+		return nil, err
 	}
 
 	// Create a proper response with the generation result including taskID and status
@@ -233,20 +231,28 @@ func (k *KlingAdaptor) DoRequest(url string, headers map[string]string, requestB
 		},
 	}
 
-	responseBytes, err := json.Marshal(responseData)
+	return jsonShimResponse(responseData)
+}
+
+// jsonShimResponse builds the compatibility *http.Response around a JSON body
+func jsonShimResponse(data interface{}) (*http.Response, error) {
+	responseBytes, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal response: %w", err)
 	}
 
-	// Create a mock HTTP response
-	mockResp := &http.Response{
-		StatusCode: 200,
-		Header:     make(http.Header),
-		Body:       io.NopCloser(bytes.NewReader(responseBytes)),
+	resp := &http.Response{
+		Status:        "200 OK",
+		StatusCode:    200,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(responseBytes)),
+		ContentLength: int64(len(responseBytes)),
 	}
-	mockResp.Header.Set("Content-Type", "application/json")
-
-	return mockResp, nil
+	resp.Header.Set("Content-Type", "application/json")
+	return resp, nil
 }
 
 // Video represents a single video in the response
@@ -336,16 +342,15 @@ func (k *KlingAdaptor) DoResponse(resp *http.Response) (taskID string, taskData
 	return vidgoResponse.Data, responseBody, nil
 }
 
-// FetchTask fetches the status of a Kling video generation task using provider
+// FetchTask wraps Fetch in a synthesized Kling-format HTTP response. It requires CompatHTTPResponse.
 func (k *KlingAdaptor) FetchTask(baseUrl, key string, taskID string) (*http.Response, error) {
-	if k.provider == nil {
-		return nil, fmt.Errorf("provider not initialized")
+	if !k.CompatHTTPResponse {
+		return nil, errHTTPShimDisabled
 	}
 
-	// Use provider to get task status
-	taskResult, err := k.provider.GetGeneration(context.Background(), taskID)
+	taskResult, err := k.Fetch(context.Background(), taskID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get task status: %w", err)
+		return nil, err
 	}
 
 	// Convert TaskResult to Kling response format
@@ -398,20 +403,7 @@ func (k *KlingAdaptor) FetchTask(baseUrl, key string, taskID string) (*http.Resp
 		}
 	}
 
-	responseBytes, err := json.Marshal(responseData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response: %w", err)
-	}
-
-	// Create a mock HTTP response
-	mockResp := &http.Response{
-		StatusCode: 200,
-		Header:     make(http.Header),
-		Body:       io.NopCloser(bytes.NewReader(responseBytes)),
-	}
-	mockResp.Header.Set("Content-Type", "application/json")
-
-	return mockResp, nil
+	return jsonShimResponse(responseData)
 }
 
 // GetModelList returns the list of supported Kling models
@@ -0,0 +1,138 @@
+package kling
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Lip-sync limits of the Kling API
+const (
+	maxLipSyncTextLength = 120
+	minVoiceSpeed        = 0.8
+	maxVoiceSpeed        = 2.0
+)
+
+// KlingLipSyncRequest represents Kling's lip-sync request format
+type KlingLipSyncRequest struct {
+	Input KlingLipSyncInput `json:"input"`
+}
+
+// KlingLipSyncInput describes the source video and the speech to sync
+type KlingLipSyncInput struct {
+	VideoID       string  `json:"video_id,omitempty"`
+	VideoURL      string  `json:"video_url,omitempty"`
+	Mode          string  `json:"mode"` // text2video or audio2video
+	Text          string  `json:"text,omitempty"`
+	VoiceID       string  `json:"voice_id,omitempty"`
+	VoiceLanguage string  `json:"voice_language,omitempty"`
+	VoiceSpeed    float64 `json:"voice_speed,omitempty"`
+	AudioType     string  `json:"audio_type,omitempty"` // file or url
+	AudioFile     string  `json:"audio_file,omitempty"`
+	AudioURL      string  `json:"audio_url,omitempty"`
+}
+
+// CreateLipSync lip-syncs a Kling video or a video URL to text or audio
+func (p *Provider) CreateLipSync(ctx context.Context, req *adapters.LipSyncRequest) (*adapters.GenerationResponse, error) {
+	if err := validateLipSync(req); err != nil {
+		return nil, err
+	}
+
+	videoID := req.VideoID
+	if videoID == "" && req.VideoURL == "" {
+		var err error
+		if videoID, err = p.videoID(ctx, req.TaskID); err != nil {
+			return nil, err
+		}
+	}
+
+	klingReq := &KlingLipSyncRequest{Input: KlingLipSyncInput{VideoID: videoID, VideoURL: req.VideoURL}}
+	if req.Text != "" {
+		klingReq.Input.Mode = "text2video"
+		klingReq.Input.Text = req.Text
+		klingReq.Input.VoiceID = req.VoiceID
+		klingReq.Input.VoiceLanguage = req.VoiceLanguage
+		if klingReq.Input.VoiceLanguage == "" {
+			klingReq.Input.VoiceLanguage = "zh"
+		}
+		klingReq.Input.VoiceSpeed = req.VoiceSpeed
+		if klingReq.Input.VoiceSpeed == 0 {
+			klingReq.Input.VoiceSpeed = 1
+		}
+	} else {
+		klingReq.Input.Mode = "audio2video"
+		if data, _, ok := adapters.DecodeDataURI(req.Audio); ok {
+			klingReq.Input.AudioType = "file"
+			klingReq.Input.AudioFile = base64.StdEncoding.EncodeToString(data)
+		} else {
+			klingReq.Input.AudioType = "url"
+			klingReq.Input.AudioURL = req.Audio
+		}
+	}
+
+	baseURL, token, err := p.resolveAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := baseURL + p.endpoints[taskTypeLipSync]
+	resp, err := p.makeRequest(ctx, "POST", url, token, adapters.TransformRequest("kling", klingReq))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var klingResp KlingGenerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&klingResp); err != nil {
+		return nil, decodeError(resp.StatusCode, err)
+	}
+
+	if klingResp.Code != 0 {
+		return nil, apiError(resp.StatusCode, klingResp.Code, klingResp.Message)
+	}
+
+	p.tasks.setTaskType(klingResp.Data.TaskID, taskTypeLipSync)
+
+	return &adapters.GenerationResponse{
+		TaskID: klingResp.Data.TaskID,
+		Status: adapters.TaskStatusQueued,
+	}, nil
+}
+
+// validateLipSync checks the source video, the speech input and the voice settings
+func validateLipSync(req *adapters.LipSyncRequest) error {
+	sources := 0
+	for _, source := range []string{req.TaskID, req.VideoID, req.VideoURL} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("lip-sync requires exactly one of task ID, video ID or video URL")
+	}
+
+	if (req.Text == "") == (req.Audio == "") {
+		return fmt.Errorf("lip-sync requires either text or audio")
+	}
+	if req.Text == "" {
+		return nil
+	}
+
+	if n := utf8.RuneCountInString(req.Text); n > maxLipSyncTextLength {
+		return fmt.Errorf("Kling lip-sync text must be at most %d characters, got %d", maxLipSyncTextLength, n)
+	}
+	if req.VoiceID == "" {
+		return fmt.Errorf("lip-sync text requires a voice ID")
+	}
+	if req.VoiceLanguage != "" && req.VoiceLanguage != "zh" && req.VoiceLanguage != "en" {
+		return fmt.Errorf("unsupported voice language: %s", req.VoiceLanguage)
+	}
+	if req.VoiceSpeed != 0 && (req.VoiceSpeed < minVoiceSpeed || req.VoiceSpeed > maxVoiceSpeed) {
+		return fmt.Errorf("voice speed must be between %v and %v, got %v", minVoiceSpeed, maxVoiceSpeed, req.VoiceSpeed)
+	}
+	return nil
+}
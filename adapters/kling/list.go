@@ -0,0 +1,113 @@
+package kling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/pace"
+)
+
+// listPageSize is the largest page the task list endpoints return
+const listPageSize = 500
+
+// maxListPages bounds how far back GetGenerations pages before leaving the
+// remaining tasks to per-task queries
+const maxListPages = 4
+
+// KlingTaskListResponse represents Kling's task list response
+type KlingTaskListResponse struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Data    []json.RawMessage `json:"data"`
+}
+
+// GetGenerations looks tasks up on the list endpoint of their task type, newest
+// first. Only task types with at least two requested tasks are listed; tasks of
+// unknown type or older than the first pages are left to GetGeneration.
+func (p *Provider) GetGenerations(ctx context.Context, taskIDs []string) (map[string]*adapters.TaskResult, error) {
+	wanted := make(map[string]map[string]bool)
+	for _, taskID := range taskIDs {
+		taskType, ok := p.tasks.taskType(taskID)
+		if !ok {
+			continue
+		}
+		if wanted[taskType] == nil {
+			wanted[taskType] = make(map[string]bool)
+		}
+		wanted[taskType][taskID] = true
+	}
+
+	results := make(map[string]*adapters.TaskResult)
+	for taskType, ids := range wanted {
+		if len(ids) < 2 {
+			continue
+		}
+
+		remaining := len(ids)
+		err := pace.Pages(ctx, func(ctx context.Context, cursor string) ([]*adapters.TaskResult, string, error) {
+			page := 1
+			if cursor != "" {
+				page, _ = strconv.Atoi(cursor)
+			}
+			tasks, err := p.listTasks(ctx, taskType, page)
+			if err != nil || len(tasks) < listPageSize || page >= maxListPages {
+				return tasks, "", err
+			}
+			return tasks, strconv.Itoa(page + 1), nil
+		}, func(result *adapters.TaskResult) bool {
+			if ids[result.TaskID] && results[result.TaskID] == nil {
+				results[result.TaskID] = result
+				remaining--
+			}
+			return remaining > 0
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// listTasks fetches one page of the tasks of a task type
+func (p *Provider) listTasks(ctx context.Context, taskType string, page int) ([]*adapters.TaskResult, error) {
+	baseURL, token, err := p.resolveAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s%s?pageNum=%d&pageSize=%d", baseURL, p.endpoints[taskType], page, listPageSize)
+	resp, err := p.makeRequest(ctx, "GET", url, token, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var klingResp KlingTaskListResponse
+	if err := json.Unmarshal(body, &klingResp); err != nil {
+		return nil, decodeError(resp.StatusCode, err)
+	}
+	if klingResp.Code != 0 {
+		return nil, apiError(resp.StatusCode, klingResp.Code, klingResp.Message)
+	}
+
+	results := make([]*adapters.TaskResult, 0, len(klingResp.Data))
+	for _, raw := range klingResp.Data {
+		var task KlingTaskResult
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return nil, fmt.Errorf("failed to decode task: %w", err)
+		}
+		result := p.convertToTaskResult(&task)
+		result.RawResponse = raw
+		results = append(results, result)
+	}
+	return results, nil
+}
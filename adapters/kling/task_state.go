@@ -0,0 +1,102 @@
+package kling
+
+import (
+	"sync"
+	"time"
+)
+
+// taskStateTTL is how long a task is remembered after it was last submitted or
+// polled. Forgotten tasks are still found, by probing the generation endpoints.
+const taskStateTTL = 24 * time.Hour
+
+// taskState is what a provider remembers about the tasks it submitted. Providers
+// rebuilt by a configuration reload share it with the provider they replace.
+// Entries expire taskStateTTL after their last use so long-running processes
+// do not keep every task ever submitted.
+type taskState struct {
+	mu sync.Mutex
+
+	// types remembers which task type a task was submitted as, keyed by task ID
+	types map[string]taskStateEntry
+
+	// clientTasks maps ClientTaskID to the task ID of tasks submitted by this process
+	clientTasks map[string]taskStateEntry
+
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type taskStateEntry struct {
+	value   string
+	expires time.Time
+}
+
+// taskType returns the task type a task was submitted or found as
+func (s *taskState) taskType(taskID string) (string, bool) {
+	return s.load(&s.types, taskID)
+}
+
+// setTaskType records the task type of a task
+func (s *taskState) setTaskType(taskID, taskType string) {
+	s.store(&s.types, taskID, taskType)
+}
+
+// taskID returns the task ID of a ClientTaskID
+func (s *taskState) taskID(clientTaskID string) (string, bool) {
+	return s.load(&s.clientTasks, clientTaskID)
+}
+
+// setClientTask records the task ID a ClientTaskID was submitted as
+func (s *taskState) setClientTask(clientTaskID, taskID string) {
+	s.store(&s.clientTasks, clientTaskID, taskID)
+}
+
+// load returns an unexpired entry and extends its lifetime
+func (s *taskState) load(entries *map[string]taskStateEntry, key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	entry, ok := (*entries)[key]
+	if !ok || !now.Before(entry.expires) {
+		return "", false
+	}
+	entry.expires = now.Add(taskStateTTL)
+	(*entries)[key] = entry
+	return entry.value, true
+}
+
+func (s *taskState) store(entries *map[string]taskStateEntry, key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	s.sweep(now)
+	if *entries == nil {
+		*entries = make(map[string]taskStateEntry)
+	}
+	(*entries)[key] = taskStateEntry{value: value, expires: now.Add(taskStateTTL)}
+}
+
+// sweep drops expired entries, at most once per hour so stores stay cheap; the
+// caller holds mu
+func (s *taskState) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Hour {
+		return
+	}
+	s.lastSweep = now
+	for _, entries := range []map[string]taskStateEntry{s.types, s.clientTasks} {
+		for key, entry := range entries {
+			if !now.Before(entry.expires) {
+				delete(entries, key)
+			}
+		}
+	}
+}
+
+func (s *taskState) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,36 @@
+package kling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskStateExpiry(t *testing.T) {
+	now := time.Now()
+	state := &taskState{now: func() time.Time { return now }}
+	state.setTaskType("task-1", taskTypeEffects)
+	state.setClientTask("order-1", "task-1")
+	state.setTaskType("task-2", taskTypeText2Video)
+
+	// Polling a task keeps it
+	now = now.Add(taskStateTTL - time.Minute)
+	if taskType, ok := state.taskType("task-1"); !ok || taskType != taskTypeEffects {
+		t.Errorf("Expected task-1 to be an effects task, got %q", taskType)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := state.taskType("task-2"); ok {
+		t.Error("Expected task-2 to have expired")
+	}
+	if _, ok := state.taskID("order-1"); ok {
+		t.Error("Expected order-1 to have expired")
+	}
+	if _, ok := state.taskType("task-1"); !ok {
+		t.Error("Expected the polled task-1 to be kept")
+	}
+
+	state.setTaskType("task-3", taskTypeText2Video)
+	if len(state.types) != 2 || len(state.clientTasks) != 0 {
+		t.Errorf("Expected expired entries to be dropped, got %d types and %d client tasks", len(state.types), len(state.clientTasks))
+	}
+}
@@ -0,0 +1,57 @@
+package kling
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultTokenRefreshMargin is how long before expiry a cached JWT is re-signed
+const defaultTokenRefreshMargin = 5 * time.Minute
+
+// maxCachedTokens bounds the cache when many per-request keys are used
+const maxCachedTokens = 1024
+
+// tokenCache reuses signed JWTs per key pair until they are within margin of
+// their expiry. It is safe for concurrent use.
+type tokenCache struct {
+	margin time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+// cachedToken is a signed JWT and its exp claim
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// get returns a cached token that expires after the refresh margin but within
+// ttl, signing a new one otherwise. The margin is capped at half of ttl so short
+// lived tokens are still reused, and a token outliving ttl is not reused so a
+// shorter TokenLifetime is honored.
+func (c *tokenCache) get(accessKey, secretKey string, ttl time.Duration) (string, error) {
+	key := accessKey + "\x00" + secretKey
+	now := time.Now()
+	margin := min(c.margin, ttl/2)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.tokens[key]; ok {
+		if remaining := cached.expiresAt.Sub(now); remaining > margin && remaining <= ttl {
+			return cached.token, nil
+		}
+	}
+
+	token, err := signJWTToken(accessKey, secretKey, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	if c.tokens == nil || len(c.tokens) >= maxCachedTokens {
+		c.tokens = make(map[string]cachedToken)
+	}
+	c.tokens[key] = cachedToken{token: token, expiresAt: time.Unix(now.Unix()+int64(ttl/time.Second), 0)}
+	return token, nil
+}
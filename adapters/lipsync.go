@@ -0,0 +1,28 @@
+package adapters
+
+import "context"
+
+// LipSyncRequest lip-syncs a video to speech, given as text for a voice or as audio
+type LipSyncRequest struct {
+	// Source video: a succeeded task, a provider video ID or a video URL
+	TaskID   string `json:"task_id,omitempty"`
+	VideoID  string `json:"video_id,omitempty"`
+	VideoURL string `json:"video_url,omitempty"`
+
+	// Text is spoken with the selected voice
+	Text          string  `json:"text,omitempty"`
+	VoiceID       string  `json:"voice_id,omitempty"`
+	VoiceLanguage string  `json:"voice_language,omitempty"` // e.g. "zh" or "en"
+	VoiceSpeed    float64 `json:"voice_speed,omitempty"`
+
+	// Audio is an audio URL or data URI, used instead of Text
+	Audio string `json:"audio,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// LipSyncer is implemented by providers that can lip-sync videos. The returned
+// task is polled with GetGeneration like any other.
+type LipSyncer interface {
+	CreateLipSync(ctx context.Context, req *LipSyncRequest) (*GenerationResponse, error)
+}
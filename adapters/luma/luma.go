@@ -0,0 +1,390 @@
+package luma
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/models"
+)
+
+// Provider implements the adapters.Provider interface for Luma Dream Machine video generation
+type Provider struct {
+	config  *adapters.ProviderConfig
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// LumaGenerationRequest represents Luma's generation request format
+type LumaGenerationRequest struct {
+	Prompt      string         `json:"prompt,omitempty"`
+	Model       string         `json:"model"`
+	AspectRatio string         `json:"aspect_ratio,omitempty"`
+	Resolution  string         `json:"resolution,omitempty"`
+	Duration    string         `json:"duration,omitempty"`
+	Loop        bool           `json:"loop,omitempty"`
+	Keyframes   *LumaKeyframes `json:"keyframes,omitempty"`
+}
+
+// LumaKeyframes holds the start (frame0) and end (frame1) keyframes
+type LumaKeyframes struct {
+	Frame0 *LumaKeyframe `json:"frame0,omitempty"`
+	Frame1 *LumaKeyframe `json:"frame1,omitempty"`
+}
+
+// LumaKeyframe is an image or generation keyframe
+type LumaKeyframe struct {
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+// LumaGeneration represents Luma's generation object
+type LumaGeneration struct {
+	ID            string     `json:"id"`
+	State         string     `json:"state"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CreatedAt     string     `json:"created_at,omitempty"`
+	Assets        LumaAssets `json:"assets"`
+}
+
+// LumaAssets holds the generated asset URLs
+type LumaAssets struct {
+	Video string `json:"video,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+// LumaError represents Luma's error response
+type LumaError struct {
+	Detail interface{} `json:"detail"`
+}
+
+// catalog describes the Luma models, see package models
+var catalog = models.List("luma")
+
+var supportedModels = catalog.Names()
+
+// supportedAspectRatios are the ratios getAspectRatio picks from; every model shares them
+var supportedAspectRatios = catalog[0].AspectRatios
+
+// extraSchema lists the Extra keys Luma understands; it has none
+var extraSchema = adapters.ExtraSchema{}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
+func init() {
+	adapters.Register("luma", New)
+}
+
+// New creates a new Luma provider instance
+func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("invalid configuration")
+	}
+
+	if err := extraSchema.Validate("Luma", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(config.APIKey) == "" {
+		return nil, fmt.Errorf("API key is required for Luma")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.lumalabs.ai"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Provider{
+		config:  config,
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  strings.TrimSpace(config.APIKey),
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Luma"
+}
+
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
+// SupportedModels returns supported models
+func (p *Provider) SupportedModels() []string {
+	return append([]string{}, supportedModels...)
+}
+
+// Capabilities returns the Luma clip lengths
+func (p *Provider) Capabilities() *adapters.Capabilities {
+	return &adapters.Capabilities{
+		Duration:     &adapters.DurationConstraints{Durations: catalog.Durations()},
+		Combinations: adapters.CatalogMatrix(catalog),
+	}
+}
+
+// Warm opens a connection to the Luma API
+func (p *Provider) Warm(ctx context.Context) error {
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
+}
+
+// ValidateRequest validates the request for Luma
+func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
+	if adapters.NegativePrompt(req) != "" {
+		return fmt.Errorf("Luma does not support negative prompts")
+	}
+	if err := adapters.ValidateImages("Luma", req.Images, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Luma", req.CallbackURL, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("Luma", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateWatermark("Luma", req.Watermark); err != nil {
+		return err
+	}
+
+	info, ok := catalog.Lookup(req.Model)
+	if !ok {
+		return fmt.Errorf("unsupported model: %s", req.Model)
+	}
+	if !info.SupportsDuration("", req.Duration) {
+		return fmt.Errorf("Luma %s only supports %s duration", info.Name, models.FormatDurations(info.Durations))
+	}
+
+	first, last, err := adapters.EdgeKeyframes("Luma", req)
+	if err != nil {
+		return err
+	}
+	if len(req.ImageBytes) > 0 || adapters.IsDataURI(req.Image) || adapters.IsDataURI(first) || adapters.IsDataURI(last) {
+		return fmt.Errorf("Luma keyframes must be image URLs")
+	}
+
+	return nil
+}
+
+// CreateGeneration creates a video generation task
+func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
+	lumaReq := p.convertToLumaRequest(req)
+
+	var generation LumaGeneration
+	if _, err := p.do(ctx, "POST", "/dream-machine/v1/generations", adapters.TransformRequest("luma", lumaReq), &generation); err != nil {
+		return nil, err
+	}
+
+	return &adapters.GenerationResponse{
+		TaskID: generation.ID,
+		Status: p.convertStatus(generation.State),
+	}, nil
+}
+
+// GetGeneration retrieves the task status
+func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	var generation LumaGeneration
+	body, err := p.do(ctx, "GET", "/dream-machine/v1/generations/"+taskID, nil, &generation)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &adapters.TaskResult{
+		TaskID:      generation.ID,
+		Status:      p.convertStatus(generation.State),
+		RawResponse: body,
+	}
+
+	if generation.Assets.Video != "" {
+		result.URL = generation.Assets.Video
+		result.Format = "mp4"
+		if generation.Assets.Image != "" {
+			result.Assets = []adapters.ResultAsset{
+				{URL: generation.Assets.Video, Kind: adapters.AssetVideo},
+				{URL: generation.Assets.Image, Kind: adapters.AssetThumbnail},
+			}
+		}
+	}
+
+	if generation.State == "failed" {
+		result.Error = &adapters.TaskError{Code: http.StatusInternalServerError, Message: generation.FailureReason}
+	}
+
+	return result, nil
+}
+
+// convertToLumaRequest converts standard request to Luma format
+func (p *Provider) convertToLumaRequest(req *adapters.GenerationRequest) *LumaGenerationRequest {
+	lumaReq := &LumaGenerationRequest{
+		Prompt:      req.Prompt,
+		Model:       req.Model,
+		AspectRatio: p.getAspectRatio(req.Width, req.Height),
+		Resolution:  p.getResolution(req.QualityLevel),
+		Duration:    fmt.Sprintf("%.0fs", req.Duration),
+	}
+
+	if lumaReq.Model == "" {
+		lumaReq.Model = "ray-2"
+	}
+
+	// 首帧取自image，尾帧取自image_tail或metadata的image_tail
+	keyframes := &LumaKeyframes{}
+	if req.Image != "" {
+		keyframes.Frame0 = &LumaKeyframe{Type: "image", URL: req.Image}
+	}
+	if req.ImageTail != "" {
+		keyframes.Frame1 = &LumaKeyframe{Type: "image", URL: req.ImageTail}
+	}
+	if req.Metadata != nil {
+		if tail, ok := req.Metadata["image_tail"].(string); ok && tail != "" && keyframes.Frame1 == nil {
+			keyframes.Frame1 = &LumaKeyframe{Type: "image", URL: tail}
+		}
+		if loop, ok := req.Metadata["loop"].(bool); ok {
+			lumaReq.Loop = loop
+		}
+	}
+	if first, last, err := adapters.EdgeKeyframes("Luma", req); err == nil {
+		if first != "" {
+			keyframes.Frame0 = &LumaKeyframe{Type: "image", URL: first}
+		}
+		if last != "" {
+			keyframes.Frame1 = &LumaKeyframe{Type: "image", URL: last}
+		}
+	}
+	if keyframes.Frame0 != nil || keyframes.Frame1 != nil {
+		lumaReq.Keyframes = keyframes
+	}
+
+	return lumaReq
+}
+
+// getAspectRatio picks the supported aspect ratio closest to width/height
+func (p *Provider) getAspectRatio(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return "16:9"
+	}
+	ratio := float64(width) / float64(height)
+
+	best, bestDiff := "16:9", -1.0
+	for _, candidate := range supportedAspectRatios {
+		var w, h float64
+		fmt.Sscanf(candidate, "%f:%f", &w, &h)
+		diff := ratio - w/h
+		if diff < 0 {
+			diff = -diff
+		}
+		if bestDiff < 0 || diff < bestDiff {
+			best, bestDiff = candidate, diff
+		}
+	}
+	return best
+}
+
+// getResolution maps quality level to a Luma resolution
+func (p *Provider) getResolution(quality adapters.QualityLevel) string {
+	switch quality {
+	case adapters.QualityLevelLow:
+		return "540p"
+	case adapters.QualityLevelHigh:
+		return "1080p"
+	default:
+		return "720p"
+	}
+}
+
+// convertStatus converts Luma state to standard status
+func (p *Provider) convertStatus(state string) adapters.TaskStatus {
+	switch state {
+	case "queued":
+		return adapters.TaskStatusQueued
+	case "dreaming":
+		return adapters.TaskStatusProcessing
+	case "completed":
+		return adapters.TaskStatusSucceeded
+	case "failed":
+		return adapters.TaskStatusFailed
+	default:
+		return adapters.TaskStatusQueued
+	}
+}
+
+// do performs an authenticated request and decodes the JSON response into out
+func (p *Provider) do(ctx context.Context, method, path string, body interface{}, out interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	baseURL, apiKey := p.baseURL, p.apiKey
+	if overrides := adapters.RequestOverridesFromContext(ctx); overrides != nil {
+		if overrides.BaseURL != "" {
+			baseURL = strings.TrimRight(overrides.BaseURL, "/")
+		}
+		if overrides.APIKey != "" {
+			apiKey = overrides.APIKey
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
+
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var lumaErr LumaError
+		json.Unmarshal(respBody, &lumaErr)
+		var message string
+		if lumaErr.Detail != nil {
+			message = fmt.Sprint(lumaErr.Detail)
+		}
+		return nil, adapters.NewAPIError(resp.StatusCode, "", message)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,28 @@
+package adapters
+
+// Generation modes accepted in GenerationRequest.Mode
+const (
+	ModeStandard = "std"
+	ModePro      = "pro"
+)
+
+// ResolveMode returns the requested generation mode: Mode, then the deprecated
+// metadata["mode"], then "pro" for QualityLevelHigh. An empty result leaves the
+// provider default.
+func ResolveMode(req *GenerationRequest) string {
+	if req.Mode != "" {
+		return req.Mode
+	}
+	if mode, ok := req.Metadata["mode"].(string); ok && mode != "" {
+		return mode
+	}
+	if req.QualityLevel == QualityLevelHigh {
+		return ModePro
+	}
+	return ""
+}
+
+// ValidMode reports whether mode is empty, "std" or "pro"
+func ValidMode(mode string) bool {
+	return mode == "" || mode == ModeStandard || mode == ModePro
+}
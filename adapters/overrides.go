@@ -0,0 +1,74 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOverrides holds per-request values that take precedence over the provider configuration
+type RequestOverrides struct {
+	APIKey  string
+	BaseURL string
+
+	// Header is added to outgoing requests; it never replaces headers the provider sets
+	Header http.Header
+
+	// Timeout replaces the provider's HTTP client timeout, see HTTPClient
+	Timeout time.Duration
+}
+
+type overridesKey struct{}
+
+// WithRequestOverrides returns a context carrying per-request overrides
+func WithRequestOverrides(ctx context.Context, overrides *RequestOverrides) context.Context {
+	return context.WithValue(ctx, overridesKey{}, overrides)
+}
+
+// RequestOverridesFromContext returns the overrides stored in ctx, or nil if there are none
+func RequestOverridesFromContext(ctx context.Context) *RequestOverrides {
+	overrides, _ := ctx.Value(overridesKey{}).(*RequestOverrides)
+	return overrides
+}
+
+// SetRequestHeaders adds the per-request headers stored in ctx to req, skipping
+// any the provider has already set, such as Authorization
+func SetRequestHeaders(ctx context.Context, req *http.Request) {
+	overrides := RequestOverridesFromContext(ctx)
+	if overrides == nil {
+		return
+	}
+	for name, values := range overrides.Header {
+		if req.Header.Get(name) != "" {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+}
+
+// HTTPClient returns client, or a copy using the per-request timeout stored in
+// ctx so a call can run longer than ProviderConfig.Timeout, and passing its
+// exchanges to the ExchangeRecorder stored in ctx
+func HTTPClient(ctx context.Context, client *http.Client) *http.Client {
+	overrides := RequestOverridesFromContext(ctx)
+	recorder := ExchangeRecorderFromContext(ctx)
+	timeout := overrides != nil && overrides.Timeout > 0 && overrides.Timeout != client.Timeout
+	if !timeout && recorder == nil {
+		return client
+	}
+	copied := *client
+	if timeout {
+		copied.Timeout = overrides.Timeout
+	}
+	if recorder != nil {
+		copied.Transport = &recordingTransport{base: client.Transport, recorder: recorder}
+	}
+	return &copied
+}
+
+// CredentialValidator is implemented by providers that can validate a per-request API key
+type CredentialValidator interface {
+	ValidateCredentials(apiKey string) error
+}
@@ -0,0 +1,77 @@
+package adapters
+
+import (
+	"strings"
+	"sync"
+)
+
+// SizePreset maps a size keyword to concrete provider dimensions
+type SizePreset struct {
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	AspectRatio string `json:"aspect_ratio"`
+}
+
+var (
+	presetMu sync.RWMutex
+
+	// sizePresets holds presets keyed by provider then keyword; the "" provider holds defaults
+	sizePresets = map[string]map[string]SizePreset{
+		"": {
+			"portrait":  {Width: 720, Height: 1280, AspectRatio: "9:16"},
+			"landscape": {Width: 1280, Height: 720, AspectRatio: "16:9"},
+			"square":    {Width: 1024, Height: 1024, AspectRatio: "1:1"},
+			"story":     {Width: 1080, Height: 1920, AspectRatio: "9:16"},
+			"reel":      {Width: 1080, Height: 1920, AspectRatio: "9:16"},
+		},
+	}
+)
+
+// RegisterSizePreset adds or replaces a size keyword for a provider.
+// An empty provider registers a default used by all providers.
+func RegisterSizePreset(provider, name string, preset SizePreset) {
+	presetMu.Lock()
+	defer presetMu.Unlock()
+
+	provider = strings.ToLower(provider)
+	if sizePresets[provider] == nil {
+		sizePresets[provider] = make(map[string]SizePreset)
+	}
+	sizePresets[provider][strings.ToLower(name)] = preset
+}
+
+// UnregisterSizePreset removes a size keyword registered for a provider
+func UnregisterSizePreset(provider, name string) {
+	presetMu.Lock()
+	defer presetMu.Unlock()
+
+	delete(sizePresets[strings.ToLower(provider)], strings.ToLower(name))
+}
+
+// LookupSizePreset resolves a size keyword for a provider, falling back to the defaults
+func LookupSizePreset(provider, name string) (SizePreset, bool) {
+	presetMu.RLock()
+	defer presetMu.RUnlock()
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if preset, ok := sizePresets[strings.ToLower(provider)][name]; ok {
+		return preset, true
+	}
+	preset, ok := sizePresets[""][name]
+	return preset, ok
+}
+
+// SizePresets returns all size keywords available to a provider
+func SizePresets(provider string) map[string]SizePreset {
+	presetMu.RLock()
+	defer presetMu.RUnlock()
+
+	presets := make(map[string]SizePreset)
+	for name, preset := range sizePresets[""] {
+		presets[name] = preset
+	}
+	for name, preset := range sizePresets[strings.ToLower(provider)] {
+		presets[name] = preset
+	}
+	return presets
+}
@@ -0,0 +1,24 @@
+package adapters
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// NegativePrompt returns the request's negative prompt, falling back to
+// metadata["negative_prompt"]
+func NegativePrompt(req *GenerationRequest) string {
+	if req.NegativePrompt != "" {
+		return req.NegativePrompt
+	}
+	negative, _ := req.Metadata["negative_prompt"].(string)
+	return negative
+}
+
+// ValidateNegativePrompt rejects negative prompts longer than maxLength characters
+func ValidateNegativePrompt(provider, negativePrompt string, maxLength int) error {
+	if n := utf8.RuneCountInString(negativePrompt); n > maxLength {
+		return fmt.Errorf("%s negative prompt must be at most %d characters, got %d", provider, maxLength, n)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory creates a provider from its configuration
+type Factory func(config *ProviderConfig) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider available to vidgo.NewClient under name. Provider
+// packages call it from init, so a provider kept in its own module, with its
+// own SDK dependencies, is enabled by importing it for side effects:
+//
+//	import _ "example.com/vidgo-s3/adapters/s3"
+//
+// Register panics if factory is nil or name is already registered.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("adapters: Register factory is nil for " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("adapters: Register called twice for provider %s", name))
+	}
+	registry[name] = factory
+}
+
+// Lookup returns the factory registered under name
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// Registered returns the names of the registered providers in sorted order
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,107 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+)
+
+// TaskRelayInfo contains information needed for task relay
+type TaskRelayInfo struct {
+	ChannelID   int // The gateway's channel, recorded in TaskRef
+	ChannelType int
+	BaseUrl     string
+	ApiKey      string
+	Action      string
+}
+
+// TaskAdaptorError represents an error in task processing
+type TaskAdaptorError struct {
+	StatusCode int    `json:"status_code"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	LocalError bool   `json:"local_error"`
+}
+
+func (e *TaskAdaptorError) Error() string {
+	return e.Message
+}
+
+// NewTaskAdaptorError converts a provider error into a relay error. API errors keep
+// their HTTP status and become "<vendor>_error_<code>"; other errors are a 502.
+func NewTaskAdaptorError(vendor string, err error) *TaskAdaptorError {
+	var taskErr *TaskAdaptorError
+	if errors.As(err, &taskErr) {
+		return taskErr
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		code := "upstream_error"
+		if apiErr.Code != "" {
+			code = vendor + "_error_" + apiErr.Code
+		}
+		return &TaskAdaptorError{StatusCode: apiErr.StatusCode, Code: code, Message: apiErr.Message}
+	}
+	return &TaskAdaptorError{StatusCode: http.StatusBadGateway, Code: "request_failed", Message: err.Error()}
+}
+
+// VidgoSubmitReq represents a video generation request.
+// Image-to-video needs image or image_tail (or both); requests with neither go to
+// text2video. metadata.image and metadata.image_tail are accepted as fallbacks.
+type VidgoSubmitReq struct {
+	Prompt         string                 `json:"prompt"`
+	NegativePrompt string                 `json:"negative_prompt,omitempty"` // Content to avoid, at most 2500 characters for Kling
+	Model          string                 `json:"model,omitempty"`
+	Mode           string                 `json:"mode,omitempty"`       // Mode: "std" or "pro", defaults to "std"
+	Image          string                 `json:"image,omitempty"`      // Image URL for image-to-video
+	ImageTail      string                 `json:"image_tail,omitempty"` // End frame image URL for image-to-video
+	Size           string                 `json:"size,omitempty"`
+	Duration       int                    `json:"duration,omitempty"`
+	GuidanceScale  *float64               `json:"guidance_scale,omitempty"` // Kling cfg_scale in [0, 1], defaults to 0.5
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// GenerationRequest converts the relay request for a provider by registry name,
+// resolving Size through the provider's size presets
+func (r *VidgoSubmitReq) GenerationRequest(provider string) *GenerationRequest {
+	req := &GenerationRequest{
+		Prompt:         r.Prompt,
+		NegativePrompt: r.NegativePrompt,
+		Model:          r.Model,
+		Image:          r.Image,
+		ImageTail:      r.ImageTail,
+		Mode:           r.Mode,
+		Duration:       float64(r.Duration),
+		GuidanceScale:  r.GuidanceScale,
+		Metadata:       r.Metadata,
+	}
+
+	if preset, ok := LookupSizePreset(provider, r.Size); ok {
+		req.Width, req.Height = preset.Width, preset.Height
+	} else {
+		switch r.Size {
+		case "1024x1024":
+			req.Width, req.Height = 1024, 1024
+		case "512x512":
+			req.Width, req.Height = 512, 512
+		case "1280x720":
+			req.Width, req.Height = 1280, 720
+		case "1920x1080":
+			req.Width, req.Height = 1920, 1080
+		case "720x1280":
+			req.Width, req.Height = 720, 1280
+		case "1080x1920":
+			req.Width, req.Height = 1080, 1920
+		}
+	}
+
+	// metadata.image takes precedence; metadata.image_tail only fills an empty image_tail
+	if r.Metadata != nil {
+		if image, ok := r.Metadata["image"].(string); ok && image != "" {
+			req.Image = image
+		}
+		if tail, ok := r.Metadata["image_tail"].(string); ok && tail != "" && req.ImageTail == "" {
+			req.ImageTail = tail
+		}
+	}
+	return req
+}
@@ -0,0 +1,397 @@
+package replicate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Provider implements the adapters.Provider interface for any Replicate-hosted video model.
+// GenerationRequest.Model names the model as "owner/name" (official models) or
+// "owner/name:version" (a specific version).
+type Provider struct {
+	config  *adapters.ProviderConfig
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	webhook string
+}
+
+// ReplicatePredictionRequest represents Replicate's create prediction request format
+type ReplicatePredictionRequest struct {
+	Version             string                 `json:"version,omitempty"`
+	Input               map[string]interface{} `json:"input"`
+	Webhook             string                 `json:"webhook,omitempty"`
+	WebhookEventsFilter []string               `json:"webhook_events_filter,omitempty"`
+}
+
+// ReplicatePrediction represents Replicate's prediction object
+type ReplicatePrediction struct {
+	ID     string          `json:"id"`
+	Model  string          `json:"model,omitempty"`
+	Status string          `json:"status"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  interface{}     `json:"error,omitempty"`
+
+	Metrics     *ReplicateMetrics `json:"metrics,omitempty"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// ReplicateMetrics holds the timings of a prediction; Replicate bills most
+// models by predict_time
+type ReplicateMetrics struct {
+	PredictTime float64 `json:"predict_time,omitempty"` // Seconds of hardware time
+}
+
+// OutputRetention is how long Replicate keeps the output files of predictions
+// created through the API after they complete
+const OutputRetention = time.Hour
+
+// ReplicateError represents Replicate's error response
+type ReplicateError struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// exampleModels lists well-known video models; any Replicate model can be used
+var exampleModels = []string{
+	"genmoai/mochi-1",
+	"tencent/hunyuan-video",
+	"minimax/video-01",
+}
+
+// extraSchema lists the Extra keys Replicate understands
+var extraSchema = adapters.ExtraSchema{
+	{Name: "webhook", Description: "URL Replicate calls when predictions complete", Validate: adapters.ValidateHTTPURL},
+}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
+func init() {
+	adapters.Register("replicate", New)
+}
+
+// New creates a new Replicate provider instance.
+// Extra["webhook"] sets a URL Replicate calls when predictions complete.
+func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("invalid configuration")
+	}
+
+	if err := extraSchema.Validate("Replicate", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(config.APIKey) == "" {
+		return nil, fmt.Errorf("API token is required for Replicate")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.replicate.com"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Provider{
+		config:  config,
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  strings.TrimSpace(config.APIKey),
+		webhook: config.Extra["webhook"],
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Replicate"
+}
+
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
+// SupportedModels returns well-known video models. Any "owner/name[:version]" is accepted.
+func (p *Provider) SupportedModels() []string {
+	return append([]string{}, exampleModels...)
+}
+
+// Warm opens a connection to the Replicate API
+func (p *Provider) Warm(ctx context.Context) error {
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
+}
+
+// ValidateRequest validates the request for Replicate
+func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
+	if _, _, err := parseModel(req.Model); err != nil {
+		return err
+	}
+	if err := adapters.ValidateImages("Replicate", req.Images, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Replicate", req.CallbackURL, true); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("Replicate", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateKeyframes("Replicate", req.Keyframes, 0); err != nil {
+		return err
+	}
+	return adapters.ValidateWatermark("Replicate", req.Watermark)
+}
+
+// CreateGeneration creates a prediction
+func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
+	model, version, err := parseModel(req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	replicateReq := p.convertToReplicateRequest(req)
+
+	// 指定版本时走通用predictions接口，否则走官方模型接口
+	path := "/v1/models/" + model + "/predictions"
+	if version != "" {
+		replicateReq.Version = version
+		path = "/v1/predictions"
+	}
+
+	var prediction ReplicatePrediction
+	if _, err := p.do(ctx, "POST", path, adapters.TransformRequest("replicate", replicateReq), &prediction); err != nil {
+		return nil, err
+	}
+
+	return &adapters.GenerationResponse{
+		TaskID: prediction.ID,
+		Status: p.convertStatus(prediction.Status),
+	}, nil
+}
+
+// GetGeneration retrieves the prediction status
+func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	var prediction ReplicatePrediction
+	body, err := p.do(ctx, "GET", "/v1/predictions/"+taskID, nil, &prediction)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.toTaskResult(&prediction, body), nil
+}
+
+// ParseWebhook converts a webhook payload sent by Replicate into a task result
+func ParseWebhook(body []byte) (*adapters.TaskResult, error) {
+	var prediction ReplicatePrediction
+	if err := json.Unmarshal(body, &prediction); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+	return (&Provider{}).toTaskResult(&prediction, body), nil
+}
+
+// toTaskResult maps a prediction to a task result
+func (p *Provider) toTaskResult(prediction *ReplicatePrediction, body []byte) *adapters.TaskResult {
+	result := &adapters.TaskResult{
+		TaskID:      prediction.ID,
+		Status:      p.convertStatus(prediction.Status),
+		RawResponse: body,
+	}
+
+	if url := outputURL(prediction.Output); url != "" {
+		result.URL = url
+		result.Format = "mp4"
+		if prediction.CompletedAt != nil {
+			expires := prediction.CompletedAt.Add(OutputRetention)
+			result.Assets = []adapters.ResultAsset{{URL: url, Kind: adapters.AssetVideo, ExpiresAt: &expires}}
+		}
+	}
+
+	if prediction.Metrics != nil && prediction.Metrics.PredictTime > 0 {
+		result.Usage = &adapters.Usage{BilledSeconds: prediction.Metrics.PredictTime}
+	}
+
+	if result.Status == adapters.TaskStatusFailed {
+		message := prediction.Status
+		if prediction.Error != nil {
+			message = fmt.Sprint(prediction.Error)
+		}
+		result.Error = &adapters.TaskError{Code: http.StatusInternalServerError, Message: message}
+	}
+	if prediction.Status == "canceled" {
+		result.Cancellation = &adapters.Cancellation{Reason: adapters.CancellationProvider, Message: result.Error.Message}
+		if prediction.CompletedAt != nil {
+			result.Cancellation.CanceledAt = *prediction.CompletedAt
+		}
+	}
+
+	return result
+}
+
+// CancelGeneration cancels a prediction that has not finished
+func (p *Provider) CancelGeneration(ctx context.Context, taskID string) error {
+	var prediction ReplicatePrediction
+	_, err := p.do(ctx, "POST", "/v1/predictions/"+taskID+"/cancel", nil, &prediction)
+	return err
+}
+
+// convertToReplicateRequest builds the model input. metadata["input"] holds
+// model-specific fields and takes precedence over the generic ones.
+func (p *Provider) convertToReplicateRequest(req *adapters.GenerationRequest) *ReplicatePredictionRequest {
+	input := map[string]interface{}{}
+	if req.Prompt != "" {
+		input["prompt"] = req.Prompt
+	}
+	if negative := adapters.NegativePrompt(req); negative != "" {
+		input["negative_prompt"] = negative
+	}
+	if encoded := adapters.ImageBase64(req); encoded != "" {
+		mediaType := http.DetectContentType(req.ImageBytes)
+		if _, dataType, ok := adapters.DecodeDataURI(req.Image); ok {
+			mediaType = dataType
+		}
+		input["image"] = "data:" + mediaType + ";base64," + encoded
+	} else if req.Image != "" {
+		input["image"] = req.Image
+	}
+	if req.Seed != nil {
+		input["seed"] = *req.Seed
+	}
+
+	replicateReq := &ReplicatePredictionRequest{Input: input, Webhook: p.webhook}
+
+	if req.Metadata != nil {
+		if extra, ok := req.Metadata["input"].(map[string]interface{}); ok {
+			for k, v := range extra {
+				input[k] = v
+			}
+		}
+		if webhook, ok := req.Metadata["webhook"].(string); ok && webhook != "" {
+			replicateReq.Webhook = webhook
+		}
+	}
+	if req.CallbackURL != "" {
+		replicateReq.Webhook = req.CallbackURL
+	}
+
+	if replicateReq.Webhook != "" {
+		replicateReq.WebhookEventsFilter = []string{"completed"}
+	}
+
+	return replicateReq
+}
+
+// parseModel splits "owner/name[:version]"
+func parseModel(model string) (string, string, error) {
+	name, version, _ := strings.Cut(model, ":")
+	owner, modelName, ok := strings.Cut(name, "/")
+	if !ok || owner == "" || modelName == "" || strings.Contains(modelName, "/") {
+		return "", "", fmt.Errorf("Replicate model must be 'owner/name' or 'owner/name:version', got %q", model)
+	}
+	return name, version, nil
+}
+
+// outputURL returns the first URL of a prediction output, which is a string or a list
+func outputURL(output json.RawMessage) string {
+	if len(output) == 0 {
+		return ""
+	}
+
+	var url string
+	if err := json.Unmarshal(output, &url); err == nil {
+		return url
+	}
+
+	var urls []string
+	if err := json.Unmarshal(output, &urls); err == nil && len(urls) > 0 {
+		return urls[0]
+	}
+	return ""
+}
+
+// convertStatus converts Replicate status to standard status
+func (p *Provider) convertStatus(status string) adapters.TaskStatus {
+	switch status {
+	case "starting":
+		return adapters.TaskStatusQueued
+	case "processing":
+		return adapters.TaskStatusProcessing
+	case "succeeded":
+		return adapters.TaskStatusSucceeded
+	case "failed", "canceled":
+		return adapters.TaskStatusFailed
+	default:
+		return adapters.TaskStatusQueued
+	}
+}
+
+// do performs an authenticated request and decodes the JSON response into out
+func (p *Provider) do(ctx context.Context, method, path string, body interface{}, out interface{}) ([]byte, error) {
+	baseURL, apiKey := p.baseURL, p.apiKey
+	if overrides := adapters.RequestOverridesFromContext(ctx); overrides != nil {
+		if overrides.BaseURL != "" {
+			baseURL = strings.TrimRight(overrides.BaseURL, "/")
+		}
+		if overrides.APIKey != "" {
+			apiKey = overrides.APIKey
+		}
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
+
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var replicateErr ReplicateError
+		json.Unmarshal(respBody, &replicateErr)
+		return nil, adapters.NewAPIError(resp.StatusCode, "", replicateErr.Detail)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return respBody, nil
+}
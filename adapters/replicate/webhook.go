@@ -0,0 +1,48 @@
+package replicate
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VerifyWebhook checks the webhook-signature header Replicate sends with every
+// webhook against secret, the signing secret ("whsec_...") from Replicate's
+// webhooks/default/secret endpoint. It returns the webhook-id, which stays the
+// same when Replicate retries a delivery, and the webhook-timestamp.
+func VerifyWebhook(header http.Header, body []byte, secret string) (string, time.Time, error) {
+	id, timestamp := header.Get("webhook-id"), header.Get("webhook-timestamp")
+	if id == "" || timestamp == "" {
+		return "", time.Time{}, fmt.Errorf("missing webhook-id or webhook-timestamp header")
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid webhook-timestamp %q", timestamp)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, "whsec_"))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid webhook secret: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	// The header lists space separated "v1,<base64>" signatures, one per active secret
+	for _, signature := range strings.Fields(header.Get("webhook-signature")) {
+		version, value, ok := strings.Cut(signature, ",")
+		if !ok || version != "v1" {
+			continue
+		}
+		if decoded, err := base64.StdEncoding.DecodeString(value); err == nil && hmac.Equal(decoded, expected) {
+			return id, time.Unix(seconds, 0), nil
+		}
+	}
+	return "", time.Time{}, fmt.Errorf("no matching webhook signature")
+}
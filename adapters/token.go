@@ -0,0 +1,52 @@
+package adapters
+
+import (
+	"context"
+	"time"
+)
+
+// minTokenTTL keeps tokens valid long enough to survive clock skew when the
+// request deadline is close
+const minTokenTTL = time.Minute
+
+// TokenLifetime bounds the lifetime of auth tokens that providers sign locally
+type TokenLifetime struct {
+	Min time.Duration // Lower bound, defaults to one minute
+	Max time.Duration // Upper bound, defaults to the provider's own lifetime
+}
+
+type tokenLifetimeKey struct{}
+
+// WithTokenLifetime returns a context whose locally signed tokens follow lifetime
+func WithTokenLifetime(ctx context.Context, lifetime TokenLifetime) context.Context {
+	return context.WithValue(ctx, tokenLifetimeKey{}, lifetime)
+}
+
+// TokenTTL returns the lifetime for a token signed under ctx. Without a
+// TokenLifetime it returns fallback; otherwise the time left until the ctx
+// deadline, clamped to the lifetime bounds.
+func TokenTTL(ctx context.Context, fallback time.Duration) time.Duration {
+	lifetime, ok := ctx.Value(tokenLifetimeKey{}).(TokenLifetime)
+	if !ok {
+		return fallback
+	}
+
+	ttl := fallback
+	if lifetime.Max > 0 {
+		ttl = lifetime.Max
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	floor := lifetime.Min
+	if floor <= 0 {
+		floor = minTokenTTL
+	}
+	if ttl < floor {
+		ttl = floor
+	}
+	return ttl
+}
@@ -0,0 +1,48 @@
+package adapters
+
+import (
+	"strings"
+	"sync"
+)
+
+// RequestTransformer rewrites a provider-native payload just before it is marshaled
+type RequestTransformer func(providerPayload any) any
+
+var (
+	transformMu         sync.RWMutex
+	requestTransformers = map[string][]RequestTransformer{}
+)
+
+// RegisterRequestTransformer registers a transformer for the given provider (e.g. "kling").
+// Transformers run in registration order; each receives the output of the previous one.
+func RegisterRequestTransformer(provider string, fn RequestTransformer) {
+	if fn == nil {
+		return
+	}
+
+	transformMu.Lock()
+	defer transformMu.Unlock()
+
+	key := strings.ToLower(provider)
+	requestTransformers[key] = append(requestTransformers[key], fn)
+}
+
+// TransformRequest applies all transformers registered for the provider to the payload
+func TransformRequest(provider string, payload any) any {
+	transformMu.RLock()
+	fns := requestTransformers[strings.ToLower(provider)]
+	transformMu.RUnlock()
+
+	for _, fn := range fns {
+		payload = fn(payload)
+	}
+	return payload
+}
+
+// ClearRequestTransformers removes all transformers registered for the provider
+func ClearRequestTransformers(provider string) {
+	transformMu.Lock()
+	defer transformMu.Unlock()
+
+	delete(requestTransformers, strings.ToLower(provider))
+}
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -35,9 +36,14 @@ const (
 // GenerationRequest represents a video generation request
 type GenerationRequest struct {
 	Prompt         string                 `json:"prompt,omitempty"`
+	NegativePrompt string                 `json:"negative_prompt,omitempty"`
 	Image          string                 `json:"image,omitempty"`
+	ImageTail      string                 `json:"image_tail,omitempty"` // End frame, URL, Base64 or data URI
+	Images         []string               `json:"images,omitempty"`     // Reference images for multi-image to video
+	Keyframes      []Keyframe             `json:"keyframes,omitempty"`  // Timed frames, see EdgeKeyframes
+	ImageBytes     []byte                 `json:"-"`                    // Raw image data, mutually exclusive with Image
 	Style          string                 `json:"style,omitempty"`
-	Mode           string                 `json:"mode,omitempty"` // Mode: "std" or "pro", defaults to "std"
+	Mode           string                 `json:"mode,omitempty"` // ModeStandard or ModePro, see ResolveMode
 	Duration       float64                `json:"duration"`
 	FPS            int                    `json:"fps,omitempty"`
 	Width          int                    `json:"width"`
@@ -45,10 +51,110 @@ type GenerationRequest struct {
 	ResponseFormat ResponseFormat         `json:"response_format,omitempty"`
 	QualityLevel   QualityLevel           `json:"quality_level,omitempty"`
 	Seed           *int                   `json:"seed,omitempty"`
+	GuidanceScale  *float64               `json:"guidance_scale,omitempty"` // Prompt adherence in [0, 1], provider default when nil
 	Model          string                 `json:"model,omitempty"`
+	CameraControl  *CameraControl         `json:"camera_control,omitempty"`
+	MotionBrush    *MotionBrush           `json:"motion_brush,omitempty"`
+	CallbackURL    string                 `json:"callback_url,omitempty"`   // Called by the provider on task completion
+	ClientTaskID   string                 `json:"client_task_id,omitempty"` // Caller's own ID for the task, unique per account
+	Watermark      WatermarkPolicy        `json:"watermark,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// CameraControlType selects a camera movement: simple, configured by CameraConfig,
+// or one of the preset movements
+type CameraControlType string
+
+const (
+	CameraControlSimple           CameraControlType = "simple"
+	CameraControlDownBack         CameraControlType = "down_back"
+	CameraControlForwardUp        CameraControlType = "forward_up"
+	CameraControlRightTurnForward CameraControlType = "right_turn_forward"
+	CameraControlLeftTurnForward  CameraControlType = "left_turn_forward"
+)
+
+// CameraControl describes the camera movement of a generated video
+type CameraControl struct {
+	Type   CameraControlType `json:"type"`
+	Config *CameraConfig     `json:"config,omitempty"` // Required for simple, not allowed for presets
+}
+
+// CameraConfig sets a simple camera movement; exactly one field must be
+// non-zero, within [-10, 10]
+type CameraConfig struct {
+	Horizontal float64 `json:"horizontal"`
+	Vertical   float64 `json:"vertical"`
+	Pan        float64 `json:"pan"`
+	Tilt       float64 `json:"tilt"`
+	Roll       float64 `json:"roll"`
+	Zoom       float64 `json:"zoom"`
+}
+
+// MotionBrush marks regions of the input image that stay still or move along
+// trajectories. Masks are URLs, Base64 or data URIs matching the image size.
+type MotionBrush struct {
+	StaticMask   string        `json:"static_mask,omitempty"`
+	DynamicMasks []DynamicMask `json:"dynamic_masks,omitempty"`
+}
+
+// DynamicMask moves the masked region along a trajectory
+type DynamicMask struct {
+	Mask         string            `json:"mask"`
+	Trajectories []TrajectoryPoint `json:"trajectories"`
+}
+
+// TrajectoryPoint is a pixel position in the input image, origin at the bottom left
+type TrajectoryPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Validate checks the movement type and its config
+func (c *CameraControl) Validate() error {
+	switch c.Type {
+	case CameraControlSimple:
+		if c.Config == nil {
+			return fmt.Errorf("camera control %q requires a config", c.Type)
+		}
+		return c.Config.validate()
+	case CameraControlDownBack, CameraControlForwardUp, CameraControlRightTurnForward, CameraControlLeftTurnForward:
+		if c.Config != nil {
+			return fmt.Errorf("camera control %q does not take a config", c.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported camera control type: %q", c.Type)
+	}
+}
+
+// validate checks that exactly one movement is set and within range
+func (c *CameraConfig) validate() error {
+	values := []struct {
+		name  string
+		value float64
+	}{
+		{"horizontal", c.Horizontal},
+		{"vertical", c.Vertical},
+		{"pan", c.Pan},
+		{"tilt", c.Tilt},
+		{"roll", c.Roll},
+		{"zoom", c.Zoom},
+	}
+	set := 0
+	for _, v := range values {
+		if v.value < -10 || v.value > 10 {
+			return fmt.Errorf("camera config %s must be between -10 and 10, got %v", v.name, v.value)
+		}
+		if v.value != 0 {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("camera config must set exactly one movement, got %d", set)
+	}
+	return nil
+}
+
 // GenerationResponse represents the response from creating a generation task
 type GenerationResponse struct {
 	TaskID string     `json:"task_id"`
@@ -63,6 +169,46 @@ type TaskResult struct {
 	Format   string     `json:"format,omitempty"`
 	Metadata *Metadata  `json:"metadata,omitempty"`
 	Error    *TaskError `json:"error,omitempty"`
+
+	// ClientTaskID is the GenerationRequest.ClientTaskID the task was submitted with
+	ClientTaskID string `json:"client_task_id,omitempty"`
+
+	// Assets lists the output files with their expiry, when the provider knows it.
+	// Providers may leave it empty; URL is then reported as a video asset.
+	Assets []ResultAsset `json:"assets,omitempty"`
+
+	// Videos lists every generated video, URL being the first. Providers that
+	// return a single video may leave it empty.
+	Videos []VideoOutput `json:"videos,omitempty"`
+
+	// Cancellation is set on failed tasks the provider reports as canceled
+	Cancellation *Cancellation `json:"cancellation,omitempty"`
+
+	// Usage is what the task was billed, when the provider reports it
+	Usage *Usage `json:"usage,omitempty"`
+
+	// RawResponse holds the undecoded provider response the result was parsed from
+	RawResponse []byte `json:"-"`
+}
+
+// VideoOutput is one video generated by a task
+type VideoOutput struct {
+	ID       string  `json:"id,omitempty"` // Provider video ID, e.g. for Kling extensions
+	URL      string  `json:"url"`
+	Duration float64 `json:"duration,omitempty"`
+	Format   string  `json:"format,omitempty"`
+
+	// WatermarkURL is a watermarked copy of the video, when the provider returns one
+	WatermarkURL string `json:"watermark_url,omitempty"`
+}
+
+// Usage is the billing information a provider reports for a task. Fields the
+// provider does not report are zero.
+type Usage struct {
+	Credits       float64 `json:"credits,omitempty"`        // Account credits or units deducted
+	UnitPrice     float64 `json:"unit_price,omitempty"`     // Price per billed second
+	Currency      string  `json:"currency,omitempty"`       // Currency of UnitPrice, e.g. "CNY"
+	BilledSeconds float64 `json:"billed_seconds,omitempty"` // Video or compute seconds billed
 }
 
 // Metadata contains video metadata information
@@ -81,6 +227,21 @@ type TaskError struct {
 	Message string `json:"message"`
 }
 
+// TaskNotFoundError is returned by GetGeneration when the provider no longer knows a
+// task. Expired is set when the provider reports the task record has expired.
+type TaskNotFoundError struct {
+	TaskID  string
+	Expired bool
+	Message string
+}
+
+func (e *TaskNotFoundError) Error() string {
+	if e.Expired {
+		return fmt.Sprintf("task %s expired: %s", e.TaskID, e.Message)
+	}
+	return fmt.Sprintf("task %s not found: %s", e.TaskID, e.Message)
+}
+
 // ProviderConfig holds configuration for a specific provider
 type ProviderConfig struct {
 	BaseURL    string            `json:"base_url"`
@@ -89,6 +250,16 @@ type ProviderConfig struct {
 	Timeout    time.Duration     `json:"timeout"`
 	RetryCount int               `json:"retry_count"`
 	Extra      map[string]string `json:"extra,omitempty"`
+
+	// AllowUnknownExtra accepts Extra keys the provider does not define, which are otherwise rejected
+	AllowUnknownExtra bool `json:"allow_unknown_extra,omitempty"`
+
+	// APIVersion selects version-aware payload building for providers whose fields changed over time
+	APIVersion string `json:"api_version,omitempty"`
+
+	// Endpoints overrides endpoint path templates keyed by task type ("*" applies to all).
+	// Templates may use the {version} and {task_type} placeholders.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
 }
 
 // Provider interface that all adapters must implement
@@ -99,3 +270,16 @@ type Provider interface {
 	SupportedModels() []string
 	ValidateRequest(req *GenerationRequest) error
 }
+
+// ConfigReporter is implemented by providers that can report their configuration
+// with defaults, such as the base URL and timeout, applied
+type ConfigReporter interface {
+	EffectiveConfig() *ProviderConfig
+}
+
+// TaskStateInheritor is implemented by providers that keep per-task state in
+// memory, so a provider rebuilt from a new configuration can keep polling tasks
+// submitted through the previous one
+type TaskStateInheritor interface {
+	InheritTaskState(from Provider)
+}
@@ -0,0 +1,158 @@
+package veo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	cloudScope      = "https://www.googleapis.com/auth/cloud-platform"
+
+	// tokenRefreshMargin refreshes cached tokens before they expire
+	tokenRefreshMargin = time.Minute
+)
+
+// ServiceAccount holds the fields of a Google service account key file used for auth
+type ServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// parseServiceAccount parses a service account key file
+func parseServiceAccount(data []byte) (*ServiceAccount, error) {
+	var account ServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account JSON: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("service account JSON requires client_email and private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = defaultTokenURL
+	}
+	return &account, nil
+}
+
+// tokenSource returns OAuth access tokens, either a static token or one exchanged
+// from a service account and cached until shortly before it expires
+type tokenSource struct {
+	static  string
+	account *ServiceAccount
+	client  *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newTokenSource builds a token source from a credential that is either a
+// service account JSON document or an OAuth access token
+func newTokenSource(credential string, client *http.Client) (*tokenSource, error) {
+	credential = strings.TrimSpace(credential)
+	if !strings.HasPrefix(credential, "{") {
+		return &tokenSource{static: credential}, nil
+	}
+
+	account, err := parseServiceAccount([]byte(credential))
+	if err != nil {
+		return nil, err
+	}
+	return &tokenSource{account: account, client: client}, nil
+}
+
+// Token returns a valid access token, exchanging a new one when the cached token expires
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	if s.account == nil {
+		return s.static, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Add(tokenRefreshMargin).Before(s.expires) {
+		return s.token, nil
+	}
+
+	token, expiresIn, err := s.exchange(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expires = time.Now().Add(expiresIn)
+	return token, nil
+}
+
+// exchange signs a JWT assertion with the service account key and exchanges it for an access token
+func (s *tokenSource) exchange(ctx context.Context) (string, time.Duration, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(s.account.PrivateKey))
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid service account private key: %w", err)
+	}
+
+	now := time.Now().Unix()
+	claims := jwt.MapClaims{
+		"iss":   s.account.ClientEmail,
+		"scope": cloudScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now,
+		"exp":   now + 3600,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if s.account.PrivateKeyID != "" {
+		token.Header["kid"] = s.account.PrivateKeyID
+	}
+	assertion, err := token.SignedString(key)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to sign token assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", 0, fmt.Errorf("token exchange failed %d: %s", resp.StatusCode, body)
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+	return tokenResp.AccessToken, time.Duration(tokenResp.ExpiresIn) * time.Second, nil
+}
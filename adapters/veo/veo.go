@@ -0,0 +1,449 @@
+package veo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/models"
+)
+
+// Provider implements the adapters.Provider interface for Google Veo on Vertex AI
+type Provider struct {
+	config     *adapters.ProviderConfig
+	client     *http.Client
+	baseURL    string
+	projectID  string
+	location   string
+	storageURI string
+	tokens     *tokenSource
+}
+
+// VeoRequest represents Vertex AI's predictLongRunning request format
+type VeoRequest struct {
+	Instances  []VeoInstance `json:"instances"`
+	Parameters VeoParameters `json:"parameters"`
+}
+
+// VeoInstance is a single generation input
+type VeoInstance struct {
+	Prompt string    `json:"prompt,omitempty"`
+	Image  *VeoImage `json:"image,omitempty"`
+}
+
+// VeoImage is an input image given inline or as a Cloud Storage URI
+type VeoImage struct {
+	BytesBase64Encoded string `json:"bytesBase64Encoded,omitempty"`
+	GcsURI             string `json:"gcsUri,omitempty"`
+	MimeType           string `json:"mimeType,omitempty"`
+}
+
+// VeoParameters holds the generation parameters
+type VeoParameters struct {
+	AspectRatio     string `json:"aspectRatio,omitempty"`
+	DurationSeconds int    `json:"durationSeconds,omitempty"`
+	SampleCount     int    `json:"sampleCount,omitempty"`
+	NegativePrompt  string `json:"negativePrompt,omitempty"`
+	Seed            *int   `json:"seed,omitempty"`
+	StorageURI      string `json:"storageUri,omitempty"`
+}
+
+// VeoOperation represents a Vertex AI long-running operation
+type VeoOperation struct {
+	Name     string            `json:"name"`
+	Done     bool              `json:"done"`
+	Error    *VeoStatus        `json:"error,omitempty"`
+	Response *VeoPredictResult `json:"response,omitempty"`
+}
+
+// VeoStatus represents a google.rpc.Status error
+type VeoStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// VeoPredictResult holds the generated videos of a finished operation
+type VeoPredictResult struct {
+	Videos                  []VeoVideo `json:"videos"`
+	RaiMediaFilteredCount   int        `json:"raiMediaFilteredCount,omitempty"`
+	RaiMediaFilteredReasons []string   `json:"raiMediaFilteredReasons,omitempty"`
+}
+
+// VeoVideo is a generated video stored in Cloud Storage or returned inline
+type VeoVideo struct {
+	GcsURI             string `json:"gcsUri,omitempty"`
+	BytesBase64Encoded string `json:"bytesBase64Encoded,omitempty"`
+	MimeType           string `json:"mimeType,omitempty"`
+}
+
+// catalog describes the Veo models and their durations, see package models
+var catalog = models.List("veo")
+
+var supportedModels = catalog.Names()
+
+// extraSchema lists the Extra keys Veo understands
+var extraSchema = adapters.ExtraSchema{
+	{Name: "credentials_file", Description: "Path to a service account key JSON file"},
+	{Name: "project_id", Description: "Google Cloud project, defaults to the service account project"},
+	{Name: "location", Description: "Vertex AI region, defaults to us-central1"},
+	{Name: "storage_uri", Description: "gs:// prefix to write videos to instead of returning them inline", Validate: func(value string) error {
+		if !strings.HasPrefix(value, "gs://") {
+			return fmt.Errorf("must start with gs://")
+		}
+		return nil
+	}},
+}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
+func init() {
+	adapters.Register("veo", New)
+}
+
+// New creates a new Veo provider instance.
+// APIKey is either a service account key JSON or an OAuth access token; a key file
+// can instead be given as Extra["credentials_file"]. Extra["project_id"] defaults to
+// the service account project and Extra["location"] to us-central1.
+func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("invalid configuration")
+	}
+
+	if err := extraSchema.Validate("Veo", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	credential := config.APIKey
+	if path := config.Extra["credentials_file"]; path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		credential = string(data)
+	}
+	if strings.TrimSpace(credential) == "" {
+		return nil, fmt.Errorf("service account JSON or access token is required for Veo")
+	}
+
+	tokens, err := newTokenSource(credential, client)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := config.Extra["project_id"]
+	if projectID == "" && tokens.account != nil {
+		projectID = tokens.account.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("project_id is required for Veo")
+	}
+
+	location := config.Extra["location"]
+	if location == "" {
+		location = "us-central1"
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://%s-aiplatform.googleapis.com", location)
+	}
+
+	return &Provider{
+		config:     config,
+		client:     client,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		projectID:  projectID,
+		location:   location,
+		storageURI: config.Extra["storage_uri"],
+		tokens:     tokens,
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Veo"
+}
+
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
+// SupportedModels returns supported models
+func (p *Provider) SupportedModels() []string {
+	return append([]string{}, supportedModels...)
+}
+
+// Warm opens a connection to Vertex AI and fetches an access token, refreshing it
+// when it is close to expiry
+func (p *Provider) Warm(ctx context.Context) error {
+	if _, err := p.tokens.Token(ctx); err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
+}
+
+// ValidateRequest validates the request for Veo
+func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
+	if err := adapters.ValidateImages("Veo", req.Images, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateKeyframes("Veo", req.Keyframes, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Veo", req.CallbackURL, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("Veo", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateWatermark("Veo", req.Watermark); err != nil {
+		return err
+	}
+
+	info, ok := catalog.Lookup(req.Model)
+	if !ok {
+		return fmt.Errorf("unsupported model: %s", req.Model)
+	}
+	if !info.SupportsDuration("", req.Duration) {
+		return fmt.Errorf("model %s does not support %.0fs duration", info.Name, req.Duration)
+	}
+
+	if strings.HasPrefix(req.Image, "http://") || strings.HasPrefix(req.Image, "https://") {
+		if _, ok := gcsURI(req.Image); !ok {
+			return fmt.Errorf("Veo images must be inline, a gs:// URI or a storage.googleapis.com URL")
+		}
+	}
+
+	return nil
+}
+
+// CreateGeneration starts a predictLongRunning operation; the operation name is the task ID
+func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = supportedModels[0]
+	}
+
+	veoReq := p.convertToVeoRequest(req)
+
+	var operation VeoOperation
+	if _, err := p.do(ctx, p.modelPath(model)+":predictLongRunning", adapters.TransformRequest("veo", veoReq), &operation); err != nil {
+		return nil, err
+	}
+
+	return &adapters.GenerationResponse{
+		TaskID: operation.Name,
+		Status: adapters.TaskStatusQueued,
+	}, nil
+}
+
+// GetGeneration fetches the long-running operation and maps it to a task result
+func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	modelPath, _, ok := strings.Cut(taskID, "/operations/")
+	if !ok {
+		return nil, fmt.Errorf("invalid Veo operation name: %s", taskID)
+	}
+
+	var operation VeoOperation
+	body, err := p.do(ctx, "/v1/"+modelPath+":fetchPredictOperation", map[string]string{"operationName": taskID}, &operation)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &adapters.TaskResult{
+		TaskID:      taskID,
+		Status:      adapters.TaskStatusProcessing,
+		RawResponse: body,
+	}
+
+	if !operation.Done {
+		return result, nil
+	}
+
+	if operation.Error != nil {
+		result.Status = adapters.TaskStatusFailed
+		result.Error = &adapters.TaskError{Code: operation.Error.Code, Message: operation.Error.Message}
+		return result, nil
+	}
+
+	if operation.Response == nil || len(operation.Response.Videos) == 0 {
+		result.Status = adapters.TaskStatusFailed
+		message := "operation finished without videos"
+		if operation.Response != nil && operation.Response.RaiMediaFilteredCount > 0 {
+			message = "video filtered by safety policy: " + strings.Join(operation.Response.RaiMediaFilteredReasons, "; ")
+		}
+		result.Error = &adapters.TaskError{Code: http.StatusUnprocessableEntity, Message: message}
+		return result, nil
+	}
+
+	result.Status = adapters.TaskStatusSucceeded
+	result.Format = "mp4"
+	for _, video := range operation.Response.Videos {
+		result.Videos = append(result.Videos, adapters.VideoOutput{URL: videoURL(video), Format: "mp4"})
+	}
+	result.URL = result.Videos[0].URL
+
+	return result, nil
+}
+
+// videoURL returns the Cloud Storage URI of a video, or a data URI for inline bytes
+func videoURL(video VeoVideo) string {
+	if video.GcsURI != "" || video.BytesBase64Encoded == "" {
+		return video.GcsURI
+	}
+	mimeType := video.MimeType
+	if mimeType == "" {
+		mimeType = "video/mp4"
+	}
+	return "data:" + mimeType + ";base64," + video.BytesBase64Encoded
+}
+
+// convertToVeoRequest converts standard request to Veo format
+func (p *Provider) convertToVeoRequest(req *adapters.GenerationRequest) *VeoRequest {
+	instance := VeoInstance{Prompt: req.Prompt}
+
+	if encoded := adapters.ImageBase64(req); encoded != "" {
+		instance.Image = &VeoImage{BytesBase64Encoded: encoded, MimeType: imageMimeType(req)}
+	} else if uri, ok := gcsURI(req.Image); ok {
+		instance.Image = &VeoImage{GcsURI: uri, MimeType: imageMimeType(req)}
+	} else if req.Image != "" {
+		instance.Image = &VeoImage{BytesBase64Encoded: req.Image, MimeType: "image/png"}
+	}
+
+	params := VeoParameters{
+		AspectRatio:     "16:9",
+		DurationSeconds: int(req.Duration),
+		SampleCount:     1,
+		Seed:            req.Seed,
+		StorageURI:      p.storageURI,
+		NegativePrompt:  adapters.NegativePrompt(req),
+	}
+	if req.Height > req.Width {
+		params.AspectRatio = "9:16"
+	}
+
+	if req.Metadata != nil {
+		if storageURI, ok := req.Metadata["storage_uri"].(string); ok && storageURI != "" {
+			params.StorageURI = storageURI
+		}
+	}
+
+	return &VeoRequest{Instances: []VeoInstance{instance}, Parameters: params}
+}
+
+// gcsURI returns the gs:// URI of a Cloud Storage object given as a gs:// URI or
+// as a storage.googleapis.com URL, such as the URLs storage.GCS uploads return
+func gcsURI(image string) (string, bool) {
+	if strings.HasPrefix(image, "gs://") {
+		return image, true
+	}
+	if object, ok := strings.CutPrefix(image, "https://storage.googleapis.com/"); ok && strings.Contains(object, "/") {
+		if path, err := url.PathUnescape(object); err == nil {
+			object = path
+		}
+		return "gs://" + object, true
+	}
+	return "", false
+}
+
+// imageMimeType detects the MIME type of an inline image, defaulting to PNG
+func imageMimeType(req *adapters.GenerationRequest) string {
+	if len(req.ImageBytes) > 0 {
+		return http.DetectContentType(req.ImageBytes)
+	}
+	if _, mediaType, ok := adapters.DecodeDataURI(req.Image); ok && mediaType != "" {
+		return mediaType
+	}
+	if strings.HasSuffix(strings.ToLower(req.Image), ".jpg") || strings.HasSuffix(strings.ToLower(req.Image), ".jpeg") {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// modelPath returns the publisher model resource path
+func (p *Provider) modelPath(model string) string {
+	return fmt.Sprintf("/v1/projects/%s/locations/%s/publishers/google/models/%s", p.projectID, p.location, model)
+}
+
+// do posts a JSON body with an OAuth bearer token and decodes the response into out
+func (p *Provider) do(ctx context.Context, path string, body interface{}, out interface{}) ([]byte, error) {
+	baseURL, tokens := p.baseURL, p.tokens
+	if overrides := adapters.RequestOverridesFromContext(ctx); overrides != nil {
+		if overrides.BaseURL != "" {
+			baseURL = strings.TrimRight(overrides.BaseURL, "/")
+		}
+		if overrides.APIKey != "" {
+			var err error
+			if tokens, err = newTokenSource(overrides.APIKey, p.client); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	token, err := tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
+
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error VeoStatus `json:"error"`
+		}
+		json.Unmarshal(respBody, &apiErr)
+		return nil, adapters.NewAPIError(resp.StatusCode, "", apiErr.Error.Message)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return respBody, nil
+}
@@ -12,12 +12,27 @@ type Provider struct {
 	config *adapters.ProviderConfig
 }
 
+// MaxReferenceImages is the maximum number of reference images Vidu accepts
+const MaxReferenceImages = 3
+
+// extraSchema lists the Extra keys Vidu understands; it has none yet
+var extraSchema = adapters.ExtraSchema{}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
 // New creates a new Vidu provider instance
 func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
 	if config == nil {
 		return nil, fmt.Errorf("invalid configuration")
 	}
 
+	if err := extraSchema.Validate("Vidu", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
 	return &Provider{
 		config: config,
 	}, nil
@@ -36,7 +51,19 @@ func (p *Provider) SupportedModels() []string {
 // ValidateRequest validates the request for Vidu
 func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
 	// TODO: Implement Vidu-specific validation
-	return nil
+	if err := adapters.ValidateImages("Vidu", req.Images, MaxReferenceImages); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Vidu", req.CallbackURL, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("Vidu", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateKeyframes("Vidu", req.Keyframes, 0); err != nil {
+		return err
+	}
+	return adapters.ValidateWatermark("Vidu", req.Watermark)
 }
 
 // CreateGeneration creates a video generation task
@@ -0,0 +1,455 @@
+package wanx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/models"
+)
+
+// Provider implements the adapters.Provider interface for Alibaba Tongyi Wanxiang (DashScope)
+type Provider struct {
+	config  *adapters.ProviderConfig
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+const synthesisPath = "/api/v1/services/aigc/video-generation/video-synthesis"
+
+// MaxNegativePromptLength is the longest negative prompt Wanx accepts, in characters
+const MaxNegativePromptLength = 500
+
+// WanxRequest represents DashScope's video synthesis request format
+type WanxRequest struct {
+	Model      string         `json:"model"`
+	Input      WanxInput      `json:"input"`
+	Parameters WanxParameters `json:"parameters"`
+}
+
+// WanxInput holds the prompt and optional first frame image
+type WanxInput struct {
+	Prompt         string `json:"prompt,omitempty"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	ImgURL         string `json:"img_url,omitempty"`
+}
+
+// WanxParameters holds the generation parameters. Text-to-video uses Size,
+// image-to-video uses Resolution.
+type WanxParameters struct {
+	Size         string `json:"size,omitempty"`
+	Resolution   string `json:"resolution,omitempty"`
+	Duration     int    `json:"duration,omitempty"`
+	PromptExtend *bool  `json:"prompt_extend,omitempty"`
+	Seed         *int   `json:"seed,omitempty"`
+	Watermark    *bool  `json:"watermark,omitempty"`
+}
+
+// WanxResponse represents DashScope's task response
+type WanxResponse struct {
+	RequestID string     `json:"request_id"`
+	Output    WanxOutput `json:"output"`
+	Usage     *WanxUsage `json:"usage,omitempty"`
+	Code      string     `json:"code,omitempty"`
+	Message   string     `json:"message,omitempty"`
+}
+
+// WanxOutput represents the output field of a DashScope task
+type WanxOutput struct {
+	TaskID     string `json:"task_id"`
+	TaskStatus string `json:"task_status"`
+	VideoURL   string `json:"video_url,omitempty"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// WanxUsage is the billing usage DashScope reports for finished tasks
+type WanxUsage struct {
+	VideoDuration float64 `json:"video_duration"`
+	VideoCount    int     `json:"video_count"`
+	VideoRatio    string  `json:"video_ratio,omitempty"`
+}
+
+// i2vModels maps a text-to-video model to its image-to-video counterpart
+var i2vModels = map[string]string{
+	"wanx2.1-t2v-turbo": "wanx2.1-i2v-turbo",
+	"wanx2.1-t2v-plus":  "wanx2.1-i2v-plus",
+}
+
+// catalog describes the Wanx models, see package models
+var catalog = models.List("wanx")
+
+var supportedModels = catalog.Names()
+
+// t2vSizes lists the supported text-to-video sizes (width*height)
+var t2vSizes = []string{"1280*720", "720*1280", "960*960", "1088*832", "832*1088", "832*480", "480*832", "624*624"}
+
+// extraSchema lists the Extra keys Wanx understands; it has none
+var extraSchema = adapters.ExtraSchema{}
+
+// ExtraKeys returns the ProviderConfig.Extra keys this provider understands
+func ExtraKeys() adapters.ExtraSchema {
+	return append(adapters.ExtraSchema{}, extraSchema...)
+}
+
+func init() {
+	adapters.Register("wanx", New)
+}
+
+// New creates a new Wanx provider instance
+func New(config *adapters.ProviderConfig) (adapters.Provider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("invalid configuration")
+	}
+
+	if err := extraSchema.Validate("Wanx", config.Extra, config.AllowUnknownExtra); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(config.APIKey) == "" {
+		return nil, fmt.Errorf("API key is required for Wanx")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://dashscope.aliyuncs.com"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &Provider{
+		config:  config,
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  strings.TrimSpace(config.APIKey),
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Wanx"
+}
+
+// EffectiveConfig returns the configuration with the provider defaults applied
+func (p *Provider) EffectiveConfig() *adapters.ProviderConfig {
+	config := *p.config
+	config.BaseURL = p.baseURL
+	config.Timeout = p.client.Timeout
+	return &config
+}
+
+// SupportedModels returns supported models
+func (p *Provider) SupportedModels() []string {
+	return append([]string{}, supportedModels...)
+}
+
+// Capabilities returns the Wanx clip lengths and combinations. Image-to-video
+// models follow the aspect ratio of the input image.
+func (p *Provider) Capabilities() *adapters.Capabilities {
+	return &adapters.Capabilities{
+		Duration:     &adapters.DurationConstraints{Durations: catalog.Durations()},
+		Combinations: adapters.CatalogMatrix(catalog),
+	}
+}
+
+// Warm opens a connection to the DashScope API
+func (p *Provider) Warm(ctx context.Context) error {
+	return adapters.WarmConnection(ctx, p.client, p.baseURL)
+}
+
+// ValidateRequest validates the request for Wanx
+func (p *Provider) ValidateRequest(req *adapters.GenerationRequest) error {
+	info, ok := catalog.Lookup(req.Model)
+	if !ok {
+		return fmt.Errorf("unsupported model: %s", req.Model)
+	}
+	if !info.SupportsDuration("", req.Duration) {
+		return fmt.Errorf("Wanx only supports %s duration", models.FormatDurations(info.Durations))
+	}
+
+	if err := adapters.ValidateNegativePrompt("Wanx", adapters.NegativePrompt(req), MaxNegativePromptLength); err != nil {
+		return err
+	}
+	if err := adapters.ValidateImages("Wanx", req.Images, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateKeyframes("Wanx", req.Keyframes, 0); err != nil {
+		return err
+	}
+	if err := adapters.ValidateCallbackURL("Wanx", req.CallbackURL, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateClientTaskID("Wanx", req.ClientTaskID, false); err != nil {
+		return err
+	}
+	if err := adapters.ValidateWatermark("Wanx", req.Watermark, adapters.WatermarkNone, adapters.WatermarkVisible); err != nil {
+		return err
+	}
+
+	if strings.Contains(req.Model, "-i2v-") && req.Image == "" && len(req.ImageBytes) == 0 {
+		return fmt.Errorf("model %s requires an image", req.Model)
+	}
+
+	return nil
+}
+
+// CreateGeneration submits an asynchronous video synthesis task
+func (p *Provider) CreateGeneration(ctx context.Context, req *adapters.GenerationRequest) (*adapters.GenerationResponse, error) {
+	wanxReq := p.convertToWanxRequest(req)
+
+	var wanxResp WanxResponse
+	if _, err := p.do(ctx, "POST", synthesisPath, adapters.TransformRequest("wanx", wanxReq), &wanxResp); err != nil {
+		return nil, err
+	}
+
+	return &adapters.GenerationResponse{
+		TaskID: wanxResp.Output.TaskID,
+		Status: p.convertStatus(wanxResp.Output.TaskStatus),
+	}, nil
+}
+
+// GetGeneration retrieves the task status
+func (p *Provider) GetGeneration(ctx context.Context, taskID string) (*adapters.TaskResult, error) {
+	var wanxResp WanxResponse
+	body, err := p.do(ctx, "GET", "/api/v1/tasks/"+taskID, nil, &wanxResp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &adapters.TaskResult{
+		TaskID:      taskID,
+		Status:      p.convertStatus(wanxResp.Output.TaskStatus),
+		RawResponse: body,
+	}
+
+	if wanxResp.Output.VideoURL != "" {
+		result.URL = wanxResp.Output.VideoURL
+		result.Format = "mp4"
+	}
+	if usage := wanxResp.Usage; usage != nil && usage.VideoDuration > 0 {
+		count := usage.VideoCount
+		if count < 1 {
+			count = 1
+		}
+		result.Usage = &adapters.Usage{BilledSeconds: usage.VideoDuration * float64(count)}
+	}
+
+	if result.Status == adapters.TaskStatusFailed {
+		message := wanxResp.Output.Message
+		if wanxResp.Output.Code != "" {
+			message = wanxResp.Output.Code + ": " + message
+		}
+		result.Error = &adapters.TaskError{Code: http.StatusInternalServerError, Message: message}
+	}
+	if wanxResp.Output.TaskStatus == "CANCELED" {
+		result.Cancellation = &adapters.Cancellation{Reason: adapters.CancellationProvider, Message: result.Error.Message}
+	}
+
+	return result, nil
+}
+
+// CancelGeneration cancels a task; DashScope only cancels tasks still PENDING
+func (p *Provider) CancelGeneration(ctx context.Context, taskID string) error {
+	var wanxResp WanxResponse
+	_, err := p.do(ctx, "POST", "/api/v1/tasks/"+taskID+"/cancel", nil, &wanxResp)
+	return err
+}
+
+// convertToWanxRequest converts standard request to Wanx format
+func (p *Provider) convertToWanxRequest(req *adapters.GenerationRequest) *WanxRequest {
+	model := req.Model
+	if model == "" {
+		model = "wanx2.1-t2v-turbo"
+	}
+
+	wanxReq := &WanxRequest{
+		Model: model,
+		Input: WanxInput{Prompt: req.Prompt, NegativePrompt: adapters.NegativePrompt(req)},
+		Parameters: WanxParameters{
+			Duration: int(req.Duration),
+			Seed:     req.Seed,
+		},
+	}
+
+	// 带图片时切换到对应的图生视频模型
+	image := req.Image
+	if encoded := adapters.ImageBase64(req); encoded != "" {
+		image = "data:" + imageMediaType(req) + ";base64," + encoded
+	}
+	if image != "" {
+		if i2v, ok := i2vModels[model]; ok {
+			wanxReq.Model = i2v
+		}
+		wanxReq.Input.ImgURL = image
+		wanxReq.Parameters.Resolution = p.getResolution(wanxReq.Model, req)
+	} else {
+		wanxReq.Parameters.Size = p.getSize(wanxReq.Model, req.Width, req.Height)
+	}
+
+	// watermark为true时在右下角添加"AI生成"水印
+	if req.Watermark != adapters.WatermarkDefault {
+		watermark := req.Watermark == adapters.WatermarkVisible
+		wanxReq.Parameters.Watermark = &watermark
+	}
+
+	if req.Metadata != nil {
+		if extend, ok := req.Metadata["prompt_extend"].(bool); ok {
+			wanxReq.Parameters.PromptExtend = &extend
+		}
+	}
+
+	return wanxReq
+}
+
+// getSize picks the text-to-video size closest in aspect ratio, then area,
+// among the sizes of the resolutions the model renders
+func (p *Provider) getSize(model string, width, height int) string {
+	if width <= 0 || height <= 0 {
+		return "1280*720"
+	}
+	ratio := float64(width) / float64(height)
+	area := float64(width * height)
+	info, known := catalog.Lookup(model)
+
+	best, bestScore := t2vSizes[0], -1.0
+	for _, size := range t2vSizes {
+		var w, h float64
+		fmt.Sscanf(size, "%f*%f", &w, &h)
+		if known && !info.SupportsResolution(sizeResolution(w, h)) {
+			continue
+		}
+		ratioDiff := ratio - w/h
+		if ratioDiff < 0 {
+			ratioDiff = -ratioDiff
+		}
+		areaDiff := (area - w*h) / area
+		if areaDiff < 0 {
+			areaDiff = -areaDiff
+		}
+		score := ratioDiff*10 + areaDiff
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = size, score
+		}
+	}
+	return best
+}
+
+// sizeResolution returns the resolution tier of a text-to-video size
+func sizeResolution(width, height float64) string {
+	if width < 720 || height < 720 {
+		return "480p"
+	}
+	return "720p"
+}
+
+// getResolution maps quality level and size to an image-to-video resolution,
+// clamped to the resolutions the model renders
+func (p *Provider) getResolution(model string, req *adapters.GenerationRequest) string {
+	resolution := "720P"
+	switch {
+	case req.QualityLevel == adapters.QualityLevelLow:
+		resolution = "480P"
+	case req.QualityLevel == adapters.QualityLevelHigh:
+	case req.Width > 0 && req.Height > 0 && req.Width <= 832 && req.Height <= 832:
+		resolution = "480P"
+	}
+
+	// plus 模型只支持 720P
+	if info, ok := catalog.Lookup(model); ok && !info.SupportsResolution(resolution) && len(info.Resolutions) > 0 {
+		resolution = strings.ToUpper(info.Resolutions[len(info.Resolutions)-1])
+	}
+	return resolution
+}
+
+// imageMediaType returns the media type of an inline image
+func imageMediaType(req *adapters.GenerationRequest) string {
+	if _, mediaType, ok := adapters.DecodeDataURI(req.Image); ok && mediaType != "" {
+		return mediaType
+	}
+	return http.DetectContentType(req.ImageBytes)
+}
+
+// convertStatus converts DashScope task_status to standard status
+func (p *Provider) convertStatus(status string) adapters.TaskStatus {
+	switch status {
+	case "PENDING":
+		return adapters.TaskStatusQueued
+	case "RUNNING":
+		return adapters.TaskStatusProcessing
+	case "SUCCEEDED":
+		return adapters.TaskStatusSucceeded
+	case "FAILED", "CANCELED", "UNKNOWN":
+		return adapters.TaskStatusFailed
+	default:
+		return adapters.TaskStatusQueued
+	}
+}
+
+// do performs an authenticated request and decodes the JSON response into out.
+// Submissions carry the X-DashScope-Async header, which DashScope requires for video synthesis.
+func (p *Provider) do(ctx context.Context, method, path string, body interface{}, out *WanxResponse) ([]byte, error) {
+	baseURL, apiKey := p.baseURL, p.apiKey
+	if overrides := adapters.RequestOverridesFromContext(ctx); overrides != nil {
+		if overrides.BaseURL != "" {
+			baseURL = strings.TrimRight(overrides.BaseURL, "/")
+		}
+		if overrides.APIKey != "" {
+			apiKey = overrides.APIKey
+		}
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
+	adapters.SetRequestHeaders(ctx, req)
+	if path == synthesisPath {
+		req.Header.Set("X-DashScope-Async", "enable")
+	}
+
+	resp, err := adapters.HTTPClient(ctx, p.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		json.Unmarshal(respBody, out)
+		return nil, adapters.NewAPIError(resp.StatusCode, out.Code, out.Message)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,29 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Warmer is implemented by providers that can establish connections and auth tokens ahead of use
+type Warmer interface {
+	Warm(ctx context.Context) error
+}
+
+// WarmConnection opens a keep-alive connection to baseURL with a HEAD request so the
+// TLS handshake is not paid by the first real request. Any HTTP status counts as success.
+func WarmConnection(ctx context.Context, client *http.Client, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", baseURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	// 读完响应体，连接才会放回连接池复用
+	io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
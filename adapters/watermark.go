@@ -0,0 +1,31 @@
+package adapters
+
+import "fmt"
+
+// WatermarkPolicy controls the visible watermark on generated videos
+type WatermarkPolicy string
+
+const (
+	WatermarkDefault WatermarkPolicy = ""        // Provider default
+	WatermarkNone    WatermarkPolicy = "none"    // No visible watermark
+	WatermarkVisible WatermarkPolicy = "visible" // Provider's visible AI-generated mark
+)
+
+// Valid reports whether p is one of the known policies
+func (p WatermarkPolicy) Valid() bool {
+	return p == WatermarkDefault || p == WatermarkNone || p == WatermarkVisible
+}
+
+// ValidateWatermark rejects a policy the provider cannot honor; supported lists
+// the non-default policies it maps
+func ValidateWatermark(provider string, policy WatermarkPolicy, supported ...WatermarkPolicy) error {
+	if policy == WatermarkDefault {
+		return nil
+	}
+	for _, p := range supported {
+		if p == policy {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not support watermark policy %q", provider, policy)
+}
@@ -0,0 +1,134 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ResultArchive stores finished task results beyond the provider's retention period.
+// Implementations must be safe for concurrent use.
+type ResultArchive interface {
+	Archive(result *TaskResult) error
+	Archived(taskID string) (*TaskResult, bool)
+}
+
+// MemoryArchive is an in-process ResultArchive
+type MemoryArchive struct {
+	mu      sync.RWMutex
+	results map[string]TaskResult
+}
+
+// NewMemoryArchive creates an empty in-memory archive
+func NewMemoryArchive() *MemoryArchive {
+	return &MemoryArchive{results: make(map[string]TaskResult)}
+}
+
+// Archive stores a copy of result
+func (a *MemoryArchive) Archive(result *TaskResult) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.results[result.TaskID] = *result
+	return nil
+}
+
+// Archived returns a copy of an archived result
+func (a *MemoryArchive) Archived(taskID string) (*TaskResult, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	result, ok := a.results[taskID]
+	if !ok {
+		return nil, false
+	}
+	return &result, true
+}
+
+// archive stores finished results if an archive is configured
+func (c *Client) archive(result *TaskResult) {
+	if c.config.Archive == nil || result.TaskID == "" {
+		return
+	}
+	if result.Status != TaskStatusSucceeded && result.Status != TaskStatusFailed {
+		return
+	}
+	start := time.Now()
+	if err := c.config.Archive.Archive(result); err != nil {
+		c.recordTimeline(result.TaskID, failureEvent(err, time.Since(start)))
+		if c.config.Debug {
+			fmt.Printf("Failed to archive task %s: %v\n", result.TaskID, err)
+		}
+		return
+	}
+	c.recordTimeline(result.TaskID, TimelineEvent{Type: TimelineEventArchived, Duration: time.Since(start)})
+}
+
+// Storage uploads objects to durable storage and returns their URL; the storage
+// package implements it for S3, Aliyun OSS and GCS
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+}
+
+// ArchiveResult copies the video of a succeeded task to storage, since provider
+// URLs expire (after about 30 days for Kling), and returns its durable URL. The
+// video is downloaded like Download into a temporary file and stored under
+// "<task ID>.<format>".
+func (c *Client) ArchiveResult(ctx context.Context, result *TaskResult, storage Storage) (string, error) {
+	if storage == nil {
+		return "", fmt.Errorf("%w: no storage given", ErrInvalidConfiguration)
+	}
+	if result == nil || result.Status != TaskStatusSucceeded {
+		return "", fmt.Errorf("only succeeded tasks can be archived")
+	}
+
+	file, err := os.CreateTemp("", "vidgo-archive-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	info, err := c.downloadResult(ctx, result, fileTarget(file))
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read temporary file: %w", err)
+	}
+
+	format := info.Format
+	if format == "" {
+		format = "mp4"
+	}
+	start := time.Now()
+	url, err := storage.Put(ctx, result.TaskID+"."+format, file)
+	if err != nil {
+		c.recordTimeline(result.TaskID, failureEvent(err, time.Since(start)))
+		return "", fmt.Errorf("failed to store video: %w", err)
+	}
+	c.recordTimeline(result.TaskID, TimelineEvent{Type: TimelineEventArchived, Duration: time.Since(start), Message: url})
+	return url, nil
+}
+
+// taskNotFound handles a task the provider no longer knows. A task this client has
+// a timeline for did exist, so its disappearance is treated as expiry. The archived
+// result is returned when there is one.
+func (c *Client) taskNotFound(taskID string, err error) (*TaskResult, bool) {
+	var notFound *TaskNotFoundError
+	if !errors.As(err, &notFound) {
+		return nil, false
+	}
+	if !notFound.Expired && len(c.GetTimeline(taskID)) > 0 {
+		notFound.Expired = true
+	}
+
+	if c.config.Archive == nil {
+		return nil, false
+	}
+	return c.config.Archive.Archived(taskID)
+}
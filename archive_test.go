@@ -0,0 +1,130 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+	"github.com/feitianbubu/vidgo/storage"
+)
+
+func TestExpiredTaskServedFromArchive(t *testing.T) {
+	var expired int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+			return
+		}
+		if atomic.LoadInt32(&expired) == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":1203,"message":"task not found"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"succeed","task_result":{"videos":[{"url":"https://cdn.example.com/v.mp4"}]}}}`))
+	}))
+	defer server.Close()
+
+	newClient := func(config *ClientConfig) *Client {
+		config.Timeout = 5 * time.Second
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key", Timeout: 5 * time.Second}, config)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	archive := NewMemoryArchive()
+	client := newClient(&ClientConfig{Archive: archive, Timelines: NewTimelineStore()})
+
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if _, err := client.GetGeneration(context.Background(), resp.TaskID); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	atomic.StoreInt32(&expired, 1)
+
+	result, err := client.GetGeneration(context.Background(), resp.TaskID)
+	if err != nil {
+		t.Fatalf("Expected archived result, got error: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "https://cdn.example.com/v.mp4" {
+		t.Errorf("Unexpected archived result: %+v", result)
+	}
+
+	// Without an archive, a task this client submitted is reported as expired
+	timelines := NewTimelineStore()
+	timelines.Record("task-1", TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued})
+	noArchive := newClient(&ClientConfig{Timelines: timelines})
+
+	_, err = noArchive.GetGeneration(context.Background(), "task-1")
+	var notFound *TaskNotFoundError
+	if !errors.Is(err, ErrTaskNotFound) || !errors.As(err, &notFound) || !notFound.Expired {
+		t.Errorf("Expected expired TaskNotFoundError, got %v", err)
+	}
+
+	// An unknown task is only not found
+	_, err = noArchive.GetGeneration(context.Background(), "task-unknown")
+	if !errors.As(err, &notFound) || notFound.Expired {
+		t.Errorf("Expected non-expired TaskNotFoundError, got %v", err)
+	}
+}
+
+// memoryStorage is a Storage that keeps uploads in memory
+type memoryStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	s.objects[key] = data
+	return "https://videos.example.com/" + key, nil
+}
+
+func TestArchiveResult(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+
+	store := &memoryStorage{objects: make(map[string][]byte)}
+	url, err := client.ArchiveResult(ctx, result, store)
+	if err != nil {
+		t.Fatalf("Failed to archive result: %v", err)
+	}
+	if url != "https://videos.example.com/"+resp.TaskID+".mp4" {
+		t.Errorf("Expected the storage URL, got %s", url)
+	}
+	if string(store.objects[resp.TaskID+".mp4"]) != "fake video "+resp.TaskID {
+		t.Errorf("Expected the video to be uploaded, got %v", store.objects)
+	}
+
+	if _, err := client.ArchiveResult(ctx, &TaskResult{TaskID: "task-1", Status: TaskStatusProcessing}, store); err == nil {
+		t.Error("Expected an error archiving an unfinished task")
+	}
+}
+
+var (
+	_ Storage = (*storage.S3)(nil)
+	_ Storage = (*storage.OSS)(nil)
+	_ Storage = (*storage.GCS)(nil)
+)
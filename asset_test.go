@@ -0,0 +1,75 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+func TestURLExpiry(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected time.Time
+	}{
+		{"s3 v4", "https://bucket.s3.amazonaws.com/v.mp4?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Date=20240804T103000Z&X-Amz-Expires=3600&X-Amz-Signature=abc", time.Date(2024, 8, 4, 11, 30, 0, 0, time.UTC)},
+		{"gcs v4", "https://storage.googleapis.com/b/v.mp4?X-Goog-Date=20240804T103000Z&X-Goog-Expires=600", time.Date(2024, 8, 4, 10, 40, 0, 0, time.UTC)},
+		{"oss", "https://b.oss-cn-beijing.aliyuncs.com/v.mp4?OSSAccessKeyId=k&Expires=1722767400&Signature=s", time.Unix(1722767400, 0)},
+		{"azure sas", "https://a.blob.core.windows.net/c/v.mp4?sv=2022-11-02&se=2024-08-04T12%3A00%3A00Z&sig=s", time.Date(2024, 8, 4, 12, 0, 0, 0, time.UTC)},
+		{"cos", "https://b.cos.ap-guangzhou.myqcloud.com/v.mp4?q-sign-algorithm=sha1&q-sign-time=1722763800;1722767400", time.Unix(1722767400, 0)},
+	}
+	for _, tt := range tests {
+		expires, ok := adapters.URLExpiry(tt.url)
+		if !ok || !expires.Equal(tt.expected) {
+			t.Errorf("%s: expected expiry %v, got %v (%v)", tt.name, tt.expected, expires, ok)
+		}
+	}
+
+	if _, ok := adapters.URLExpiry("https://example.com/video.mp4"); ok {
+		t.Error("Expected no expiry for an unsigned URL")
+	}
+}
+
+func TestResultAssets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"pred-1","status":"succeeded","output":"https://replicate.delivery/v.mp4","completed_at":"2024-08-04T10:30:00Z"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderReplicate, &ProviderConfig{BaseURL: server.URL, APIKey: "r8_test", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	result, err := client.GetGeneration(context.Background(), "pred-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	if len(result.Assets) != 1 || result.Assets[0].URL != result.URL || result.Assets[0].Kind != AssetVideo {
+		t.Fatalf("Expected the video URL as the only asset, got %+v", result.Assets)
+	}
+	expires, ok := result.ExpiresAt()
+	if want := time.Date(2024, 8, 4, 11, 30, 0, 0, time.UTC); !ok || !expires.Equal(want) {
+		t.Errorf("Expected expiry %v, got %v", want, expires)
+	}
+	asset := result.Assets[0]
+	if asset.IsExpired(expires.Add(-time.Minute)) || !asset.IsExpired(expires) {
+		t.Error("Expected the asset to expire exactly at its expiry")
+	}
+	if ttl, ok := asset.TTL(expires.Add(-time.Minute)); !ok || ttl != time.Minute {
+		t.Errorf("Expected a TTL of 1m, got %v", ttl)
+	}
+
+	unknown := ResultAsset{URL: "https://example.com/video.mp4"}
+	if unknown.IsExpired(time.Now()) {
+		t.Error("Expected an asset without expiry never to be expired")
+	}
+	if _, ok := (&TaskResult{Assets: []ResultAsset{unknown}}).ExpiresAt(); ok {
+		t.Error("Expected no expiry for a result without known expiries")
+	}
+}
@@ -0,0 +1,104 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestAwaitGeneration(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{
+		Timeout:      5 * time.Second,
+		PollPolicies: map[string]*PollPolicy{"": {Interval: 10 * time.Millisecond}},
+	})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err := client.AwaitGeneration(ctx, resp.TaskID, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to await generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded {
+		t.Errorf("Expected succeeded, got %s", result.Status)
+	}
+
+	// When the wait elapses first, the latest result is returned without an error
+	fake.ProcessingPolls = 1000
+	resp, err = client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A slow cat", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	start := time.Now()
+	result, err = client.AwaitGeneration(ctx, resp.TaskID, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to await generation: %v", err)
+	}
+	if result.Status != TaskStatusProcessing {
+		t.Errorf("Expected processing, got %s", result.Status)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected AwaitGeneration to return after the wait, took %v", elapsed)
+	}
+}
+
+func TestKlingAdaptorFetchTaskWait(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	fake.ProcessingPolls = 3
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{Image: "https://example.com/cat.png", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	adaptor := NewKlingAdaptor()
+	adaptor.PollPolicy = &PollPolicy{Interval: 10 * time.Millisecond}
+	httpResp, err := adaptor.FetchTaskWait(context.Background(), server.URL, "ak,sk", resp.TaskID, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to fetch task: %v", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, _ := io.ReadAll(httpResp.Body)
+	var task struct {
+		Data struct {
+			TaskStatus string `json:"task_status"`
+		} `json:"data"`
+	}
+	json.Unmarshal(body, &task)
+	if task.Data.TaskStatus != fakekling.StatusSucceed {
+		t.Errorf("Expected the long poll to end on succeed, got %s", body)
+	}
+
+	// A cancelled caller ends the wait instead of sleeping through it
+	fake.ProcessingPolls = 1000
+	resp, err = client.CreateGeneration(context.Background(), &GenerationRequest{Image: "https://example.com/cat.png", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	adaptor.PollPolicy = &PollPolicy{Interval: time.Hour}
+	start := time.Now()
+	if _, err := adaptor.FetchTaskWait(ctx, server.URL, "ak,sk", resp.TaskID, 2*time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected FetchTaskWait to return on cancellation, took %v", elapsed)
+	}
+}
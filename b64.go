@@ -0,0 +1,57 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+// DefaultMaxB64Size is the default ClientConfig.MaxB64Size, 20 MB
+const DefaultMaxB64Size = 20 << 20
+
+// attachB64 fills B64Data on a succeeded result of a task submitted with
+// ResponseFormatB64JSON. Videos that fail to download or exceed MaxB64Size
+// leave B64Data empty, so callers fall back to the URL. The task is forgotten
+// once its first finished result is delivered, so later fetches carry only the URL.
+func (c *Client) attachB64(ctx context.Context, result *TaskResult) {
+	if taskPending(result.Status) {
+		return
+	}
+	if _, ok := c.b64Tasks.LoadAndDelete(result.TaskID); !ok {
+		return
+	}
+	if result.Status != TaskStatusSucceeded || result.B64Data != "" {
+		return
+	}
+
+	limit := c.config.MaxB64Size
+	if limit <= 0 {
+		limit = DefaultMaxB64Size
+	}
+	buf := &cappedBuffer{max: limit}
+	target := &downloadTarget{w: buf, reset: func() error {
+		buf.Reset()
+		return nil
+	}}
+	if _, err := c.downloadResult(ctx, result, target); err != nil {
+		if c.config.Debug {
+			fmt.Printf("Failed to inline video of task %s: %v\n", result.TaskID, err)
+		}
+		return
+	}
+	result.B64Data = base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// cappedBuffer is a bytes.Buffer that refuses to grow beyond max bytes
+type cappedBuffer struct {
+	bytes.Buffer
+	max int64
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if int64(b.Len()+len(p)) > b.max {
+		return 0, fmt.Errorf("video exceeds the %d byte base64 limit", b.max)
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,64 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestResponseFormatB64JSON(t *testing.T) {
+	server := httptest.NewServer(fakekling.New("ak", "sk"))
+	defer server.Close()
+
+	newClient := func(maxSize int64) *Client {
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second, MaxB64Size: maxSize})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	ctx := context.Background()
+	generate := func(client *Client, format ResponseFormat) *TaskResult {
+		resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, ResponseFormat: format})
+		if err != nil {
+			t.Fatalf("Failed to create generation: %v", err)
+		}
+		result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+		if err != nil {
+			t.Fatalf("Failed to wait for completion: %v", err)
+		}
+		return result
+	}
+
+	client := newClient(0)
+	result := generate(client, ResponseFormatB64JSON)
+	if data, _ := base64.StdEncoding.DecodeString(result.B64Data); string(data) != "fake video "+result.TaskID {
+		t.Errorf("Expected the base64 video, got %q", result.B64Data)
+	}
+	if result.URL == "" {
+		t.Error("Expected the URL to be kept")
+	}
+	if _, ok := client.b64Tasks.Load(result.TaskID); ok {
+		t.Error("Expected the task to be forgotten once its video was delivered")
+	}
+	if again, err := client.GetGeneration(ctx, result.TaskID); err != nil || again.B64Data != "" {
+		t.Errorf("Expected later fetches to carry only the URL, got %+v (%v)", again, err)
+	}
+	if result := generate(client, ResponseFormatURL); result.B64Data != "" {
+		t.Errorf("Expected no base64 data for the url format, got %q", result.B64Data)
+	}
+
+	if result := generate(newClient(4), ResponseFormatB64JSON); result.B64Data != "" || result.URL == "" {
+		t.Errorf("Expected an oversize video to fall back to the URL, got %+v", result)
+	}
+
+	var validationErr *ValidationError
+	if _, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, ResponseFormat: "mp4"}); !errors.As(err, &validationErr) || validationErr.Field != "response_format" {
+		t.Errorf("Expected response_format validation error, got %v", err)
+	}
+}
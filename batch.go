@@ -0,0 +1,172 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BatchOptions configures CreateGenerations
+type BatchOptions struct {
+	Concurrency int // Maximum submissions in flight, defaults to 4
+
+	// FailFast stops submitting after the first failed item; items not yet
+	// submitted are marked BatchSkipped. Submissions in flight still finish.
+	FailFast bool
+}
+
+// BatchOutcome classifies a batch item
+type BatchOutcome string
+
+const (
+	BatchSucceeded        BatchOutcome = "succeeded"
+	BatchValidationFailed BatchOutcome = "validation_failed" // The request is invalid, retrying cannot help
+	BatchProviderError    BatchOutcome = "provider_error"    // Submission failed, the request may be retried
+	BatchSkipped          BatchOutcome = "skipped"           // Not submitted because FailFast stopped the batch
+)
+
+// BatchItem is the outcome of one request of a batch
+type BatchItem struct {
+	Index    int                 `json:"index"` // Position of the request in the batch
+	Request  *GenerationRequest  `json:"-"`
+	Response *GenerationResponse `json:"response,omitempty"`
+	Err      error               `json:"-"`
+	Outcome  BatchOutcome        `json:"outcome"`
+}
+
+// BatchResult holds the per-item outcomes of a batch, in request order
+type BatchResult struct {
+	Items []*BatchItem `json:"items"`
+}
+
+// Succeeded returns the items that were submitted
+func (r *BatchResult) Succeeded() []*BatchItem {
+	return r.filter(BatchSucceeded)
+}
+
+// ValidationFailures returns the items rejected as invalid
+func (r *BatchResult) ValidationFailures() []*BatchItem {
+	return r.filter(BatchValidationFailed)
+}
+
+// ProviderErrors returns the items whose submission failed
+func (r *BatchResult) ProviderErrors() []*BatchItem {
+	return r.filter(BatchProviderError)
+}
+
+// Skipped returns the items a FailFast batch did not submit
+func (r *BatchResult) Skipped() []*BatchItem {
+	return r.filter(BatchSkipped)
+}
+
+// Err returns nil if every item succeeded, otherwise an error summarizing the failures
+func (r *BatchResult) Err() error {
+	invalid, failed, skipped := len(r.ValidationFailures()), len(r.ProviderErrors()), len(r.Skipped())
+	if invalid == 0 && failed == 0 && skipped == 0 {
+		return nil
+	}
+	if skipped > 0 {
+		return fmt.Errorf("batch: %d of %d requests failed (%d invalid, %d provider errors), %d skipped", invalid+failed, len(r.Items), invalid, failed, skipped)
+	}
+	return fmt.Errorf("batch: %d of %d requests failed (%d invalid, %d provider errors)", invalid+failed, len(r.Items), invalid, failed)
+}
+
+func (r *BatchResult) filter(outcome BatchOutcome) []*BatchItem {
+	var items []*BatchItem
+	for _, item := range r.Items {
+		if item.Outcome == outcome {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// batchOutcome classifies a submission error
+func batchOutcome(err error) BatchOutcome {
+	var validationErr *ValidationError
+	switch {
+	case err == nil:
+		return BatchSucceeded
+	case errors.As(err, &validationErr), errors.Is(err, ErrInvalidRequest):
+		return BatchValidationFailed
+	}
+	return BatchProviderError
+}
+
+// CreateGenerations submits requests with bounded concurrency. Unless
+// opts.FailFast is set, a failed item does not abort the batch; inspect the
+// result or call Err.
+func (c *Client) CreateGenerations(ctx context.Context, reqs []*GenerationRequest, opts BatchOptions) *BatchResult {
+	result := &BatchResult{Items: make([]*BatchItem, len(reqs))}
+	for i, req := range reqs {
+		result.Items[i] = &BatchItem{Index: i, Request: req}
+	}
+	c.submitBatch(ctx, result.Items, opts)
+	return result
+}
+
+// RetryFailed resubmits only the items of result that failed with provider
+// errors or were skipped, updating them in place. Validation failures are left
+// untouched.
+func (c *Client) RetryFailed(ctx context.Context, result *BatchResult, opts BatchOptions) *BatchResult {
+	var items []*BatchItem
+	for _, item := range result.Items {
+		if item.Outcome == BatchProviderError || item.Outcome == BatchSkipped {
+			items = append(items, item)
+		}
+	}
+	c.submitBatch(ctx, items, opts)
+	return result
+}
+
+// submitBatch submits the items concurrently and records their outcomes
+func (c *Client) submitBatch(ctx context.Context, items []*BatchItem, opts BatchOptions) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	sem := make(chan struct{}, concurrency)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func(item *BatchItem) {
+			defer wg.Done()
+
+			c.metrics.queued.Add(1)
+			select {
+			case sem <- struct{}{}:
+				c.metrics.queued.Add(-1)
+				defer func() { <-sem }()
+			case <-stop:
+				c.metrics.queued.Add(-1)
+				item.Response, item.Err, item.Outcome = nil, nil, BatchSkipped
+				return
+			case <-ctx.Done():
+				c.metrics.queued.Add(-1)
+				item.Response, item.Err, item.Outcome = nil, ctx.Err(), BatchProviderError
+				return
+			}
+			select {
+			case <-stop:
+				item.Response, item.Err, item.Outcome = nil, nil, BatchSkipped
+				return
+			default:
+			}
+
+			if item.Request == nil {
+				item.Err = &ValidationError{Field: "request", Message: "request cannot be nil"}
+			} else {
+				item.Response, item.Err = c.CreateGeneration(ctx, item.Request)
+			}
+			item.Outcome = batchOutcome(item.Err)
+			if item.Err != nil && opts.FailFast {
+				stopOnce.Do(func() { close(stop) })
+			}
+		}(item)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,90 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestCreateGenerationsPartialSuccess(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+
+	reqs := []*GenerationRequest{
+		{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+		{Prompt: "", Duration: 5},
+		{Prompt: "A dog", Duration: 7, Width: 1280, Height: 720},
+		{Prompt: "A bird", Duration: 5, Width: 1280, Height: 720},
+	}
+
+	// Only one submission reaches the server before the injected error is used up
+	fake.FailNext(http.StatusInternalServerError, fakekling.CodeInternalError, "internal error")
+	result := client.CreateGenerations(context.Background(), reqs, BatchOptions{Concurrency: 1})
+
+	if len(result.Items) != len(reqs) {
+		t.Fatalf("Expected %d items, got %d", len(reqs), len(result.Items))
+	}
+	for i, item := range result.Items {
+		if item.Index != i || item.Request != reqs[i] {
+			t.Errorf("Item %d lost its index or request", i)
+		}
+	}
+	if got := len(result.ValidationFailures()); got != 2 {
+		t.Errorf("Expected 2 validation failures, got %d", got)
+	}
+	if result.Items[1].Outcome != BatchValidationFailed || result.Items[2].Outcome != BatchValidationFailed {
+		t.Errorf("Expected items 1 and 2 to be invalid, got %s and %s", result.Items[1].Outcome, result.Items[2].Outcome)
+	}
+	if got := len(result.ProviderErrors()); got != 1 {
+		t.Fatalf("Expected 1 provider error, got %d", got)
+	}
+	if got := len(result.Succeeded()); got != 1 {
+		t.Errorf("Expected 1 success, got %d", got)
+	}
+	if result.Err() == nil {
+		t.Error("Expected a batch error")
+	}
+
+	failed := result.ProviderErrors()[0].Index
+	tasks := fake.Tasks()
+	client.RetryFailed(context.Background(), result, BatchOptions{})
+
+	if fake.Tasks() != tasks+1 {
+		t.Errorf("Expected only the failed item to be resubmitted, got %d new tasks", fake.Tasks()-tasks)
+	}
+	if item := result.Items[failed]; item.Outcome != BatchSucceeded || item.Response == nil || item.Err != nil {
+		t.Errorf("Expected item %d to succeed on retry, got %+v", failed, item)
+	}
+	if got := len(result.ValidationFailures()); got != 2 {
+		t.Errorf("Expected validation failures to be kept, got %d", got)
+	}
+}
+
+func TestCreateGenerationsFailFast(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	req := &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}
+	reqs := []*GenerationRequest{req, req, req, req}
+
+	fake.FailNext(http.StatusInternalServerError, fakekling.CodeInternalError, "internal error")
+	result := client.CreateGenerations(context.Background(), reqs, BatchOptions{Concurrency: 1, FailFast: true})
+
+	if got := len(result.Skipped()); got != 3 {
+		t.Errorf("Expected 3 skipped items, got %d", got)
+	}
+	if fake.Tasks() != 0 {
+		t.Errorf("Expected no tasks after the first failure, got %d", fake.Tasks())
+	}
+	if err := result.Err(); err == nil || !strings.Contains(err.Error(), "3 skipped") {
+		t.Errorf("Expected a batch error counting skipped items, got %v", err)
+	}
+
+	client.RetryFailed(context.Background(), result, BatchOptions{})
+	if got := len(result.Succeeded()); got != 4 || fake.Tasks() != 4 {
+		t.Errorf("Expected all 4 items to be submitted on retry, got %d succeeded and %d tasks", got, fake.Tasks())
+	}
+}
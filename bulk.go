@@ -0,0 +1,239 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BulkFormat is the input format of a bulk submission file
+type BulkFormat string
+
+const (
+	BulkFormatCSV  BulkFormat = "csv"
+	BulkFormatJSON BulkFormat = "json"
+)
+
+// BulkStatusCancelled is the manifest status of rows that were not submitted
+// because the context was done first
+const BulkStatusCancelled TaskStatus = "cancelled"
+
+// BulkOptions configures SubmitBulk
+type BulkOptions struct {
+	Concurrency  int           // Maximum tasks in flight, defaults to 4
+	PollInterval time.Duration // Passed to WaitForCompletion, zero uses the adaptive policy
+	Wait         bool          // Wait for each task to finish before writing its manifest entry
+}
+
+// BulkResult is a single manifest entry
+type BulkResult struct {
+	Row    int        `json:"row"`
+	TaskID string     `json:"task_id,omitempty"`
+	Status TaskStatus `json:"status,omitempty"`
+	URL    string     `json:"url,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// ReadBulkRequests parses generation requests from CSV (with a header row) or JSON
+// (an array or one object per line). Unknown CSV columns are passed through as metadata.
+func ReadBulkRequests(r io.Reader, format BulkFormat) ([]*GenerationRequest, error) {
+	switch format {
+	case BulkFormatCSV:
+		return readBulkCSV(r)
+	case BulkFormatJSON:
+		return readBulkJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported bulk format: %s", format)
+	}
+}
+
+// readBulkCSV parses a CSV file whose first row names the columns
+func readBulkCSV(r io.Reader) ([]*GenerationRequest, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	reqs := make([]*GenerationRequest, 0, len(records)-1)
+	for i, record := range records[1:] {
+		req := &GenerationRequest{}
+		for col, value := range record {
+			if col >= len(header) || value == "" {
+				continue
+			}
+			if err := setBulkField(req, strings.TrimSpace(strings.ToLower(header[col])), value); err != nil {
+				return nil, fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// setBulkField assigns a CSV column to the matching request field
+func setBulkField(req *GenerationRequest, column, value string) error {
+	var err error
+	switch column {
+	case "prompt":
+		req.Prompt = value
+	case "image":
+		req.Image = value
+	case "style":
+		req.Style = value
+	case "model":
+		req.Model = value
+	case "quality_level":
+		req.QualityLevel = QualityLevel(value)
+	case "response_format":
+		req.ResponseFormat = ResponseFormat(value)
+	case "duration":
+		req.Duration, err = strconv.ParseFloat(value, 64)
+	case "fps":
+		req.FPS, err = strconv.Atoi(value)
+	case "width":
+		req.Width, err = strconv.Atoi(value)
+	case "height":
+		req.Height, err = strconv.Atoi(value)
+	case "seed":
+		var seed int
+		seed, err = strconv.Atoi(value)
+		req.Seed = &seed
+	default:
+		if req.Metadata == nil {
+			req.Metadata = make(map[string]interface{})
+		}
+		req.Metadata[column] = value
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", column, value)
+	}
+	return nil
+}
+
+// readBulkJSON parses a JSON array or newline-delimited JSON objects
+func readBulkJSON(r io.Reader) ([]*GenerationRequest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+
+	var reqs []*GenerationRequest
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &reqs); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		return reqs, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	for row := 1; ; row++ {
+		var req GenerationRequest
+		if err := decoder.Decode(&req); err == io.EOF {
+			return reqs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("row %d: failed to decode JSON: %w", row, err)
+		}
+		reqs = append(reqs, &req)
+	}
+}
+
+// SubmitBulk validates and submits requests with bounded concurrency, writing one
+// JSON manifest line per request as it completes. Invalid rows are reported in the
+// manifest instead of aborting the batch, and rows that never ran because ctx was
+// done are written with BulkStatusCancelled, so the manifest lists every row.
+func (c *Client) SubmitBulk(ctx context.Context, reqs []*GenerationRequest, opts *BulkOptions, manifest io.Writer) ([]*BulkResult, error) {
+	if opts == nil {
+		opts = &BulkOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	results := make([]*BulkResult, len(reqs))
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		writeErr error
+	)
+	encoder := json.NewEncoder(manifest)
+
+	write := func(result *BulkResult) {
+		results[result.Row-1] = result
+		if manifest == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if err := encoder.Encode(result); err != nil && writeErr == nil {
+			writeErr = fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(row int, req *GenerationRequest) {
+			defer wg.Done()
+
+			c.metrics.queued.Add(1)
+			select {
+			case sem <- struct{}{}:
+				c.metrics.queued.Add(-1)
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				c.metrics.queued.Add(-1)
+			}
+			// A slot may be free when ctx is already done
+			if err := ctx.Err(); err != nil {
+				write(&BulkResult{Row: row, Status: BulkStatusCancelled, Error: err.Error()})
+				return
+			}
+
+			write(c.submitBulkRow(ctx, row, req, opts))
+		}(i+1, req)
+	}
+
+	wg.Wait()
+	return results, writeErr
+}
+
+// submitBulkRow submits a single row and optionally waits for it to finish
+func (c *Client) submitBulkRow(ctx context.Context, row int, req *GenerationRequest, opts *BulkOptions) *BulkResult {
+	result := &BulkResult{Row: row}
+
+	resp, err := c.CreateGeneration(ctx, req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.TaskID = resp.TaskID
+	result.Status = resp.Status
+
+	if !opts.Wait {
+		return result
+	}
+
+	taskResult, err := c.WaitForCompletion(ctx, resp.TaskID, opts.PollInterval)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = taskResult.Status
+	result.URL = taskResult.URL
+	if taskResult.Error != nil {
+		result.Error = taskResult.Error.Message
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReadBulkRequests(t *testing.T) {
+	csvInput := "prompt,duration,width,height,model,mode\n" +
+		"A cat,5,512,512,kling-v1,pro\n" +
+		"A dog,10,1280,720,,\n"
+
+	reqs, err := ReadBulkRequests(strings.NewReader(csvInput), BulkFormatCSV)
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].Prompt != "A cat" || reqs[0].Duration != 5 || reqs[0].Model != "kling-v1" {
+		t.Errorf("Unexpected first request: %+v", reqs[0])
+	}
+	if reqs[0].Metadata["mode"] != "pro" {
+		t.Errorf("Expected unknown column to be passed as metadata, got %v", reqs[0].Metadata)
+	}
+	if reqs[1].Width != 1280 || reqs[1].Height != 720 {
+		t.Errorf("Unexpected second request: %+v", reqs[1])
+	}
+
+	if _, err := ReadBulkRequests(strings.NewReader("prompt,duration\nA cat,five\n"), BulkFormatCSV); err == nil {
+		t.Error("Invalid duration should return error")
+	}
+
+	jsonInput := `{"prompt":"A cat","duration":5,"width":512,"height":512}
+{"prompt":"A dog","duration":10,"width":512,"height":512}`
+	reqs, err = ReadBulkRequests(strings.NewReader(jsonInput), BulkFormatJSON)
+	if err != nil {
+		t.Fatalf("Failed to read JSON lines: %v", err)
+	}
+	if len(reqs) != 2 || reqs[1].Prompt != "A dog" {
+		t.Errorf("Unexpected JSON requests: %+v", reqs)
+	}
+}
+
+func TestSubmitBulk(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	reqs := []*GenerationRequest{
+		{Prompt: "A cat", Duration: 5, Width: 512, Height: 512},
+		{Prompt: "A dog", Duration: 7, Width: 512, Height: 512},
+	}
+
+	var manifest bytes.Buffer
+	results, err := client.SubmitBulk(context.Background(), reqs, nil, &manifest)
+	if err != nil {
+		t.Fatalf("Failed to submit bulk: %v", err)
+	}
+
+	if results[0].TaskID != "task-1" || results[0].Error != "" {
+		t.Errorf("Expected first row to be submitted, got %+v", results[0])
+	}
+	if results[1].Error == "" {
+		t.Errorf("Expected second row to fail validation, got %+v", results[1])
+	}
+	if body := server.body(); body["prompt"] != "A cat" {
+		t.Errorf("Expected only the valid row to be submitted, got %v", body)
+	}
+	if lines := strings.Count(manifest.String(), "\n"); lines != 2 {
+		t.Errorf("Expected 2 manifest lines, got %d", lines)
+	}
+}
+
+func TestSubmitBulkCancelled(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reqs := []*GenerationRequest{
+		{Prompt: "A cat", Duration: 5, Width: 512, Height: 512},
+		{Prompt: "A dog", Duration: 5, Width: 512, Height: 512},
+		{Prompt: "A bird", Duration: 5, Width: 512, Height: 512},
+	}
+
+	var manifest bytes.Buffer
+	results, err := client.SubmitBulk(ctx, reqs, &BulkOptions{Concurrency: 1}, &manifest)
+	if err != nil {
+		t.Fatalf("Failed to submit bulk: %v", err)
+	}
+	for _, result := range results {
+		if result.Status != BulkStatusCancelled || result.Error == "" {
+			t.Errorf("Expected row %d to be cancelled, got %+v", result.Row, result)
+		}
+	}
+	if lines := strings.Count(manifest.String(), `"status":"cancelled"`); lines != len(reqs) {
+		t.Errorf("Expected %d cancelled manifest lines, got %d:\n%s", len(reqs), lines, manifest.String())
+	}
+	if bodies := server.submissions(); len(bodies) != 0 {
+		t.Errorf("Expected nothing to be submitted, got %d submissions", len(bodies))
+	}
+}
@@ -0,0 +1,155 @@
+package vidgo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// ResultCache reuses generations for identical requests so the same video is not
+// generated twice. In-flight tasks are shared too; failed tasks are forgotten
+// and expired entries are evicted. It is safe for concurrent use.
+type ResultCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry // keyed by request hash
+	tasks     map[string]string      // task ID to request hash
+	lastSweep time.Time
+}
+
+type cacheEntry struct {
+	taskID  string
+	result  *TaskResult
+	expires time.Time
+}
+
+// NewResultCache creates a cache whose entries expire ttl after submission or completion
+func NewResultCache(ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		ttl:     ttl,
+		entries: make(map[string]*cacheEntry),
+		tasks:   make(map[string]string),
+	}
+}
+
+// RequestHash returns a stable hash of a normalized request for a provider. It
+// fails when the request cannot be marshalled, e.g. for Metadata holding a func.
+func RequestHash(provider ProviderType, req *GenerationRequest) (string, error) {
+	payload, err := json.Marshal(struct {
+		Provider   ProviderType       `json:"provider"`
+		Request    *GenerationRequest `json:"request"`
+		ImageBytes []byte             `json:"image_bytes,omitempty"`
+	}{provider, req, req.ImageBytes})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Lookup returns the task for a request hash, if one is cached
+func (c *ResultCache) Lookup(hash string) (*GenerationResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[hash]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		c.remove(hash)
+		return nil, false
+	}
+
+	status := TaskStatusQueued
+	if entry.result != nil {
+		status = entry.result.Status
+	}
+	return &GenerationResponse{TaskID: entry.taskID, Status: status, Cached: true}, true
+}
+
+// Result returns the cached final result of a task
+func (c *ResultCache) Result(taskID string) (*TaskResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[c.tasks[taskID]]
+	if !ok || entry.taskID != taskID || entry.result == nil || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	result := *entry.result
+	return &result, true
+}
+
+// submitted associates a newly submitted task with its request hash
+func (c *ResultCache) submitted(hash, taskID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.sweep(now)
+	c.remove(hash)
+	c.entries[hash] = &cacheEntry{taskID: taskID, expires: now.Add(c.ttl)}
+	c.tasks[taskID] = hash
+}
+
+// sweep evicts expired entries, at most once per ttl so submissions stay cheap;
+// the caller holds mu
+func (c *ResultCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for hash, entry := range c.entries {
+		if now.After(entry.expires) {
+			c.remove(hash)
+		}
+	}
+}
+
+// completed stores a successful result of a task and forgets failed tasks
+func (c *ResultCache) completed(taskID string, result *TaskResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[c.tasks[taskID]]
+	if !ok || entry.taskID != taskID {
+		return
+	}
+
+	switch result.Status {
+	case TaskStatusSucceeded:
+		stored := *result
+		entry.result = &stored
+		entry.expires = time.Now().Add(c.ttl)
+	case TaskStatusFailed:
+		c.remove(c.tasks[taskID])
+	}
+}
+
+func (c *ResultCache) remove(hash string) {
+	if entry, ok := c.entries[hash]; ok {
+		delete(c.tasks, entry.taskID)
+		delete(c.entries, hash)
+	}
+}
+
+// WithoutCache bypasses the client's ResultCache for a single call
+func WithoutCache() CallOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+// resultCache returns the cache to use for a call, or nil. Calls with
+// customer-supplied credentials or another endpoint never share cached results.
+func (c *Client) resultCache(o *callOptions) *ResultCache {
+	if c.config.ResultCache == nil || o.noCache || o.apiKey != "" || o.baseURL != "" {
+		return nil
+	}
+	return c.config.ResultCache
+}
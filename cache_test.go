@@ -0,0 +1,123 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResultCache(t *testing.T) {
+	var submits, polls int32
+	server := newKlingStub(t)
+	server.reply = func(r *http.Request, body map[string]interface{}) string {
+		if r.Method == http.MethodPost {
+			if atomic.AddInt32(&submits, 1) > 1 {
+				return `{"code":0,"message":"ok","data":{"task_id":"task-2"}}`
+			}
+			return ""
+		}
+		atomic.AddInt32(&polls, 1)
+		return `{"code":0,"message":"ok","data":{"id":"task-1","status":"succeed","task_result":{"videos":[{"url":"https://cdn.example.com/v.mp4"}]}}}`
+	}
+	client := server.client(t, &ClientConfig{Timeout: 5 * time.Second, ResultCache: NewResultCache(time.Hour), Timelines: NewTimelineStore()})
+
+	newRequest := func() *GenerationRequest {
+		return &GenerationRequest{Prompt: "Stock footage of a beach", Duration: 5, Width: 1280, Height: 720}
+	}
+
+	first, err := client.CreateGeneration(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	// An identical in-flight request shares the task
+	second, err := client.CreateGeneration(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if second.TaskID != first.TaskID || !second.Cached || first.Cached {
+		t.Errorf("Expected cached in-flight task %s, got %+v", first.TaskID, second)
+	}
+	if metrics := client.QueueMetrics(); metrics.Pending != 1 || metrics.DispatchRate != 1/dispatchWindow.Seconds() {
+		t.Errorf("Expected one pending task and one dispatch, got %+v", metrics)
+	}
+	if events := client.GetTimeline(first.TaskID); len(events) != 2 || events[1].Message != "cached" {
+		t.Errorf("Expected the cache hit on the timeline, got %+v", events)
+	}
+
+	if _, err := client.GetGeneration(context.Background(), first.TaskID); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	result, err := client.GetGeneration(context.Background(), first.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || atomic.LoadInt32(&polls) != 1 {
+		t.Errorf("Expected cached result after one poll, got %+v after %d polls", result, polls)
+	}
+
+	third, err := client.CreateGeneration(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if third.TaskID != first.TaskID || third.Status != TaskStatusSucceeded {
+		t.Errorf("Expected cached succeeded task, got %+v", third)
+	}
+
+	fresh, err := client.CreateGeneration(context.Background(), newRequest(), WithoutCache())
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if fresh.Cached || fresh.TaskID != "task-2" || atomic.LoadInt32(&submits) != 2 {
+		t.Errorf("Expected WithoutCache to submit a new task, got %+v", fresh)
+	}
+	if bodies := server.submissions(); len(bodies) != 2 || bodies[1]["prompt"] != "Stock footage of a beach" {
+		t.Errorf("Expected the request to be submitted twice, got %v", bodies)
+	}
+
+	// Another endpoint may serve another account, so it does not share cached tasks
+	routed, err := client.CreateGeneration(context.Background(), newRequest(), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if routed.Cached || atomic.LoadInt32(&submits) != 3 {
+		t.Errorf("Expected WithBaseURL to bypass the cache, got %+v", routed)
+	}
+
+	hash, _ := RequestHash(ProviderKling, newRequest())
+	other, _ := RequestHash(ProviderKling, &GenerationRequest{Prompt: "Other", Duration: 5, Width: 1280, Height: 720})
+	if hash == "" || hash == other {
+		t.Error("Different requests should hash differently")
+	}
+	if _, err := RequestHash(ProviderKling, &GenerationRequest{Prompt: "Beach", Metadata: map[string]interface{}{"callback": func() {}}}); err == nil {
+		t.Error("Expected an error for a request that cannot be marshalled")
+	}
+}
+
+func TestResultCacheExpiry(t *testing.T) {
+	cache := NewResultCache(time.Millisecond)
+	cache.submitted("hash", "task-1")
+	cache.completed("task-1", &TaskResult{TaskID: "task-1", Status: TaskStatusSucceeded})
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Lookup("hash"); ok {
+		t.Error("Expected expired entry to miss")
+	}
+
+	// Expired entries are evicted even if they are never looked up again
+	cache.submitted("other", "task-2")
+	time.Sleep(5 * time.Millisecond)
+	cache.submitted("new", "task-3")
+	if len(cache.entries) != 1 || len(cache.tasks) != 1 {
+		t.Errorf("Expected expired entries to be evicted, got %d entries and %d tasks", len(cache.entries), len(cache.tasks))
+	}
+
+	cache = NewResultCache(time.Hour)
+	cache.submitted("hash", "task-1")
+	cache.completed("task-1", &TaskResult{TaskID: "task-1", Status: TaskStatusFailed})
+	if _, ok := cache.Lookup("hash"); ok {
+		t.Error("Expected failed task to be forgotten")
+	}
+}
@@ -0,0 +1,198 @@
+package vidgo
+
+import (
+	"container/heap"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/adapters/kling"
+	"github.com/feitianbubu/vidgo/adapters/replicate"
+)
+
+// ParseCallback converts the payload a provider posts to GenerationRequest.CallbackURL
+// into a task result, the same one GetGeneration would return
+func ParseCallback(provider ProviderType, body []byte) (*TaskResult, error) {
+	var parse func([]byte) (*adapters.TaskResult, error)
+	switch provider {
+	case ProviderKling:
+		parse = kling.ParseCallback
+	case ProviderReplicate:
+		parse = replicate.ParseWebhook
+	default:
+		return nil, fmt.Errorf("%w: %s does not send callbacks", ErrUnsupportedOperation, provider)
+	}
+
+	result, err := parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return fromAdapterResult(provider, result), nil
+}
+
+// DefaultCallbackTolerance is how old a callback may be before CallbackVerifier rejects it
+const DefaultCallbackTolerance = 5 * time.Minute
+
+// CallbackVerifier authenticates provider callbacks before parsing them, so a
+// forged or replayed callback cannot report a task as finished
+type CallbackVerifier struct {
+	Provider ProviderType
+
+	// Secret is the Replicate webhook signing secret ("whsec_..."). Kling does not
+	// sign callbacks, so for Kling it is a token the CallbackURL carries as ?token=.
+	Secret string
+
+	// Tolerance bounds the age of a callback by its timestamp (Replicate's
+	// webhook-timestamp, Kling's updated_at), defaults to DefaultCallbackTolerance
+	Tolerance time.Duration
+
+	// Ledger, when set, rejects callbacks that were already processed
+	Ledger CallbackLedger
+
+	// Client re-fetches the task of a Kling callback. Kling does not sign the
+	// body, so Verify only trusts its task ID and returns the status from
+	// GetGeneration instead. Required for Kling.
+	Client *Client
+
+	now func() time.Time
+}
+
+// Verify authenticates a callback request whose body has already been read and
+// parses it like ParseCallback. It fails with ErrAuthenticationFailed for a bad
+// signature or token, ErrInvalidRequest for a stale callback and
+// ErrDuplicateCallback for one the Ledger has seen; acknowledge duplicates with
+// a 2xx status so the provider stops redelivering them.
+func (v *CallbackVerifier) Verify(r *http.Request, body []byte) (*TaskResult, error) {
+	if v.Secret == "" {
+		return nil, fmt.Errorf("%w: callback secret is empty", ErrInvalidConfiguration)
+	}
+	now := time.Now()
+	if v.now != nil {
+		now = v.now()
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultCallbackTolerance
+	}
+
+	var id string
+	var sent time.Time
+	switch v.Provider {
+	case ProviderKling:
+		if v.Client == nil {
+			return nil, fmt.Errorf("%w: verifying Kling callbacks needs a client to re-fetch the task", ErrInvalidConfiguration)
+		}
+		if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(v.Secret)) != 1 {
+			return nil, fmt.Errorf("%w: invalid callback token", ErrAuthenticationFailed)
+		}
+		var err error
+		if id, sent, err = kling.CallbackID(body); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+	case ProviderReplicate:
+		var err error
+		if id, sent, err = replicate.VerifyWebhook(r.Header, body, v.Secret); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrAuthenticationFailed, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s does not send callbacks", ErrUnsupportedOperation, v.Provider)
+	}
+
+	if !sent.IsZero() && (now.Sub(sent) > tolerance || sent.Sub(now) > tolerance) {
+		return nil, fmt.Errorf("%w: callback timestamp %s is outside the %s tolerance", ErrInvalidRequest, sent.UTC().Format(time.RFC3339), tolerance)
+	}
+
+	result, err := ParseCallback(v.Provider, body)
+	if err != nil {
+		return nil, err
+	}
+	if v.Provider == ProviderKling {
+		// The token proves the URL, not the body, so the status comes from Kling
+		if result, err = v.Client.GetGeneration(r.Context(), result.TaskID, WithoutCache()); err != nil {
+			return nil, fmt.Errorf("failed to re-fetch callback task: %w", err)
+		}
+	}
+
+	if v.Ledger != nil {
+		// Replays are rejected by timestamp once the tolerance has passed, so the
+		// ledger only needs to remember IDs until then
+		ttl := 24 * time.Hour
+		if !sent.IsZero() {
+			ttl = sent.Add(tolerance).Sub(now) + time.Second
+		}
+		first, err := v.Ledger.Record(string(v.Provider)+":"+id, ttl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record callback: %w", err)
+		}
+		if !first {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateCallback, id)
+		}
+	}
+	return result, nil
+}
+
+// CallbackLedger remembers processed callback IDs. Implementations must be safe
+// for concurrent use; share one between replicas behind a load balancer.
+type CallbackLedger interface {
+	// Record marks id as processed for ttl and reports whether it was new
+	Record(id string, ttl time.Duration) (bool, error)
+}
+
+// MemoryCallbackLedger is an in-process CallbackLedger
+type MemoryCallbackLedger struct {
+	mu      sync.Mutex
+	seen    map[string]time.Time // ID to expiry
+	expires ledgerExpiries       // min-heap of expiries, so Record drops only expired IDs
+}
+
+// NewMemoryCallbackLedger creates an empty in-memory ledger
+func NewMemoryCallbackLedger() *MemoryCallbackLedger {
+	return &MemoryCallbackLedger{seen: make(map[string]time.Time)}
+}
+
+// Record marks id as processed for ttl and reports whether it was new. Expired
+// IDs are dropped as new ones are recorded.
+func (l *MemoryCallbackLedger) Record(id string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for len(l.expires) > 0 && !now.Before(l.expires[0].until) {
+		expired := heap.Pop(&l.expires).(ledgerExpiry)
+		// A later Record of the same ID pushed a newer expiry
+		if until, ok := l.seen[expired.id]; ok && until.Equal(expired.until) {
+			delete(l.seen, expired.id)
+		}
+	}
+
+	if until, ok := l.seen[id]; ok && now.Before(until) {
+		return false, nil
+	}
+	until := now.Add(ttl)
+	l.seen[id] = until
+	heap.Push(&l.expires, ledgerExpiry{id: id, until: until})
+	return true, nil
+}
+
+type ledgerExpiry struct {
+	id    string
+	until time.Time
+}
+
+// ledgerExpiries implements heap.Interface ordered by expiry
+type ledgerExpiries []ledgerExpiry
+
+func (h ledgerExpiries) Len() int           { return len(h) }
+func (h ledgerExpiries) Less(i, j int) bool { return h[i].until.Before(h[j].until) }
+func (h ledgerExpiries) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *ledgerExpiries) Push(x any)        { *h = append(*h, x.(ledgerExpiry)) }
+
+func (h *ledgerExpiries) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,214 @@
+package vidgo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestKlingCallbackURL(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	req := &GenerationRequest{
+		Prompt:      "A cat walking",
+		Duration:    5.0,
+		Width:       1280,
+		Height:      720,
+		CallbackURL: "https://example.com/hooks/kling",
+	}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if body := server.body(); body["callback_url"] != req.CallbackURL {
+		t.Errorf("Expected callback_url %s, got %v", req.CallbackURL, body)
+	}
+
+	req.CallbackURL = "ftp://example.com/hooks"
+	_, err := client.CreateGeneration(context.Background(), req)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "callback_url" {
+		t.Errorf("Expected callback_url validation error, got %v", err)
+	}
+}
+
+func TestParseCallback(t *testing.T) {
+	payload := []byte(`{
+		"task_id": "task-1",
+		"task_status": "succeed",
+		"task_status_msg": "",
+		"created_at": 1722769557708,
+		"updated_at": 1722769557708,
+		"task_result": {"videos": [{"id": "video-1", "url": "https://example.com/video.mp4", "duration": "5.1"}]}
+	}`)
+
+	result, err := ParseCallback(ProviderKling, payload)
+	if err != nil {
+		t.Fatalf("Failed to parse callback: %v", err)
+	}
+	if result.TaskID != "task-1" {
+		t.Errorf("Expected task ID task-1, got %s", result.TaskID)
+	}
+	if result.Status != TaskStatusSucceeded {
+		t.Errorf("Expected status succeeded, got %s", result.Status)
+	}
+	if result.URL != "https://example.com/video.mp4" {
+		t.Errorf("Expected video URL, got %s", result.URL)
+	}
+	if result.Metadata == nil || result.Metadata.Duration != 5.1 {
+		t.Errorf("Expected duration 5.1, got %+v", result.Metadata)
+	}
+
+	if _, err := ParseCallback(ProviderKling, []byte(`{"task_status":"succeed"}`)); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest for callback without task ID, got %v", err)
+	}
+	if _, err := ParseCallback(ProviderLuma, payload); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation for Luma, got %v", err)
+	}
+}
+
+func TestCallbackURLUnsupported(t *testing.T) {
+	client, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:      "A cat walking",
+		Duration:    5.0,
+		Width:       1280,
+		Height:      720,
+		CallbackURL: "https://example.com/hooks/luma",
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest for Luma callback URL, got %v", err)
+	}
+}
+
+func TestCallbackVerifierKling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/videos/text2video/task-1":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1","task_status":"succeed","task_result":{"videos":[{"url":"https://cdn.example.com/1.mp4"}]}}}`))
+		case "/v1/videos/text2video/task-2":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-2","task_status":"processing"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	payload := []byte(`{"task_id": "task-1", "task_status": "succeed", "updated_at": 1722769557708}`)
+	sent := time.UnixMilli(1722769557708)
+	verifier := &CallbackVerifier{Provider: ProviderKling, Secret: "s3cret", Ledger: NewMemoryCallbackLedger(), Client: client}
+	verifier.now = func() time.Time { return sent.Add(time.Minute) }
+
+	forged := httptest.NewRequest(http.MethodPost, "/hooks/kling?token=guess", nil)
+	if _, err := verifier.Verify(forged, payload); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("Expected ErrAuthenticationFailed for a wrong token, got %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/hooks/kling?token=s3cret", nil)
+	result, err := verifier.Verify(r, payload)
+	if err != nil {
+		t.Fatalf("Failed to verify callback: %v", err)
+	}
+	if result.TaskID != "task-1" || result.Status != TaskStatusSucceeded || result.URL != "https://cdn.example.com/1.mp4" {
+		t.Errorf("Expected the re-fetched succeeded task-1, got %+v", result)
+	}
+	if _, err := verifier.Verify(r, payload); !errors.Is(err, ErrDuplicateCallback) || HTTPStatus(err) != http.StatusConflict {
+		t.Errorf("Expected ErrDuplicateCallback for a redelivery, got %v", err)
+	}
+
+	// A leaked token cannot mark a running task as finished
+	forgedBody := []byte(`{"task_id": "task-2", "task_status": "succeed", "updated_at": 1722769557708}`)
+	if result, err := verifier.Verify(r, forgedBody); err != nil || result.Status != TaskStatusProcessing {
+		t.Errorf("Expected the status Kling reports for task-2, got %+v, %v", result, err)
+	}
+	if _, err := (&CallbackVerifier{Provider: ProviderKling, Secret: "s3cret"}).Verify(r, payload); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration without a client, got %v", err)
+	}
+
+	verifier.now = func() time.Time { return sent.Add(time.Hour) }
+	if _, err := verifier.Verify(r, []byte(`{"task_id": "task-1", "task_status": "failed", "updated_at": 1722769557708}`)); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest for a stale callback, got %v", err)
+	}
+}
+
+func TestCallbackVerifierReplicate(t *testing.T) {
+	key := []byte("replicate-signing-key")
+	secret := "whsec_" + base64.StdEncoding.EncodeToString(key)
+	payload := []byte(`{"id": "pred-1", "status": "succeeded", "output": "https://example.com/video.mp4"}`)
+	now := time.Now()
+
+	request := func(id string, sentAt time.Time, body []byte) *http.Request {
+		timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(id + "." + timestamp + "."))
+		mac.Write(body)
+		r := httptest.NewRequest(http.MethodPost, "/hooks/replicate", nil)
+		r.Header.Set("webhook-id", id)
+		r.Header.Set("webhook-timestamp", timestamp)
+		r.Header.Set("webhook-signature", "v1,b2xkLXNpZ25hdHVyZQ== v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+		return r
+	}
+	verifier := &CallbackVerifier{Provider: ProviderReplicate, Secret: secret, Ledger: NewMemoryCallbackLedger()}
+
+	result, err := verifier.Verify(request("msg-1", now, payload), payload)
+	if err != nil {
+		t.Fatalf("Failed to verify webhook: %v", err)
+	}
+	if result.TaskID != "pred-1" || result.Status != TaskStatusSucceeded {
+		t.Errorf("Expected succeeded pred-1, got %+v", result)
+	}
+	if _, err := verifier.Verify(request("msg-1", now, payload), payload); !errors.Is(err, ErrDuplicateCallback) {
+		t.Errorf("Expected ErrDuplicateCallback for a redelivery, got %v", err)
+	}
+
+	tampered := []byte(`{"id": "pred-2", "status": "succeeded"}`)
+	if _, err := verifier.Verify(request("msg-2", now, payload), tampered); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("Expected ErrAuthenticationFailed for a tampered body, got %v", err)
+	}
+	if _, err := verifier.Verify(request("msg-3", now.Add(-time.Hour), payload), payload); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest for a replayed old webhook, got %v", err)
+	}
+	if _, err := (&CallbackVerifier{Provider: ProviderReplicate}).Verify(request("msg-4", now, payload), payload); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration without a secret, got %v", err)
+	}
+}
+
+func TestMemoryCallbackLedger(t *testing.T) {
+	ledger := NewMemoryCallbackLedger()
+	if first, _ := ledger.Record("a", time.Millisecond); !first {
+		t.Error("Expected a new ID to be recorded")
+	}
+	if first, _ := ledger.Record("b", time.Hour); !first {
+		t.Error("Expected a new ID to be recorded")
+	}
+	if first, _ := ledger.Record("b", time.Hour); first {
+		t.Error("Expected a duplicate ID to be rejected")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if first, _ := ledger.Record("c", time.Hour); !first {
+		t.Error("Expected a new ID to be recorded")
+	}
+	if _, ok := ledger.seen["a"]; ok || len(ledger.seen) != 2 || len(ledger.expires) != 2 {
+		t.Errorf("Expected only the expired ID to be dropped, got %v", ledger.seen)
+	}
+	if first, _ := ledger.Record("a", time.Hour); !first {
+		t.Error("Expected an expired ID to be recorded again")
+	}
+}
@@ -0,0 +1,61 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CancelGeneration asks the provider to cancel an unfinished task and records
+// why. Later results of the task carry the reason in TaskResult.Cancellation,
+// so billing and UX can tell a user abort from a budget guard, a system
+// deadline or a provider-side cancellation. Providers without a cancel API
+// fail with ErrUnsupportedOperation.
+func (c *Client) CancelGeneration(ctx context.Context, taskID string, reason CancellationReason, opts ...CallOption) error {
+	if taskID == "" {
+		return &ValidationError{Field: "task_id", Message: "task ID cannot be empty"}
+	}
+	if !reason.Valid() {
+		return &ValidationError{Field: "reason", Message: "unknown cancellation reason: " + string(reason)}
+	}
+
+	canceler, ok := c.current().(Canceler)
+	if !ok {
+		return fmt.Errorf("%w: %s does not support canceling tasks", ErrUnsupportedOperation, c.current().Name())
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return err
+	}
+	err = c.withRetry(c.captureContext(ctx, "cancel_generation", taskID), o, func(ctx context.Context) error {
+		return canceler.CancelGeneration(ctx, taskID)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.cancellations.Store(taskID, &Cancellation{Reason: reason, CanceledAt: time.Now()})
+	if statuses := c.statusCache(o); statuses != nil {
+		statuses.forget(c.current().Name() + ":" + taskID)
+	}
+	return nil
+}
+
+// applyCancellation records the reason given to CancelGeneration on a failed
+// result. The reason is forgotten once the task finished, so only the first
+// finished result carries it.
+func (c *Client) applyCancellation(taskID string, result *TaskResult) {
+	if taskPending(result.Status) {
+		return
+	}
+	value, ok := c.cancellations.LoadAndDelete(taskID)
+	if !ok || result.Status != TaskStatusFailed {
+		return
+	}
+	cancellation := *value.(*Cancellation)
+	if result.Cancellation != nil && result.Cancellation.Message != "" {
+		cancellation.Message = result.Cancellation.Message
+	}
+	result.Cancellation = &cancellation
+}
@@ -0,0 +1,77 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCancelGeneration(t *testing.T) {
+	var mu sync.Mutex
+	canceled := map[string]bool{"pred-2": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/predictions/"), "/cancel")
+		if r.Method == "POST" {
+			canceled[id] = true
+		}
+		if canceled[id] {
+			w.Write([]byte(`{"id":"` + id + `","status":"canceled","completed_at":"2024-05-01T12:00:00Z"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"` + id + `","status":"processing"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderReplicate, &ProviderConfig{BaseURL: server.URL, APIKey: "r8_test", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := client.CancelGeneration(ctx, "pred-1", CancellationBudget); err != nil {
+		t.Fatalf("Failed to cancel generation: %v", err)
+	}
+	result, err := client.GetGeneration(ctx, "pred-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusFailed || result.Cancellation == nil || result.Cancellation.Reason != CancellationBudget {
+		t.Fatalf("Expected a failed result canceled by the budget guard, got %+v", result)
+	}
+	if result.Cancellation.Reason.UserInitiated() || result.Cancellation.CanceledAt.IsZero() {
+		t.Errorf("Expected a system-initiated cancellation with a time, got %+v", result.Cancellation)
+	}
+	if _, ok := client.cancellations.Load("pred-1"); ok {
+		t.Error("Expected the cancellation to be forgotten once the task finished")
+	}
+
+	// Tasks the provider canceled on its own report the provider as the reason
+	result, err = client.GetGeneration(ctx, "pred-2")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Cancellation == nil || result.Cancellation.Reason != CancellationProvider {
+		t.Errorf("Expected a provider cancellation, got %+v", result.Cancellation)
+	}
+
+	var validationErr *ValidationError
+	if err := client.CancelGeneration(ctx, "pred-3", "bored"); !errors.As(err, &validationErr) || validationErr.Field != "reason" {
+		t.Errorf("Expected reason validation error, got %v", err)
+	}
+
+	kling, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := kling.CancelGeneration(ctx, "task-1", CancellationUser); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation for Kling, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package vidgo
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// CapabilitiesChangedEvent reports how the models and capabilities of a client
+// changed, e.g. after a Reload switched to an account with more models.
+// Gateways can use it to update the model lists they expose.
+type CapabilitiesChangedEvent struct {
+	Time                time.Time        `json:"time"`
+	Provider            string           `json:"provider"`
+	AddedModels         []string         `json:"added_models,omitempty"`
+	RemovedModels       []string         `json:"removed_models,omitempty"`
+	AddedDurations      []float64        `json:"added_durations,omitempty"`
+	RemovedDurations    []float64        `json:"removed_durations,omitempty"`
+	AddedCombinations   CapabilityMatrix `json:"added_combinations,omitempty"`
+	RemovedCombinations CapabilityMatrix `json:"removed_combinations,omitempty"`
+	Models              []string         `json:"models"`                 // The full current model list
+	Capabilities        *Capabilities    `json:"capabilities,omitempty"` // The full current capabilities
+}
+
+// capabilitySnapshot is what a client last reported through GetSupportedModels and Capabilities
+type capabilitySnapshot struct {
+	models       []string
+	capabilities *Capabilities
+}
+
+// takeCapabilitySnapshot reads the models and capabilities of provider
+func takeCapabilitySnapshot(provider Provider) *capabilitySnapshot {
+	snapshot := &capabilitySnapshot{models: append([]string(nil), provider.SupportedModels()...)}
+	if caps, ok := provider.(adapters.CapabilitiesProvider); ok {
+		snapshot.capabilities = caps.Capabilities()
+	}
+	return snapshot
+}
+
+// RefreshModels re-reads the models and capabilities of the current provider
+// and compares them with the last snapshot. It returns nil if nothing changed;
+// otherwise the change is also reported through ClientConfig.OnCapabilitiesChanged.
+// Reload refreshes automatically.
+func (c *Client) RefreshModels() *CapabilitiesChangedEvent {
+	provider := c.current()
+	snapshot := takeCapabilitySnapshot(provider)
+
+	c.capsMu.Lock()
+	event := diffCapabilities(c.caps, snapshot)
+	c.caps = snapshot
+	c.capsMu.Unlock()
+	if event == nil {
+		return nil
+	}
+
+	event.Time = time.Now()
+	event.Provider = provider.Name()
+	if c.config.OnCapabilitiesChanged != nil {
+		c.config.OnCapabilitiesChanged(*event)
+	}
+	return event
+}
+
+// diffCapabilities returns the differences between two snapshots, or nil if there are none
+func diffCapabilities(old, new *capabilitySnapshot) *CapabilitiesChangedEvent {
+	if old == nil {
+		old = &capabilitySnapshot{}
+	}
+
+	event := &CapabilitiesChangedEvent{Models: new.models, Capabilities: new.capabilities}
+	event.AddedModels, event.RemovedModels = diffSets(old.models, new.models)
+	event.AddedDurations, event.RemovedDurations = diffSets(capabilityDurations(old.capabilities), capabilityDurations(new.capabilities))
+	event.AddedCombinations, event.RemovedCombinations = diffSets(old.capabilities.Matrix(), new.capabilities.Matrix())
+
+	if len(event.AddedModels) == 0 && len(event.RemovedModels) == 0 &&
+		len(event.AddedDurations) == 0 && len(event.RemovedDurations) == 0 &&
+		len(event.AddedCombinations) == 0 && len(event.RemovedCombinations) == 0 &&
+		reflect.DeepEqual(old.capabilities, new.capabilities) {
+		return nil
+	}
+	sort.Strings(event.AddedModels)
+	sort.Strings(event.RemovedModels)
+	sort.Float64s(event.AddedDurations)
+	sort.Float64s(event.RemovedDurations)
+	return event
+}
+
+func capabilityDurations(caps *Capabilities) []float64 {
+	if caps == nil || caps.Duration == nil {
+		return nil
+	}
+	return caps.Duration.Durations
+}
+
+// diffSets returns the values only in new and the values only in old, in their original order
+func diffSets[T comparable](old, new []T) (added, removed []T) {
+	inOld := make(map[T]bool, len(old))
+	for _, v := range old {
+		inOld[v] = true
+	}
+	inNew := make(map[T]bool, len(new))
+	for _, v := range new {
+		inNew[v] = true
+		if !inOld[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !inNew[v] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,83 @@
+package vidgo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// catalogProvider is a custom provider whose models and durations can change
+type catalogProvider struct {
+	models    []string
+	durations []float64
+}
+
+func (p *catalogProvider) Name() string { return "catalog" }
+
+func (p *catalogProvider) CreateGeneration(ctx context.Context, req *GenerationRequest) (*GenerationResponse, error) {
+	return &GenerationResponse{TaskID: "task-1", Status: TaskStatusQueued}, nil
+}
+
+func (p *catalogProvider) GetGeneration(ctx context.Context, taskID string) (*TaskResult, error) {
+	return &TaskResult{TaskID: taskID, Status: TaskStatusQueued}, nil
+}
+
+func (p *catalogProvider) SupportedModels() []string { return p.models }
+
+func (p *catalogProvider) ValidateRequest(req *GenerationRequest) error { return nil }
+
+func (p *catalogProvider) Capabilities() *Capabilities {
+	return &Capabilities{Duration: &DurationConstraints{Durations: p.durations}}
+}
+
+func TestRefreshModels(t *testing.T) {
+	provider := &catalogProvider{models: []string{"v1", "v2"}, durations: []float64{5, 10}}
+	var events []CapabilitiesChangedEvent
+	client := NewClientWithProvider(provider, &ClientConfig{
+		OnCapabilitiesChanged: func(event CapabilitiesChangedEvent) { events = append(events, event) },
+	})
+
+	if event := client.RefreshModels(); event != nil || len(events) != 0 {
+		t.Errorf("Expected no change before the provider changes, got %+v", event)
+	}
+
+	provider.models = []string{"v2", "v3"}
+	provider.durations = []float64{5}
+	event := client.RefreshModels()
+	if event == nil {
+		t.Fatal("Expected a capabilities changed event")
+	}
+	if !reflect.DeepEqual(event.AddedModels, []string{"v3"}) || !reflect.DeepEqual(event.RemovedModels, []string{"v1"}) {
+		t.Errorf("Expected v3 added and v1 removed, got %v and %v", event.AddedModels, event.RemovedModels)
+	}
+	if len(event.AddedDurations) != 0 || !reflect.DeepEqual(event.RemovedDurations, []float64{10}) {
+		t.Errorf("Expected duration 10 removed, got %v and %v", event.AddedDurations, event.RemovedDurations)
+	}
+	if event.Provider != "catalog" || !reflect.DeepEqual(event.Models, []string{"v2", "v3"}) {
+		t.Errorf("Expected the current catalog models, got %s %v", event.Provider, event.Models)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected one callback, got %d", len(events))
+	}
+
+	if event := client.RefreshModels(); event != nil || len(events) != 1 {
+		t.Errorf("Expected no change after the snapshot is updated, got %+v", event)
+	}
+}
+
+func TestDiffCapabilitiesMatrix(t *testing.T) {
+	old := &capabilitySnapshot{models: []string{"kling-v1"}, capabilities: &Capabilities{
+		Combinations: CapabilityMatrix{{Model: "kling-v1", Duration: 5}, {Model: "kling-v1", Duration: 10}},
+	}}
+	new := &capabilitySnapshot{models: []string{"kling-v1"}, capabilities: &Capabilities{
+		Combinations: CapabilityMatrix{{Model: "kling-v1", Duration: 5}},
+	}}
+
+	event := diffCapabilities(old, new)
+	if event == nil || !reflect.DeepEqual(event.RemovedCombinations, CapabilityMatrix{{Model: "kling-v1", Duration: 10}}) {
+		t.Errorf("Expected the 10s combination to be removed, got %+v", event)
+	}
+	if event := diffCapabilities(new, new); event != nil {
+		t.Errorf("Expected no event for identical snapshots, got %+v", event)
+	}
+}
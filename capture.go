@@ -0,0 +1,259 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// maxExampleString is the longest string value kept in a captured body; longer
+// values, such as Base64 images, are truncated
+const maxExampleString = 256
+
+// ExampleCapture saves sanitized request/response examples of real provider calls
+// for generating API docs. Each provider endpoint gets one file holding its latest
+// exchange, <Dir>/<provider>/<operation>/<METHOD>_<path>.json, with credentials
+// masked, signed URL queries dropped and long strings truncated.
+type ExampleCapture struct {
+	Dir string
+}
+
+// CapturedExample is the content of an ExampleCapture file
+type CapturedExample struct {
+	Provider   string           `json:"provider"`
+	Operation  string           `json:"operation"` // Client method, e.g. "create_generation"
+	Endpoint   string           `json:"endpoint"`  // Method and path, with the task ID as {task_id}
+	CapturedAt time.Time        `json:"captured_at"`
+	SDKVersion string           `json:"sdk_version"`
+	Request    CapturedRequest  `json:"request"`
+	Response   CapturedResponse `json:"response"`
+}
+
+// CapturedRequest is the sanitized request of a CapturedExample
+type CapturedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// CapturedResponse is the sanitized response of a CapturedExample
+type CapturedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// captureContext attaches an ExampleCapture recorder for operation to ctx when
+// capture is configured
+func (c *Client) captureContext(ctx context.Context, operation, taskID string) context.Context {
+	if c.config.Capture == nil {
+		return ctx
+	}
+	return adapters.WithExchangeRecorder(ctx, &captureRecorder{
+		capture:   c.config.Capture,
+		provider:  strings.ToLower(c.current().Name()),
+		operation: operation,
+		taskID:    taskID,
+		debug:     c.config.Debug,
+	})
+}
+
+// captureRecorder writes the exchanges of one client call to an ExampleCapture
+type captureRecorder struct {
+	capture   *ExampleCapture
+	provider  string
+	operation string
+	taskID    string
+	debug     bool
+}
+
+func (r *captureRecorder) RecordExchange(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) {
+	path := req.URL.Path
+	if r.taskID != "" {
+		path = strings.ReplaceAll(path, r.taskID, "{task_id}")
+	}
+	example := &CapturedExample{
+		Provider:   r.provider,
+		Operation:  r.operation,
+		Endpoint:   req.Method + " " + path,
+		CapturedAt: time.Now().UTC(),
+		SDKVersion: adapters.SDKVersion,
+		Request: CapturedRequest{
+			Method: req.Method,
+			URL:    sanitizeExampleURL(req.URL, r.taskID),
+			Header: sanitizeExampleHeader(req.Header),
+			Body:   sanitizeExampleBody(reqBody),
+		},
+		Response: CapturedResponse{
+			Status: resp.StatusCode,
+			Header: sanitizeExampleHeader(resp.Header),
+			Body:   sanitizeExampleBody(respBody),
+		},
+	}
+
+	name := req.Method + "_" + exampleFileName(path) + ".json"
+	if err := r.capture.write(filepath.Join(r.provider, r.operation, name), example); err != nil && r.debug {
+		fmt.Printf("Failed to capture example: %v\n", err)
+	}
+}
+
+// write saves an example under name, replacing the previous one atomically
+func (c *ExampleCapture) write(name string, example *CapturedExample) error {
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(example); err != nil {
+		return err
+	}
+
+	path := filepath.Join(c.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data.Bytes())
+}
+
+// writeFileAtomic writes data to path under a temporary name and renames it
+// into place, so path never holds a partial file
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// exampleFileName turns a URL path into a file name, e.g. "v1_videos_text2video_task_id"
+func exampleFileName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r == '{' || r == '}':
+			return -1
+		}
+		return '_'
+	}, strings.Trim(path, "/"))
+	if name == "" {
+		return "root"
+	}
+	return name
+}
+
+// sanitizeExampleURL masks credentials in the query and user info of a request URL
+func sanitizeExampleURL(u *url.URL, taskID string) string {
+	copied := *u
+	query := copied.Query()
+	for key := range query {
+		if secretKey(key) || strings.Contains(strings.ToLower(key), "signature") {
+			query.Set(key, redactedValue)
+		}
+	}
+	copied.RawQuery = query.Encode()
+	copied.User = nil
+	if taskID != "" {
+		copied.Path = strings.ReplaceAll(copied.Path, taskID, "{task_id}")
+		copied.RawPath = ""
+	}
+	return copied.String()
+}
+
+// sanitizeExampleHeader masks credential headers such as Authorization and cookies
+func sanitizeExampleHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	sanitized := header.Clone()
+	for name := range sanitized {
+		switch lower := strings.ToLower(name); {
+		case lower == "authorization", lower == "proxy-authorization", lower == "cookie", lower == "set-cookie", secretKey(lower):
+			sanitized[name] = []string{redactedValue}
+		}
+	}
+	return sanitized
+}
+
+// sanitizeExampleBody masks secret fields of a JSON body, drops the query of signed
+// URLs and truncates long strings. Other bodies are kept as a JSON string, or
+// summarized when they are binary.
+func sanitizeExampleBody(body []byte) json.RawMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&value) != nil {
+		text := string(body)
+		if contentType := http.DetectContentType(body); !strings.HasPrefix(contentType, "text/") {
+			text = fmt.Sprintf("<%d bytes of %s>", len(body), contentType)
+		}
+		value = truncateExample(text)
+	}
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	if encoder.Encode(sanitizeExampleValue(value)) != nil {
+		return nil
+	}
+	return bytes.TrimSpace(data.Bytes())
+}
+
+// sanitizeExampleValue sanitizes a decoded JSON value in place
+func sanitizeExampleValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s, ok := item.(string); ok && s != "" && secretKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = sanitizeExampleValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = sanitizeExampleValue(item)
+		}
+	case string:
+		if u, err := url.Parse(v); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.RawQuery != "" {
+			u.RawQuery = ""
+			v = u.String()
+		}
+		return truncateExample(v)
+	}
+	return value
+}
+
+// truncateExample shortens strings longer than maxExampleString
+func truncateExample(s string) string {
+	if len(s) <= maxExampleString {
+		return s
+	}
+	return fmt.Sprintf("%s...(%d bytes)", s[:64], len(s))
+}
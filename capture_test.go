@@ -0,0 +1,79 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestExampleCapture(t *testing.T) {
+	server := httptest.NewServer(fakekling.New("ak", "sk"))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second, Capture: &ExampleCapture{Dir: dir}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	image := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(512, 512))
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Image: image, Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if _, err := client.GetGeneration(ctx, resp.TaskID); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	read := func(name string) *CapturedExample {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Expected example %s: %v", name, err)
+		}
+		var example CapturedExample
+		if err := json.Unmarshal(data, &example); err != nil {
+			t.Fatalf("Failed to decode example %s: %v", name, err)
+		}
+		return &example
+	}
+
+	created := read("kling/create_generation/POST_v1_videos_image2video.json")
+	if created.Request.Header.Get("Authorization") != redactedValue {
+		t.Errorf("Expected the Authorization header to be redacted, got %v", created.Request.Header)
+	}
+	var body struct {
+		Image string `json:"image"`
+	}
+	json.Unmarshal(created.Request.Body, &body)
+	if len(body.Image) > maxExampleString || !strings.HasSuffix(body.Image, " bytes)") {
+		t.Errorf("Expected the Base64 image to be truncated, got %q", body.Image)
+	}
+	if created.Response.Status != 200 || !strings.Contains(string(created.Response.Body), resp.TaskID) {
+		t.Errorf("Expected the submit response, got %d %s", created.Response.Status, created.Response.Body)
+	}
+
+	fetched := read("kling/get_generation/GET_v1_videos_image2video_task_id.json")
+	if fetched.Endpoint != "GET /v1/videos/image2video/{task_id}" {
+		t.Errorf("Expected the task ID to be templated, got %s", fetched.Endpoint)
+	}
+}
+
+func TestSanitizeExampleBody(t *testing.T) {
+	body := sanitizeExampleBody([]byte(`{"api_key":"sk-live","url":"https://cdn.example.com/a.mp4?Signature=abc","items":[{"access_token":"t"}],"count":3}`))
+	expected := `{"api_key":"REDACTED","count":3,"items":[{"access_token":"REDACTED"}],"url":"https://cdn.example.com/a.mp4"}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+	if body := sanitizeExampleBody([]byte{0x00, 0x01, 0x02}); string(body) != `"<3 bytes of application/octet-stream>"` {
+		t.Errorf("Expected a binary summary, got %s", body)
+	}
+}
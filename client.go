@@ -2,17 +2,39 @@ package vidgo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/feitianbubu/vidgo/adapters"
-	"github.com/feitianbubu/vidgo/adapters/kling"
+	"github.com/feitianbubu/vidgo/pace"
 )
 
-// Client is the main client for video generation
+// Client is the main client for video generation.
+// A Client is safe for concurrent use by multiple goroutines.
 type Client struct {
-	provider Provider
-	config   *ClientConfig
+	providerMu     sync.RWMutex
+	provider       Provider
+	providerType   ProviderType
+	providerConfig *ProviderConfig // Configuration provider was created from, for Reload
+	config         *ClientConfig
+	metrics        queueMetrics
+
+	// b64Tasks holds the IDs of tasks submitted with ResponseFormatB64JSON
+	b64Tasks sync.Map
+
+	// cancellations holds the *Cancellation recorded by CancelGeneration, keyed by task ID
+	cancellations sync.Map
+
+	// caps is the model and capability snapshot RefreshModels compares against
+	capsMu sync.Mutex
+	caps   *capabilitySnapshot
+
+	stop      chan struct{}
+	closeOnce sync.Once
 }
 
 // ClientConfig holds configuration for the client
@@ -21,6 +43,95 @@ type ClientConfig struct {
 	MaxRetries int
 	RetryDelay time.Duration
 	Debug      bool
+
+	// DisallowBYOK rejects per-call customer-supplied credentials
+	DisallowBYOK bool
+
+	// PollPolicies configures adaptive polling per model; the "" key is the default
+	PollPolicies map[string]*PollPolicy
+
+	// Planner, when set, derives polling policies from historical latencies
+	Planner *Planner
+
+	// Timelines, when set, records the state transition history of tasks
+	Timelines *TimelineStore
+
+	// ImageUploader, when set, uploads raw image bytes and data URIs and sends the URL instead
+	ImageUploader ImageUploader
+
+	// NormalizeImages auto-rotates, strips metadata from and downscales local images
+	// to the provider limits before validation and upload
+	NormalizeImages bool
+
+	// UpgradePolicy, when set, retries capacity-rejected std submissions in a higher tier
+	UpgradePolicy *UpgradePolicy
+
+	// ResultCache, when set, returns earlier results for identical requests instead of regenerating
+	ResultCache *ResultCache
+
+	// Prewarm establishes provider connections and auth tokens when the client is created
+	Prewarm bool
+
+	// WarmInterval, with Prewarm, re-warms in the background at this interval until Close
+	WarmInterval time.Duration
+
+	// Evaluators, when set, score succeeded videos in WaitForCompletion
+	Evaluators []Evaluator
+
+	// DownloadTimeout bounds a whole video download, defaults to Timeout
+	DownloadTimeout time.Duration
+
+	// Archive, when set, keeps finished results so they can still be returned after the
+	// provider has expired the task
+	Archive ResultArchive
+
+	// DownloadReadTimeout aborts a download attempt that receives no data for this
+	// long; the next attempt resumes where it stopped
+	DownloadReadTimeout time.Duration
+
+	// HTTPClient sends video downloads and thumbnail requests, e.g. through a
+	// proxy; defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	// MaxB64Size caps the videos returned inline for ResponseFormatB64JSON,
+	// defaults to DefaultMaxB64Size
+	MaxB64Size int64
+
+	// TokenMinTTL and TokenMaxTTL bound locally signed auth tokens (Kling JWTs).
+	// When either is set, each token lives until the call deadline within these
+	// bounds instead of a fixed 30 minutes; TokenMinTTL defaults to one minute.
+	TokenMinTTL time.Duration
+	TokenMaxTTL time.Duration
+
+	// OnReload, when set, receives an audit event for every configuration reload
+	OnReload func(event ReloadEvent)
+
+	// OnResidency, when set, receives an audit event for every request with a
+	// Residency, whether it was allowed or rejected with ErrResidencyNotAllowed
+	OnResidency func(event ResidencyEvent)
+
+	// OnCapabilitiesChanged, when set, is called when RefreshModels or Reload
+	// detects added or removed models, durations or combinations
+	OnCapabilitiesChanged func(event CapabilitiesChangedEvent)
+
+	// Telemetry, when set, reports anonymized aggregate usage; it falls back to
+	// SetDefaultTelemetry and is disabled by default
+	Telemetry *Telemetry
+
+	// ProviderConcurrency is the provider account's concurrent task limit, used
+	// to report utilization in QueueMetrics
+	ProviderConcurrency int
+
+	// StatusCache, when set, serves GetGeneration from memory and refreshes stale
+	// entries in the background
+	StatusCache *StatusCache
+
+	// Capture, when set, saves sanitized provider request/response examples
+	Capture *ExampleCapture
+
+	// SLO, when set, records the outcome and latency of every task the client
+	// submits, and provider failures of CreateGeneration
+	SLO *SLOTracker
 }
 
 // DefaultClientConfig returns default client configuration
@@ -45,10 +156,15 @@ func NewClient(providerType ProviderType, providerConfig *ProviderConfig, client
 		config = clientConfig[0]
 	}
 
-	return &Client{
-		provider: provider,
-		config:   config,
-	}, nil
+	client := &Client{
+		provider:       provider,
+		providerType:   providerType,
+		providerConfig: cloneProviderConfig(providerConfig),
+		config:         config,
+		caps:           takeCapabilitySnapshot(provider),
+	}
+	client.prewarm()
+	return client, nil
 }
 
 // NewClientWithProvider creates a new client with a custom provider
@@ -58,128 +174,302 @@ func NewClientWithProvider(provider Provider, config ...*ClientConfig) *Client {
 		clientConfig = config[0]
 	}
 
-	return &Client{
+	client := &Client{
 		provider: provider,
 		config:   clientConfig,
+		caps:     takeCapabilitySnapshot(provider),
 	}
+	client.prewarm()
+	return client
 }
 
 // CreateGeneration creates a new video generation task
-func (c *Client) CreateGeneration(ctx context.Context, req *GenerationRequest) (*GenerationResponse, error) {
-	if err := c.validateRequest(req); err != nil {
+func (c *Client) CreateGeneration(ctx context.Context, req *GenerationRequest, opts ...CallOption) (*GenerationResponse, error) {
+	req, err := c.resolveSize(req)
+	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
+	req, err = loadImageFile(req)
+	if err != nil {
+		return nil, err
+	}
 
-	var lastErr error
-	for i := 0; i <= c.config.MaxRetries; i++ {
-		if i > 0 {
-			select {
-			case <-time.After(c.config.RetryDelay):
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			}
-		}
+	req, err = c.normalizeImage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validateRequest(req); err != nil {
+		return nil, err
+	}
+	if err := c.checkResidency(req); err != nil {
+		return nil, err
+	}
 
-		resp, err := c.provider.CreateGeneration(ctx, req)
-		if err == nil {
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, hash := c.resultCache(o), ""
+	if cache != nil {
+		// Requests that cannot be hashed are submitted without the cache
+		if hash, err = RequestHash(c.registryName(), req); err != nil {
+			cache = nil
+		} else if resp, ok := cache.Lookup(hash); ok {
+			c.created(resp, req)
 			return resp, nil
 		}
+	}
 
-		lastErr = err
-		if !IsRetryableError(err) {
-			break
+	prepared, err := c.prepareImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// The provider may accept the inline image but not the URL it was uploaded to
+	if prepared != req {
+		if err := c.validateRequest(prepared); err != nil {
+			return nil, err
 		}
+		req = prepared
+	}
 
-		if c.config.Debug {
-			fmt.Printf("Attempt %d failed: %v, retrying...\n", i+1, err)
+	var resp *GenerationResponse
+	err = c.withRetry(c.captureContext(ctx, "create_generation", ""), o, func(ctx context.Context) error {
+		var err error
+		resp, err = c.current().CreateGeneration(ctx, req)
+		return err
+	})
+	if err != nil {
+		if resp, err = c.upgrade(ctx, o, req, err); err != nil {
+			c.recordSLOFailure(err)
+			return nil, err
 		}
 	}
 
-	return nil, lastErr
+	if cache != nil {
+		cache.submitted(hash, resp.TaskID)
+	}
+
+	c.created(resp, req)
+	return resp, nil
+}
+
+// created records a task returned by CreateGeneration, whether it was submitted
+// or reused from the ResultCache
+func (c *Client) created(resp *GenerationResponse, req *GenerationRequest) {
+	if req.ResponseFormat == ResponseFormatB64JSON {
+		c.b64Tasks.Store(resp.TaskID, true)
+	}
+
+	event := TimelineEvent{Type: TimelineEventSubmitted, Status: resp.Status}
+	if resp.Cached {
+		event.Message = "cached"
+		if taskPending(resp.Status) {
+			c.metrics.reused(resp.TaskID, req.Model)
+		}
+	} else {
+		c.metrics.dispatched(resp.TaskID, req.Model)
+	}
+	c.recordTimeline(resp.TaskID, event)
 }
 
 // GetGeneration retrieves the status and result of a generation task
-func (c *Client) GetGeneration(ctx context.Context, taskID string) (*TaskResult, error) {
+func (c *Client) GetGeneration(ctx context.Context, taskID string, opts ...CallOption) (*TaskResult, error) {
 	if taskID == "" {
 		return nil, &ValidationError{Field: "task_id", Message: "task ID cannot be empty"}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	var lastErr error
-	for i := 0; i <= c.config.MaxRetries; i++ {
-		if i > 0 {
-			select {
-			case <-time.After(c.config.RetryDelay):
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			}
+	statuses := c.statusCache(o)
+	if statuses == nil {
+		return c.getGeneration(ctx, taskID, o)
+	}
+	key := c.current().Name() + ":" + taskID
+	if result, revalidate := statuses.get(key); result != nil {
+		if revalidate {
+			go c.revalidate(context.WithoutCancel(ctx), statuses, key, taskID, o)
 		}
+		return result, nil
+	}
+	return statuses.fetch(key, func() (*TaskResult, error) {
+		return c.getGeneration(ctx, taskID, o)
+	})
+}
 
-		result, err := c.provider.GetGeneration(ctx, taskID)
-		if err == nil {
+// getGeneration fetches a task from the provider, or from the ResultCache or Archive
+func (c *Client) getGeneration(ctx context.Context, taskID string, o *callOptions) (*TaskResult, error) {
+	cache := c.resultCache(o)
+	if cache != nil {
+		if result, ok := cache.Result(taskID); ok {
+			c.attachB64(ctx, result)
 			return result, nil
 		}
+	}
 
-		lastErr = err
-		if !IsRetryableError(err) {
-			break
+	var result *TaskResult
+	start := time.Now()
+	err := c.withRetry(c.captureContext(ctx, "get_generation", taskID), o, func(ctx context.Context) error {
+		var err error
+		result, err = c.current().GetGeneration(ctx, taskID)
+		return err
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			c.metrics.finished(taskID)
+			c.b64Tasks.Delete(taskID)
+			c.cancellations.Delete(taskID)
 		}
-
-		if c.config.Debug {
-			fmt.Printf("Attempt %d failed: %v, retrying...\n", i+1, err)
+		if archived, ok := c.taskNotFound(taskID, err); ok {
+			return archived, nil
 		}
+		c.recordTimeline(taskID, failureEvent(err, elapsed))
+		return nil, err
+	}
+
+	c.polled(taskID, result, cache, elapsed)
+	c.attachB64(ctx, result)
+	return result, nil
+}
+
+// polled records a status fetched from the provider
+func (c *Client) polled(taskID string, result *TaskResult, cache *ResultCache, elapsed time.Duration) {
+	c.applyCancellation(taskID, result)
+	if !taskPending(result.Status) {
+		c.finished(taskID, result.Status)
+	}
+
+	if cache != nil {
+		cache.completed(taskID, result)
 	}
+	c.archive(result)
+
+	c.recordTimeline(taskID, TimelineEvent{Type: TimelineEventPolled, Status: result.Status, Duration: elapsed})
+}
 
-	return nil, lastErr
+// finished records that a task no longer runs at the provider and, for tasks
+// submitted by this client, its outcome in the SLO tracker
+func (c *Client) finished(taskID string, status TaskStatus) {
+	submitted, ok := c.metrics.finished(taskID)
+	if ok && c.config.SLO != nil {
+		c.config.SLO.Record(c.registryName(), status, time.Since(submitted))
+	}
+}
+
+// sloStatus evaluates the client's SLO for its provider, nil without an SLO tracker
+func (c *Client) sloStatus() *SLOStatus {
+	if c.config.SLO == nil {
+		return nil
+	}
+	status := c.config.SLO.Status(c.registryName())
+	return &status
 }
 
-// WaitForCompletion waits for a generation task to complete
-func (c *Client) WaitForCompletion(ctx context.Context, taskID string, pollInterval time.Duration) (*TaskResult, error) {
-	if pollInterval <= 0 {
-		pollInterval = 5 * time.Second
+// recordSLOFailure records a CreateGeneration error the provider is responsible
+// for, such as a 5xx or a network error, as a failed task
+func (c *Client) recordSLOFailure(err error) {
+	if c.config.SLO != nil && IsRetryableError(err) {
+		c.config.SLO.Record(c.registryName(), TaskStatusFailed, 0)
 	}
+}
 
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+// registryName is the registry name of the current provider
+func (c *Client) registryName() ProviderType {
+	return ProviderType(strings.ToLower(c.current().Name()))
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			result, err := c.GetGeneration(ctx, taskID)
-			if err != nil {
-				return nil, err
-			}
+// withRetry runs fn with the call timeout, retrying retryable errors
+func (c *Client) withRetry(ctx context.Context, o *callOptions, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(o.context(ctx), o.timeout)
+	defer cancel()
 
-			switch result.Status {
-			case TaskStatusSucceeded, TaskStatusFailed:
-				return result, nil
-			case TaskStatusQueued, TaskStatusProcessing:
-				continue
-			default:
-				return result, nil
-			}
+	retry := pace.Retry{
+		MaxRetries: o.maxRetries,
+		Backoff:    pace.Constant(o.retryDelay),
+		Retryable:  IsRetryableError,
+	}
+	if c.config.Debug {
+		retry.OnRetry = func(attempt int, err error, _ time.Duration) {
+			fmt.Printf("Attempt %d failed: %v, retrying...\n", attempt, err)
 		}
 	}
+	return retry.Do(ctx, func(ctx context.Context) error {
+		c.metrics.inFlight.Add(1)
+		err := fn(ctx)
+		c.metrics.inFlight.Add(-1)
+		if t := c.telemetry(); t != nil {
+			t.Record(c.current().Name(), err)
+		}
+		return err
+	})
+}
+
+// WaitForCompletion waits for a generation task to complete.
+// A positive pollInterval polls at a fixed rate; otherwise the PollPolicy of the
+// model the task was submitted with is used. Succeeded results are scored by the
+// configured Evaluators.
+func (c *Client) WaitForCompletion(ctx context.Context, taskID string, pollInterval time.Duration, opts ...CallOption) (*TaskResult, error) {
+	var policy *PollPolicy
+	if pollInterval > 0 {
+		policy = &PollPolicy{InitialDelay: pollInterval, Interval: pollInterval}
+	}
+
+	result, err := c.poll(ctx, taskID, poller{policy: policy}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.evaluateFinished(ctx, result)
+	return result, nil
+}
+
+// AwaitGeneration long-polls a task: it returns as soon as the task reaches a
+// terminal status, or the latest result once wait has elapsed. Polls follow the
+// PollPolicy of the task's model and completed results come from the ResultCache.
+func (c *Client) AwaitGeneration(ctx context.Context, taskID string, wait time.Duration, opts ...CallOption) (*TaskResult, error) {
+	if wait <= 0 {
+		return c.GetGeneration(ctx, taskID, opts...)
+	}
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	result, err := c.poll(ctx, taskID, poller{immediate: true, deadline: deadline.C}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// taskPending reports whether a task with this status is still running
+func taskPending(status TaskStatus) bool {
+	return status == TaskStatusQueued || status == TaskStatusProcessing
 }
 
 // GetProviderName returns the name of the current provider
 func (c *Client) GetProviderName() string {
-	return c.provider.Name()
+	return c.current().Name()
 }
 
 // GetSupportedModels returns supported models for the current provider
 func (c *Client) GetSupportedModels() []string {
-	return c.provider.SupportedModels()
+	return c.current().SupportedModels()
 }
 
-// createProvider creates a provider instance based on the provider type
+// PeerFeatures returns the X-Vidgo-Features hints of a downstream vidgo-based relay,
+// or nil if the provider has not seen any
+func (c *Client) PeerFeatures() adapters.Features {
+	if negotiator, ok := c.current().(adapters.FeatureNegotiator); ok {
+		return negotiator.PeerFeatures()
+	}
+	return nil
+}
+
+// createProvider creates a provider instance from the factory registered for the provider type
 func createProvider(providerType ProviderType, config *ProviderConfig) (Provider, error) {
 
 	adapterConfig := &adapters.ProviderConfig{
@@ -189,18 +479,36 @@ func createProvider(providerType ProviderType, config *ProviderConfig) (Provider
 		Timeout:    config.Timeout,
 		RetryCount: config.RetryCount,
 		Extra:      config.Extra,
+		APIVersion: config.APIVersion,
+		Endpoints:  config.Endpoints,
+
+		AllowUnknownExtra: config.AllowUnknownExtra,
 	}
 
-	switch providerType {
-	case ProviderKling:
-		adapterProvider, err := kling.New(adapterConfig)
-		if err != nil {
-			return nil, err
+	switch config.Environment {
+	case "", EnvironmentProduction:
+	case EnvironmentSandbox:
+		// None of the built-in providers publishes a sandbox endpoint, so a sandbox
+		// config must name one instead of falling back to the production default
+		if config.BaseURL == "" {
+			return nil, fmt.Errorf("%w: the %s environment requires base_url, e.g. a provider test endpoint or cmd/fakekling", ErrInvalidConfiguration, EnvironmentSandbox)
 		}
-		return &adapterWrapper{provider: adapterProvider}, nil
 	default:
+		return nil, fmt.Errorf("%w: unknown environment %q, expected %s or %s", ErrInvalidConfiguration, config.Environment, EnvironmentProduction, EnvironmentSandbox)
+	}
+	if err := validateRegion(config.Region); err != nil {
+		return nil, err
+	}
+
+	factory, ok := adapters.Lookup(string(providerType))
+	if !ok {
 		return nil, ErrUnsupportedProvider
 	}
+	adapterProvider, err := factory(adapterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &adapterWrapper{provider: adapterProvider}, nil
 }
 
 // validateRequest validates the generation request
@@ -209,8 +517,16 @@ func (c *Client) validateRequest(req *GenerationRequest) error {
 		return &ValidationError{Field: "request", Message: "request cannot be nil"}
 	}
 
-	if req.Prompt == "" && req.Image == "" {
-		return &ValidationError{Field: "prompt/image", Message: "at least one of prompt or image must be provided"}
+	if req.Prompt == "" && req.Image == "" && len(req.ImageBytes) == 0 && req.ImageFile == "" && req.ImageTail == "" && len(req.Images) == 0 && len(req.Keyframes) == 0 {
+		return &ValidationError{Field: "prompt/image", Message: "at least one of prompt, image, image tail, images or keyframes must be provided"}
+	}
+
+	if err := validateImage(req); err != nil {
+		return err
+	}
+
+	if err := c.preflightImage(req); err != nil {
+		return err
 	}
 
 	if req.Duration <= 0 {
@@ -224,5 +540,42 @@ func (c *Client) validateRequest(req *GenerationRequest) error {
 	if req.Height <= 0 {
 		return &ValidationError{Field: "height", Message: "height must be positive"}
 	}
-	return c.provider.ValidateRequest(req)
+
+	switch req.ResponseFormat {
+	case "", ResponseFormatURL, ResponseFormatB64JSON:
+	default:
+		return &ValidationError{Field: "response_format", Message: "response format must be url or b64_json"}
+	}
+
+	if req.CameraControl != nil {
+		if err := req.CameraControl.Validate(); err != nil {
+			return &ValidationError{Field: "camera_control", Message: err.Error()}
+		}
+	}
+
+	if !adapters.ValidMode(req.Mode) {
+		return &ValidationError{Field: "mode", Message: fmt.Sprintf("mode must be %q or %q, got %q", ModeStandard, ModePro, req.Mode)}
+	}
+
+	if !adapters.ValidGuidanceScale(req.GuidanceScale) {
+		return &ValidationError{Field: "guidance_scale", Message: fmt.Sprintf("guidance scale must be between 0 and 1, got %g", *req.GuidanceScale)}
+	}
+
+	if !req.Watermark.Valid() {
+		return &ValidationError{Field: "watermark", Message: fmt.Sprintf("unknown watermark policy %q", req.Watermark)}
+	}
+
+	if req.CallbackURL != "" {
+		if err := adapters.ValidateHTTPURL(req.CallbackURL); err != nil {
+			return &ValidationError{Field: "callback_url", Message: err.Error()}
+		}
+	}
+	if err := c.current().ValidateRequest(req); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) || errors.Is(err, ErrInvalidRequest) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return nil
 }
@@ -0,0 +1,41 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetGenerationByClientID retrieves a task by the GenerationRequest.ClientTaskID it
+// was submitted with, so callers can correlate provider tasks with their own job
+// IDs and recover tasks whose provider task ID was lost in a crash
+func (c *Client) GetGenerationByClientID(ctx context.Context, clientTaskID string, opts ...CallOption) (*TaskResult, error) {
+	if clientTaskID == "" {
+		return nil, &ValidationError{Field: "client_task_id", Message: "client task ID cannot be empty"}
+	}
+
+	lookup, ok := c.current().(ClientTaskLookup)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support client task IDs", ErrUnsupportedOperation, c.current().Name())
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *TaskResult
+	err = c.withRetry(c.captureContext(ctx, "get_generation_by_client_id", clientTaskID), o, func(ctx context.Context) error {
+		var err error
+		result, err = lookup.GetGenerationByClientID(ctx, clientTaskID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !taskPending(result.Status) {
+		c.finished(result.TaskID, result.Status)
+	}
+	c.recordTimeline(result.TaskID, TimelineEvent{Type: TimelineEventPolled, Status: result.Status})
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestGetGenerationByClientID(t *testing.T) {
+	server := httptest.NewServer(fakekling.New("ak", "sk"))
+	defer server.Close()
+
+	newClient := func() *Client {
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+	ctx := context.Background()
+	req := &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720, ClientTaskID: "job-42"}
+
+	client := newClient()
+	resp, err := client.CreateGeneration(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	result, err := client.GetGenerationByClientID(ctx, "job-42")
+	if err != nil {
+		t.Fatalf("Failed to get generation by client ID: %v", err)
+	}
+	if result.TaskID != resp.TaskID || result.ClientTaskID != "job-42" {
+		t.Errorf("Expected task %s for job-42, got %+v", resp.TaskID, result)
+	}
+
+	// A restarted process only knows the client task ID
+	result, err = newClient().GetGenerationByClientID(ctx, "job-42")
+	if err != nil {
+		t.Fatalf("Failed to recover generation by client ID: %v", err)
+	}
+	if result.TaskID != resp.TaskID {
+		t.Errorf("Expected recovered task %s, got %s", resp.TaskID, result.TaskID)
+	}
+
+	if _, err := client.CreateGeneration(ctx, req); err == nil {
+		t.Error("Expected a duplicate client task ID to be rejected")
+	}
+	if _, err := client.GetGenerationByClientID(ctx, "job-missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for an unknown client task ID, got %v", err)
+	}
+}
+
+func TestClientTaskIDUnsupported(t *testing.T) {
+	client, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:       "A cat walking",
+		Duration:     5.0,
+		Width:        1280,
+		Height:       720,
+		ClientTaskID: "job-42",
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest for Luma client task ID, got %v", err)
+	}
+	if _, err := client.GetGenerationByClientID(context.Background(), "job-42"); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation for Luma lookup, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package vidgo
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -160,3 +161,126 @@ func TestIsRetryableError(t *testing.T) {
 		t.Error("400 error should not be retryable")
 	}
 }
+
+func TestKlingAPIVersionPayload(t *testing.T) {
+	server := newKlingStub(t)
+
+	req := &GenerationRequest{
+		Prompt:   "Test prompt",
+		Duration: 5.0,
+		Width:    512,
+		Height:   512,
+		Model:    "kling-v1-6",
+	}
+
+	tests := []struct {
+		version      string
+		hasModel     bool
+		hasModelName bool
+	}{
+		{"", true, true},
+		{"legacy", true, false},
+		{"v1", false, true},
+	}
+
+	for _, tt := range tests {
+		client, err := NewClient(ProviderKling, &ProviderConfig{
+			BaseURL:    server.URL,
+			APIKey:     "test_access_key,test_secret_key",
+			Timeout:    5 * time.Second,
+			APIVersion: tt.version,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create client for version '%s': %v", tt.version, err)
+		}
+
+		if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+			t.Fatalf("Failed to create generation for version '%s': %v", tt.version, err)
+		}
+
+		body := server.body()
+		if _, ok := body["model"]; ok != tt.hasModel {
+			t.Errorf("Version '%s': expected model present=%v, got %v", tt.version, tt.hasModel, ok)
+		}
+		if _, ok := body["model_name"]; ok != tt.hasModelName {
+			t.Errorf("Version '%s': expected model_name present=%v, got %v", tt.version, tt.hasModelName, ok)
+		}
+	}
+
+	if _, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", APIVersion: "v9"}); err == nil {
+		t.Error("Unknown API version should return error")
+	}
+}
+
+func TestEndpointTemplates(t *testing.T) {
+	server := newKlingStub(t)
+	client, err := NewClient(ProviderKling, &ProviderConfig{
+		BaseURL:   server.URL,
+		APIKey:    "test_access_key,test_secret_key",
+		Timeout:   5 * time.Second,
+		Endpoints: map[string]string{"*": "/proxy/kling/{version}/{task_type}"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &GenerationRequest{Prompt: "Test prompt", Image: "https://example.com/cat.png", Duration: 5.0, Width: 512, Height: 512}
+	resp, err := client.CreateGeneration(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if _, err := client.GetGeneration(context.Background(), resp.TaskID); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	expected := []string{"POST /proxy/kling/v1/image2video", "GET /proxy/kling/v1/image2video/task-1"}
+	paths := server.requestLog()
+	if len(paths) != 2 || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("Expected paths %v, got %v", expected, paths)
+	}
+
+	invalid := []map[string]string{
+		{"*": "/v1/videos"},
+		{"*": "v1/{task_type}"},
+		{"image2video": "/v1/{region}/image2video"},
+		{"text2speech": "/v1/text2speech"},
+	}
+	for _, endpoints := range invalid {
+		if _, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Endpoints: endpoints}); err == nil {
+			t.Errorf("Expected endpoints %v to be rejected", endpoints)
+		}
+	}
+}
+
+func TestSizePresets(t *testing.T) {
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req, err := client.resolveSize(&GenerationRequest{Prompt: "Test prompt", Duration: 5, Size: "Portrait"})
+	if err != nil {
+		t.Fatalf("Failed to resolve size: %v", err)
+	}
+	if req.Width != 720 || req.Height != 1280 {
+		t.Errorf("Expected 720x1280 for portrait, got %dx%d", req.Width, req.Height)
+	}
+
+	RegisterSizePreset(ProviderKling, "cinema", SizePreset{Width: 1920, Height: 816, AspectRatio: "16:9"})
+	t.Cleanup(func() { UnregisterSizePreset(ProviderKling, "cinema") })
+	if _, ok := SizePresets(ProviderKling)["cinema"]; !ok {
+		t.Error("Expected registered preset to be listed for Kling")
+	}
+	if _, ok := LookupSizePreset(ProviderJimeng, "cinema"); ok {
+		t.Error("Kling preset should not apply to Jimeng")
+	}
+
+	if _, err := client.resolveSize(&GenerationRequest{Prompt: "Test prompt", Duration: 5, Size: "panorama"}); err == nil {
+		t.Error("Unknown size preset should return error")
+	}
+
+	adaptor := NewKlingAdaptor()
+	if ratio := adaptor.getAspectRatio("reel"); ratio != "9:16" {
+		t.Errorf("Expected relay to map 'reel' to 9:16, got '%s'", ratio)
+	}
+}
@@ -0,0 +1,37 @@
+// Command fakekling serves an in-memory emulation of the Kling video API for
+// local development.
+//
+// Usage:
+//
+//	fakekling [-addr :8089] [-access-key ak] [-secret-key sk]
+//
+// Point a client at it with ProviderConfig{BaseURL: "http://localhost:8089", APIKey: "ak,sk"}.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func main() {
+	addr := flag.String("addr", ":8089", "listen address")
+	accessKey := flag.String("access-key", "ak", "access key accepted in the JWT iss claim")
+	secretKey := flag.String("secret-key", "sk", "secret key used to verify JWT signatures")
+	queuedPolls := flag.Int("queued-polls", 1, "status requests a task reports as submitted")
+	processingPolls := flag.Int("processing-polls", 1, "status requests a task reports as processing")
+	flag.Parse()
+
+	server := fakekling.New(*accessKey, *secretKey)
+	server.QueuedPolls = *queuedPolls
+	server.ProcessingPolls = *processingPolls
+
+	fmt.Printf("fake Kling API listening on %s (API key %q)\n", *addr, *accessKey+","+*secretKey)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		fmt.Fprintf(os.Stderr, "fakekling: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,285 @@
+// Command vidgo-migrate rewrites usages of the relay types duplicated in
+// github.com/feitianbubu/vidgo/adapters/kling, and of the root KlingAdaptor,
+// to their consolidated counterparts in the root github.com/feitianbubu/vidgo
+// package: KlingAdaptor becomes TaskAdaptor and NewKlingAdaptor becomes
+// NewTaskAdaptor.
+//
+// Usage:
+//
+//	vidgo-migrate [-w] path ...
+//
+// Directories are walked recursively and a trailing /... is accepted, so
+// ./... migrates the current module. Without -w the rewritten files are
+// printed to stdout.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	klingImportPath = "github.com/feitianbubu/vidgo/adapters/kling"
+	vidgoImportPath = "github.com/feitianbubu/vidgo"
+)
+
+// klingRenames maps deprecated adapters/kling identifiers to their root package names
+var klingRenames = map[string]string{
+	"TaskRelayInfo":    "TaskRelayInfo",
+	"TaskAdaptorError": "TaskAdaptorError",
+	"VidgoSubmitReq":   "VidgoSubmitReq",
+	"TaskResponse":     "TaskResponse",
+	"KlingAdaptor":     "TaskAdaptor",
+	"NewKlingAdaptor":  "NewTaskAdaptor",
+}
+
+// vidgoRenames maps deprecated root identifiers to their replacements
+var vidgoRenames = map[string]string{
+	"KlingAdaptor":    "TaskAdaptor",
+	"NewKlingAdaptor": "NewTaskAdaptor",
+}
+
+// adaptorNames are the identifiers that only migrate when the file does not
+// use KlingAdaptor methods TaskAdaptor lacks or types differently
+var adaptorNames = map[string]bool{"KlingAdaptor": true, "NewKlingAdaptor": true}
+
+// klingOnlyMethods are KlingAdaptor fields and methods without a TaskAdaptor
+// counterpart; TaskAdaptor.Submit and Fetch return vidgo rather than adapters types
+var klingOnlyMethods = map[string]bool{"Submit": true, "Fetch": true, "FetchTaskWait": true, "CompatHTTPResponse": true}
+
+func main() {
+	write := flag.Bool("w", false, "write result to source files instead of stdout")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: vidgo-migrate [-w] path ...")
+		os.Exit(2)
+	}
+
+	for _, arg := range flag.Args() {
+		if err := migratePath(arg, *write); err != nil {
+			log.Fatalf("Failed to migrate %s: %v", arg, err)
+		}
+	}
+}
+
+// migratePath migrates a file, or every Go file below a directory or pattern
+func migratePath(arg string, write bool) error {
+	root := walkRoot(arg)
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		return migrateFile(path, write)
+	})
+}
+
+// walkRoot turns a package pattern such as ./... into the directory to walk
+func walkRoot(arg string) string {
+	if arg == "..." {
+		return "."
+	}
+	if strings.HasSuffix(arg, "/...") {
+		if root := strings.TrimSuffix(arg, "/..."); root != "" {
+			return root
+		}
+		return "/"
+	}
+	return arg
+}
+
+// skipDir reports whether a directory is ignored by the go tool, e.g. vendor and testdata
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// migrateFile rewrites a single file, printing or writing it when it changed
+func migrateFile(path string, write bool) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	out, changed, kept, err := migrate(path, src)
+	if err != nil {
+		return err
+	}
+	if kept {
+		fmt.Fprintf(os.Stderr, "%s: uses KlingAdaptor methods TaskAdaptor lacks or types differently (Submit, Fetch, FetchTaskWait, CompatHTTPResponse); KlingAdaptor left unchanged\n", path)
+	}
+	if !changed {
+		return nil
+	}
+
+	if write {
+		fmt.Fprintf(os.Stderr, "migrated %s\n", path)
+		return os.WriteFile(path, out, 0o644)
+	}
+	fmt.Printf("// %s\n%s\n", path, out)
+	return nil
+}
+
+// migrate rewrites src and reports whether anything changed. kept reports
+// that KlingAdaptor usages were left alone because the file calls methods
+// TaskAdaptor does not have in the same shape.
+func migrate(filename string, src []byte) (out []byte, changed, kept bool, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	klingName, klingSpec := importName(file, klingImportPath, "kling")
+	vidgoName, vidgoSpec := importName(file, vidgoImportPath, "vidgo")
+	if klingSpec == nil && vidgoSpec == nil {
+		return src, false, false, nil
+	}
+	keepAdaptor := usesKlingOnlyMethods(file)
+
+	klingStillUsed := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		var renames map[string]string
+		switch {
+		case klingSpec != nil && ident.Name == klingName:
+			renames = klingRenames
+		case vidgoSpec != nil && ident.Name == vidgoName:
+			renames = vidgoRenames
+		default:
+			return true
+		}
+
+		newName, ok := renames[sel.Sel.Name]
+		if ok && keepAdaptor && adaptorNames[sel.Sel.Name] {
+			kept = true
+			ok = false
+		}
+		if !ok {
+			if ident.Name == klingName && klingSpec != nil {
+				klingStillUsed = true
+			}
+			return true
+		}
+		ident.Name = vidgoName
+		sel.Sel.Name = newName
+		changed = true
+		return true
+	})
+
+	if !changed {
+		return src, false, kept, nil
+	}
+
+	if klingSpec != nil {
+		if vidgoSpec == nil {
+			klingSpec.Path.Value = strconv.Quote(vidgoImportPath)
+			klingSpec.Name = nil
+			if klingStillUsed {
+				addImport(file, klingImportPath, klingName)
+			}
+		} else if !klingStillUsed {
+			removeImport(file, klingSpec)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, false, false, err
+	}
+	return buf.Bytes(), true, kept, nil
+}
+
+// usesKlingOnlyMethods reports whether the file selects a KlingAdaptor field or
+// method TaskAdaptor lacks. Without type information any selector of that name counts.
+func usesKlingOnlyMethods(file *ast.File) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && klingOnlyMethods[sel.Sel.Name] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// importName returns the local name and spec of an import, or nil if it is absent
+func importName(file *ast.File, path, defaultName string) (string, *ast.ImportSpec) {
+	for _, spec := range file.Imports {
+		if importPath, _ := strconv.Unquote(spec.Path.Value); importPath == path {
+			if spec.Name != nil {
+				return spec.Name.Name, spec
+			}
+			return defaultName, spec
+		}
+	}
+	return defaultName, nil
+}
+
+// addImport appends an import spec to the first import declaration
+func addImport(file *ast.File, path, name string) {
+	spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+	if name != "kling" {
+		spec.Name = ast.NewIdent(name)
+	}
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			if !gen.Lparen.IsValid() {
+				gen.Lparen = gen.Pos()
+			}
+			gen.Specs = append(gen.Specs, spec)
+			file.Imports = append(file.Imports, spec)
+			return
+		}
+	}
+}
+
+// removeImport drops an import spec from the file
+func removeImport(file *ast.File, target *ast.ImportSpec) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for i, spec := range gen.Specs {
+			if spec == target {
+				gen.Specs = append(gen.Specs[:i], gen.Specs[i+1:]...)
+				break
+			}
+		}
+	}
+	for i, spec := range file.Imports {
+		if spec == target {
+			file.Imports = append(file.Imports[:i], file.Imports[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	src := `package relay
+
+import "github.com/feitianbubu/vidgo/adapters/kling"
+
+func submit(body []byte) (*kling.VidgoSubmitReq, *kling.TaskAdaptorError) {
+	adaptor := kling.NewKlingAdaptor()
+	adaptor.Init(&kling.TaskRelayInfo{ApiKey: "ak,sk"})
+	return adaptor.ValidateRequestAndSetAction(body, "generate")
+}
+`
+
+	out, changed, _, err := migrate("relay.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if !changed {
+		t.Fatal("Expected source to change")
+	}
+
+	result := string(out)
+	if strings.Contains(result, "kling.") || strings.Contains(result, "adapters/kling") {
+		t.Errorf("Expected all kling usages to be rewritten, got:\n%s", result)
+	}
+	for _, want := range []string{`"github.com/feitianbubu/vidgo"`, "vidgo.VidgoSubmitReq", "vidgo.NewTaskAdaptor()", "vidgo.TaskRelayInfo"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output:\n%s", want, result)
+		}
+	}
+}
+
+func TestMigrateKeepsOtherKlingUsages(t *testing.T) {
+	src := `package relay
+
+import (
+	"github.com/feitianbubu/vidgo"
+	"github.com/feitianbubu/vidgo/adapters/kling"
+)
+
+var _ = kling.New
+var _ *kling.TaskRelayInfo
+var _ = vidgo.ProviderKling
+`
+
+	out, changed, _, err := migrate("relay.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if !changed {
+		t.Fatal("Expected source to change")
+	}
+
+	result := string(out)
+	if !strings.Contains(result, "kling.New") || !strings.Contains(result, "*vidgo.TaskRelayInfo") {
+		t.Errorf("Unexpected migration output:\n%s", result)
+	}
+	if !strings.Contains(result, `"github.com/feitianbubu/vidgo/adapters/kling"`) {
+		t.Errorf("Expected kling import to be kept:\n%s", result)
+	}
+}
+
+func TestMigrateRootKlingAdaptor(t *testing.T) {
+	src := `package relay
+
+import "github.com/feitianbubu/vidgo"
+
+var adaptor *vidgo.KlingAdaptor = vidgo.NewKlingAdaptor()
+`
+
+	out, changed, kept, err := migrate("relay.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if !changed || kept {
+		t.Fatalf("Expected the adaptor to be migrated, got changed=%v kept=%v", changed, kept)
+	}
+	if result := string(out); !strings.Contains(result, "*vidgo.TaskAdaptor = vidgo.NewTaskAdaptor()") {
+		t.Errorf("Expected KlingAdaptor to become TaskAdaptor:\n%s", result)
+	}
+}
+
+func TestMigrateKeepsKlingOnlyAdaptorMethods(t *testing.T) {
+	src := `package relay
+
+import "github.com/feitianbubu/vidgo/adapters/kling"
+
+func fetch(id string) {
+	adaptor := kling.NewKlingAdaptor()
+	adaptor.Init(&kling.TaskRelayInfo{ApiKey: "ak,sk"})
+	adaptor.Fetch(nil, id)
+}
+`
+
+	out, changed, kept, err := migrate("relay.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if !changed || !kept {
+		t.Fatalf("Expected a partial migration, got changed=%v kept=%v", changed, kept)
+	}
+
+	result := string(out)
+	if !strings.Contains(result, "kling.NewKlingAdaptor()") || !strings.Contains(result, "&vidgo.TaskRelayInfo{") {
+		t.Errorf("Expected only the relay types to be migrated:\n%s", result)
+	}
+	if !strings.Contains(result, `"github.com/feitianbubu/vidgo/adapters/kling"`) {
+		t.Errorf("Expected kling import to be kept:\n%s", result)
+	}
+}
+
+func TestMigratePathPattern(t *testing.T) {
+	dir := t.TempDir()
+	src := "package relay\n\nimport \"github.com/feitianbubu/vidgo/adapters/kling\"\n\nvar _ *kling.VidgoSubmitReq\n"
+	for _, name := range []string{"relay/relay.go", "vendor/dep/dep.go", "relay/testdata/fixture.go"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := migratePath(dir+"/...", true); err != nil {
+		t.Fatalf("Failed to migrate %s/...: %v", dir, err)
+	}
+
+	for name, wantMigrated := range map[string]bool{"relay/relay.go": true, "vendor/dep/dep.go": false, "relay/testdata/fixture.go": false} {
+		out, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if migrated := strings.Contains(string(out), "*vidgo.VidgoSubmitReq"); migrated != wantMigrated {
+			t.Errorf("Expected %s migrated=%v, got:\n%s", name, wantMigrated, out)
+		}
+	}
+}
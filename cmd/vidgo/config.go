@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/feitianbubu/vidgo"
+)
+
+// configValidate implements `vidgo config validate`: it checks config files
+// against the vidgo.Config format and prints one line per problem
+func configValidate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected at least one config file")
+	}
+
+	failed := false
+	for _, path := range args {
+		if _, err := vidgo.LoadConfig(path); err != nil {
+			failed = true
+			var joined interface{ Unwrap() []error }
+			if errors.As(err, &joined) {
+				for _, e := range joined.Unwrap() {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", path, e)
+				}
+			} else {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			continue
+		}
+		fmt.Printf("%s: ok\n", path)
+	}
+	if failed {
+		return fmt.Errorf("invalid configuration")
+	}
+	return nil
+}
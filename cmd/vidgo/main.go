@@ -0,0 +1,124 @@
+// Command vidgo is a command line client for the vidgo SDK.
+//
+// Usage:
+//
+//	vidgo batch submit [flags] file.csv|file.json
+//	vidgo support-bundle [flags]
+//	vidgo config validate file.json...
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo"
+)
+
+const usage = `usage:
+  vidgo batch submit [flags] file.csv|file.json
+  vidgo support-bundle [flags]
+  vidgo config validate file.json...`
+
+func main() {
+	var err error
+	switch {
+	case len(os.Args) >= 3 && os.Args[1] == "batch" && os.Args[2] == "submit":
+		err = batchSubmit(os.Args[3:])
+	case len(os.Args) >= 2 && os.Args[1] == "support-bundle":
+		err = supportBundle(os.Args[2:])
+	case len(os.Args) >= 3 && os.Args[1] == "config" && os.Args[2] == "validate":
+		err = configValidate(os.Args[3:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vidgo: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// batchSubmit implements `vidgo batch submit`
+func batchSubmit(args []string) error {
+	fs := flag.NewFlagSet("batch submit", flag.ExitOnError)
+	provider := fs.String("provider", string(vidgo.ProviderKling), "provider type")
+	baseURL := fs.String("base-url", "", "provider base URL")
+	apiKey := fs.String("api-key", os.Getenv("VIDGO_API_KEY"), "provider API key (defaults to $VIDGO_API_KEY)")
+	format := fs.String("format", "", "input format: csv or json (inferred from the file extension)")
+	manifestPath := fs.String("manifest", "", "results manifest path (defaults to stdout)")
+	concurrency := fs.Int("concurrency", 4, "maximum tasks in flight")
+	wait := fs.Bool("wait", true, "wait for each task to finish before recording it")
+	pollInterval := fs.Duration("poll-interval", 0, "fixed poll interval (zero uses the adaptive policy)")
+	timeout := fs.Duration("timeout", 30*time.Minute, "overall timeout")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected exactly one input file")
+	}
+	path := fs.Arg(0)
+
+	bulkFormat := vidgo.BulkFormat(*format)
+	if bulkFormat == "" {
+		bulkFormat = vidgo.BulkFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+		if bulkFormat == "jsonl" {
+			bulkFormat = vidgo.BulkFormatJSON
+		}
+	}
+
+	input, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	reqs, err := vidgo.ReadBulkRequests(input, bulkFormat)
+	if err != nil {
+		return err
+	}
+
+	client, err := vidgo.NewClient(vidgo.ProviderType(*provider), &vidgo.ProviderConfig{
+		BaseURL: *baseURL,
+		APIKey:  *apiKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	var manifest io.Writer = os.Stdout
+	if *manifestPath != "" {
+		file, err := os.Create(*manifestPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		manifest = file
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	results, err := client.SubmitBulk(ctx, reqs, &vidgo.BulkOptions{
+		Concurrency:  *concurrency,
+		PollInterval: *pollInterval,
+		Wait:         *wait,
+	}, manifest)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "submitted %d rows, %d failed\n", len(results), failed)
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo"
+)
+
+// supportBundle implements `vidgo support-bundle`: it writes a tar.gz holding the
+// effective configuration and the most recent lines of an audit log
+func supportBundle(args []string) error {
+	fs := flag.NewFlagSet("support-bundle", flag.ExitOnError)
+	provider := fs.String("provider", string(vidgo.ProviderKling), "provider type")
+	configPath := fs.String("config", "", "JSON provider config file, as read by FileConfigSource")
+	baseURL := fs.String("base-url", "", "provider base URL (without -config)")
+	apiKey := fs.String("api-key", os.Getenv("VIDGO_API_KEY"), "provider API key (without -config, defaults to $VIDGO_API_KEY)")
+	auditPath := fs.String("audit", "", "audit log to include, e.g. JSON lines of ReloadEvent")
+	auditLines := fs.Int("audit-lines", 500, "most recent audit log lines to include")
+	redact := fs.Bool("redact", true, "mask credentials in the configuration")
+	output := fs.String("o", "", "bundle path (defaults to vidgo-support-<time>.tar.gz)")
+	fs.Parse(args)
+
+	config := &vidgo.ProviderConfig{BaseURL: *baseURL, APIKey: *apiKey}
+	if *configPath != "" {
+		var err error
+		if config, err = vidgo.FileConfigSource(*configPath)(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	client, err := vidgo.NewClient(vidgo.ProviderType(*provider), config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	dump, err := client.DumpConfig(*redact)
+	if err != nil {
+		return err
+	}
+
+	files := []bundleFile{{name: "config.json", data: dump}}
+	if *auditPath != "" {
+		audit, err := tailLines(*auditPath, *auditLines)
+		if err != nil {
+			return err
+		}
+		files = append(files, bundleFile{name: "audit.log", data: audit})
+	}
+
+	if *output == "" {
+		*output = "vidgo-support-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	}
+	if err := writeBundle(*output, files); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *output)
+	return nil
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// writeBundle writes files into a gzipped tar archive at path
+func writeBundle(path string, files []bundleFile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		header := &tar.Header{Name: f.name, Mode: 0600, Size: int64(len(f.data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// tailLines returns the last n lines of the file at path
+func tailLines(path string, n int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
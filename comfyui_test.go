@@ -0,0 +1,155 @@
+package vidgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testComfyWorkflow = `{
+	"3": {"class_type": "KSampler", "inputs": {"seed": "{{seed}}", "steps": 30}},
+	"6": {"class_type": "CLIPTextEncode", "inputs": {"text": "{{prompt}}"}},
+	"10": {"class_type": "LoadImage", "inputs": {"image": "{{image}}"}},
+	"20": {"class_type": "EmptyHunyuanLatentVideo", "inputs": {"width": "{{width}}", "height": "{{height}}", "length": "{{frames}}"}},
+	"30": {"class_type": "VHS_VideoCombine", "inputs": {"frame_rate": "{{fps}}", "filename_prefix": "vidgo_{{seed}}"}}
+}`
+
+func TestComfyUIProvider(t *testing.T) {
+	var queued map[string]interface{}
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/upload/image":
+			file, header, err := r.FormFile("image")
+			if err != nil {
+				t.Errorf("Expected image upload: %v", err)
+				return
+			}
+			file.Close()
+			w.Write([]byte(`{"name":"` + header.Filename + `","subfolder":"","type":"input"}`))
+		case r.URL.Path == "/prompt":
+			json.NewDecoder(r.Body).Decode(&queued)
+			w.Write([]byte(`{"prompt_id":"prompt-1","number":1,"node_errors":{}}`))
+		case r.URL.Path == "/queue":
+			w.Write([]byte(`{"queue_running":[[1,"prompt-1",{},{},["30"]]],"queue_pending":[]}`))
+		case r.URL.Path == "/history/prompt-1":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				w.Write([]byte(`{}`))
+				return
+			}
+			w.Write([]byte(`{"prompt-1":{"outputs":{"9":{"images":[{"filename":"preview.png","subfolder":"","type":"temp"}],"animated":[false]},"30":{"gifs":[{"filename":"vidgo_00001.mp4","subfolder":"videos","type":"output","format":"video/h264-mp4"}]}},"status":{"status_str":"success","completed":true,"messages":[]}}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderComfyUI, &ProviderConfig{
+		BaseURL: server.URL,
+		Timeout: 5 * time.Second,
+		Extra:   map[string]string{"workflow": testComfyWorkflow},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	seed := 42
+	req := &GenerationRequest{
+		Prompt:     "A cat",
+		ImageBytes: testPNG(64, 64),
+		Duration:   2,
+		FPS:        24,
+		Width:      848,
+		Height:     480,
+		Seed:       &seed,
+		Metadata:   map[string]interface{}{"inputs": map[string]interface{}{"3.steps": 50}},
+	}
+	resp, err := client.CreateGeneration(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if resp.TaskID != "prompt-1" {
+		t.Errorf("Expected prompt-1, got %s", resp.TaskID)
+	}
+
+	workflow := queued["prompt"].(map[string]interface{})
+	inputs := func(node string) map[string]interface{} {
+		return workflow[node].(map[string]interface{})["inputs"].(map[string]interface{})
+	}
+	if inputs("3")["seed"] != 42.0 || inputs("3")["steps"] != 50.0 {
+		t.Errorf("Unexpected sampler inputs: %v", inputs("3"))
+	}
+	if inputs("6")["text"] != "A cat" || inputs("20")["width"] != 848.0 || inputs("20")["length"] != 49.0 {
+		t.Errorf("Unexpected placeholder substitution: %v", workflow)
+	}
+	sum := sha256.Sum256(req.ImageBytes)
+	if name := "vidgo-" + hex.EncodeToString(sum[:16]) + ".png"; inputs("10")["image"] != name {
+		t.Errorf("Expected uploaded image name %s, got %v", name, inputs("10")["image"])
+	}
+	if inputs("30")["filename_prefix"] != "vidgo_42" {
+		t.Errorf("Expected vidgo_42, got %v", inputs("30")["filename_prefix"])
+	}
+
+	result, err := client.GetGeneration(context.Background(), "prompt-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusProcessing {
+		t.Errorf("Expected running prompt to be processing, got %s", result.Status)
+	}
+
+	result, err = client.GetGeneration(context.Background(), "prompt-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	want := server.URL + "/view?filename=vidgo_00001.mp4&subfolder=videos&type=output"
+	if result.Status != TaskStatusSucceeded || result.URL != want || result.Format != "mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	// The workflow needs an image
+	if err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 2, Width: 848, Height: 480}); err == nil {
+		t.Error("Request without image should return error")
+	}
+}
+
+func TestComfyUIFetchImageLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cat.png":
+			w.Write(testPNG(64, 64))
+			w.Write(make([]byte, 21<<20))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderComfyUI, &ProviderConfig{
+		BaseURL: server.URL,
+		Timeout: 5 * time.Second,
+		Extra:   map[string]string{"workflow": testComfyWorkflow},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat",
+		Image:    server.URL + "/cat.png",
+		Duration: 2,
+		Width:    848,
+		Height:   480,
+	})
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Expected oversized image to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jwtIssuer returns the iss claim of a Bearer JWT without verifying it
+func jwtIssuer(authorization string) string {
+	parts := strings.Split(strings.TrimPrefix(authorization, "Bearer "), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+	}
+	json.Unmarshal(payload, &claims)
+	return claims.Iss
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	server := newKlingStub(t)
+	server.reply = klingSucceeded
+	client := server.client(t, &ClientConfig{
+		Timeout:   5 * time.Second,
+		Timelines: NewTimelineStore(),
+		Planner:   NewPlanner(nil),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := &GenerationRequest{Prompt: fmt.Sprintf("prompt %d", i), Duration: 5, Width: 1280, Height: 720, Size: "landscape"}
+
+			var opts []CallOption
+			if i%2 == 0 {
+				opts = append(opts, WithAPIKey(fmt.Sprintf("ak-%d,sk-%d", i, i)))
+			}
+			resp, err := client.CreateGeneration(context.Background(), req, opts...)
+			if err != nil {
+				t.Errorf("CreateGeneration failed: %v", err)
+				return
+			}
+			if _, err := client.GetGeneration(context.Background(), resp.TaskID, opts...); err != nil {
+				t.Errorf("GetGeneration failed: %v", err)
+			}
+			client.GetTimeline(resp.TaskID)
+		}(i)
+	}
+	wg.Wait()
+
+	if bodies := server.submissions(); len(bodies) != 20 {
+		t.Errorf("Expected 20 submissions, got %d", len(bodies))
+	}
+}
+
+func TestTaskAdaptorConcurrentRelays(t *testing.T) {
+	var mu sync.Mutex
+	var mismatches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Prompt string `json:"prompt"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		// Each relay uses prompt "p-N" with key "ak-N"; a shared adaptor would mix them up
+		issuer := jwtIssuer(r.Header.Get("Authorization"))
+		if strings.TrimPrefix(body.Prompt, "p-") != strings.TrimPrefix(issuer, "ak-") {
+			mu.Lock()
+			mismatches = append(mismatches, body.Prompt+"/"+issuer)
+			mu.Unlock()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+	}))
+	defer server.Close()
+
+	adaptor := NewTaskAdaptor()
+	info := &TaskRelayInfo{BaseUrl: server.URL, Action: "generate"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			relayInfo := *info
+			relayInfo.ApiKey = fmt.Sprintf("ak-%d,sk-%d", i, i)
+			body := []byte(fmt.Sprintf(`{"prompt":"p-%d","metadata":{"image":"https://example.com/a.png"}}`, i))
+			if _, _, taskErr := adaptor.ProcessVideoGeneration(&relayInfo, body); taskErr != nil {
+				t.Errorf("ProcessVideoGeneration failed: %v", taskErr)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(mismatches) > 0 {
+		t.Errorf("Expected each relay to use its own credentials, got mismatches: %v", mismatches)
+	}
+}
+
+func TestConcurrentJWTCreation(t *testing.T) {
+	adaptor := NewKlingAdaptor()
+	adaptor.Init(&TaskRelayInfo{ApiKey: "test_access_key,test_secret_key"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := adaptor.createJWTToken()
+			if err != nil || jwtIssuer("Bearer "+token) != "test_access_key" {
+				t.Errorf("Expected token issued for test_access_key, got %q (%v)", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
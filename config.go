@@ -0,0 +1,333 @@
+package vidgo
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// ConfigSchema is the JSON Schema (draft 2020-12) of the Config file format, for
+// validating templated deployment configs before they are rolled out
+//
+//go:embed docs/config.schema.json
+var ConfigSchema []byte
+
+// Config is the file format for deploying a set of vidgo clients. It is decoded
+// strictly: unknown fields are errors, so typos fail validation instead of being
+// ignored. Durations are strings such as "30s".
+type Config struct {
+	Providers map[ProviderType]*ProviderSettings `json:"providers"`
+	Client    ClientSettings                     `json:"client"`
+	Queue     QueueSettings                      `json:"queue"`
+}
+
+// ProviderSettings configures one provider in a Config
+type ProviderSettings struct {
+	BaseURL           string            `json:"base_url,omitempty"`
+	APIKey            string            `json:"api_key,omitempty"`
+	SecretKey         string            `json:"secret_key,omitempty"`
+	Timeout           ConfigDuration    `json:"timeout,omitempty"`
+	RetryCount        int               `json:"retry_count,omitempty"`
+	Extra             map[string]string `json:"extra,omitempty"`
+	AllowUnknownExtra bool              `json:"allow_unknown_extra,omitempty"`
+	APIVersion        string            `json:"api_version,omitempty"`
+	Endpoints         map[string]string `json:"endpoints,omitempty"`
+	Environment       string            `json:"environment,omitempty"`
+	Region            string            `json:"region,omitempty"`
+}
+
+// ClientSettings configures the clients built from a Config
+type ClientSettings struct {
+	Timeout             ConfigDuration `json:"timeout,omitempty"`
+	MaxRetries          *int           `json:"max_retries,omitempty"`
+	RetryDelay          ConfigDuration `json:"retry_delay,omitempty"`
+	Debug               bool           `json:"debug,omitempty"`
+	DisallowBYOK        bool           `json:"disallow_byok,omitempty"`
+	NormalizeImages     bool           `json:"normalize_images,omitempty"`
+	Prewarm             bool           `json:"prewarm,omitempty"`
+	WarmInterval        ConfigDuration `json:"warm_interval,omitempty"`
+	DownloadTimeout     ConfigDuration `json:"download_timeout,omitempty"`
+	DownloadReadTimeout ConfigDuration `json:"download_read_timeout,omitempty"`
+	MaxB64Size          int64          `json:"max_b64_size,omitempty"`
+	CaptureDir          string         `json:"capture_dir,omitempty"`
+	TokenMinTTL         ConfigDuration `json:"token_min_ttl,omitempty"`
+	TokenMaxTTL         ConfigDuration `json:"token_max_ttl,omitempty"`
+	Poll                *PollSettings  `json:"poll,omitempty"`
+}
+
+// PollSettings is the default PollPolicy of the clients built from a Config
+type PollSettings struct {
+	InitialDelay ConfigDuration `json:"initial_delay,omitempty"`
+	Interval     ConfigDuration `json:"interval,omitempty"`
+	Multiplier   float64        `json:"multiplier,omitempty"`
+	MaxInterval  ConfigDuration `json:"max_interval,omitempty"`
+	Jitter       float64        `json:"jitter,omitempty"`
+}
+
+// QueueSettings configures concurrency limits
+type QueueSettings struct {
+	// ProviderConcurrency is the provider account's concurrent task limit
+	ProviderConcurrency int `json:"provider_concurrency,omitempty"`
+
+	// BatchConcurrency is the default BatchOptions and BulkOptions concurrency
+	BatchConcurrency int `json:"batch_concurrency,omitempty"`
+}
+
+// ConfigDuration is a time.Duration encoded as a string such as "1m30s"
+type ConfigDuration time.Duration
+
+func (d ConfigDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *ConfigDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*d = ConfigDuration(v)
+	return nil
+}
+
+// LoadConfig reads, defaults and validates the Config at path
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig strictly decodes a JSON Config, applies defaults and validates it
+func ParseConfig(data []byte) (*Config, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var config Config
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode config: %v", ErrInvalidConfiguration, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected data after the config object", ErrInvalidConfiguration)
+	}
+	config.ApplyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// ApplyDefaults fills unset client and queue settings with the values NewClient
+// and the batch helpers would use
+func (c *Config) ApplyDefaults() {
+	defaults := DefaultClientConfig()
+	if c.Client.Timeout == 0 {
+		c.Client.Timeout = ConfigDuration(defaults.Timeout)
+	}
+	if c.Client.MaxRetries == nil {
+		retries := defaults.MaxRetries
+		c.Client.MaxRetries = &retries
+	}
+	if c.Client.RetryDelay == 0 {
+		c.Client.RetryDelay = ConfigDuration(defaults.RetryDelay)
+	}
+	if poll := c.Client.Poll; poll != nil {
+		policy := DefaultPollPolicy()
+		if poll.InitialDelay == 0 {
+			poll.InitialDelay = ConfigDuration(policy.InitialDelay)
+		}
+		if poll.Interval == 0 {
+			poll.Interval = ConfigDuration(policy.Interval)
+		}
+		if poll.Multiplier == 0 {
+			poll.Multiplier = policy.Multiplier
+		}
+		if poll.MaxInterval == 0 {
+			poll.MaxInterval = ConfigDuration(policy.MaxInterval)
+		}
+	}
+	if c.Queue.BatchConcurrency == 0 {
+		c.Queue.BatchConcurrency = 4
+	}
+}
+
+// Validate reports every problem in the config as joined *ValidationError values
+// whose Field is the JSON path, e.g. "providers.kling.base_url"
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	nonNegative := func(field string, d ConfigDuration) {
+		if d < 0 {
+			invalid(field, "must not be negative")
+		}
+	}
+
+	if len(c.Providers) == 0 {
+		invalid("providers", "at least one provider is required")
+	}
+	names := make([]string, 0, len(c.Providers))
+	for name := range c.Providers {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := "providers." + name
+		settings := c.Providers[ProviderType(name)]
+		if _, ok := adapters.Lookup(name); !ok {
+			invalid(field, "unknown provider, expected one of %v", RegisteredProviders())
+			continue
+		}
+		if settings == nil {
+			invalid(field, "must be an object")
+			continue
+		}
+		if settings.BaseURL != "" {
+			if err := adapters.ValidateHTTPURL(settings.BaseURL); err != nil {
+				invalid(field+".base_url", "%v", err)
+			}
+		}
+		switch settings.Environment {
+		case "", EnvironmentProduction:
+		case EnvironmentSandbox:
+			if settings.BaseURL == "" {
+				invalid(field+".base_url", "is required in the %s environment", EnvironmentSandbox)
+			}
+		default:
+			invalid(field+".environment", "must be %s or %s", EnvironmentProduction, EnvironmentSandbox)
+		}
+		if validateRegion(settings.Region) != nil {
+			invalid(field+".region", "must be %s or %s", RegionCN, RegionGlobal)
+		}
+		nonNegative(field+".timeout", settings.Timeout)
+		if settings.RetryCount < 0 {
+			invalid(field+".retry_count", "must not be negative")
+		}
+	}
+
+	nonNegative("client.timeout", c.Client.Timeout)
+	if c.Client.MaxRetries != nil && *c.Client.MaxRetries < 0 {
+		invalid("client.max_retries", "must not be negative")
+	}
+	nonNegative("client.retry_delay", c.Client.RetryDelay)
+	nonNegative("client.warm_interval", c.Client.WarmInterval)
+	nonNegative("client.download_timeout", c.Client.DownloadTimeout)
+	nonNegative("client.download_read_timeout", c.Client.DownloadReadTimeout)
+	if c.Client.MaxB64Size < 0 {
+		invalid("client.max_b64_size", "must not be negative")
+	}
+	nonNegative("client.token_min_ttl", c.Client.TokenMinTTL)
+	nonNegative("client.token_max_ttl", c.Client.TokenMaxTTL)
+	if c.Client.TokenMaxTTL > 0 && c.Client.TokenMinTTL > c.Client.TokenMaxTTL {
+		invalid("client.token_min_ttl", "must not exceed token_max_ttl")
+	}
+	if c.Client.WarmInterval > 0 && !c.Client.Prewarm {
+		invalid("client.warm_interval", "requires prewarm")
+	}
+	if poll := c.Client.Poll; poll != nil {
+		nonNegative("client.poll.initial_delay", poll.InitialDelay)
+		if poll.Interval <= 0 {
+			invalid("client.poll.interval", "must be positive")
+		}
+		if poll.Multiplier < 1 {
+			invalid("client.poll.multiplier", "must be at least 1")
+		}
+		if poll.MaxInterval < poll.Interval {
+			invalid("client.poll.max_interval", "must not be less than interval")
+		}
+		if poll.Jitter < 0 || poll.Jitter > 1 {
+			invalid("client.poll.jitter", "must be between 0 and 1")
+		}
+	}
+
+	if c.Queue.ProviderConcurrency < 0 {
+		invalid("queue.provider_concurrency", "must not be negative")
+	}
+	if c.Queue.BatchConcurrency < 0 {
+		invalid("queue.batch_concurrency", "must not be negative")
+	}
+	return errors.Join(errs...)
+}
+
+// ProviderConfig returns the ProviderConfig for NewClient
+func (s *ProviderSettings) ProviderConfig() *ProviderConfig {
+	return &ProviderConfig{
+		BaseURL:           s.BaseURL,
+		APIKey:            s.APIKey,
+		SecretKey:         s.SecretKey,
+		Timeout:           time.Duration(s.Timeout),
+		RetryCount:        s.RetryCount,
+		Extra:             s.Extra,
+		AllowUnknownExtra: s.AllowUnknownExtra,
+		APIVersion:        s.APIVersion,
+		Endpoints:         s.Endpoints,
+		Environment:       s.Environment,
+		Region:            s.Region,
+	}
+}
+
+// ClientConfig returns the ClientConfig for NewClient
+func (c *Config) ClientConfig() *ClientConfig {
+	config := DefaultClientConfig()
+	config.Timeout = time.Duration(c.Client.Timeout)
+	if c.Client.MaxRetries != nil {
+		config.MaxRetries = *c.Client.MaxRetries
+	}
+	config.RetryDelay = time.Duration(c.Client.RetryDelay)
+	config.Debug = c.Client.Debug
+	config.DisallowBYOK = c.Client.DisallowBYOK
+	config.NormalizeImages = c.Client.NormalizeImages
+	config.Prewarm = c.Client.Prewarm
+	config.WarmInterval = time.Duration(c.Client.WarmInterval)
+	config.DownloadTimeout = time.Duration(c.Client.DownloadTimeout)
+	config.DownloadReadTimeout = time.Duration(c.Client.DownloadReadTimeout)
+	config.MaxB64Size = c.Client.MaxB64Size
+	if c.Client.CaptureDir != "" {
+		config.Capture = &ExampleCapture{Dir: c.Client.CaptureDir}
+	}
+	config.TokenMinTTL = time.Duration(c.Client.TokenMinTTL)
+	config.TokenMaxTTL = time.Duration(c.Client.TokenMaxTTL)
+	config.ProviderConcurrency = c.Queue.ProviderConcurrency
+	if poll := c.Client.Poll; poll != nil {
+		config.PollPolicies = map[string]*PollPolicy{"": {
+			InitialDelay: time.Duration(poll.InitialDelay),
+			Interval:     time.Duration(poll.Interval),
+			Multiplier:   poll.Multiplier,
+			MaxInterval:  time.Duration(poll.MaxInterval),
+			Jitter:       poll.Jitter,
+		}}
+	}
+	return config
+}
+
+// NewClients creates a client for every configured provider. Each client gets
+// its own ClientConfig.
+func (c *Config) NewClients() (map[ProviderType]*Client, error) {
+	clients := make(map[ProviderType]*Client, len(c.Providers))
+	for name, settings := range c.Providers {
+		client, err := NewClient(name, settings.ProviderConfig(), c.ClientConfig())
+		if err != nil {
+			for _, created := range clients {
+				created.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		clients[name] = client
+	}
+	return clients, nil
+}
@@ -0,0 +1,130 @@
+package vidgo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`{
+		"providers": {
+			"kling": {"api_key": "ak,sk", "timeout": "45s"},
+			"wanx": {"api_key": "sk-test", "extra": {"region": "intl"}, "allow_unknown_extra": true}
+		},
+		"client": {"max_retries": 0, "poll": {"interval": "10s", "jitter": 0.1}},
+		"queue": {"provider_concurrency": 5}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	if time.Duration(config.Providers[ProviderKling].Timeout) != 45*time.Second {
+		t.Errorf("Expected kling timeout 45s, got %v", time.Duration(config.Providers[ProviderKling].Timeout))
+	}
+	clientConfig := config.ClientConfig()
+	if clientConfig.Timeout != 30*time.Second || clientConfig.RetryDelay != time.Second {
+		t.Errorf("Expected default timeout and retry delay, got %v and %v", clientConfig.Timeout, clientConfig.RetryDelay)
+	}
+	if clientConfig.MaxRetries != 0 {
+		t.Errorf("Expected explicit max_retries 0 to be kept, got %d", clientConfig.MaxRetries)
+	}
+	if policy := clientConfig.PollPolicies[""]; policy == nil || policy.Interval != 10*time.Second || policy.MaxInterval != 30*time.Second || policy.Multiplier != 1.5 {
+		t.Errorf("Expected defaulted poll policy with a 10s interval, got %+v", policy)
+	}
+	if clientConfig.ProviderConcurrency != 5 || config.Queue.BatchConcurrency != 4 {
+		t.Errorf("Expected provider concurrency 5 and batch concurrency 4, got %d and %d", clientConfig.ProviderConcurrency, config.Queue.BatchConcurrency)
+	}
+
+	clients, err := config.NewClients()
+	if err != nil {
+		t.Fatalf("Failed to create clients: %v", err)
+	}
+	if len(clients) != 2 || clients[ProviderWanx] == nil {
+		t.Errorf("Expected kling and wanx clients, got %v", clients)
+	}
+}
+
+func TestParseConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected []string
+	}{
+		{"unknown field", `{"providers": {"kling": {"api_key": "ak,sk", "timout": "5s"}}}`, []string{"unknown field \"timout\""}},
+		{"numeric duration", `{"providers": {"kling": {"timeout": 30000000000}}}`, []string{"duration must be a string"}},
+		{"no providers", `{}`, []string{"providers"}},
+		{"multiple", `{
+			"providers": {"sora": {}, "luma": {"base_url": "ftp://example.com", "retry_count": -1}},
+			"client": {"warm_interval": "1m", "poll": {"interval": "10s", "max_interval": "5s"}}
+		}`, []string{"providers.sora", "providers.luma.base_url", "providers.luma.retry_count", "client.warm_interval", "client.poll.max_interval"}},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseConfig([]byte(tt.config))
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		for _, expected := range tt.expected {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("%s: expected error mentioning %s, got %v", tt.name, expected, err)
+			}
+		}
+	}
+
+	var validationErr *ValidationError
+	if _, err := ParseConfig([]byte(`{"providers": {"kling": {"retry_count": -1}}}`)); !errors.As(err, &validationErr) || validationErr.Field != "providers.kling.retry_count" {
+		t.Errorf("Expected providers.kling.retry_count validation error, got %v", err)
+	}
+	if _, err := ParseConfig([]byte(`{"providers": {}} {}`)); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration for trailing data, got %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vidgo.json")
+	os.WriteFile(path, []byte(`{"providers": {"luma": {"api_key": "test-key"}}}`), 0o600)
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Providers[ProviderLuma].APIKey != "test-key" {
+		t.Errorf("Expected luma api key, got %+v", config.Providers[ProviderLuma])
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Providers struct {
+				PropertyNames struct {
+					Enum []string `json:"enum"`
+				} `json:"propertyNames"`
+			} `json:"providers"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(ConfigSchema, &schema); err != nil {
+		t.Fatalf("Failed to decode config schema: %v", err)
+	}
+
+	enum := schema.Properties.Providers.PropertyNames.Enum
+	for _, provider := range []ProviderType{ProviderKling, ProviderJimeng, ProviderLuma, ProviderVeo, ProviderWanx, ProviderReplicate, ProviderComfyUI} {
+		if !strings.Contains(fmt.Sprint(enum), string(provider)) {
+			t.Errorf("Expected schema providers to include %s, got %v", provider, enum)
+		}
+	}
+	for _, name := range enum {
+		if _, err := ParseConfig([]byte(`{"providers": {"` + name + `": {}}}`)); err != nil {
+			t.Errorf("Expected schema provider %s to be valid, got %v", name, err)
+		}
+	}
+}
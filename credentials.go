@@ -0,0 +1,52 @@
+package vidgo
+
+import (
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Credentials is a customer-supplied (BYOK) provider credential used for a single
+// request instead of the configured provider key. It is never logged or persisted:
+// String, GoString and MarshalJSON all return a redacted value.
+type Credentials struct {
+	// APIKey uses the same format as ProviderConfig.APIKey (Kling: "access_key,secret_key")
+	APIKey string
+}
+
+// WithCredentials uses customer-supplied credentials for a single call.
+// It fails with ErrBYOKNotAllowed when the client has DisallowBYOK set.
+func WithCredentials(credentials *Credentials) CallOption {
+	return func(o *callOptions) {
+		if credentials != nil {
+			o.apiKey = credentials.APIKey
+		}
+	}
+}
+
+// String implements fmt.Stringer without exposing the key
+func (c Credentials) String() string {
+	return "Credentials{APIKey: [REDACTED]}"
+}
+
+// GoString implements fmt.GoStringer without exposing the key
+func (c Credentials) GoString() string {
+	return c.String()
+}
+
+// MarshalJSON prevents credentials from being persisted
+func (c Credentials) MarshalJSON() ([]byte, error) {
+	return []byte(`{"api_key":"[REDACTED]"}`), nil
+}
+
+// validateCredentials checks a BYOK key against the client policy and the provider format
+func (c *Client) validateCredentials(apiKey string) error {
+	if c.config.DisallowBYOK {
+		return ErrBYOKNotAllowed
+	}
+
+	if validator, ok := c.current().(adapters.CredentialValidator); ok {
+		if err := validator.ValidateCredentials(apiKey); err != nil {
+			return &ValidationError{Field: "credentials", Message: err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// errDownloadStalled is returned when no data arrives within DownloadReadTimeout
+var errDownloadStalled = errors.New("download stalled")
+
+// DownloadInfo describes a downloaded video
+type DownloadInfo struct {
+	ContentType string // Media type from the response, sniffed from the data when missing
+	Format      string // File extension without the dot, e.g. "mp4"; empty when unknown
+	Size        int64
+}
+
+// Download fetches the video of a finished task. The transfer is bounded by
+// DownloadTimeout rather than Timeout; an attempt that fails or stalls for
+// DownloadReadTimeout is resumed from the bytes already received when the
+// server supports range requests, and restarted otherwise.
+func (c *Client) Download(ctx context.Context, result *TaskResult) ([]byte, error) {
+	var buf bytes.Buffer
+	target := &downloadTarget{w: &buf, reset: func() error {
+		buf.Reset()
+		return nil
+	}}
+	if _, err := c.downloadResult(ctx, result, target); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTo streams the video of a finished task to w, retrying like Download.
+// Since w cannot be rewound, a retry fails when the server ignores range
+// requests after part of the video was written.
+func (c *Client) DownloadTo(ctx context.Context, result *TaskResult, w io.Writer) (*DownloadInfo, error) {
+	return c.downloadResult(ctx, result, &downloadTarget{w: w})
+}
+
+// DownloadToFile streams the video of a finished task to path. The file is
+// written under a temporary name and renamed into place once complete, so path
+// never holds a partial video.
+func (c *Client) DownloadToFile(ctx context.Context, result *TaskResult, path string) (*DownloadInfo, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create video file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	info, err := c.downloadResult(ctx, result, fileTarget(file))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write video file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write video file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to write video file: %w", err)
+	}
+	return info, nil
+}
+
+// fileTarget downloads into file, truncating it when a download restarts
+func fileTarget(file *os.File) *downloadTarget {
+	return &downloadTarget{w: file, reset: func() error {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return file.Truncate(0)
+	}}
+}
+
+// downloadResult downloads the video of result into target and records the download
+func (c *Client) downloadResult(ctx context.Context, result *TaskResult, target *downloadTarget) (*DownloadInfo, error) {
+	if result == nil || result.URL == "" {
+		return nil, fmt.Errorf("task has no video URL")
+	}
+
+	start := time.Now()
+	var err error
+	if data, contentType, ok := adapters.DecodeDataURI(result.URL); ok {
+		// Providers such as Veo return the video inline when no storage is configured
+		target.contentType = contentType
+		if _, err = target.Write(data); err != nil {
+			err = fmt.Errorf("failed to write video: %w", err)
+		}
+	} else {
+		err = c.download(ctx, result.URL, target)
+	}
+	c.recordDownload(result.TaskID, int(target.written), time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+	return target.info(), nil
+}
+
+// downloadTarget receives the bytes of a download and the media type they were served with
+type downloadTarget struct {
+	w       io.Writer
+	written int64
+	reset   func() error // Discards the written bytes; nil when the writer cannot be rewound
+
+	contentType string
+	sniff       []byte // Leading bytes for content type detection
+}
+
+func (t *downloadTarget) Write(p []byte) (int, error) {
+	if len(t.sniff) < 512 {
+		t.sniff = append(t.sniff, p[:min(len(p), 512-len(t.sniff))]...)
+	}
+	n, err := t.w.Write(p)
+	t.written += int64(n)
+	return n, err
+}
+
+// restart discards the written bytes so the download can start over
+func (t *downloadTarget) restart() error {
+	if t.written == 0 {
+		return nil
+	}
+	if t.reset == nil {
+		return fmt.Errorf("server ignored the range request and %d bytes were already written", t.written)
+	}
+	if err := t.reset(); err != nil {
+		return err
+	}
+	t.written = 0
+	t.sniff = t.sniff[:0]
+	return nil
+}
+
+// info reports the size and detected format of the download
+func (t *downloadTarget) info() *DownloadInfo {
+	contentType := t.contentType
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	if contentType == "" || contentType == "application/octet-stream" || contentType == "binary/octet-stream" {
+		contentType, _, _ = mime.ParseMediaType(http.DetectContentType(t.sniff))
+	}
+	return &DownloadInfo{ContentType: contentType, Format: videoFormats[contentType], Size: t.written}
+}
+
+// videoFormats maps video media types to file extensions
+var videoFormats = map[string]string{
+	"video/mp4":       "mp4",
+	"video/webm":      "webm",
+	"video/quicktime": "mov",
+	"video/x-msvideo": "avi",
+	"video/avi":       "avi",
+	"image/gif":       "gif",
+}
+
+// download fetches url into target within DownloadTimeout, resuming failed attempts
+func (c *Client) download(ctx context.Context, url string, target *downloadTarget) error {
+	timeout := c.config.DownloadTimeout
+	if timeout == 0 {
+		timeout = c.config.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	attempts := c.config.MaxRetries + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			if c.config.Debug {
+				fmt.Printf("Download attempt %d failed at %d bytes: %v, resuming...\n", i, target.written, lastErr)
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("failed to download video: %w", ctx.Err())
+			case <-time.After(c.config.RetryDelay):
+			}
+		}
+
+		retry, err := c.downloadAttempt(ctx, url, target)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return fmt.Errorf("failed to download video: %w", lastErr)
+}
+
+// httpClient returns the HTTP client for downloads
+func (c *Client) httpClient() *http.Client {
+	if c.config.HTTPClient != nil {
+		return c.config.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// downloadAttempt writes the remaining bytes of url to target, reporting whether
+// a failure is worth retrying
+func (c *Client) downloadAttempt(ctx context.Context, url string, target *downloadTarget) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create download request: %w", err)
+	}
+	offset := target.written
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	// The read timeout covers the response headers too
+	var stalled atomic.Bool
+	var watchdog *time.Timer
+	if c.config.DownloadReadTimeout > 0 {
+		watchdog = time.AfterFunc(c.config.DownloadReadTimeout, func() {
+			stalled.Store(true)
+			cancel()
+		})
+		defer watchdog.Stop()
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		if stalled.Load() {
+			return true, errDownloadStalled
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			// The server returned another range, start over from the first byte
+			if err := target.restart(); err != nil {
+				return false, fmt.Errorf("server returned range %q for offset %d: %w", resp.Header.Get("Content-Range"), offset, err)
+			}
+			if watchdog != nil {
+				watchdog.Stop()
+			}
+			resp.Body.Close()
+			return c.downloadAttempt(ctx, url, target)
+		}
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range, start over
+		if err := target.restart(); err != nil {
+			return false, err
+		}
+		target.contentType = resp.Header.Get("Content-Type")
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(chunk)
+		if _, err := target.Write(chunk[:n]); err != nil {
+			return false, fmt.Errorf("failed to write video: %w", err)
+		}
+		if n > 0 && watchdog != nil {
+			watchdog.Reset(c.config.DownloadReadTimeout)
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			if stalled.Load() {
+				return true, errDownloadStalled
+			}
+			return true, err
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadResumesStalledTransfer(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789"), 10000)
+	half := len(video) / 2
+
+	var attempts int32
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// Send half of the video, then stall
+			w.Header().Set("Content-Length", strconv.Itoa(len(video)))
+			w.Write(video[:half])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+
+		ranges = append(ranges, r.Header.Get("Range"))
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "-"))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(video)-1, len(video)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(video[start:])
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, &ClientConfig{
+		Timeout:             50 * time.Millisecond,
+		MaxRetries:          2,
+		RetryDelay:          10 * time.Millisecond,
+		DownloadTimeout:     5 * time.Second,
+		DownloadReadTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	data, err := client.Download(context.Background(), &TaskResult{URL: server.URL + "/video.mp4"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(data, video) {
+		t.Errorf("Expected %d bytes, got %d", len(video), len(data))
+	}
+	if len(ranges) != 1 || ranges[0] != fmt.Sprintf("bytes=%d-", half) {
+		t.Errorf("Expected resume from byte %d, got ranges %v", half, ranges)
+	}
+}
+
+// countingTransport counts the requests it sends
+type countingTransport struct {
+	requests int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDownloadRestartsOnRangeMismatch(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789"), 10000)
+
+	var attempts int32
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(video)))
+			w.Write(video[:len(video)/2])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+
+		// Answer any range with the whole video
+		ranges = append(ranges, r.Header.Get("Range"))
+		if r.Header.Get("Range") != "" {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(video)-1, len(video)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(video)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, &ClientConfig{
+		Timeout:             5 * time.Second,
+		MaxRetries:          1,
+		RetryDelay:          10 * time.Millisecond,
+		DownloadReadTimeout: 100 * time.Millisecond,
+		HTTPClient:          &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	data, err := client.Download(context.Background(), &TaskResult{URL: server.URL + "/video.mp4"})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(data, video) {
+		t.Errorf("Expected %d bytes, got %d", len(video), len(data))
+	}
+	if len(ranges) != 2 || ranges[0] != fmt.Sprintf("bytes=%d-", len(video)/2) || ranges[1] != "" {
+		t.Errorf("Expected a resume then a restart from zero, got ranges %q", ranges)
+	}
+	if n := atomic.LoadInt32(&transport.requests); n != 3 {
+		t.Errorf("Expected 3 requests through the configured HTTP client, got %d", n)
+	}
+}
+
+func TestDownloadRestartsWithoutRangeSupport(t *testing.T) {
+	video := bytes.Repeat([]byte("abcdefghij"), 5000)
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(video)))
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// Drop the connection after a partial body
+			w.Write(video[:100])
+			return
+		}
+		w.Write(video)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, &ClientConfig{
+		Timeout:    5 * time.Second,
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	data, err := client.Download(context.Background(), &TaskResult{URL: server.URL})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(data, video) {
+		t.Errorf("Expected %d bytes, got %d", len(video), len(data))
+	}
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	if _, err := client.Download(context.Background(), &TaskResult{URL: notFound.URL}); err == nil {
+		t.Error("Missing video should return error")
+	}
+}
+
+func TestDownloadToFile(t *testing.T) {
+	video := append([]byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), bytes.Repeat([]byte{0}, 5000)...)
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(video)))
+		if atomic.AddInt32(&attempts, 1)%2 == 1 {
+			// Drop the connection after a partial body
+			w.Write(video[:100])
+			return
+		}
+		w.Write(video)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, &ClientConfig{
+		Timeout:    5 * time.Second,
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "video")
+	info, err := client.DownloadToFile(context.Background(), &TaskResult{URL: server.URL}, path)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if info.ContentType != "video/mp4" || info.Format != "mp4" || info.Size != int64(len(video)) {
+		t.Errorf("Expected a sniffed %d byte mp4, got %+v", len(video), info)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, video) {
+		t.Errorf("Expected the file to hold %d bytes, got %d", len(video), len(data))
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected only the video file, got %d entries", len(entries))
+	}
+
+	// A plain writer cannot be rewound when the server ignores the range request
+	var buf bytes.Buffer
+	if _, err := client.DownloadTo(context.Background(), &TaskResult{URL: server.URL}, &buf); err == nil {
+		t.Error("Expected an error restarting a streamed download")
+	}
+
+	// Canceled contexts stop the download
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.DownloadTo(ctx, &TaskResult{URL: server.URL}, &buf); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDownloadDataURI(t *testing.T) {
+	client, err := NewClient(ProviderVeo, &ProviderConfig{APIKey: "ya29.test", Extra: map[string]string{"project_id": "my-project"}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	video := []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+	var buf bytes.Buffer
+	info, err := client.DownloadTo(context.Background(), &TaskResult{URL: "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(video)}, &buf)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), video) || info.Format != "mp4" || info.Size != int64(len(video)) {
+		t.Errorf("Expected the inline %d byte mp4, got %+v", len(video), info)
+	}
+}
@@ -0,0 +1,242 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// DurationConstraints describes the clip lengths a provider generates
+type DurationConstraints = adapters.DurationConstraints
+
+// SegmentStrategy is how a target duration is reached
+type SegmentStrategy string
+
+const (
+	SegmentSingle SegmentStrategy = "single" // One clip at least as long as the target
+	SegmentExtend SegmentStrategy = "extend" // One clip followed by a chain of extensions
+	SegmentConcat SegmentStrategy = "concat" // Several clips joined by a Stitcher
+)
+
+// durationEpsilon absorbs float rounding when comparing durations
+const durationEpsilon = 1e-9
+
+// DurationPlan is the segmentation chosen for a target duration
+type DurationPlan struct {
+	Target     float64         `json:"target"`
+	Strategy   SegmentStrategy `json:"strategy"`
+	Clips      []float64       `json:"clips"`                // Durations of the generated clips, one unless concatenated
+	Extensions int             `json:"extensions,omitempty"` // Extensions chained onto the clip
+	Duration   float64         `json:"duration"`             // Expected length of the result, at least Target
+}
+
+// Tasks returns the number of provider tasks the plan submits
+func (p *DurationPlan) Tasks() int {
+	return len(p.Clips) + p.Extensions
+}
+
+// Stitcher joins the videos of succeeded clips, in order, into one result
+type Stitcher func(ctx context.Context, clips []*TaskResult) (*TaskResult, error)
+
+// DurationOptions configures GenerateToDuration
+type DurationOptions struct {
+	Strategy     SegmentStrategy // Empty picks single, then extend, then concat
+	PollInterval time.Duration   // Passed to WaitForCompletion, zero uses the adaptive policy
+	Stitcher     Stitcher        // Required by the concat strategy
+}
+
+// PlanDuration segments a target duration within the given constraints. An empty
+// strategy picks a single clip when one is long enough, then an extension chain
+// when ExtendSeconds is positive, then concatenation. Each strategy uses as few
+// tasks as possible and, among those, overshoots the target the least.
+func PlanDuration(target float64, constraints *DurationConstraints, strategy SegmentStrategy) (*DurationPlan, error) {
+	if target <= 0 {
+		return nil, &ValidationError{Field: "duration", Message: "duration must be positive"}
+	}
+	if constraints == nil || len(constraints.Durations) == 0 {
+		if strategy != "" && strategy != SegmentSingle {
+			return nil, &ValidationError{Field: "strategy", Message: "provider does not publish its clip durations"}
+		}
+		return &DurationPlan{Target: target, Strategy: SegmentSingle, Clips: []float64{target}, Duration: target}, nil
+	}
+
+	durations := append([]float64(nil), constraints.Durations...)
+	sort.Float64s(durations)
+
+	switch strategy {
+	case SegmentSingle:
+		if plan := planSingle(target, durations); plan != nil {
+			return plan, nil
+		}
+		return nil, &ValidationError{Field: "duration", Message: fmt.Sprintf("no single clip reaches %gs, longest is %gs", target, durations[len(durations)-1])}
+	case SegmentExtend:
+		if plan := planExtend(target, durations, constraints); plan != nil {
+			return plan, nil
+		}
+		return nil, &ValidationError{Field: "duration", Message: fmt.Sprintf("%gs cannot be reached by extension", target)}
+	case SegmentConcat:
+		return planConcat(target, durations), nil
+	case "":
+		if plan := planSingle(target, durations); plan != nil {
+			return plan, nil
+		}
+		if plan := planExtend(target, durations, constraints); plan != nil {
+			return plan, nil
+		}
+		return planConcat(target, durations), nil
+	default:
+		return nil, &ValidationError{Field: "strategy", Message: fmt.Sprintf("unknown segment strategy %q", strategy)}
+	}
+}
+
+// planSingle picks the shortest clip reaching the target
+func planSingle(target float64, durations []float64) *DurationPlan {
+	for _, d := range durations {
+		if d+durationEpsilon >= target {
+			return &DurationPlan{Target: target, Strategy: SegmentSingle, Clips: []float64{d}, Duration: d}
+		}
+	}
+	return nil
+}
+
+// planExtend picks the clip and extension count with the fewest tasks, then the least overshoot
+func planExtend(target float64, durations []float64, constraints *DurationConstraints) *DurationPlan {
+	step := constraints.ExtendSeconds
+	if step <= 0 {
+		return nil
+	}
+
+	var best *DurationPlan
+	for _, d := range durations {
+		n := 0
+		if target > d {
+			n = int(math.Ceil((target - d - durationEpsilon) / step))
+		}
+		total := d + float64(n)*step
+		if constraints.MaxExtended > 0 && total > constraints.MaxExtended+durationEpsilon {
+			continue
+		}
+		if best == nil || n < best.Extensions || (n == best.Extensions && total < best.Duration) {
+			best = &DurationPlan{Target: target, Strategy: SegmentExtend, Clips: []float64{d}, Extensions: n, Duration: total}
+		}
+	}
+	return best
+}
+
+// planConcat uses as many longest clips as needed, shortening each one as far as
+// the remaining clips still cover the target
+func planConcat(target float64, durations []float64) *DurationPlan {
+	longest := durations[len(durations)-1]
+	count := int(math.Ceil(target/longest - durationEpsilon))
+
+	plan := &DurationPlan{Target: target, Strategy: SegmentConcat}
+	remaining := target
+	for i := 0; i < count; i++ {
+		after := float64(count-i-1) * longest
+		clip := longest
+		for _, d := range durations {
+			if d+after+durationEpsilon >= remaining {
+				clip = d
+				break
+			}
+		}
+		plan.Clips = append(plan.Clips, clip)
+		plan.Duration += clip
+		remaining -= clip
+	}
+	return plan
+}
+
+// PlanDuration segments a target duration using the current provider's clip
+// durations. Extension is only planned when the provider can extend videos.
+func (c *Client) PlanDuration(target float64, strategy SegmentStrategy) (*DurationPlan, error) {
+	var constraints *DurationConstraints
+	if caps := c.Capabilities(); caps != nil && caps.Duration != nil {
+		limits := *caps.Duration
+		if _, ok := c.current().(Extender); !ok {
+			limits.ExtendSeconds = 0
+		}
+		constraints = &limits
+	}
+	return PlanDuration(target, constraints, strategy)
+}
+
+// GenerateToDuration generates a video of at least req.Duration seconds, hiding
+// the provider's clip limits: the request is planned with PlanDuration, every
+// task is submitted and waited for, and a single result is returned. Extension
+// chains return the final extended video; concatenated clips are joined by
+// opts.Stitcher. A failed task's result is returned as is.
+func (c *Client) GenerateToDuration(ctx context.Context, req *GenerationRequest, opts DurationOptions) (*TaskResult, error) {
+	if req == nil {
+		return nil, &ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	plan, err := c.PlanDuration(req.Duration, opts.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Strategy == SegmentConcat && len(plan.Clips) > 1 && opts.Stitcher == nil {
+		return nil, &ValidationError{Field: "stitcher", Message: "concatenating clips requires a Stitcher"}
+	}
+
+	if plan.Strategy != SegmentConcat {
+		clip := *req
+		clip.Duration = plan.Clips[0]
+		resp, err := c.CreateGeneration(ctx, &clip)
+		if err != nil {
+			return nil, err
+		}
+		result, err := c.WaitForCompletion(ctx, resp.TaskID, opts.PollInterval)
+		if err != nil || result.Status != TaskStatusSucceeded {
+			return result, err
+		}
+		return c.extendChain(ctx, req, result, plan.Extensions, opts.PollInterval)
+	}
+
+	reqs := make([]*GenerationRequest, len(plan.Clips))
+	for i, duration := range plan.Clips {
+		clip := *req
+		clip.Duration = duration
+		reqs[i] = &clip
+	}
+	batch := c.CreateGenerations(ctx, reqs, BatchOptions{})
+	if err := batch.Err(); err != nil {
+		return nil, err
+	}
+
+	clips := make([]*TaskResult, len(batch.Items))
+	for i, item := range batch.Items {
+		result, err := c.WaitForCompletion(ctx, item.Response.TaskID, opts.PollInterval)
+		if err != nil || result.Status != TaskStatusSucceeded {
+			return result, err
+		}
+		clips[i] = result
+	}
+	if len(clips) == 1 {
+		return clips[0], nil
+	}
+	return opts.Stitcher(ctx, clips)
+}
+
+// extendChain extends a succeeded task n times, each extension continuing the previous one
+func (c *Client) extendChain(ctx context.Context, req *GenerationRequest, result *TaskResult, n int, pollInterval time.Duration) (*TaskResult, error) {
+	for i := 0; i < n; i++ {
+		resp, err := c.ExtendGeneration(ctx, &ExtendRequest{
+			TaskID:         result.TaskID,
+			Prompt:         req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result, err = c.WaitForCompletion(ctx, resp.TaskID, pollInterval)
+		if err != nil || result.Status != TaskStatusSucceeded {
+			return result, err
+		}
+	}
+	return result, nil
+}
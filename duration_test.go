@@ -0,0 +1,87 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestPlanDuration(t *testing.T) {
+	kling := &DurationConstraints{Durations: []float64{5, 10}, ExtendSeconds: 4.5, MaxExtended: 180}
+	clips := &DurationConstraints{Durations: []float64{5, 10}}
+
+	tests := []struct {
+		name        string
+		target      float64
+		constraints *DurationConstraints
+		strategy    SegmentStrategy
+		expected    string
+	}{
+		{"single", 7, kling, "", "single [10] +0 = 10"},
+		{"extend", 22, kling, "", "extend [10] +3 = 23.5"},
+		{"extend exact", 14.5, kling, "", "extend [10] +1 = 14.5"},
+		{"concat", 22, clips, "", "concat [5 10 10] +0 = 25"},
+		{"forced concat", 12, kling, SegmentConcat, "concat [5 10] +0 = 15"},
+		{"unknown limits", 22, nil, "", "single [22] +0 = 22"},
+	}
+
+	for _, tt := range tests {
+		plan, err := PlanDuration(tt.target, tt.constraints, tt.strategy)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		got := fmt.Sprintf("%s %v +%d = %g", plan.Strategy, plan.Clips, plan.Extensions, plan.Duration)
+		if got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+
+	if _, err := PlanDuration(200, kling, SegmentExtend); err == nil {
+		t.Error("Expected an error beyond the extension limit")
+	}
+	if _, err := PlanDuration(22, clips, SegmentSingle); err == nil {
+		t.Error("Expected an error when no single clip is long enough")
+	}
+}
+
+func TestGenerateToDuration(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+	req := &GenerationRequest{Prompt: "A cat walking", Duration: 19, Width: 1280, Height: 720}
+
+	result, err := client.GenerateToDuration(ctx, req, DurationOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.Metadata == nil || result.Metadata.Duration != 19 {
+		t.Errorf("Expected a succeeded 19s video, got %+v", result)
+	}
+	if fake.Tasks() != 3 {
+		t.Errorf("Expected 3 tasks (clip and 2 extensions), got %d", fake.Tasks())
+	}
+
+	var stitched []*TaskResult
+	result, err = client.GenerateToDuration(ctx, req, DurationOptions{
+		Strategy:     SegmentConcat,
+		PollInterval: time.Millisecond,
+		Stitcher: func(ctx context.Context, clips []*TaskResult) (*TaskResult, error) {
+			stitched = clips
+			return &TaskResult{TaskID: "stitched", Status: TaskStatusSucceeded}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate concatenated video: %v", err)
+	}
+	if result.TaskID != "stitched" || len(stitched) != 2 {
+		t.Errorf("Expected 2 stitched clips, got %d clips and result %+v", len(stitched), result)
+	}
+
+	if _, err := client.GenerateToDuration(ctx, req, DurationOptions{Strategy: SegmentConcat}); err == nil {
+		t.Error("Expected an error when concatenating without a Stitcher")
+	}
+}
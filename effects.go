@@ -0,0 +1,69 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// EffectRequest applies a preset video effect, such as "hug" or "squish", to images
+type EffectRequest = adapters.EffectRequest
+
+// CreateEffect submits a preset video effect task. The returned task is polled
+// with GetGeneration or WaitForCompletion like any other.
+func (c *Client) CreateEffect(ctx context.Context, req *EffectRequest, opts ...CallOption) (*GenerationResponse, error) {
+	if req == nil {
+		return nil, &ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+	if req.Effect == "" {
+		return nil, &ValidationError{Field: "effect", Message: "effect cannot be empty"}
+	}
+	if len(req.Images) == 0 {
+		return nil, &ValidationError{Field: "images", Message: "at least one image must be provided"}
+	}
+	for _, image := range req.Images {
+		if image == "" {
+			return nil, &ValidationError{Field: "images", Message: "images cannot be empty"}
+		}
+		if adapters.IsDataURI(image) {
+			if _, _, ok := adapters.DecodeDataURI(image); !ok {
+				return nil, &ValidationError{Field: "images", Message: "invalid data URI"}
+			}
+		}
+	}
+
+	generator, ok := c.current().(EffectGenerator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support video effects", ErrUnsupportedOperation, c.current().Name())
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *GenerationResponse
+	err = c.withRetry(c.captureContext(ctx, "create_effect", ""), o, func(ctx context.Context) error {
+		var err error
+		resp, err = generator.CreateEffect(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.metrics.dispatched(resp.TaskID, req.Model)
+	c.recordTimeline(resp.TaskID, TimelineEvent{Type: TimelineEventSubmitted, Status: resp.Status})
+	return resp, nil
+}
+
+// SupportedEffects lists the video effects the provider offers for a model, or
+// nil if the provider has no effects
+func (c *Client) SupportedEffects(model string) []string {
+	generator, ok := c.current().(EffectGenerator)
+	if !ok {
+		return nil
+	}
+	return generator.SupportedEffects(model)
+}
@@ -0,0 +1,98 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestCreateEffect(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	resp, err := client.CreateEffect(ctx, &EffectRequest{Effect: "hug", Images: []string{"https://example.com/a.png", "https://example.com/b.png"}, Duration: 10})
+	if err != nil {
+		t.Fatalf("Failed to create effect: %v", err)
+	}
+	result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for effect: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL == "" {
+		t.Errorf("Expected a succeeded effect video, got %+v", result)
+	}
+
+	if _, err := client.CreateEffect(ctx, &EffectRequest{Effect: "squish", Images: []string{"https://example.com/cat.png"}}); err != nil {
+		t.Errorf("Failed to create single-image effect: %v", err)
+	}
+
+	invalid := map[string]*EffectRequest{
+		"no effect":        {Images: []string{"https://example.com/a.png"}},
+		"unknown effect":   {Effect: "moonwalk", Images: []string{"https://example.com/a.png"}},
+		"model mismatch":   {Effect: "squish", Model: "kling-v1", Images: []string{"https://example.com/a.png"}},
+		"one image hug":    {Effect: "hug", Images: []string{"https://example.com/a.png"}},
+		"two image squish": {Effect: "squish", Images: []string{"https://example.com/a.png", "https://example.com/b.png"}},
+		"long squish":      {Effect: "squish", Images: []string{"https://example.com/a.png"}, Duration: 10},
+	}
+	for name, req := range invalid {
+		if _, err := client.CreateEffect(ctx, req); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+
+	if effects := client.SupportedEffects("kling-v1"); len(effects) != 3 {
+		t.Errorf("Expected 3 effects for kling-v1, got %v", effects)
+	}
+
+	luma, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "luma-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := luma.CreateEffect(ctx, &EffectRequest{Effect: "hug", Images: []string{"a", "b"}}); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation, got %v", err)
+	}
+	if effects := luma.SupportedEffects(""); effects != nil {
+		t.Errorf("Expected no effects for Luma, got %v", effects)
+	}
+}
+
+func TestKlingEffectPayload(t *testing.T) {
+	var payload struct {
+		EffectScene string `json:"effect_scene"`
+		Input       struct {
+			ModelName string   `json:"model_name"`
+			Image     string   `json:"image"`
+			Images    []string `json:"images"`
+			Duration  string   `json:"duration"`
+		} `json:"input"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/videos/effects" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"code":0,"data":{"task_id":"effect-1"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.CreateEffect(context.Background(), &EffectRequest{Effect: "fuzzyfuzzy", Images: []string{"data:image/png;base64,aGVsbG8="}}); err != nil {
+		t.Fatalf("Failed to create effect: %v", err)
+	}
+	if payload.EffectScene != "fuzzyfuzzy" || payload.Input.ModelName != "kling-v1-6" || payload.Input.Duration != "5" {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if payload.Input.Image != "aGVsbG8=" || payload.Input.Images != nil {
+		t.Errorf("Expected a single Base64 image, got %+v", payload.Input)
+	}
+}
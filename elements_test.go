@@ -0,0 +1,55 @@
+package vidgo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKlingElementsRouting(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	req := &GenerationRequest{
+		Prompt:   "Two cats playing",
+		Duration: 5.0,
+		Width:    512,
+		Height:   512,
+		Model:    "kling-v1-6",
+		Metadata: map[string]interface{}{
+			"image_list": []interface{}{"https://example.com/a.png", "https://example.com/b.png"},
+		},
+	}
+
+	resp, err := client.CreateGeneration(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if _, err := client.GetGeneration(context.Background(), resp.TaskID); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	expected := []string{"POST /v1/videos/multi-image2video", "GET /v1/videos/multi-image2video/task-1"}
+	paths := server.requestLog()
+	if len(paths) != len(expected) || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("Expected requests %v, got %v", expected, paths)
+	}
+	if images, _ := server.body()["image_list"].([]interface{}); len(images) != 2 {
+		t.Errorf("Expected two images in image_list, got %v", images)
+	}
+
+	// Unsupported model must be rejected before submission
+	req.Model = "kling-v2-master"
+	if _, err := client.CreateGeneration(context.Background(), req); err == nil {
+		t.Error("Elements request with unsupported model should return error")
+	}
+
+	// More than four images must be rejected
+	req.Model = "kling-v1-6"
+	req.Metadata["image_list"] = []string{"a", "b", "c", "d", "e"}
+	if _, err := client.CreateGeneration(context.Background(), req); err == nil {
+		t.Error("Elements request with five images should return error")
+	}
+	if body := server.body(); body != nil {
+		t.Errorf("Expected rejected requests not to be submitted, got %v", body)
+	}
+}
@@ -0,0 +1,40 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestSandboxEnvironment(t *testing.T) {
+	if _, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Environment: EnvironmentSandbox}); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration for a sandbox without base_url, got %v", err)
+	}
+	if _, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Environment: "staging"}); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration for an unknown environment, got %v", err)
+	}
+
+	server := httptest.NewServer(fakekling.New("ak", "sk"))
+	defer server.Close()
+	config := &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk", Environment: EnvironmentSandbox}
+	client, err := NewClient(ProviderKling, config, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create sandbox client: %v", err)
+	}
+	if _, err := client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}); err != nil {
+		t.Errorf("Failed to create generation in the sandbox: %v", err)
+	}
+
+	var event ReloadEvent
+	client.config.OnReload = func(e ReloadEvent) { event = e }
+	if err := client.Reload(&ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk", Environment: EnvironmentProduction}); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if len(event.Changes) != 1 || event.Changes[0] != "environment" {
+		t.Errorf("Expected an environment change, got %v", event.Changes)
+	}
+}
@@ -1,8 +1,11 @@
 package vidgo
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 // Common errors
@@ -16,13 +19,22 @@ var (
 	ErrAuthenticationFailed = errors.New("authentication failed")
 	ErrRateLimitExceeded    = errors.New("rate limit exceeded")
 	ErrInsufficientQuota    = errors.New("insufficient quota")
+	ErrBYOKNotAllowed       = errors.New("customer-supplied credentials are not allowed")
+	ErrUnsupportedOperation = errors.New("operation not supported by provider")
+	ErrDuplicateCallback    = errors.New("duplicate callback")
+	ErrLeaseHeld            = errors.New("pipeline run is leased to another owner")
+	ErrLeaseLost            = errors.New("pipeline lease was taken over")
+	ErrResidencyNotAllowed  = errors.New("data residency not allowed in provider region")
+	ErrRelayLoop            = errors.New("relay loop detected")
 )
 
 // APIError represents an error returned by the video generation API
+// Code is the HTTP status; ProviderCode is the provider's own error code, if any.
 type APIError struct {
-	Code     int    `json:"code"`
-	Message  string `json:"message"`
-	Provider string `json:"provider,omitempty"`
+	Code         int    `json:"code"`
+	Message      string `json:"message"`
+	Provider     string `json:"provider,omitempty"`
+	ProviderCode string `json:"provider_code,omitempty"`
 }
 
 func (e *APIError) Error() string {
@@ -32,6 +44,46 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.Code, e.Message)
 }
 
+// Is matches the sentinel errors of the status: ErrAuthenticationFailed,
+// ErrInsufficientQuota, ErrRateLimitExceeded, and ErrProviderAPIError for all
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrProviderAPIError:
+		return true
+	case ErrAuthenticationFailed:
+		return e.Code == http.StatusUnauthorized || e.Code == http.StatusForbidden
+	case ErrInsufficientQuota:
+		return e.Code == http.StatusPaymentRequired
+	case ErrRateLimitExceeded:
+		return e.Code == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// TaskNotFoundError reports a task the provider no longer knows, typically because
+// its record expired. It matches ErrTaskNotFound with errors.Is.
+type TaskNotFoundError struct {
+	TaskID   string `json:"task_id"`
+	Provider string `json:"provider,omitempty"`
+	Expired  bool   `json:"expired"`
+	Message  string `json:"message,omitempty"`
+}
+
+func (e *TaskNotFoundError) Error() string {
+	reason := "not found"
+	if e.Expired {
+		reason = "expired"
+	}
+	if e.Provider != "" {
+		return fmt.Sprintf("[%s] task %s %s: %s", e.Provider, e.TaskID, reason, e.Message)
+	}
+	return fmt.Sprintf("task %s %s: %s", e.TaskID, reason, e.Message)
+}
+
+func (e *TaskNotFoundError) Is(target error) bool {
+	return target == ErrTaskNotFound
+}
+
 // ValidationError represents a request validation error
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -44,11 +96,154 @@ func (e *ValidationError) Error() string {
 
 // IsRetryableError determines if an error is retryable
 func IsRetryableError(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
 		// Retry on server errors (5xx) and rate limiting (429)
-		return apiErr.Code >= 500 || apiErr.Code == 429
+		return apiErr.Code >= 500 || apiErr.Code == http.StatusTooManyRequests
 	}
 
 	// Retry on network errors
 	return errors.Is(err, ErrNetworkError) || errors.Is(err, ErrRateLimitExceeded)
 }
+
+// HTTPStatus maps an error returned by vidgo to the HTTP status a relay should
+// answer with. Unknown errors map to 500.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var taskErr *TaskAdaptorError
+	if errors.As(err, &taskErr) && taskErr.StatusCode != 0 {
+		return taskErr.StatusCode
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == http.StatusTooManyRequests:
+			return http.StatusTooManyRequests
+		case apiErr.Code == http.StatusServiceUnavailable:
+			return http.StatusServiceUnavailable
+		case apiErr.Code >= 500:
+			return http.StatusBadGateway
+		case apiErr.Code >= 400:
+			return apiErr.Code
+		}
+		return http.StatusBadGateway
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrUnsupportedProvider):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrAuthenticationFailed):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrInsufficientQuota):
+		return http.StatusPaymentRequired
+	case errors.Is(err, ErrBYOKNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDuplicateCallback):
+		return http.StatusConflict
+	case errors.Is(err, ErrRateLimitExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrUnsupportedOperation):
+		return http.StatusNotImplemented
+	case errors.Is(err, ErrNetworkError), errors.Is(err, ErrProviderAPIError):
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorBody is the JSON error body relays send alongside HTTPStatus
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failed relay call
+type ErrorDetail struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Field     string `json:"field,omitempty"`
+	Provider  string `json:"provider,omitempty"`
+	Retryable bool   `json:"retryable"`
+}
+
+// NewErrorBody builds the error body for err. Codes are stable snake_case
+// identifiers such as "invalid_request" or "rate_limited".
+func NewErrorBody(err error) ErrorBody {
+	detail := ErrorDetail{
+		Code:      errorCode(err),
+		Retryable: IsRetryableError(err),
+	}
+	if err != nil {
+		detail.Message = err.Error()
+	}
+
+	var taskErr *TaskAdaptorError
+	var validationErr *ValidationError
+	var apiErr *APIError
+	var notFoundErr *TaskNotFoundError
+	switch {
+	case errors.As(err, &taskErr):
+		detail.Retryable = !taskErr.LocalError && (taskErr.StatusCode == http.StatusTooManyRequests || taskErr.StatusCode >= 500)
+	case errors.As(err, &validationErr):
+		detail.Field = validationErr.Field
+		detail.Message = validationErr.Message
+	case errors.As(err, &apiErr):
+		detail.Provider = apiErr.Provider
+		detail.Message = apiErr.Message
+		detail.Retryable = apiErr.Code >= 500 || apiErr.Code == http.StatusTooManyRequests
+	case errors.As(err, &notFoundErr):
+		detail.Provider = notFoundErr.Provider
+	}
+	return ErrorBody{Error: detail}
+}
+
+// WriteError writes err as a JSON error body with the status from HTTPStatus
+func WriteError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatus(err))
+	json.NewEncoder(w).Encode(NewErrorBody(err))
+}
+
+func errorCode(err error) string {
+	var taskErr *TaskAdaptorError
+	if errors.As(err, &taskErr) && taskErr.Code != "" {
+		return taskErr.Code
+	}
+
+	switch HTTPStatus(err) {
+	case http.StatusBadRequest:
+		return "invalid_request"
+	case http.StatusUnauthorized:
+		return "authentication_failed"
+	case http.StatusPaymentRequired:
+		return "insufficient_quota"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "task_not_found"
+	case http.StatusConflict:
+		return "duplicate"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusNotImplemented:
+		return "unsupported_operation"
+	case http.StatusBadGateway:
+		return "upstream_error"
+	case http.StatusServiceUnavailable:
+		return "upstream_unavailable"
+	case http.StatusGatewayTimeout:
+		return "upstream_timeout"
+	}
+	return "internal_error"
+}
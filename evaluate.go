@@ -0,0 +1,383 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimelineEventEvaluated records the quality scores of a finished task
+const TimelineEventEvaluated TimelineEventType = "evaluated"
+
+// Evaluator scores a downloaded video. Scores range from 0 (unusable) to 1 (good)
+// and are attached to TaskResult.Scores under the evaluator's name.
+type Evaluator interface {
+	Name() string
+	Evaluate(ctx context.Context, result *TaskResult, video []byte) (float64, error)
+}
+
+// Evaluate downloads a succeeded task's video, runs the configured evaluators and
+// attaches their scores to result. Evaluators that fail leave no score.
+func (c *Client) Evaluate(ctx context.Context, result *TaskResult) error {
+	return c.evaluate(ctx, result, c.config.Evaluators)
+}
+
+// evaluate scores a succeeded result with evaluators
+func (c *Client) evaluate(ctx context.Context, result *TaskResult, evaluators []Evaluator) error {
+	if len(evaluators) == 0 || result == nil || result.Status != TaskStatusSucceeded {
+		return nil
+	}
+
+	video, err := c.Download(ctx, result)
+	if err != nil {
+		return err
+	}
+
+	if result.Scores == nil {
+		result.Scores = make(map[string]float64)
+	}
+
+	var errs []error
+	for _, evaluator := range evaluators {
+		score, err := evaluator.Evaluate(ctx, result, video)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", evaluator.Name(), err))
+			continue
+		}
+		result.Scores[evaluator.Name()] = score
+	}
+
+	c.recordTimeline(result.TaskID, TimelineEvent{
+		Type:    TimelineEventEvaluated,
+		Status:  result.Status,
+		Message: formatScores(result.Scores),
+	})
+
+	return errors.Join(errs...)
+}
+
+// formatScores renders scores as "name=0.87 name=0.50" in name order
+func formatScores(scores map[string]float64) string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%.2f", name, scores[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+// DurationEvaluator scores how closely the MP4 duration matches the expected one.
+// Truncated or malformed files score 0.
+type DurationEvaluator struct {
+	// Expected duration in seconds. When zero, TaskResult.Metadata.Duration is used,
+	// and without either any playable duration scores 1.
+	Expected float64
+}
+
+// Name returns the score name
+func (e *DurationEvaluator) Name() string {
+	return "duration"
+}
+
+// Evaluate scores the video duration
+func (e *DurationEvaluator) Evaluate(ctx context.Context, result *TaskResult, video []byte) (float64, error) {
+	duration, err := mp4Duration(video)
+	if err != nil {
+		return 0, nil
+	}
+
+	expected := e.Expected
+	if expected == 0 && result.Metadata != nil {
+		expected = result.Metadata.Duration
+	}
+	if expected <= 0 {
+		return 1, nil
+	}
+
+	diff := duration - expected
+	if diff < 0 {
+		diff = -diff
+	}
+	return clampScore(1 - diff/expected), nil
+}
+
+// BitrateEvaluator flags heavily compressed videos, which tend to show blocking
+// and banding artifacts, by comparing the average bitrate to a minimum.
+type BitrateEvaluator struct {
+	// MinBitrate in bits per second at which a video scores 1, defaults to 1 Mbps
+	MinBitrate float64
+}
+
+// Name returns the score name
+func (e *BitrateEvaluator) Name() string {
+	return "bitrate"
+}
+
+// Evaluate scores the average bitrate
+func (e *BitrateEvaluator) Evaluate(ctx context.Context, result *TaskResult, video []byte) (float64, error) {
+	duration, err := mp4Duration(video)
+	if err != nil || duration <= 0 {
+		return 0, nil
+	}
+
+	minBitrate := e.MinBitrate
+	if minBitrate <= 0 {
+		minBitrate = 1000000
+	}
+	return clampScore(float64(len(video)) * 8 / duration / minBitrate), nil
+}
+
+// SharpnessEvaluator scores the sharpness of the first frame from the variance of
+// its Laplacian, penalizing the blocking artifacts of heavy compression: edges
+// that line up with the codec's 8x8 blocks. It decodes the frame with ffmpeg
+// and fails, leaving no score, when ffmpeg is not installed.
+type SharpnessEvaluator struct {
+	// MinVariance is the Laplacian variance of the luma at which a frame scores 1,
+	// defaults to 100
+	MinVariance float64
+}
+
+// Name returns the score name
+func (e *SharpnessEvaluator) Name() string {
+	return "sharpness"
+}
+
+// Evaluate scores the first frame of the video
+func (e *SharpnessEvaluator) Evaluate(ctx context.Context, result *TaskResult, video []byte) (float64, error) {
+	file, err := os.CreateTemp("", "vidgo-*.mp4")
+	if err != nil {
+		return 0, fmt.Errorf("failed to write video: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(video)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write video: %w", err)
+	}
+
+	data, err := extractFrame(ctx, file.Name())
+	if err != nil {
+		return 0, err
+	}
+	frame, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode frame: %w", err)
+	}
+
+	minVariance := e.MinVariance
+	if minVariance <= 0 {
+		minVariance = 100
+	}
+	return frameSharpness(frame, minVariance), nil
+}
+
+// frameSharpness scores the Laplacian variance of a frame's luma against
+// minVariance, divided by 1 plus how much stronger the horizontal gradients are
+// on 8-pixel block boundaries than inside blocks
+func frameSharpness(frame image.Image, minVariance float64) float64 {
+	bounds := frame.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 16 || height < 3 {
+		return 0
+	}
+	luma := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			luma[y*width+x] = float64(color.GrayModel.Convert(frame.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y)
+		}
+	}
+
+	var sum, sumSquares, n float64
+	var edge, inner, edges, inners float64
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			i := y*width + x
+			laplacian := 4*luma[i] - luma[i-1] - luma[i+1] - luma[i-width] - luma[i+width]
+			sum += laplacian
+			sumSquares += laplacian * laplacian
+			n++
+
+			gradient := math.Abs(luma[i] - luma[i-1])
+			if x%8 == 0 {
+				edge += gradient
+				edges++
+			} else {
+				inner += gradient
+				inners++
+			}
+		}
+	}
+	mean := sum / n
+	variance := sumSquares/n - mean*mean
+
+	blocking := 0.0
+	if edges > 0 && inners > 0 && edge > 0 {
+		// Flat block interiors with steps between them are blocking, not detail
+		blocking = math.Max(edge/edges/math.Max(inner/inners, 1)-1, 0)
+	}
+	return clampScore(variance/minVariance) / (1 + blocking)
+}
+
+// HTTPEvaluator delegates scoring to an external service, such as a VLM judge.
+// It posts {"task_id", "url", "video"} and expects {"score": 0.0-1.0}.
+type HTTPEvaluator struct {
+	ScoreName string
+	Endpoint  string
+	Headers   map[string]string
+	Client    *http.Client
+
+	// SendVideo includes the video bytes (Base64) instead of relying on the URL
+	SendVideo bool
+}
+
+// Name returns the score name
+func (e *HTTPEvaluator) Name() string {
+	if e.ScoreName != "" {
+		return e.ScoreName
+	}
+	return "external"
+}
+
+// Evaluate asks the external service for a score
+func (e *HTTPEvaluator) Evaluate(ctx context.Context, result *TaskResult, video []byte) (float64, error) {
+	payload := map[string]interface{}{"task_id": result.TaskID, "url": result.URL}
+	if e.SendVideo {
+		payload["video"] = video
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return 0, fmt.Errorf("evaluator error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var scored struct {
+		Score *float64 `json:"score"`
+	}
+	if err := json.Unmarshal(respBody, &scored); err != nil || scored.Score == nil {
+		return 0, fmt.Errorf("invalid evaluator response: %s", string(respBody))
+	}
+	return clampScore(*scored.Score), nil
+}
+
+// clampScore limits a score to [0, 1]
+func clampScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
+
+// mp4Duration reads the duration in seconds from the movie header (moov/mvhd) of an MP4 file
+func mp4Duration(data []byte) (float64, error) {
+	moov, ok := mp4Box(data, "moov")
+	if !ok {
+		return 0, fmt.Errorf("no moov box")
+	}
+	mvhd, ok := mp4Box(moov, "mvhd")
+	if !ok || len(mvhd) < 20 {
+		return 0, fmt.Errorf("no mvhd box")
+	}
+
+	var timescale uint32
+	var duration uint64
+	if mvhd[0] == 1 {
+		if len(mvhd) < 32 {
+			return 0, fmt.Errorf("truncated mvhd box")
+		}
+		timescale = binary.BigEndian.Uint32(mvhd[20:24])
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(mvhd[12:16])
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	}
+	if timescale == 0 {
+		return 0, fmt.Errorf("invalid mvhd timescale")
+	}
+	return float64(duration) / float64(timescale), nil
+}
+
+// mp4Box returns the payload of the first top-level box of a type within data
+func mp4Box(data []byte, boxType string) ([]byte, bool) {
+	boxes := mp4Boxes(data, boxType)
+	if len(boxes) == 0 {
+		return nil, false
+	}
+	return boxes[0], true
+}
+
+// mp4Boxes returns the payloads of the top-level boxes of a type within data,
+// stopping at the first malformed box
+func mp4Boxes(data []byte, boxType string) [][]byte {
+	var boxes [][]byte
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[0:4]))
+		header := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return boxes
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return boxes
+		}
+		if string(data[4:8]) == boxType {
+			boxes = append(boxes, data[header:size])
+		}
+		data = data[size:]
+	}
+	return boxes
+}
@@ -0,0 +1,251 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testMP4 builds a minimal MP4 with an ftyp box, a moov/mvhd header and padding
+func testMP4(durationMs uint32, padding int) []byte {
+	box := func(boxType string, payload []byte) []byte {
+		b := make([]byte, 8, 8+len(payload))
+		binary.BigEndian.PutUint32(b, uint32(8+len(payload)))
+		copy(b[4:], boxType)
+		return append(b, payload...)
+	}
+
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], durationMs)
+
+	data := box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41"))
+	data = append(data, box("moov", box("mvhd", mvhd))...)
+	return append(data, box("mdat", make([]byte, padding))...)
+}
+
+func TestMP4Duration(t *testing.T) {
+	duration, err := mp4Duration(testMP4(5000, 16))
+	if err != nil {
+		t.Fatalf("Failed to read duration: %v", err)
+	}
+	if duration != 5 {
+		t.Errorf("Expected duration 5, got %v", duration)
+	}
+
+	if _, err := mp4Duration([]byte("<html>not a video</html>")); err == nil {
+		t.Error("Non-MP4 data should return error")
+	}
+}
+
+func TestEvaluators(t *testing.T) {
+	video := testMP4(4000, 500000)
+	result := &TaskResult{TaskID: "task-1", Status: TaskStatusSucceeded, URL: "https://cdn.example.com/v.mp4"}
+
+	score, _ := (&DurationEvaluator{Expected: 5}).Evaluate(context.Background(), result, video)
+	if score < 0.79 || score > 0.81 {
+		t.Errorf("Expected duration score 0.8, got %v", score)
+	}
+
+	score, _ = (&DurationEvaluator{Expected: 5}).Evaluate(context.Background(), result, video[:40])
+	if score != 0 {
+		t.Errorf("Expected truncated video to score 0, got %v", score)
+	}
+
+	score, _ = (&BitrateEvaluator{MinBitrate: 2000000}).Evaluate(context.Background(), result, video)
+	if score < 0.49 || score > 0.51 {
+		t.Errorf("Expected bitrate score 0.5, got %v", score)
+	}
+
+	judge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["url"] != result.URL || r.Header.Get("Authorization") != "Bearer judge" {
+			t.Errorf("Unexpected evaluator request: %v", payload)
+		}
+		w.Write([]byte(`{"score":0.9}`))
+	}))
+	defer judge.Close()
+
+	score, err := (&HTTPEvaluator{ScoreName: "vlm", Endpoint: judge.URL, Headers: map[string]string{"Authorization": "Bearer judge"}}).Evaluate(context.Background(), result, video)
+	if err != nil || score != 0.9 {
+		t.Errorf("Expected external score 0.9, got %v (%v)", score, err)
+	}
+}
+
+// grayPNG encodes a width x height grayscale image whose pixels are set by level
+func grayPNG(width, height int, level func(x, y int) uint8) []byte {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: level(x, y)})
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func TestSharpnessEvaluator(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	noise := make([]uint8, 64*64)
+	for i := range noise {
+		noise[i] = uint8(random.Intn(256))
+	}
+	frames := map[string][]byte{
+		"detailed": grayPNG(64, 64, func(x, y int) uint8 { return noise[y*64+x] }),
+		"flat":     grayPNG(64, 64, func(x, y int) uint8 { return 128 }),
+		// Constant 8x8 blocks at random levels, as heavy compression leaves
+		"blocky": grayPNG(64, 64, func(x, y int) uint8 { return noise[(y/8)*64+x/8] }),
+	}
+	scores := make(map[string]float64)
+	for name, data := range frames {
+		frame, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to decode %s frame: %v", name, err)
+		}
+		scores[name] = frameSharpness(frame, 100)
+	}
+	if scores["detailed"] != 1 || scores["flat"] != 0 {
+		t.Errorf("Expected a detailed frame to score 1 and a flat one 0, got %v", scores)
+	}
+	if scores["blocky"] > 0.1 {
+		t.Errorf("Expected blocking artifacts to score below 0.1, got %v", scores["blocky"])
+	}
+
+	fakeFFmpeg(t, frames["detailed"])
+	result := &TaskResult{TaskID: "task-1", Status: TaskStatusSucceeded}
+	if score, err := (&SharpnessEvaluator{}).Evaluate(context.Background(), result, testMP4(5000, 16)); err != nil || score != 1 {
+		t.Errorf("Expected the extracted frame to score 1, got %v (%v)", score, err)
+	}
+
+	ffmpegPath = filepath.Join(t.TempDir(), "no-ffmpeg")
+	if _, err := (&SharpnessEvaluator{}).Evaluate(context.Background(), result, testMP4(5000, 16)); err == nil {
+		t.Error("Expected an error without ffmpeg")
+	}
+}
+
+// taskScoreEvaluator scores results by task ID
+type taskScoreEvaluator map[string]float64
+
+func (e taskScoreEvaluator) Name() string { return "stub" }
+
+func (e taskScoreEvaluator) Evaluate(ctx context.Context, result *TaskResult, video []byte) (float64, error) {
+	return e[result.TaskID], nil
+}
+
+func TestRaceMinScores(t *testing.T) {
+	newServer := func(taskID string) *httptest.Server {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/video.mp4" {
+				w.Write(testMP4(5000, 16))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == "POST" {
+				w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"` + taskID + `"}}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"` + taskID + `","status":"succeed","task_result":{"videos":[{"url":"` + server.URL + `/video.mp4"}]}}}`))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	newClient := func(server *httptest.Server) *Client {
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, &ClientConfig{Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	// The race evaluates results itself, its clients have no evaluators
+	result, err := Race(context.Background(), map[ProviderType]*Client{
+		ProviderKling:  newClient(newServer("blurry")),
+		ProviderJimeng: newClient(newServer("sharp")),
+	}, map[ProviderType]*GenerationRequest{
+		ProviderKling:  {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+		ProviderJimeng: {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+	}, &RaceOptions{
+		PollInterval: 10 * time.Millisecond,
+		Evaluators:   []Evaluator{taskScoreEvaluator{"blurry": 0.2, "sharp": 0.9}},
+		MinScores:    map[string]float64{"stub": 0.5},
+	})
+	if err != nil {
+		t.Fatalf("Race failed: %v", err)
+	}
+	if result.TaskID != "sharp" || result.Result.Scores["stub"] != 0.9 {
+		t.Errorf("Expected the sharp result to win, got %s with %v", result.TaskID, result.Result.Scores)
+	}
+}
+
+func TestRaceAcceptsEvaluatedScores(t *testing.T) {
+	newServer := func(taskID string, video []byte) *httptest.Server {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/video.mp4" {
+				w.Write(video)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == "POST" {
+				w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"` + taskID + `"}}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"` + taskID + `","status":"succeed","task_result":{"videos":[{"url":"` + server.URL + `/video.mp4"}]}}}`))
+		}))
+		return server
+	}
+
+	// The first provider returns a truncated video, only the second is acceptable
+	broken := newServer("broken", testMP4(5000, 16)[:40])
+	defer broken.Close()
+	good := newServer("good", testMP4(5000, 16))
+	defer good.Close()
+
+	timelines := NewTimelineStore()
+	newClient := func(url string) *Client {
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: url, APIKey: "test_access_key,test_secret_key", Timeout: 5 * time.Second},
+			&ClientConfig{Timeout: 5 * time.Second, Timelines: timelines, Evaluators: []Evaluator{&DurationEvaluator{Expected: 5}}})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	// Both contestants use the Kling provider; the second is registered as Jimeng only to key the race
+	result, err := Race(context.Background(), map[ProviderType]*Client{
+		ProviderKling:  newClient(broken.URL),
+		ProviderJimeng: newClient(good.URL),
+	}, map[ProviderType]*GenerationRequest{
+		ProviderKling:  {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+		ProviderJimeng: {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+	}, &RaceOptions{
+		PollInterval: 10 * time.Millisecond,
+		Accept: func(_ ProviderType, result *TaskResult) bool {
+			return result.Scores["duration"] > 0.9
+		},
+	})
+	if err != nil {
+		t.Fatalf("Race failed: %v", err)
+	}
+	if result.TaskID != "good" || result.Result.Scores["duration"] != 1 {
+		t.Errorf("Unexpected race result: %+v", result.Result)
+	}
+
+	events := timelines.Get("good")
+	if len(events) == 0 || events[len(events)-1].Type != TimelineEventEvaluated || events[len(events)-1].Message != "duration=1.00" {
+		t.Errorf("Expected evaluated timeline event, got %+v", events)
+	}
+}
@@ -0,0 +1,47 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// ExtendRequest continues a generated video with more footage
+type ExtendRequest = adapters.ExtendRequest
+
+// ExtendGeneration continues the video of a succeeded task, identified by its task
+// ID or provider video ID. The returned task is polled with GetGeneration or
+// WaitForCompletion like any other.
+func (c *Client) ExtendGeneration(ctx context.Context, req *ExtendRequest, opts ...CallOption) (*GenerationResponse, error) {
+	if req == nil {
+		return nil, &ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+	if req.TaskID == "" && req.VideoID == "" {
+		return nil, &ValidationError{Field: "task_id/video_id", Message: "a task ID or video ID must be provided"}
+	}
+
+	extender, ok := c.current().(Extender)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support video extension", ErrUnsupportedOperation, c.current().Name())
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *GenerationResponse
+	err = c.withRetry(c.captureContext(ctx, "extend_generation", req.TaskID), o, func(ctx context.Context) error {
+		var err error
+		resp, err = extender.ExtendGeneration(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.metrics.dispatched(resp.TaskID, "")
+	c.recordTimeline(resp.TaskID, TimelineEvent{Type: TimelineEventSubmitted, Status: resp.Status})
+	return resp, nil
+}
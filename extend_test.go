@@ -0,0 +1,87 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestExtendGeneration(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	// Unfinished tasks have no video to extend yet
+	if _, err := client.ExtendGeneration(ctx, &ExtendRequest{TaskID: resp.TaskID}); err == nil {
+		t.Error("Expected extending an unfinished task to fail")
+	}
+
+	if _, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond); err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+
+	extended, err := client.ExtendGeneration(ctx, &ExtendRequest{TaskID: resp.TaskID, Prompt: "The cat jumps"})
+	if err != nil {
+		t.Fatalf("Failed to extend generation: %v", err)
+	}
+	if extended.TaskID == resp.TaskID {
+		t.Error("Expected a new task for the extension")
+	}
+
+	result, err := client.WaitForCompletion(ctx, extended.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for extension: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.Metadata == nil || result.Metadata.Duration != 9.5 {
+		t.Errorf("Expected a succeeded 9.5s extension, got %+v", result)
+	}
+
+	// Extensions can be extended again by task ID, or by video ID
+	if _, err := client.ExtendGeneration(ctx, &ExtendRequest{TaskID: extended.TaskID}); err != nil {
+		t.Errorf("Failed to extend an extension: %v", err)
+	}
+	if _, err := client.ExtendGeneration(ctx, &ExtendRequest{VideoID: resp.TaskID + "-video"}); err != nil {
+		t.Errorf("Failed to extend by video ID: %v", err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := client.ExtendGeneration(ctx, &ExtendRequest{Prompt: "more"}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError, got %v", err)
+	}
+
+	luma, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "luma-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := luma.ExtendGeneration(ctx, &ExtendRequest{TaskID: "task-1"}); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation, got %v", err)
+	}
+}
+
+func TestExtendGenerationCfgScale(t *testing.T) {
+	stub := newKlingStub(t)
+	client := stub.client(t)
+	ctx := context.Background()
+
+	if _, err := client.ExtendGeneration(ctx, &ExtendRequest{VideoID: "video-1", Metadata: map[string]interface{}{"cfg_scale": 0.0}}); err != nil {
+		t.Fatalf("Failed to extend generation: %v", err)
+	}
+	if cfgScale, ok := stub.body()["cfg_scale"]; !ok || cfgScale != 0.0 {
+		t.Errorf("Expected cfg_scale 0 to be sent, got %v", cfgScale)
+	}
+
+	if _, err := client.ExtendGeneration(ctx, &ExtendRequest{VideoID: "video-1"}); err != nil {
+		t.Fatalf("Failed to extend generation: %v", err)
+	}
+	if cfgScale, ok := stub.body()["cfg_scale"]; ok {
+		t.Errorf("Expected no cfg_scale without metadata, got %v", cfgScale)
+	}
+}
@@ -0,0 +1,40 @@
+package vidgo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/feitianbubu/vidgo/adapters/veo"
+)
+
+func TestExtraSchemaValidation(t *testing.T) {
+	_, err := NewClient(ProviderReplicate, &ProviderConfig{APIKey: "r8_test", Extra: map[string]string{"webhok": "https://example.com/hook"}})
+	if err == nil || !strings.Contains(err.Error(), `did you mean "webhook"`) {
+		t.Errorf("Expected typo suggestion, got %v", err)
+	}
+
+	_, err = NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Extra: map[string]string{"region": "cn"}})
+	if err == nil {
+		t.Error("Unknown Extra key should return error")
+	}
+
+	_, err = NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Extra: map[string]string{"region": "cn"}, AllowUnknownExtra: true})
+	if err != nil {
+		t.Errorf("AllowUnknownExtra should accept unknown keys, got %v", err)
+	}
+
+	_, err = NewClient(ProviderReplicate, &ProviderConfig{APIKey: "r8_test", Extra: map[string]string{"webhook": "example.com/hook"}})
+	if err == nil {
+		t.Error("Invalid webhook URL should return error")
+	}
+
+	_, err = NewClient(ProviderVeo, &ProviderConfig{APIKey: "ya29.token", Extra: map[string]string{"project_id": "demo", "storage_uri": "s3://bucket"}})
+	if err == nil || !strings.Contains(err.Error(), "storage_uri") {
+		t.Errorf("Expected storage_uri validation error, got %v", err)
+	}
+
+	keys := veo.ExtraKeys()
+	if len(keys) != 4 || keys[0].Name != "credentials_file" {
+		t.Errorf("Unexpected Veo Extra keys: %+v", keys)
+	}
+}
@@ -0,0 +1,591 @@
+// Package fakekling emulates the Kling video API in memory, so relays and
+// clients can be developed and tested offline.
+//
+// A Server is an http.Handler and works with httptest:
+//
+//	server := httptest.NewServer(fakekling.New("ak", "sk"))
+//	defer server.Close()
+//
+// or standalone through the fakekling command.
+package fakekling
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Kling error codes returned by the server
+const (
+	CodeSuccess          = 0
+	CodeAuthFailed       = 1000 // Missing or malformed Authorization header
+	CodeTokenInvalid     = 1002 // Bad signature or unknown access key
+	CodeTokenExpired     = 1004 // Token exp has passed or nbf is in the future
+	CodeQuotaExhausted   = 1102 // Every resource pack is used up
+	CodeInvalidParams    = 1201 // Request body failed validation
+	CodeResourceNotFound = 1203 // Unknown task or endpoint
+	CodeRateLimited      = 1302 // Returned by injected errors
+	CodeInternalError    = 5000 // Returned by injected errors
+)
+
+// Task statuses, in order of progression
+const (
+	StatusSubmitted  = "submitted"
+	StatusProcessing = "processing"
+	StatusSucceed    = "succeed"
+	StatusFailed     = "failed"
+)
+
+// FailMarker in a prompt makes the task end in StatusFailed
+const FailMarker = "[fail]"
+
+// taskTypes are the supported {task_type} path segments
+var taskTypes = map[string]bool{"text2video": true, "image2video": true, "multi-image2video": true, "video-extend": true, "lip-sync": true, "effects": true}
+
+// models are the accepted model names
+var models = map[string]bool{"kling-v1": true, "kling-v1-6": true, "kling-v2-master": true}
+
+// Server is an in-memory Kling API. It is safe for concurrent use.
+type Server struct {
+	AccessKey string
+	SecretKey string
+
+	// QueuedPolls and ProcessingPolls are the number of status requests a task
+	// reports as submitted and processing before it finishes
+	QueuedPolls     int
+	ProcessingPolls int
+
+	// ResourcePacks are reported by /account/costs; each task uses one unit of the
+	// first pack with units left, and submissions fail once all are used up
+	ResourcePacks []ResourcePack
+
+	mu       sync.Mutex
+	tasks    map[string]*task
+	nextID   int
+	injected []injectedError
+}
+
+// ResourcePack is a prepaid pack of generation units
+type ResourcePack struct {
+	ID        string
+	Name      string
+	Total     float64
+	Remaining float64
+}
+
+// task is a submitted generation task
+type task struct {
+	id         string
+	externalID string
+	taskType   string
+	failed     bool
+	duration   string
+	polls      int
+	seq        int // Submission order
+	createdAt  int64
+}
+
+// injectedError is returned instead of handling the next request
+type injectedError struct {
+	status  int
+	code    int
+	message string
+}
+
+// New returns a Server that accepts JWTs signed with the given keys. Tasks are
+// submitted for one poll and processing for one more before they finish.
+func New(accessKey, secretKey string) *Server {
+	return &Server{
+		AccessKey:       accessKey,
+		SecretKey:       secretKey,
+		QueuedPolls:     1,
+		ProcessingPolls: 1,
+		ResourcePacks:   []ResourcePack{{ID: "pack-1", Name: "fake video pack", Total: 10000, Remaining: 10000}},
+		tasks:           make(map[string]*task),
+	}
+}
+
+// FailNext makes the next request fail with the given HTTP status and Kling
+// error code, e.g. http.StatusTooManyRequests and CodeRateLimited. Calls queue up.
+func (s *Server) FailNext(status, code int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.injected = append(s.injected, injectedError{status: status, code: code, message: message})
+}
+
+// Tasks returns the number of submitted tasks
+func (s *Server) Tasks() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tasks)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/videos/") {
+		s.serveVideo(w, r)
+		return
+	}
+
+	if err := s.popInjected(); err != nil {
+		writeError(w, err.status, err.code, err.message)
+		return
+	}
+
+	if status, code, message := s.authenticate(r); code != CodeSuccess {
+		writeError(w, status, code, message)
+		return
+	}
+
+	if r.URL.Path == "/account/costs" && r.Method == http.MethodGet {
+		s.costs(w, r)
+		return
+	}
+
+	// /v1/videos/{task_type}[/{task_id}]
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[0] != "v1" || parts[1] != "videos" || !taskTypes[parts[2]] {
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, "resource not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		s.create(w, r, parts[2])
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		s.list(w, r, parts[2])
+	case len(parts) == 4 && r.Method == http.MethodGet:
+		s.get(w, r, parts[2], parts[3])
+	default:
+		writeError(w, http.StatusMethodNotAllowed, CodeInvalidParams, "method not allowed")
+	}
+}
+
+// popInjected returns the next injected error, if any
+func (s *Server) popInjected() *injectedError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.injected) == 0 {
+		return nil
+	}
+	err := s.injected[0]
+	s.injected = s.injected[1:]
+	return &err
+}
+
+// authenticate validates the Bearer JWT the way Kling does
+func (s *Server) authenticate(r *http.Request) (int, int, string) {
+	tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || tokenString == "" {
+		return http.StatusUnauthorized, CodeAuthFailed, "Authorization header is missing or malformed"
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return []byte(s.SecretKey), nil
+	})
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet) != 0 {
+			return http.StatusUnauthorized, CodeTokenExpired, "token is expired or not yet valid"
+		}
+		return http.StatusUnauthorized, CodeTokenInvalid, "token is invalid"
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	if issuer, _ := claims["iss"].(string); issuer != s.AccessKey {
+		return http.StatusUnauthorized, CodeTokenInvalid, "unknown access key"
+	}
+	if _, ok := claims["exp"]; !ok {
+		return http.StatusUnauthorized, CodeTokenInvalid, "token has no exp claim"
+	}
+	return http.StatusOK, CodeSuccess, ""
+}
+
+// createRequest holds the request fields the server validates
+type createRequest struct {
+	Prompt    string `json:"prompt"`
+	Image     string `json:"image"`
+	ImageTail string `json:"image_tail"`
+	ImageList []struct {
+		Image string `json:"image"`
+	} `json:"image_list"`
+	VideoID        string `json:"video_id"`
+	ExternalTaskID string `json:"external_task_id"`
+	Model          string `json:"model"`
+	ModelName      string `json:"model_name"`
+	Mode           string `json:"mode"`
+	Duration       string `json:"duration"`
+	EffectScene    string `json:"effect_scene"`
+	Input          *struct {
+		ModelName string   `json:"model_name"`
+		Image     string   `json:"image"`
+		Images    []string `json:"images"`
+		Duration  string   `json:"duration"`
+		VideoID   string   `json:"video_id"`
+		VideoURL  string   `json:"video_url"`
+		Mode      string   `json:"mode"`
+		Text      string   `json:"text"`
+		VoiceID   string   `json:"voice_id"`
+		AudioFile string   `json:"audio_file"`
+		AudioURL  string   `json:"audio_url"`
+	} `json:"input"`
+}
+
+// create handles POST /v1/videos/{task_type}
+func (s *Server) create(w http.ResponseWriter, r *http.Request, taskType string) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidParams, "invalid request body")
+		return
+	}
+	if message := validate(taskType, &req); message != "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidParams, message)
+		return
+	}
+
+	duration := req.Duration
+	if taskType == "effects" {
+		duration = req.Input.Duration
+	}
+	if duration == "" {
+		duration = "5"
+	}
+
+	s.mu.Lock()
+	if taskType == "video-extend" {
+		source, ok := s.videoTask(req.VideoID)
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, CodeInvalidParams, "video_id does not refer to a generated video")
+			return
+		}
+		duration = extendedDuration(source.duration)
+	}
+	if taskType == "lip-sync" && req.Input.VideoID != "" {
+		source, ok := s.videoTask(req.Input.VideoID)
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, CodeInvalidParams, "video_id does not refer to a generated video")
+			return
+		}
+		duration = source.duration
+	}
+	if req.ExternalTaskID != "" {
+		if _, ok := s.lookup(req.ExternalTaskID); ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, CodeInvalidParams, "external_task_id already exists")
+			return
+		}
+	}
+	if !s.useUnit() {
+		s.mu.Unlock()
+		writeError(w, http.StatusTooManyRequests, CodeQuotaExhausted, "account balance not enough")
+		return
+	}
+	s.nextID++
+	t := &task{
+		id:         fmt.Sprintf("fake-%d", s.nextID),
+		externalID: req.ExternalTaskID,
+		taskType:   taskType,
+		failed:     strings.Contains(req.Prompt, FailMarker),
+		duration:   duration,
+		seq:        s.nextID,
+		createdAt:  time.Now().UnixMilli(),
+	}
+	s.tasks[t.id] = t
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"task_id":     t.id,
+		"task_status": StatusSubmitted,
+		"created_at":  t.createdAt,
+		"updated_at":  t.createdAt,
+	})
+}
+
+// useUnit takes one unit from the first pack with units left. Callers hold s.mu.
+func (s *Server) useUnit() bool {
+	for i := range s.ResourcePacks {
+		if s.ResourcePacks[i].Remaining >= 1 {
+			s.ResourcePacks[i].Remaining--
+			return true
+		}
+	}
+	return false
+}
+
+// costs handles GET /account/costs
+func (s *Server) costs(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("start_time") == "" || r.URL.Query().Get("end_time") == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidParams, "start_time and end_time are required")
+		return
+	}
+
+	s.mu.Lock()
+	infos := make([]map[string]interface{}, 0, len(s.ResourcePacks))
+	for _, pack := range s.ResourcePacks {
+		status := "online"
+		if pack.Remaining < 1 {
+			status = "runOut"
+		}
+		infos = append(infos, map[string]interface{}{
+			"resource_pack_id":   pack.ID,
+			"resource_pack_name": pack.Name,
+			"resource_pack_type": "decreasing_total",
+			"total_quantity":     pack.Total,
+			"remaining_quantity": pack.Remaining,
+			"status":             status,
+		})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code":                          0,
+		"msg":                           "",
+		"resource_pack_subscribe_infos": infos,
+	})
+}
+
+// validate checks the request the way the Kling API does, returning an error message
+func validate(taskType string, req *createRequest) string {
+	switch taskType {
+	case "text2video":
+		if req.Prompt == "" {
+			return "prompt is required"
+		}
+	case "image2video":
+		if req.Image == "" && req.ImageTail == "" {
+			return "image or image_tail is required"
+		}
+	case "video-extend":
+		if req.VideoID == "" {
+			return "video_id is required"
+		}
+	case "lip-sync":
+		switch {
+		case req.Input == nil:
+			return "input is required"
+		case req.Input.VideoID == "" && req.Input.VideoURL == "":
+			return "input.video_id or input.video_url is required"
+		case req.Input.Mode == "text2video" && (req.Input.Text == "" || req.Input.VoiceID == ""):
+			return "text2video lip-sync requires text and voice_id"
+		case req.Input.Mode == "audio2video" && req.Input.AudioFile == "" && req.Input.AudioURL == "":
+			return "audio2video lip-sync requires audio_file or audio_url"
+		case req.Input.Mode != "text2video" && req.Input.Mode != "audio2video":
+			return "input.mode must be text2video or audio2video"
+		}
+	case "effects":
+		switch {
+		case req.EffectScene == "":
+			return "effect_scene is required"
+		case req.Input == nil:
+			return "input is required"
+		case req.Input.Image == "" && len(req.Input.Images) == 0:
+			return "input.image or input.images is required"
+		case req.Input.Images != nil && len(req.Input.Images) != 2:
+			return "input.images must contain 2 images"
+		case req.Input.Duration != "" && req.Input.Duration != "5" && req.Input.Duration != "10":
+			return "input.duration must be 5 or 10"
+		case req.Input.ModelName != "" && !models[req.Input.ModelName]:
+			return fmt.Sprintf("model %s is not supported", req.Input.ModelName)
+		}
+	case "multi-image2video":
+		if len(req.ImageList) == 0 || len(req.ImageList) > 4 {
+			return "image_list must contain 1 to 4 images"
+		}
+	}
+
+	for _, model := range []string{req.ModelName, req.Model} {
+		if model != "" && !models[model] {
+			return fmt.Sprintf("model %s is not supported", model)
+		}
+	}
+	if req.Mode != "" && req.Mode != "std" && req.Mode != "pro" {
+		return "mode must be std or pro"
+	}
+	if req.Duration != "" && req.Duration != "5" && req.Duration != "10" {
+		return "duration must be 5 or 10"
+	}
+	return ""
+}
+
+// get handles GET /v1/videos/{task_type}/{task_id}; the path segment may also be
+// the external_task_id given at creation
+func (s *Server) get(w http.ResponseWriter, r *http.Request, taskType, taskID string) {
+	s.mu.Lock()
+	t, ok := s.lookup(taskID)
+	if !ok || t.taskType != taskType {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, "task not found")
+		return
+	}
+	t.polls++
+	status := s.status(t)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, taskData(r, t, status))
+}
+
+// list handles GET /v1/videos/{task_type}?pageNum=&pageSize=, returning the
+// tasks of a type newest first. Each listed task counts as a poll.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, taskType string) {
+	pageNum, pageSize := 1, 30
+	if v := r.URL.Query().Get("pageNum"); v != "" {
+		pageNum, _ = strconv.Atoi(v)
+	}
+	if v := r.URL.Query().Get("pageSize"); v != "" {
+		pageSize, _ = strconv.Atoi(v)
+	}
+	if pageNum < 1 || pageNum > 1000 || pageSize < 1 || pageSize > 500 {
+		writeError(w, http.StatusBadRequest, CodeInvalidParams, "pageNum must be 1-1000 and pageSize 1-500")
+		return
+	}
+
+	s.mu.Lock()
+	var tasks []*task
+	for _, t := range s.tasks {
+		if t.taskType == taskType {
+			tasks = append(tasks, t)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].seq > tasks[j].seq })
+
+	data := []map[string]interface{}{}
+	for i := (pageNum - 1) * pageSize; i < len(tasks) && len(data) < pageSize; i++ {
+		tasks[i].polls++
+		data = append(data, taskData(r, tasks[i], s.status(tasks[i])))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// taskData is the task object returned by the status and list endpoints
+func taskData(r *http.Request, t *task, status string) map[string]interface{} {
+	// Both the official task_id/task_status fields and the id/status fields
+	// returned by older relays are sent
+	data := map[string]interface{}{
+		"task_id":     t.id,
+		"id":          t.id,
+		"task_status": status,
+		"status":      status,
+		"created_at":  t.createdAt,
+		"updated_at":  time.Now().UnixMilli(),
+	}
+	if t.externalID != "" {
+		data["task_info"] = map[string]interface{}{"external_task_id": t.externalID}
+	}
+	switch status {
+	case StatusFailed:
+		data["task_status_msg"] = "generation failed"
+	case StatusSucceed:
+		data["task_result"] = map[string]interface{}{
+			"videos": []map[string]interface{}{{
+				"id":       t.id + "-video",
+				"url":      videoURL(r, t.id),
+				"duration": t.duration,
+			}},
+		}
+	}
+	return data
+}
+
+// lookup finds a task by its ID or external_task_id; the caller holds mu
+func (s *Server) lookup(id string) (*task, bool) {
+	if t, ok := s.tasks[id]; ok {
+		return t, true
+	}
+	for _, t := range s.tasks {
+		if t.externalID != "" && t.externalID == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// status returns the task status after its polls so far; the caller holds mu
+func (s *Server) status(t *task) string {
+	switch {
+	case t.polls <= s.QueuedPolls:
+		return StatusSubmitted
+	case t.polls <= s.QueuedPolls+s.ProcessingPolls:
+		return StatusProcessing
+	case t.failed:
+		return StatusFailed
+	default:
+		return StatusSucceed
+	}
+}
+
+// videoTask returns the succeeded task that generated a video; the caller holds mu
+func (s *Server) videoTask(videoID string) (*task, bool) {
+	t, ok := s.tasks[strings.TrimSuffix(videoID, "-video")]
+	if !ok || !strings.HasSuffix(videoID, "-video") || t.failed || s.status(t) != StatusSucceed {
+		return nil, false
+	}
+	return t, true
+}
+
+// extendedDuration adds the 4.5 seconds a video extension generates
+func extendedDuration(duration string) string {
+	seconds, _ := strconv.ParseFloat(duration, 64)
+	return strconv.FormatFloat(seconds+4.5, 'f', -1, 64)
+}
+
+// videoURL is the absolute URL of a task's video on this server
+func videoURL(r *http.Request, taskID string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/videos/" + taskID + ".mp4"
+}
+
+// serveVideo serves placeholder bytes for a succeeded task's video URL
+func (s *Server) serveVideo(w http.ResponseWriter, r *http.Request) {
+	taskID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/videos/"), ".mp4")
+
+	s.mu.Lock()
+	t, ok := s.tasks[taskID]
+	ready := ok && !t.failed && s.status(t) == StatusSucceed
+	s.mu.Unlock()
+
+	if !ready {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Write([]byte("fake video " + taskID))
+}
+
+// writeJSON writes a successful Kling response envelope
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    CodeSuccess,
+		"message": "SUCCEED",
+		"data":    data,
+	})
+}
+
+// writeError writes a Kling error response envelope
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
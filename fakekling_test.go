@@ -0,0 +1,91 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestFakeKling(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	var statuses []TaskStatus
+	for i := 0; i < 3; i++ {
+		result, err := client.GetGeneration(ctx, resp.TaskID)
+		if err != nil {
+			t.Fatalf("Failed to get generation: %v", err)
+		}
+		statuses = append(statuses, result.Status)
+		if result.Status == TaskStatusSucceeded {
+			video, err := client.Download(ctx, result)
+			if err != nil {
+				t.Fatalf("Failed to download video: %v", err)
+			}
+			if !strings.HasPrefix(string(video), "fake video") {
+				t.Errorf("Unexpected video body %q", video)
+			}
+		}
+	}
+	expected := []TaskStatus{TaskStatusQueued, TaskStatusProcessing, TaskStatusSucceeded}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Errorf("Expected statuses %v, got %v", expected, statuses)
+			break
+		}
+	}
+
+	// Failed prompts end in a failed task
+	resp, err = client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat " + fakekling.FailMarker, Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+	if result.Status != TaskStatusFailed {
+		t.Errorf("Expected failed, got %s", result.Status)
+	}
+
+	// Unknown tasks map to TaskNotFoundError
+	if _, err := client.GetGeneration(ctx, "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", err)
+	}
+
+	// Wrong secret keys are rejected
+	wrongKey, _ := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,wrong"}, &ClientConfig{Timeout: 5 * time.Second})
+	if _, err := wrongKey.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}); err == nil {
+		t.Error("Expected a wrong secret key to be rejected")
+	}
+
+	// Injected errors are returned once
+	fake.FailNext(http.StatusTooManyRequests, fakekling.CodeRateLimited, "rate limited")
+	if _, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A dog", Duration: 5, Width: 1280, Height: 720}, WithMaxRetries(0)); err == nil {
+		t.Error("Expected the injected error")
+	}
+	if _, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A dog", Duration: 5, Width: 1280, Height: 720}); err != nil {
+		t.Errorf("Expected the next request to succeed, got %v", err)
+	}
+	if fake.Tasks() != 3 {
+		t.Errorf("Expected 3 tasks, got %d", fake.Tasks())
+	}
+}
@@ -0,0 +1,53 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+func TestParseFeatures(t *testing.T) {
+	features := adapters.ParseFeatures("sdk=1.0, Callback ,passthrough, x-future=2")
+	if features["sdk"] != "1.0" || !features.Has(adapters.FeatureCallback) || !features.Has(adapters.FeaturePassthrough) || features["x-future"] != "2" {
+		t.Errorf("Unexpected features: %v", features)
+	}
+	if got := features.String(); got != "callback, passthrough, sdk=1.0, x-future=2" {
+		t.Errorf("Unexpected header value: %s", got)
+	}
+}
+
+func TestFeatureNegotiation(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(adapters.FeaturesHeader))
+		// A downstream vidgo relay announces what it supports
+		w.Header().Set(adapters.FeaturesHeader, "sdk=1.0, callback")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if client.PeerFeatures() != nil {
+		t.Error("Expected no peer features before the first request")
+	}
+
+	req := &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}
+	if _, err := client.CreateGeneration(context.Background(), req, WithFeatures(adapters.Features{adapters.FeaturePassthrough: ""})); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	if len(received) != 1 || received[0] != "passthrough, sdk="+adapters.SDKVersion {
+		t.Errorf("Unexpected advertised features: %v", received)
+	}
+	if peer := client.PeerFeatures(); !peer.Has(adapters.FeatureCallback) || peer["sdk"] != "1.0" {
+		t.Errorf("Unexpected peer features: %v", peer)
+	}
+}
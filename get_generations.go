@@ -0,0 +1,120 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// fetchConcurrency is how many tasks GetGenerations fetches at once when they
+// are not covered by a batch lookup
+const fetchConcurrency = 4
+
+// GetGenerations retrieves many tasks and returns their results by task ID.
+// Providers with a batch lookup (BatchTaskGetter, e.g. Kling's task list) answer
+// most tasks in a few requests; the rest are fetched concurrently with
+// GetGeneration. Tasks that fail are left out of the map and their errors joined
+// into the returned error.
+func (c *Client) GetGenerations(ctx context.Context, taskIDs []string, opts ...CallOption) (map[string]*TaskResult, error) {
+	seen := make(map[string]bool, len(taskIDs))
+	var ids []string
+	for i, taskID := range taskIDs {
+		if taskID == "" {
+			return nil, &ValidationError{Field: fmt.Sprintf("task_ids[%d]", i), Message: "task ID cannot be empty"}
+		}
+		if !seen[taskID] {
+			seen[taskID] = true
+			ids = append(ids, taskID)
+		}
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	results, errs := c.getGenerations(ctx, ids, o, opts)
+	return results, joinTaskErrors(ids, errs)
+}
+
+// getGenerations fetches distinct tasks, returning the results and errors by task ID
+func (c *Client) getGenerations(ctx context.Context, ids []string, o *callOptions, opts []CallOption) (map[string]*TaskResult, map[string]error) {
+	results := make(map[string]*TaskResult, len(ids))
+	if batch, ok := c.current().(BatchTaskGetter); ok && c.supportsBatchLookup() && len(ids) > 1 {
+		cache := c.resultCache(o)
+		var found map[string]*TaskResult
+		start := time.Now()
+		err := c.withRetry(c.captureContext(ctx, "get_generations", ""), o, func(ctx context.Context) error {
+			var err error
+			found, err = batch.GetGenerations(ctx, ids)
+			return err
+		})
+		elapsed := time.Since(start)
+		if err != nil && c.config.Debug {
+			fmt.Printf("Batch task lookup failed: %v, fetching tasks one by one\n", err)
+		}
+		statuses := c.statusCache(o)
+		for _, taskID := range ids {
+			if result, ok := found[taskID]; ok {
+				c.polled(taskID, result, cache, elapsed)
+				if statuses != nil {
+					statuses.put(c.current().Name()+":"+taskID, result)
+				}
+				results[taskID] = result
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	sem := make(chan struct{}, fetchConcurrency)
+	var wg sync.WaitGroup
+	for _, taskID := range ids {
+		if _, ok := results[taskID]; ok {
+			continue
+		}
+		wg.Add(1)
+		go func(taskID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := c.GetGeneration(ctx, taskID, opts...)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[taskID] = err
+				return
+			}
+			results[taskID] = result
+		}(taskID)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// supportsBatchLookup reports whether the provider can fetch many tasks at once
+func (c *Client) supportsBatchLookup() bool {
+	provider := c.current()
+	if w, ok := provider.(*adapterWrapper); ok {
+		_, ok = w.provider.(adapters.BatchTaskGetter)
+		return ok
+	}
+	_, ok := provider.(BatchTaskGetter)
+	return ok
+}
+
+// joinTaskErrors joins per-task errors in task order
+func joinTaskErrors(taskIDs []string, errs map[string]error) error {
+	var joined []error
+	for _, taskID := range taskIDs {
+		if err, ok := errs[taskID]; ok {
+			joined = append(joined, fmt.Errorf("task %s: %w", taskID, err))
+			delete(errs, taskID)
+		}
+	}
+	return errors.Join(joined...)
+}
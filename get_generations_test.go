@@ -0,0 +1,73 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestGetGenerations(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	var mu sync.Mutex
+	var lists, gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			if strings.Count(strings.Trim(r.URL.Path, "/"), "/") == 2 {
+				lists++
+			} else {
+				gets++
+			}
+			mu.Unlock()
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	var taskIDs []string
+	for _, prompt := range []string{"A cat", "A dog", "A bird"} {
+		resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: prompt, Duration: 5, Width: 1280, Height: 720})
+		if err != nil {
+			t.Fatalf("Failed to create generation: %v", err)
+		}
+		taskIDs = append(taskIDs, resp.TaskID)
+	}
+
+	results, err := client.GetGenerations(ctx, taskIDs)
+	if err != nil {
+		t.Fatalf("Failed to get generations: %v", err)
+	}
+	if len(results) != 3 || results[taskIDs[1]].TaskID != taskIDs[1] {
+		t.Errorf("Expected 3 results by task ID, got %v", results)
+	}
+	if lists != 1 || gets != 0 {
+		t.Errorf("Expected 1 list request and no task requests, got %d and %d", lists, gets)
+	}
+
+	// Tasks the provider cannot batch are fetched one by one; failures are reported per task
+	results, err = client.GetGenerations(ctx, append(taskIDs, "unknown-task"))
+	if len(results) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(results))
+	}
+	if !errors.Is(err, ErrTaskNotFound) || !strings.Contains(err.Error(), "unknown-task") {
+		t.Errorf("Expected ErrTaskNotFound for unknown-task, got %v", err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := client.GetGenerations(ctx, []string{taskIDs[0], ""}); !errors.As(err, &validationErr) || validationErr.Field != "task_ids[1]" {
+		t.Errorf("Expected task_ids[1] validation error, got %v", err)
+	}
+}
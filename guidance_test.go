@@ -0,0 +1,63 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestGuidanceScale(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+	scale := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		scale    *float64
+		expected float64
+	}{
+		{"default", nil, 0.5},
+		{"custom", scale(0.8), 0.8},
+		{"zero", scale(0), 0},
+	}
+
+	for _, tt := range tests {
+		req := &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720, GuidanceScale: tt.scale}
+		if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+			t.Fatalf("%s: failed to create generation: %v", tt.name, err)
+		}
+		if body := server.body(); body["cfg_scale"] != tt.expected {
+			t.Errorf("%s: expected cfg_scale %g, got %v", tt.name, tt.expected, body["cfg_scale"])
+		}
+	}
+
+	_, err := client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, GuidanceScale: scale(1.5)})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "guidance_scale" {
+		t.Errorf("Expected guidance_scale validation error, got %v", err)
+	}
+	if body := server.body(); body != nil {
+		t.Errorf("Expected an invalid guidance scale not to be submitted, got %v", body)
+	}
+}
+
+func TestKlingAdaptorGuidanceScale(t *testing.T) {
+	adaptor := NewKlingAdaptor()
+	scale := 0.7
+
+	data, err := adaptor.BuildRequestBody(&VidgoSubmitReq{Prompt: "A cat", Duration: 5, GuidanceScale: &scale})
+	if err != nil {
+		t.Fatalf("Failed to build request body: %v", err)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(data, &body)
+	if body["cfg_scale"] != 0.7 {
+		t.Errorf("Expected cfg_scale 0.7, got %v", body["cfg_scale"])
+	}
+
+	scale = -0.1
+	if err := adaptor.actionValidate(&VidgoSubmitReq{Prompt: "A cat", GuidanceScale: &scale}, "generate"); err == nil {
+		t.Error("Expected an out-of-range guidance scale to be rejected")
+	}
+}
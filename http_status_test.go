@@ -0,0 +1,142 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"validation", &ValidationError{Field: "prompt", Message: "required"}, http.StatusBadRequest},
+		{"invalid request", fmt.Errorf("%w: bad size", ErrInvalidRequest), http.StatusBadRequest},
+		{"auth", ErrAuthenticationFailed, http.StatusUnauthorized},
+		{"quota", ErrInsufficientQuota, http.StatusPaymentRequired},
+		{"not found", &TaskNotFoundError{TaskID: "t1"}, http.StatusNotFound},
+		{"rate limited", ErrRateLimitExceeded, http.StatusTooManyRequests},
+		{"unsupported", fmt.Errorf("%w: luma does not support extension", ErrUnsupportedOperation), http.StatusNotImplemented},
+		{"api 500", &APIError{Code: 500, Message: "boom"}, http.StatusBadGateway},
+		{"api 503", &APIError{Code: 503, Message: "busy"}, http.StatusServiceUnavailable},
+		{"api 429", &APIError{Code: 429, Message: "slow down"}, http.StatusTooManyRequests},
+		{"network", ErrNetworkError, http.StatusBadGateway},
+		{"unknown", fmt.Errorf("something else"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("%s: Expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, &APIError{Code: 503, Message: "busy", Provider: "kling"})
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+	var body ErrorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	if body.Error.Code != "upstream_unavailable" || body.Error.Provider != "kling" || !body.Error.Retryable {
+		t.Errorf("Unexpected error body: %+v", body.Error)
+	}
+
+	rec = httptest.NewRecorder()
+	WriteError(rec, &ValidationError{Field: "duration", Message: "must be 5 or 10"})
+	body = ErrorBody{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusBadRequest || body.Error.Field != "duration" || body.Error.Retryable {
+		t.Errorf("Expected 400 for duration, got %d %+v", rec.Code, body.Error)
+	}
+}
+
+func TestKlingAdaptorDoResponseStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   int
+		code   string
+	}{
+		{http.StatusUnauthorized, `{"code":1002,"message":"token expired"}`, http.StatusUnauthorized, "kling_error_1002"},
+		{http.StatusOK, `{"code":1102,"message":"resource pack exhausted"}`, http.StatusPaymentRequired, "kling_error_1102"},
+		{http.StatusBadRequest, `{"code":1201,"message":"invalid duration"}`, http.StatusBadRequest, "kling_error_1201"},
+		{http.StatusTooManyRequests, `{"code":1302,"message":"rate limited"}`, http.StatusTooManyRequests, "kling_error_1302"},
+		{http.StatusInternalServerError, `{"code":5001,"message":"unavailable"}`, http.StatusServiceUnavailable, "kling_error_5001"},
+		{http.StatusBadGateway, `<html>bad gateway</html>`, http.StatusBadGateway, "upstream_error"},
+	}
+
+	adaptor := &KlingAdaptor{}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Body: io.NopCloser(strings.NewReader(tt.body))}
+		_, _, taskErr := adaptor.DoResponse(resp)
+		if taskErr == nil {
+			t.Errorf("Expected error for %s", tt.body)
+			continue
+		}
+		if taskErr.StatusCode != tt.want || taskErr.Code != tt.code {
+			t.Errorf("Expected %d %s, got %d %s", tt.want, tt.code, taskErr.StatusCode, taskErr.Code)
+		}
+		if got := HTTPStatus(taskErr); got != tt.want {
+			t.Errorf("Expected HTTPStatus %d, got %d", tt.want, got)
+		}
+	}
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"code":0,"data":{"task_id":"t1"}}`))}
+	if taskID, _, taskErr := adaptor.DoResponse(resp); taskErr != nil || taskID != "t1" {
+		t.Errorf("Expected t1, got %s (%v)", taskID, taskErr)
+	}
+}
+
+func TestClientProviderErrorStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/broke") {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":1102,"message":"resource pack exhausted"}`))
+			return
+		}
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"code":1302,"message":"rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"},
+		&ClientConfig{Timeout: 5 * time.Second, MaxRetries: 2, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if calls.Load() != 3 {
+		t.Errorf("Expected a 429 to be retried twice, got %d calls", calls.Load())
+	}
+	if !IsRetryableError(err) || !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("Expected a retryable rate limit error, got %v", err)
+	}
+	body := NewErrorBody(err)
+	if HTTPStatus(err) != http.StatusTooManyRequests || body.Error.Code != "rate_limited" || !body.Error.Retryable || body.Error.Provider != "Kling" {
+		t.Errorf("Expected a retryable rate_limited 429 from Kling, got %d %+v", HTTPStatus(err), body)
+	}
+
+	// A depleted resource pack is a quota error, not worth retrying
+	_, err = client.GetGeneration(context.Background(), "broke")
+	if !errors.Is(err, ErrInsufficientQuota) || IsRetryableError(err) || HTTPStatus(err) != http.StatusPaymentRequired {
+		t.Errorf("Expected a non-retryable insufficient quota error, got %v (%d)", err, HTTPStatus(err))
+	}
+}
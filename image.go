@@ -0,0 +1,127 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// ImageUploader uploads image data to storage and returns a URL the provider can fetch.
+// When configured, raw image bytes and data URIs are uploaded instead of sent inline.
+type ImageUploader func(ctx context.Context, data []byte, contentType string) (string, error)
+
+// prepareImage converts ImageBytes or a data URI into a form the provider accepts,
+// uploading it when an ImageUploader is configured. It returns a copy when it changes.
+func (c *Client) prepareImage(ctx context.Context, req *GenerationRequest) (*GenerationRequest, error) {
+	data, contentType := req.ImageBytes, ""
+	if len(data) == 0 {
+		var ok bool
+		if data, contentType, ok = adapters.DecodeDataURI(req.Image); !ok {
+			return req, nil
+		}
+	}
+
+	if c.config.ImageUploader == nil {
+		return req, nil
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	url, err := c.config.ImageUploader(ctx, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	prepared := *req
+	prepared.Image = url
+	prepared.ImageBytes = nil
+	return &prepared, nil
+}
+
+// loadImageFile reads ImageFile into ImageBytes, so local files go through the
+// same normalization, validation and upload as raw bytes. It returns a copy when
+// it changes.
+func loadImageFile(req *GenerationRequest) (*GenerationRequest, error) {
+	if req == nil || req.ImageFile == "" {
+		return req, nil
+	}
+	if err := validateImage(req); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(req.ImageFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, &ValidationError{Field: "image_file", Message: "image file is empty"}
+	}
+
+	prepared := *req
+	prepared.ImageFile = ""
+	prepared.ImageBytes = data
+	return &prepared, nil
+}
+
+// validateImage checks the image input fields
+func validateImage(req *GenerationRequest) error {
+	if req.Image != "" && len(req.ImageBytes) > 0 {
+		return &ValidationError{Field: "image", Message: "image and image_bytes are mutually exclusive"}
+	}
+	if req.ImageFile != "" && (req.Image != "" || len(req.ImageBytes) > 0) {
+		return &ValidationError{Field: "image_file", Message: "image_file cannot be combined with image or image_bytes"}
+	}
+	if adapters.IsDataURI(req.Image) {
+		if _, _, ok := adapters.DecodeDataURI(req.Image); !ok {
+			return &ValidationError{Field: "image", Message: "invalid data URI"}
+		}
+	}
+	if adapters.IsDataURI(req.ImageTail) {
+		if _, _, ok := adapters.DecodeDataURI(req.ImageTail); !ok {
+			return &ValidationError{Field: "image_tail", Message: "invalid data URI"}
+		}
+	}
+	if len(req.Images) > 0 && (req.Image != "" || len(req.ImageBytes) > 0 || req.ImageFile != "" || req.ImageTail != "") {
+		return &ValidationError{Field: "images", Message: "images cannot be combined with image, image_bytes, image_file or image_tail"}
+	}
+	for i, image := range req.Images {
+		if image == "" {
+			return &ValidationError{Field: fmt.Sprintf("images[%d]", i), Message: "image cannot be empty"}
+		}
+		if adapters.IsDataURI(image) {
+			if _, _, ok := adapters.DecodeDataURI(image); !ok {
+				return &ValidationError{Field: fmt.Sprintf("images[%d]", i), Message: "invalid data URI"}
+			}
+		}
+	}
+	return validateKeyframes(req)
+}
+
+// validateKeyframes checks keyframe images and that their times increase within the duration
+func validateKeyframes(req *GenerationRequest) error {
+	if len(req.Keyframes) > 0 && (req.Image != "" || len(req.ImageBytes) > 0 || req.ImageFile != "" || req.ImageTail != "" || len(req.Images) > 0) {
+		return &ValidationError{Field: "keyframes", Message: "keyframes cannot be combined with image, image_bytes, image_file, image_tail or images"}
+	}
+	for i, keyframe := range req.Keyframes {
+		field := fmt.Sprintf("keyframes[%d]", i)
+		if keyframe.Image == "" {
+			return &ValidationError{Field: field, Message: "image cannot be empty"}
+		}
+		if adapters.IsDataURI(keyframe.Image) {
+			if _, _, ok := adapters.DecodeDataURI(keyframe.Image); !ok {
+				return &ValidationError{Field: field, Message: "invalid data URI"}
+			}
+		}
+		if keyframe.AtSeconds < 0 || (req.Duration > 0 && keyframe.AtSeconds > req.Duration) {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("at_seconds must be between 0 and the duration, got %g", keyframe.AtSeconds)}
+		}
+		if i > 0 && keyframe.AtSeconds <= req.Keyframes[i-1].AtSeconds {
+			return &ValidationError{Field: field, Message: "keyframes must be in increasing time order"}
+		}
+	}
+	return nil
+}
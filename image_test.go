@@ -0,0 +1,99 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	imagepng "image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestImageInputs(t *testing.T) {
+	server := newKlingStub(t)
+	png := testPNG(512, 512)
+	encoded := base64.StdEncoding.EncodeToString(png)
+	client := server.client(t)
+
+	req := &GenerationRequest{Image: "data:image/png;base64," + encoded, Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation from data URI: %v", err)
+	}
+	if body := server.body(); body["image"] != encoded {
+		t.Errorf("Expected plain Base64 image, got %v", body["image"])
+	}
+
+	req = &GenerationRequest{ImageBytes: png, Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation from bytes: %v", err)
+	}
+	if body := server.body(); body["image"] != encoded {
+		t.Errorf("Expected Base64 image from bytes, got %v", body["image"])
+	}
+
+	var uploadedType string
+	client = server.client(t, &ClientConfig{
+		Timeout: 5 * time.Second,
+		ImageUploader: func(ctx context.Context, data []byte, contentType string) (string, error) {
+			uploadedType = contentType
+			return "https://storage.example.com/upload.png", nil
+		},
+	})
+
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation with uploader: %v", err)
+	}
+	if body := server.body(); body["image"] != "https://storage.example.com/upload.png" || uploadedType != "image/png" {
+		t.Errorf("Expected uploaded URL with image/png, got %v (%s)", body["image"], uploadedType)
+	}
+
+	req = &GenerationRequest{Image: "https://example.com/a.png", ImageBytes: png, Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); err == nil {
+		t.Error("Image and ImageBytes together should return error")
+	}
+	if body := server.body(); body != nil {
+		t.Errorf("Expected a rejected request not to be submitted, got %v", body)
+	}
+}
+
+func TestImageFile(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	png := testPNG(512, 512)
+	path := filepath.Join(t.TempDir(), "cat.png")
+	os.WriteFile(path, png, 0o600)
+	req := &GenerationRequest{ImageFile: path, Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation from file: %v", err)
+	}
+	if body := server.body(); body["image"] != base64.StdEncoding.EncodeToString(png) {
+		t.Errorf("Expected Base64 image from file, got %v", body["image"])
+	}
+	if req.ImageBytes != nil {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+
+	req = &GenerationRequest{ImageFile: filepath.Join(t.TempDir(), "missing.png"), Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for a missing file, got %v", err)
+	}
+
+	var validationErr *ValidationError
+	req = &GenerationRequest{ImageFile: path, Image: "https://example.com/a.png", Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); !errors.As(err, &validationErr) || validationErr.Field != "image_file" {
+		t.Errorf("Expected image_file validation error, got %v", err)
+	}
+}
+
+// testPNG encodes a blank PNG of the given size
+func testPNG(width, height int) []byte {
+	var buf bytes.Buffer
+	imagepng.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)))
+	return buf.Bytes()
+}
@@ -0,0 +1,123 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJimengProvider(t *testing.T) {
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "HMAC-SHA256 Credential=test_access_key/") || !strings.Contains(auth, "Signature=") {
+			t.Errorf("Unexpected Authorization header: %s", auth)
+		}
+		if r.Header.Get("X-Date") == "" || r.Header.Get("X-Content-Sha256") == "" {
+			t.Error("Expected X-Date and X-Content-Sha256 headers")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("Action") {
+		case "CVSync2AsyncSubmitTask":
+			json.NewDecoder(r.Body).Decode(&submitted)
+			w.Write([]byte(`{"code":10000,"message":"Success","data":{"task_id":"7392"}}`))
+		case "CVSync2AsyncGetResult":
+			w.Write([]byte(`{"code":10000,"message":"Success","data":{"status":"done","video_url":"https://cdn.example.com/v.mp4"}}`))
+		default:
+			t.Errorf("Unexpected action: %s", r.URL.Query().Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderJimeng, &ProviderConfig{
+		BaseURL:   server.URL,
+		APIKey:    "test_access_key",
+		SecretKey: "test_secret_key",
+		Timeout:   5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat",
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		Model:    "jimeng-v1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if submitted["req_key"] != "jimeng_vgfm_t2v_l20" || submitted["aspect_ratio"] != "16:9" {
+		t.Errorf("Unexpected submitted payload: %v", submitted)
+	}
+
+	result, err := client.GetGeneration(context.Background(), resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "https://cdn.example.com/v.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	if err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 5, Width: 512, Height: 512, Model: "jimeng-v9"}); err == nil {
+		t.Error("Unsupported Jimeng model should return error")
+	}
+}
+
+func TestJimengResolutionTiers(t *testing.T) {
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submitted = nil
+		json.NewDecoder(r.Body).Decode(&submitted)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":10000,"message":"Success","data":{"task_id":"7392"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderJimeng, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		req        *GenerationRequest
+		reqKey     string
+		resolution interface{}
+	}{
+		{"size derived 1080p", &GenerationRequest{Model: "jimeng-v2", Width: 1920, Height: 1080}, "jimeng_t2v_v30_1080p", nil},
+		{"size falls back to available tier", &GenerationRequest{Model: "seedance-1.0-lite", Width: 640, Height: 360}, "jimeng_t2v_v30", nil},
+		{"quality level", &GenerationRequest{Model: "seedance-1.0-pro", Width: 1280, Height: 720, QualityLevel: QualityLevelHigh}, "jimeng_ti2v_v30_pro", "1080p"},
+		{"metadata resolution", &GenerationRequest{Model: "seedance-1.0-pro", Width: 1280, Height: 720, Metadata: map[string]interface{}{"resolution": "480p"}}, "jimeng_ti2v_v30_pro", "480p"},
+		{"quality level falls back to available tier", &GenerationRequest{Model: "jimeng-v1", Width: 1280, Height: 720, QualityLevel: QualityLevelHigh}, "jimeng_vgfm_t2v_l20", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Prompt = "A cat"
+			tt.req.Duration = 5
+			if _, err := client.CreateGeneration(context.Background(), tt.req); err != nil {
+				t.Fatalf("Failed to create generation: %v", err)
+			}
+			if submitted["req_key"] != tt.reqKey || submitted["resolution"] != tt.resolution {
+				t.Errorf("Expected req_key %s resolution %v, got %v", tt.reqKey, tt.resolution, submitted)
+			}
+		})
+	}
+
+	err = client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Model: "jimeng-v1", Metadata: map[string]interface{}{"resolution": "1080p"}})
+	if err == nil || !strings.Contains(err.Error(), "1080p") {
+		t.Errorf("Expected unavailable tier to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKlingKeyframes(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+	ctx := context.Background()
+
+	req := &GenerationRequest{
+		Prompt:   "A flower blooming",
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		Keyframes: []Keyframe{
+			{Image: "https://example.com/bud.png", AtSeconds: 0},
+			{Image: "https://example.com/flower.png", AtSeconds: 5},
+		},
+	}
+	if _, err := client.CreateGeneration(ctx, req); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if path := server.path(); path != "/v1/videos/image2video" {
+		t.Errorf("Expected image2video endpoint, got %s", path)
+	}
+	if body := server.body(); body["image"] != "https://example.com/bud.png" || body["image_tail"] != "https://example.com/flower.png" {
+		t.Errorf("Expected keyframes as image and image_tail, got %v", body)
+	}
+
+	// Kling cannot condition on frames in the middle of the clip
+	req.Keyframes = []Keyframe{{Image: "https://example.com/bud.png", AtSeconds: 0}, {Image: "https://example.com/half.png", AtSeconds: 2.5}}
+	if _, err := client.CreateGeneration(ctx, req); !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "2.5s") {
+		t.Errorf("Expected ErrInvalidRequest for a keyframe at 2.5s, got %v", err)
+	}
+
+	req.Keyframes = []Keyframe{{Image: "https://example.com/flower.png", AtSeconds: 5}, {Image: "https://example.com/bud.png", AtSeconds: 0}}
+	var validationErr *ValidationError
+	if _, err := client.CreateGeneration(ctx, req); !errors.As(err, &validationErr) || validationErr.Field != "keyframes[1]" {
+		t.Errorf("Expected keyframes[1] validation error for unordered keyframes, got %v", err)
+	}
+
+	req.Keyframes = []Keyframe{{Image: "https://example.com/bud.png"}}
+	req.Image = "https://example.com/start.png"
+	if _, err := client.CreateGeneration(ctx, req); !errors.As(err, &validationErr) || validationErr.Field != "keyframes" {
+		t.Errorf("Expected keyframes validation error when combined with image, got %v", err)
+	}
+	if body := server.body(); body != nil {
+		t.Errorf("Expected rejected keyframes not to be submitted, got %v", body)
+	}
+}
+
+func TestKeyframesUnsupported(t *testing.T) {
+	client, err := NewClient(ProviderWanx, &ProviderConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{
+		Duration:  5,
+		Width:     1280,
+		Height:    720,
+		Keyframes: []Keyframe{{Image: "https://example.com/bud.png"}},
+	})
+	if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "does not support keyframes") {
+		t.Errorf("Expected unsupported keyframes error, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package vidgo
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,18 +13,31 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/adapters/kling"
+	"github.com/feitianbubu/vidgo/pace"
+
 	"github.com/golang-jwt/jwt"
 )
 
 // KlingAdaptor implements TaskAdaptorInterface for Kling video generation
+//
+// Deprecated: use TaskAdaptor, which dispatches to this adaptor for the kling
+// vendor. Run cmd/vidgo-migrate to rewrite usages.
 type KlingAdaptor struct {
 	ChannelType int
 	accessKey   string
 	secretKey   string
 	baseURL     string
+	provider    adapters.Provider // Serves Submit and Fetch; nil for an invalid API key
+
+	// PollPolicy paces the status checks of FetchTaskWait, defaults to LongPollPolicy
+	PollPolicy *PollPolicy
 }
 
 // NewKlingAdaptor creates a new KlingAdaptor instance
+//
+// Deprecated: use NewTaskAdaptor. Run cmd/vidgo-migrate to rewrite usages.
 func NewKlingAdaptor() *KlingAdaptor {
 	return &KlingAdaptor{}
 }
@@ -32,11 +46,11 @@ func NewKlingAdaptor() *KlingAdaptor {
 func (k *KlingAdaptor) Init(info *TaskRelayInfo) {
 	k.ChannelType = info.ChannelType
 
-	// Set default official URL if baseUrl is empty
-	if info.BaseUrl == "" {
-		info.BaseUrl = "https://api.klingai.com"
-	}
+	// Set default official URL if baseUrl is empty; info may be shared, so it is not modified
 	k.baseURL = info.BaseUrl
+	if k.baseURL == "" {
+		k.baseURL = "https://api.klingai.com"
+	}
 
 	// Parse API key in format "access_key,secret_key"
 	keyParts := strings.Split(info.ApiKey, ",")
@@ -44,6 +58,42 @@ func (k *KlingAdaptor) Init(info *TaskRelayInfo) {
 		k.accessKey = strings.TrimSpace(keyParts[0])
 		k.secretKey = strings.TrimSpace(keyParts[1])
 	}
+
+	k.provider, _ = kling.New(&adapters.ProviderConfig{APIKey: info.ApiKey, BaseURL: k.baseURL})
+}
+
+// Submit creates a Kling task for a validated request. Errors are *TaskAdaptorError.
+func (k *KlingAdaptor) Submit(ctx context.Context, vidgoRequest *VidgoSubmitReq) (*GenerationResponse, error) {
+	if k.provider == nil {
+		return nil, errKlingNotInitialized()
+	}
+	resp, err := k.provider.CreateGeneration(ctx, vidgoRequest.GenerationRequest("kling"))
+	if err != nil {
+		return nil, adapters.NewTaskAdaptorError("kling", err)
+	}
+	return &GenerationResponse{TaskID: resp.TaskID, Status: TaskStatus(resp.Status)}, nil
+}
+
+// Fetch returns the status of a Kling task. Errors are *TaskAdaptorError.
+func (k *KlingAdaptor) Fetch(ctx context.Context, taskID string) (*TaskResult, error) {
+	if k.provider == nil {
+		return nil, errKlingNotInitialized()
+	}
+	result, err := k.provider.GetGeneration(ctx, taskID)
+	if err != nil {
+		return nil, adapters.NewTaskAdaptorError("kling", err)
+	}
+	return fromAdapterResult(ProviderKling, result), nil
+}
+
+// errKlingNotInitialized is returned by Submit and Fetch before Init or after Init with an invalid API key
+func errKlingNotInitialized() *TaskAdaptorError {
+	return &TaskAdaptorError{
+		StatusCode: http.StatusUnauthorized,
+		Code:       "invalid_api_key",
+		Message:    "Kling adaptor is not initialized with a valid 'access_key,secret_key' API key",
+		LocalError: true,
+	}
 }
 
 // ValidateRequestAndSetAction validates the request and sets the action for Kling
@@ -94,19 +144,23 @@ func (k *KlingAdaptor) BuildRequestHeader(info *TaskRelayInfo) map[string]string
 		"Accept":        "application/json",
 		"Authorization": "Bearer " + token,
 		"User-Agent":    "vidgo-sdk/1.0",
+
+		adapters.FeaturesHeader: adapters.Features{adapters.FeatureSDK: adapters.SDKVersion}.String(),
 	}
 }
 
 // KlingRequest represents Kling-specific request format
 type KlingRequest struct {
-	Prompt      string  `json:"prompt,omitempty"`
-	Image       string  `json:"image,omitempty"`
-	Mode        string  `json:"mode,omitempty"`
-	Duration    string  `json:"duration,omitempty"`
-	AspectRatio string  `json:"aspect_ratio,omitempty"`
-	Model       string  `json:"model,omitempty"`
-	ModelName   string  `json:"model_name,omitempty"`
-	CfgScale    float64 `json:"cfg_scale,omitempty"`
+	Prompt         string  `json:"prompt,omitempty"`
+	NegativePrompt string  `json:"negative_prompt,omitempty"`
+	Image          string  `json:"image,omitempty"`
+	ImageTail      string  `json:"image_tail,omitempty"`
+	Mode           string  `json:"mode,omitempty"`
+	Duration       string  `json:"duration,omitempty"`
+	AspectRatio    string  `json:"aspect_ratio,omitempty"`
+	Model          string  `json:"model,omitempty"`
+	ModelName      string  `json:"model_name,omitempty"`
+	CfgScale       float64 `json:"cfg_scale"`
 }
 
 // BuildRequestBody builds the request body for Kling API call
@@ -114,7 +168,7 @@ func (k *KlingAdaptor) BuildRequestBody(vidgoRequest *VidgoSubmitReq) ([]byte, e
 	// Convert to Kling format
 	klingReq := k.convertToKlingRequest(vidgoRequest)
 
-	data, err := json.Marshal(klingReq)
+	data, err := json.Marshal(adapters.TransformRequest(string(ProviderKling), klingReq))
 	if err != nil {
 		return nil, err
 	}
@@ -124,18 +178,31 @@ func (k *KlingAdaptor) BuildRequestBody(vidgoRequest *VidgoSubmitReq) ([]byte, e
 // convertToKlingRequest converts standard request to Kling format
 func (k *KlingAdaptor) convertToKlingRequest(req *VidgoSubmitReq) *KlingRequest {
 	klingReq := &KlingRequest{
-		Prompt:    req.Prompt,
-		ModelName: req.Model, // 1. modelName取自vidgo的model
-		Model:     req.Model,
-		CfgScale:  0.5, // Default cfg_scale
+		Prompt:         req.Prompt,
+		NegativePrompt: req.NegativePrompt,
+		ModelName:      req.Model, // 1. modelName取自vidgo的model
+		Model:          req.Model,
+		CfgScale:       kling.DefaultCfgScale,
 	}
 
-	// 2. image取自vidgo的image
+	if req.GuidanceScale != nil {
+		klingReq.CfgScale = *req.GuidanceScale
+	}
+
+	// 2. image取自vidgo的image，data URI转为可灵接受的纯Base64
 	klingReq.Image = req.Image
+	if data, _, ok := adapters.DecodeDataURI(req.Image); ok {
+		klingReq.Image = base64.StdEncoding.EncodeToString(data)
+	}
+	klingReq.ImageTail = req.ImageTail
+	if data, _, ok := adapters.DecodeDataURI(req.ImageTail); ok {
+		klingReq.ImageTail = base64.StdEncoding.EncodeToString(data)
+	}
 
-	// 3. mode取自metadata的mode，如果没取到默认为std
-	klingReq.Mode = "std" // 默认为std
-	if req.Metadata != nil {
+	// 3. mode取自vidgo的mode，兼容metadata的mode，如果没取到默认为std
+	klingReq.Mode = req.Mode
+	if klingReq.Mode == "" {
+		klingReq.Mode = ModeStandard
 		if mode, ok := req.Metadata["mode"].(string); ok && mode != "" {
 			klingReq.Mode = mode
 		}
@@ -161,6 +228,10 @@ func (k *KlingAdaptor) convertToKlingRequest(req *VidgoSubmitReq) *KlingRequest
 
 // getAspectRatio determines aspect ratio from size string
 func (k *KlingAdaptor) getAspectRatio(size string) string {
+	if preset, ok := adapters.LookupSizePreset(string(ProviderKling), size); ok {
+		return preset.AspectRatio
+	}
+
 	switch size {
 	case "1024x1024", "512x512":
 		return "1:1"
@@ -213,19 +284,37 @@ func (k *KlingAdaptor) DoResponse(resp *http.Response) (taskID string, taskData
 	// Try to parse as Kling response first
 	var klingResponse KlingResponse
 	err = json.Unmarshal(responseBody, &klingResponse)
-	if err == nil && klingResponse.Code == 0 {
+	if err == nil && klingResponse.Code == 0 && resp.StatusCode < 400 {
 		// Success response from Kling
 		return klingResponse.Data.TaskID, responseBody, nil
 	}
+	if err == nil && klingResponse.Code != 0 {
+		taskErr = &TaskAdaptorError{
+			StatusCode: klingErrorStatus(klingResponse.Code, resp.StatusCode),
+			Code:       fmt.Sprintf("kling_error_%d", klingResponse.Code),
+			Message:    klingResponse.Message,
+			LocalError: false,
+		}
+		return
+	}
 
 	// If not Kling format, try standard format
 	var vidgoResponse TaskResponse[string]
 	err = json.Unmarshal(responseBody, &vidgoResponse)
 	if err != nil {
+		if resp.StatusCode >= 400 {
+			taskErr = &TaskAdaptorError{
+				StatusCode: upstreamStatus(resp.StatusCode),
+				Code:       "upstream_error",
+				Message:    string(responseBody),
+				LocalError: false,
+			}
+			return
+		}
 		// warn log
 		fmt.Printf("unmarshal Kling response fail: %s, body: %s\n", err.Error(), responseBody)
 		taskErr = &TaskAdaptorError{
-			StatusCode: 500,
+			StatusCode: http.StatusBadGateway,
 			Code:       "unmarshal_response_body_failed",
 			Message:    errors.Wrapf(err, "body: %s", responseBody).Error(),
 			LocalError: true,
@@ -235,7 +324,7 @@ func (k *KlingAdaptor) DoResponse(resp *http.Response) (taskID string, taskData
 
 	if !vidgoResponse.IsSuccess() {
 		taskErr = &TaskAdaptorError{
-			StatusCode: resp.StatusCode,
+			StatusCode: upstreamStatus(resp.StatusCode),
 			Code:       vidgoResponse.Code,
 			Message:    vidgoResponse.Message,
 			LocalError: false,
@@ -243,22 +332,51 @@ func (k *KlingAdaptor) DoResponse(resp *http.Response) (taskID string, taskData
 		return
 	}
 
-	// Handle error responses
-	if klingResponse.Code != 0 {
-		taskErr = &TaskAdaptorError{
-			StatusCode: resp.StatusCode,
-			Code:       fmt.Sprintf("kling_error_%d", klingResponse.Code),
-			Message:    klingResponse.Message,
-			LocalError: false,
-		}
-		return
+	return vidgoResponse.Data, responseBody, nil
+}
+
+// klingErrorStatus maps a Kling business error code to the HTTP status relayed
+// to callers; Kling often answers errors with 200 or a generic 400/500.
+func klingErrorStatus(code, httpStatus int) int {
+	switch {
+	case code >= 1000 && code < 1100:
+		return http.StatusUnauthorized
+	case code == 1101 || code == 1102:
+		return http.StatusPaymentRequired
+	case code == 1103:
+		return http.StatusForbidden
+	case code == 1203:
+		return http.StatusNotFound
+	case code >= 1200 && code < 1300, code == 1300, code == 1301:
+		return http.StatusBadRequest
+	case code >= 1302 && code < 1400:
+		return http.StatusTooManyRequests
+	case code == 5001:
+		return http.StatusServiceUnavailable
+	case code >= 5000:
+		return http.StatusBadGateway
 	}
+	return upstreamStatus(httpStatus)
+}
 
-	return vidgoResponse.Data, responseBody, nil
+// upstreamStatus maps an upstream HTTP status to the one relayed to callers:
+// client errors pass through, server errors become 502 (503 is kept).
+func upstreamStatus(status int) int {
+	switch {
+	case status == http.StatusServiceUnavailable:
+		return http.StatusServiceUnavailable
+	case status >= 500, status < 400:
+		return http.StatusBadGateway
+	}
+	return status
 }
 
 // FetchTask fetches the status of a Kling video generation task
 func (k *KlingAdaptor) FetchTask(baseUrl, key string, taskID string) (*http.Response, error) {
+	return k.fetchTask(context.Background(), baseUrl, key, taskID)
+}
+
+func (k *KlingAdaptor) fetchTask(ctx context.Context, baseUrl, key string, taskID string) (*http.Response, error) {
 	// Set default official URL if baseUrl is empty
 	if baseUrl == "" {
 		baseUrl = "https://api.klingai.com"
@@ -279,7 +397,7 @@ func (k *KlingAdaptor) FetchTask(baseUrl, key string, taskID string) (*http.Resp
 
 	// 设置超时时间
 	timeout := time.Second * 15
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// 使用带有超时的 context 创建新的请求
@@ -287,15 +405,88 @@ func (k *KlingAdaptor) FetchTask(baseUrl, key string, taskID string) (*http.Resp
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("User-Agent", "vidgo-sdk/1.0")
+	adapters.SetFeatureHeader(ctx, req)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
+
+	// 超时context在返回前取消，先读完响应体
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
 	return resp, nil
 }
 
+// LongPollPolicy returns the default PollPolicy of FetchTaskWait: the first check
+// is immediate and later ones back off from one second, since the relay's caller
+// is waiting on the response
+func LongPollPolicy() *PollPolicy {
+	return &PollPolicy{
+		Interval:    time.Second,
+		Multiplier:  1.5,
+		MaxInterval: 5 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// FetchTaskWait fetches a task like FetchTask, but holds the call up to wait until
+// the task has succeeded or failed, so relays can offer a long-poll fetch. The
+// last response is returned either way; it fails with ctx.Err() if ctx is done first.
+func (k *KlingAdaptor) FetchTaskWait(ctx context.Context, baseUrl, key string, taskID string, wait time.Duration) (*http.Response, error) {
+	policy := k.PollPolicy
+	if policy == nil {
+		policy = LongPollPolicy()
+	}
+	backoff := policy.Backoff()
+
+	deadline := time.Now().Add(wait)
+	for attempt := 0; ; attempt++ {
+		if err := pace.Sleep(ctx, backoff.Delay(attempt)); err != nil {
+			return nil, err
+		}
+
+		resp, err := k.fetchTask(ctx, baseUrl, key, taskID)
+		if err != nil || resp.StatusCode != http.StatusOK || time.Now().Add(backoff.Base(attempt+1)).After(deadline) {
+			return resp, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if klingTaskFinished(body) {
+			return resp, nil
+		}
+	}
+}
+
+// klingTaskFinished reports whether a Kling task status body has a terminal status.
+// Kling sends task_status; vidgo-based relays send status.
+func klingTaskFinished(body []byte) bool {
+	var response struct {
+		Data struct {
+			Status     string `json:"status"`
+			TaskStatus string `json:"task_status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return true
+	}
+	for _, status := range []string{response.Data.Status, response.Data.TaskStatus} {
+		if status == "succeed" || status == "failed" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetModelList returns the list of supported Kling models
 func (k *KlingAdaptor) GetModelList() []string {
 	return []string{
@@ -318,6 +509,18 @@ func (k *KlingAdaptor) actionValidate(vidgoRequest *VidgoSubmitReq, action strin
 		return fmt.Errorf("prompt is required")
 	}
 
+	if err := adapters.ValidateNegativePrompt("Kling", vidgoRequest.NegativePrompt, kling.MaxNegativePromptLength); err != nil {
+		return err
+	}
+
+	if !adapters.ValidMode(vidgoRequest.Mode) {
+		return fmt.Errorf("mode must be std or pro, got %s", vidgoRequest.Mode)
+	}
+
+	if !adapters.ValidGuidanceScale(vidgoRequest.GuidanceScale) {
+		return fmt.Errorf("guidance_scale must be between 0 and 1, got %g", *vidgoRequest.GuidanceScale)
+	}
+
 	// Validate model if specified
 	if vidgoRequest.Model != "" {
 		validModels := k.GetModelList()
@@ -0,0 +1,100 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/adapters/kling"
+)
+
+func TestDeprecatedKlingAdaptorTypedResults(t *testing.T) {
+	server := newKlingStub(t)
+	server.reply = klingSucceeded
+
+	adaptor := kling.NewKlingAdaptor()
+	adaptor.Init(&kling.TaskRelayInfo{BaseUrl: server.URL, ApiKey: "test_access_key,test_secret_key"})
+
+	req := &kling.VidgoSubmitReq{Prompt: "A cat", Image: "https://example.com/cat.png", Duration: 5, Size: "1280x720"}
+	resp, err := adaptor.Submit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if resp.TaskID != "task-1" {
+		t.Errorf("Expected task-1, got %s", resp.TaskID)
+	}
+	if body := server.body(); server.path() != "/v1/videos/image2video" || body["image"] != "https://example.com/cat.png" {
+		t.Errorf("Expected an image2video submission, got %s %v", server.path(), body)
+	}
+
+	result, err := adaptor.Fetch(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if result.Status != adapters.TaskStatusSucceeded || result.URL != "https://cdn.example.com/v.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	// The HTTP-shaped methods fail unless the shim is opted into
+	if _, err := adaptor.DoRequest("", nil, []byte(`{"prompt":"A cat"}`)); err == nil {
+		t.Error("DoRequest without CompatHTTPResponse should return error")
+	}
+	if _, err := adaptor.FetchTask(server.URL, "", "task-1"); err == nil {
+		t.Error("FetchTask without CompatHTTPResponse should return error")
+	}
+
+	adaptor.CompatHTTPResponse = true
+	shim, err := adaptor.DoRequest("", nil, []byte(`{"prompt":"A cat","image":"https://example.com/cat.png","duration":5}`))
+	if err != nil {
+		t.Fatalf("DoRequest failed: %v", err)
+	}
+	taskID, _, taskErr := adaptor.DoResponse(shim)
+	if taskErr != nil || taskID != "task-1" {
+		t.Errorf("Expected task-1 from shim response, got %s (%v)", taskID, taskErr)
+	}
+	if resp, err := adaptor.FetchTask(server.URL, "", "task-1"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("FetchTask failed: %v", err)
+	}
+}
+
+func TestTaskAdaptorTypedWorkflow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST":
+			w.Write([]byte(`{"code":1102,"message":"resource pack exhausted"}`))
+		case strings.HasSuffix(r.URL.Path, "/task-1"):
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1","task_status":"succeed","task_result":{"videos":[{"url":"https://cdn.example.com/v.mp4"}]}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":1203,"message":"task not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	adaptor := NewTaskAdaptor()
+	info := &TaskRelayInfo{BaseUrl: server.URL, ApiKey: "ak,sk", Action: "generate"}
+
+	// Errors are always *TaskAdaptorError, never a typed nil
+	resp, err := adaptor.SubmitTask(context.Background(), info, []byte(`{"prompt":"A cat","duration":5}`))
+	var taskErr *TaskAdaptorError
+	if resp != nil || !errors.As(err, &taskErr) || taskErr.StatusCode != http.StatusPaymentRequired || taskErr.Code != "kling_error_1102" {
+		t.Errorf("Expected a 402 kling_error_1102, got %+v, %v", resp, err)
+	}
+
+	result, err := adaptor.FetchTaskResult(context.Background(), info, "task-1")
+	if err != nil {
+		t.Fatalf("FetchTaskResult failed: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "https://cdn.example.com/v.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	if _, err := adaptor.FetchTaskResult(context.Background(), &TaskRelayInfo{ApiKey: "malformed"}, "task-1"); !errors.As(err, &taskErr) || taskErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a 401 for an invalid API key, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package vidgo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKlingCameraControl(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	_, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:        "A cat walking",
+		Duration:      5,
+		Width:         1280,
+		Height:        720,
+		CameraControl: &CameraControl{Type: CameraControlSimple, Config: &CameraConfig{Zoom: -5}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	payload := server.body()
+	control, _ := payload["camera_control"].(map[string]interface{})
+	config, _ := control["config"].(map[string]interface{})
+	if control["type"] != "simple" || config["zoom"] != -5.0 || config["pan"] != 0.0 {
+		t.Errorf("Unexpected camera_control: %v", payload["camera_control"])
+	}
+
+	invalid := map[string]*CameraControl{
+		"missing config":   {Type: CameraControlSimple},
+		"two movements":    {Type: CameraControlSimple, Config: &CameraConfig{Pan: 1, Tilt: 1}},
+		"no movement":      {Type: CameraControlSimple, Config: &CameraConfig{}},
+		"out of range":     {Type: CameraControlSimple, Config: &CameraConfig{Roll: 11}},
+		"preset config":    {Type: CameraControlDownBack, Config: &CameraConfig{Zoom: 1}},
+		"unsupported type": {Type: "orbit"},
+	}
+	for name, control := range invalid {
+		err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, CameraControl: control})
+		if err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+
+	// Raw metadata maps are still validated
+	err = client.validateRequest(&GenerationRequest{
+		Prompt: "A cat", Duration: 5, Width: 1280, Height: 720,
+		Metadata: map[string]interface{}{"camera_control": map[string]interface{}{"type": "simple"}},
+	})
+	if err == nil {
+		t.Error("Expected metadata camera_control without config to be rejected")
+	}
+}
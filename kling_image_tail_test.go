@@ -0,0 +1,61 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func TestKlingImageTail(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	// An end frame alone is enough for image2video
+	tail := base64.StdEncoding.EncodeToString(testPNG(512, 512))
+	_, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		ImageTail: "data:image/png;base64," + tail,
+		Duration:  5,
+		Width:     1280,
+		Height:    720,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if path := server.path(); path != "/v1/videos/image2video" {
+		t.Errorf("Expected image2video endpoint, got %s", path)
+	}
+	if payload := server.body(); payload["image_tail"] != tail || payload["image"] != nil {
+		t.Errorf("Unexpected image fields: %v", payload)
+	}
+
+	// End frames cannot be combined with camera control
+	err = client.validateRequest(&GenerationRequest{
+		Image:         "https://example.com/start.png",
+		ImageTail:     "https://example.com/end.png",
+		Duration:      5,
+		Width:         1280,
+		Height:        720,
+		CameraControl: &CameraControl{Type: CameraControlForwardUp},
+	})
+	if err == nil {
+		t.Error("Expected image_tail with camera control to be rejected")
+	}
+
+	if err := client.validateRequest(&GenerationRequest{ImageTail: "data:image/png;base64,!!", Duration: 5, Width: 1280, Height: 720}); err == nil {
+		t.Error("Expected an invalid image_tail data URI to be rejected")
+	}
+
+	// The relay adaptor forwards image_tail
+	adaptor := NewKlingAdaptor()
+	adaptor.Init(&TaskRelayInfo{ApiKey: "test_access_key,test_secret_key"})
+	body, err := adaptor.BuildRequestBody(&VidgoSubmitReq{Prompt: "A cat", Image: "https://example.com/start.png", ImageTail: "https://example.com/end.png"})
+	if err != nil {
+		t.Fatalf("Failed to build request body: %v", err)
+	}
+	var relayPayload map[string]interface{}
+	json.Unmarshal(body, &relayPayload)
+	if relayPayload["image_tail"] != "https://example.com/end.png" {
+		t.Errorf("Expected image_tail in relay body, got %v", relayPayload["image_tail"])
+	}
+}
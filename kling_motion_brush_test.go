@@ -0,0 +1,62 @@
+package vidgo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKlingMotionBrush(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	_, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Image:    "https://example.com/cat.png",
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		MotionBrush: &MotionBrush{
+			StaticMask: "data:image/png;base64,c3RhdGlj",
+			DynamicMasks: []DynamicMask{{
+				Mask:         "https://example.com/mask.png",
+				Trajectories: []TrajectoryPoint{{X: 10, Y: 20}, {X: 30, Y: 40}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if path := server.path(); path != "/v1/videos/image2video" {
+		t.Errorf("Expected image2video endpoint, got %s", path)
+	}
+	payload := server.body()
+	if payload["static_mask"] != "c3RhdGlj" {
+		t.Errorf("Expected static_mask without data URI prefix, got %v", payload["static_mask"])
+	}
+	masks, _ := payload["dynamic_masks"].([]interface{})
+	if len(masks) != 1 {
+		t.Fatalf("Expected 1 dynamic mask, got %v", payload["dynamic_masks"])
+	}
+	mask := masks[0].(map[string]interface{})
+	points, _ := mask["trajectories"].([]interface{})
+	if mask["mask"] != "https://example.com/mask.png" || len(points) != 2 {
+		t.Errorf("Unexpected dynamic mask: %v", mask)
+	}
+
+	trajectory := func(n int) []TrajectoryPoint {
+		return make([]TrajectoryPoint, n)
+	}
+	invalid := map[string]*GenerationRequest{
+		"no image":        {Prompt: "A cat", MotionBrush: &MotionBrush{StaticMask: "https://example.com/mask.png"}},
+		"empty brush":     {Image: "https://example.com/cat.png", MotionBrush: &MotionBrush{}},
+		"one point":       {Image: "https://example.com/cat.png", MotionBrush: &MotionBrush{DynamicMasks: []DynamicMask{{Mask: "m", Trajectories: trajectory(1)}}}},
+		"too many points": {Image: "https://example.com/cat.png", MotionBrush: &MotionBrush{DynamicMasks: []DynamicMask{{Mask: "m", Trajectories: trajectory(78)}}}},
+		"empty mask":      {Image: "https://example.com/cat.png", MotionBrush: &MotionBrush{DynamicMasks: []DynamicMask{{Trajectories: trajectory(2)}}}},
+		"too many masks":  {Image: "https://example.com/cat.png", MotionBrush: &MotionBrush{DynamicMasks: make([]DynamicMask, 7)}},
+	}
+	for name, req := range invalid {
+		req.Duration, req.Width, req.Height = 5, 1280, 720
+		if err := client.validateRequest(req); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
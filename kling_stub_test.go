@@ -0,0 +1,131 @@
+package vidgo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+// klingStub is a Kling API that accepts every submission as task-1, reports
+// every task as processing and records the requests it receives
+type klingStub struct {
+	*httptest.Server
+
+	// reply, when set, answers a request instead of the defaults unless it
+	// returns ""; body is nil for anything but submissions
+	reply func(r *http.Request, body map[string]interface{}) string
+
+	mu       sync.Mutex
+	requests []string                 // "METHOD /path" of every request
+	bodies   []map[string]interface{} // Submission bodies not yet taken
+}
+
+// newKlingStub starts a klingStub that is closed when the test ends
+func newKlingStub(t *testing.T) *klingStub {
+	s := &klingStub{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&body)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			s.bodies = append(s.bodies, body)
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if s.reply != nil {
+			if response := s.reply(r, body); response != "" {
+				w.Write([]byte(response))
+				return
+			}
+		}
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1","task_status":"processing"}}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// klingSucceeded is a klingStub reply that reports every polled task as succeeded
+func klingSucceeded(r *http.Request, body map[string]interface{}) string {
+	if r.Method == http.MethodPost {
+		return ""
+	}
+	return `{"code":0,"message":"ok","data":{"task_id":"task-1","task_status":"succeed","task_result":{"videos":[{"url":"https://cdn.example.com/v.mp4"}]}}}`
+}
+
+// client creates a Kling client for the stub
+func (s *klingStub) client(t *testing.T, config ...*ClientConfig) *Client {
+	t.Helper()
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: s.URL, APIKey: "test_access_key,test_secret_key", Timeout: 5 * time.Second}, config...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+// body takes the body of the latest submission, or nil if nothing was
+// submitted since bodies were last taken
+func (s *klingStub) body() map[string]interface{} {
+	bodies := s.submissions()
+	if len(bodies) == 0 {
+		return nil
+	}
+	return bodies[len(bodies)-1]
+}
+
+// submissions takes the bodies of all submissions since bodies were last taken
+func (s *klingStub) submissions() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bodies := s.bodies
+	s.bodies = nil
+	return bodies
+}
+
+// requestLog returns "METHOD /path" of every request so far
+func (s *klingStub) requestLog() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// path returns the path of the latest request
+func (s *klingStub) path() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return ""
+	}
+	_, path, _ := strings.Cut(s.requests[len(s.requests)-1], " ")
+	return path
+}
+
+// newFakeKlingClient serves fake until the test ends and returns a client for
+// it with the "ak,sk" credentials
+func newFakeKlingClient(t *testing.T, fake *fakekling.Server, config ...*ClientConfig) *Client {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, config...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
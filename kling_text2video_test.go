@@ -0,0 +1,87 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestKlingText2Video(t *testing.T) {
+	var paths []string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/videos/text2video":
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+		case "/v1/videos/text2video/task-1":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"processing"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":1203,"message":"task not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	newClient := func() *Client {
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key", Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	client := newClient()
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat walking",
+		Duration: 5,
+		Width:    720,
+		Height:   1280,
+		Metadata: map[string]interface{}{
+			"camera_control": map[string]interface{}{"type": "forward_up"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if payload["aspect_ratio"] != "9:16" || payload["image"] != nil {
+		t.Errorf("Unexpected text2video payload: %v", payload)
+	}
+	if control, _ := payload["camera_control"].(map[string]interface{}); control["type"] != "forward_up" {
+		t.Errorf("Expected camera_control to be sent, got %v", payload["camera_control"])
+	}
+
+	if _, err := client.GetGeneration(context.Background(), resp.TaskID); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	// A client that did not submit the task falls back to the text2video status path
+	result, err := newClient().GetGeneration(context.Background(), resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusProcessing {
+		t.Errorf("Expected processing, got %s", result.Status)
+	}
+
+	expected := []string{
+		"POST /v1/videos/text2video",
+		"GET /v1/videos/text2video/task-1",
+		"GET /v1/videos/image2video/task-1",
+		"GET /v1/videos/text2video/task-1",
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected requests %v, got %v", expected, paths)
+			break
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// LipSyncRequest lip-syncs a video to speech, given as text for a voice or as audio
+type LipSyncRequest = adapters.LipSyncRequest
+
+// CreateLipSync lip-syncs a video to text or audio. The source is a succeeded
+// task, a provider video ID or a video URL. The returned task is polled with
+// GetGeneration or WaitForCompletion like any other.
+func (c *Client) CreateLipSync(ctx context.Context, req *LipSyncRequest, opts ...CallOption) (*GenerationResponse, error) {
+	if req == nil {
+		return nil, &ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+	if req.TaskID == "" && req.VideoID == "" && req.VideoURL == "" {
+		return nil, &ValidationError{Field: "video", Message: "a task ID, video ID or video URL must be provided"}
+	}
+	if req.Text == "" && req.Audio == "" {
+		return nil, &ValidationError{Field: "text/audio", Message: "text or audio must be provided"}
+	}
+
+	syncer, ok := c.current().(LipSyncer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support lip-sync", ErrUnsupportedOperation, c.current().Name())
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *GenerationResponse
+	err = c.withRetry(c.captureContext(ctx, "create_lip_sync", ""), o, func(ctx context.Context) error {
+		var err error
+		resp, err = syncer.CreateLipSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.metrics.dispatched(resp.TaskID, "")
+	c.recordTimeline(resp.TaskID, TimelineEvent{Type: TimelineEventSubmitted, Status: resp.Status})
+	return resp, nil
+}
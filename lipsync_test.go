@@ -0,0 +1,79 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestCreateLipSync(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A person talking", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if _, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond); err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+
+	synced, err := client.CreateLipSync(ctx, &LipSyncRequest{TaskID: resp.TaskID, Text: "你好", VoiceID: "genshin_vindi2"})
+	if err != nil {
+		t.Fatalf("Failed to create lip-sync: %v", err)
+	}
+	result, err := client.WaitForCompletion(ctx, synced.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for lip-sync: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL == "" {
+		t.Errorf("Expected a succeeded lip-sync video, got %+v", result)
+	}
+
+	if _, err := client.CreateLipSync(ctx, &LipSyncRequest{VideoURL: "https://example.com/talk.mp4", Audio: "https://example.com/speech.mp3"}); err != nil {
+		t.Errorf("Failed to create audio lip-sync: %v", err)
+	}
+
+	invalid := map[string]*LipSyncRequest{
+		"no video":       {Text: "hi", VoiceID: "v"},
+		"two videos":     {VideoID: "v", VideoURL: "https://example.com/v.mp4", Text: "hi", VoiceID: "v"},
+		"text and audio": {VideoID: "v", Text: "hi", VoiceID: "v", Audio: "https://example.com/a.mp3"},
+		"no voice":       {VideoID: "v", Text: "hi"},
+		"long text":      {VideoID: "v", Text: strings.Repeat("字", 121), VoiceID: "v"},
+		"slow voice":     {VideoID: "v", Text: "hi", VoiceID: "v", VoiceSpeed: 0.5},
+	}
+	for name, req := range invalid {
+		if _, err := client.CreateLipSync(ctx, req); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+
+	luma, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "luma-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := luma.CreateLipSync(ctx, &LipSyncRequest{VideoID: "v", Text: "hi", VoiceID: "v"}); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation, got %v", err)
+	}
+}
+
+func TestKlingLipSyncAudioFile(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+	if _, err := client.CreateLipSync(context.Background(), &LipSyncRequest{VideoID: "video-1", Audio: "data:audio/mpeg;base64,YXVkaW8="}); err != nil {
+		t.Fatalf("Failed to create lip-sync: %v", err)
+	}
+
+	if path := server.path(); path != "/v1/videos/lip-sync" {
+		t.Errorf("Expected lip-sync endpoint, got %s", path)
+	}
+	input, _ := server.body()["input"].(map[string]interface{})
+	if input["mode"] != "audio2video" || input["audio_type"] != "file" || input["audio_file"] != "YXVkaW8=" || input["video_id"] != "video-1" {
+		t.Errorf("Unexpected lip-sync input: %v", input)
+	}
+}
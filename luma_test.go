@@ -0,0 +1,85 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLumaProvider(t *testing.T) {
+	var submitted lumaPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer luma_key" {
+			t.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/dream-machine/v1/generations":
+			json.NewDecoder(r.Body).Decode(&submitted)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"gen-1","state":"queued"}`))
+		case r.Method == "GET" && r.URL.Path == "/dream-machine/v1/generations/gen-1":
+			w.Write([]byte(`{"id":"gen-1","state":"completed","assets":{"video":"https://cdn.example.com/luma.mp4","image":"https://cdn.example.com/luma.jpg"}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderLuma, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "luma_key",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat",
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		Image:    "https://example.com/start.jpg",
+		Metadata: map[string]interface{}{"image_tail": "https://example.com/end.jpg"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if submitted.Model != "ray-2" || submitted.Duration != "5s" || submitted.AspectRatio != "16:9" {
+		t.Errorf("Unexpected submitted payload: %+v", submitted)
+	}
+	if submitted.Keyframes.Frame0.URL != "https://example.com/start.jpg" || submitted.Keyframes.Frame1.URL != "https://example.com/end.jpg" {
+		t.Errorf("Unexpected keyframes: %+v", submitted.Keyframes)
+	}
+
+	result, err := client.GetGeneration(context.Background(), resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "https://cdn.example.com/luma.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(result.Assets) != 2 || result.Assets[1].Kind != AssetThumbnail || result.Assets[1].URL != "https://cdn.example.com/luma.jpg" {
+		t.Errorf("Expected the Luma image as a thumbnail asset, got %+v", result.Assets)
+	}
+
+	if err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 10, Width: 512, Height: 512}); err == nil {
+		t.Error("Unsupported Luma duration should return error")
+	}
+}
+
+// lumaPayload mirrors the keyframe fields of the Luma request for assertions
+type lumaPayload struct {
+	Model       string `json:"model"`
+	Duration    string `json:"duration"`
+	AspectRatio string `json:"aspect_ratio"`
+	Keyframes   struct {
+		Frame0 struct{ URL string } `json:"frame0"`
+		Frame1 struct{ URL string } `json:"frame1"`
+	} `json:"keyframes"`
+}
@@ -0,0 +1,41 @@
+package vidgo
+
+import "testing"
+
+func TestCapabilityMatrix(t *testing.T) {
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	matrix := client.Capabilities().Matrix()
+	if len(matrix) == 0 {
+		t.Fatal("Expected Kling to publish its combinations")
+	}
+	for _, c := range matrix.Filter(Combination{Model: "kling-v1", Mode: ModePro}) {
+		if c.Duration != 5 {
+			t.Errorf("Expected kling-v1 pro to allow only 5s, got %+v", c)
+		}
+	}
+	if !matrix.Allows(Combination{Model: "kling-v1-6", Mode: ModePro, Duration: 10, AspectRatio: "9:16"}) {
+		t.Error("Expected kling-v1-6 to allow 10s pro")
+	}
+
+	if err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Model: "kling-v1", Mode: ModePro, Duration: 10, Width: 1280, Height: 720}); err == nil {
+		t.Error("Expected kling-v1 10s pro to be rejected")
+	}
+	if err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Model: "kling-v1", Duration: 10, Width: 1280, Height: 720}); err != nil {
+		t.Errorf("Expected kling-v1 10s std to be valid, got %v", err)
+	}
+
+	wanx, err := NewClient(ProviderWanx, &ProviderConfig{APIKey: "sk-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if i2v := wanx.Capabilities().Matrix().Filter(Combination{Model: "wanx2.1-i2v-plus"}); len(i2v) != 1 || i2v[0].AspectRatio != "" {
+		t.Errorf("Expected one Wanx i2v combination following the image, got %+v", i2v)
+	}
+	if matrix := (*Capabilities)(nil).Matrix(); !matrix.Allows(Combination{Model: "any"}) {
+		t.Error("Expected an unknown matrix to allow everything")
+	}
+}
@@ -0,0 +1,64 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestGenerationMode(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	tests := []struct {
+		name     string
+		req      GenerationRequest
+		expected string
+	}{
+		{"default", GenerationRequest{}, "std"},
+		{"mode", GenerationRequest{Mode: ModePro}, "pro"},
+		{"quality level", GenerationRequest{QualityLevel: QualityLevelHigh}, "pro"},
+		{"mode over quality level", GenerationRequest{Mode: ModeStandard, QualityLevel: QualityLevelHigh}, "std"},
+		{"deprecated metadata", GenerationRequest{Metadata: map[string]interface{}{"mode": "pro"}}, "pro"},
+		{"mode over metadata", GenerationRequest{Mode: ModeStandard, Metadata: map[string]interface{}{"mode": "pro"}}, "std"},
+	}
+
+	for _, tt := range tests {
+		req := tt.req
+		req.Prompt, req.Duration, req.Width, req.Height = "A cat walking", 5, 1280, 720
+		if _, err := client.CreateGeneration(context.Background(), &req); err != nil {
+			t.Fatalf("%s: failed to create generation: %v", tt.name, err)
+		}
+		if body := server.body(); body["mode"] != tt.expected {
+			t.Errorf("%s: expected mode %s, got %v", tt.name, tt.expected, body["mode"])
+		}
+	}
+
+	_, err := client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Mode: "ultra"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "mode" {
+		t.Errorf("Expected mode validation error, got %v", err)
+	}
+	if body := server.body(); body != nil {
+		t.Errorf("Expected an invalid mode not to be submitted, got %v", body)
+	}
+}
+
+func TestKlingAdaptorMode(t *testing.T) {
+	adaptor := NewKlingAdaptor()
+
+	data, err := adaptor.BuildRequestBody(&VidgoSubmitReq{Prompt: "A cat", Duration: 5, Mode: "pro"})
+	if err != nil {
+		t.Fatalf("Failed to build request body: %v", err)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(data, &body)
+	if body["mode"] != "pro" {
+		t.Errorf("Expected top-level mode pro, got %v", body["mode"])
+	}
+
+	if err := adaptor.actionValidate(&VidgoSubmitReq{Prompt: "A cat", Mode: "ultra"}, "generate"); err == nil {
+		t.Error("Expected an invalid mode to be rejected")
+	}
+}
@@ -0,0 +1,30 @@
+package vidgo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/feitianbubu/vidgo/models"
+)
+
+// ModelInfo describes the durations, resolutions, aspect ratios, modes and
+// features of a provider model, see package models
+type ModelInfo = models.Info
+
+// GetModelInfo returns the catalog entry of a model of the current provider;
+// an empty model returns the model used when a request names none. Providers
+// without a fixed catalog, such as Replicate and ComfyUI, fail with
+// ErrUnsupportedOperation.
+func (c *Client) GetModelInfo(model string) (*ModelInfo, error) {
+	name := c.current().Name()
+	catalog := models.List(strings.ToLower(name))
+	if catalog == nil {
+		return nil, fmt.Errorf("%w: %s has no model catalog", ErrUnsupportedOperation, name)
+	}
+
+	info, ok := catalog.Lookup(model)
+	if !ok {
+		return nil, &ValidationError{Field: "model", Message: fmt.Sprintf("%s does not offer model %s", name, model)}
+	}
+	return info, nil
+}
@@ -0,0 +1,73 @@
+package vidgo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/feitianbubu/vidgo/models"
+)
+
+func TestGetModelInfo(t *testing.T) {
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	info, err := client.GetModelInfo("kling-v1")
+	if err != nil {
+		t.Fatalf("Failed to get model info: %v", err)
+	}
+	if !reflect.DeepEqual(info.DurationsFor(ModePro), []float64{5}) || !info.SupportsDuration(ModeStandard, 10) {
+		t.Errorf("Expected kling-v1 to render 10s in std mode only, got %+v", info)
+	}
+	if !info.Supports(models.FeatureCameraControl) || info.Supports(models.FeatureElements) {
+		t.Errorf("Expected kling-v1 camera control without elements, got %v", info.Features)
+	}
+
+	// Callers cannot change the catalog through the returned info
+	info.Durations[0] = 7
+	if again, _ := client.GetModelInfo("kling-v1"); again.Durations[0] != 5 {
+		t.Errorf("Expected the catalog to be unchanged, got %v", again.Durations)
+	}
+
+	if info, err := client.GetModelInfo(""); err != nil || info.Name != "kling-v2-master" {
+		t.Errorf("Expected the default model kling-v2-master, got %+v (%v)", info, err)
+	}
+	var validationErr *ValidationError
+	if _, err := client.GetModelInfo("kling-v9"); !errors.As(err, &validationErr) || validationErr.Field != "model" {
+		t.Errorf("Expected a model validation error, got %v", err)
+	}
+
+	// The catalog drives the supported models and validation of each adapter
+	for _, provider := range []ProviderType{ProviderKling, ProviderJimeng, ProviderLuma, ProviderVeo, ProviderWanx} {
+		catalog := models.List(string(provider))
+		c, err := NewClient(provider, &ProviderConfig{APIKey: "ak,sk", SecretKey: "sk", Extra: map[string]string{"project_id": "p"}, AllowUnknownExtra: true})
+		if err != nil {
+			t.Fatalf("Failed to create %s client: %v", provider, err)
+		}
+		if !reflect.DeepEqual(c.GetSupportedModels(), catalog.Names()) {
+			t.Errorf("Expected %s models %v, got %v", provider, catalog.Names(), c.GetSupportedModels())
+		}
+		if _, ok := catalog.Lookup(""); !ok {
+			t.Errorf("Expected %s to have a default model", provider)
+		}
+	}
+
+	replicate, err := NewClient(ProviderReplicate, &ProviderConfig{APIKey: "r8_test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := replicate.GetModelInfo("genmoai/mochi-1"); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation for Replicate, got %v", err)
+	}
+}
+
+func TestFormatDurations(t *testing.T) {
+	tests := map[string][]float64{"5s": {5}, "5s or 10s": {5, 10}, "5s, 6s, 7s or 8s": {5, 6, 7, 8}}
+	for expected, durations := range tests {
+		if got := models.FormatDurations(durations); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package models
+
+// Modes of providers that offer a quality/speed trade-off, see adapters.ModeStandard
+const (
+	modeStandard = "std"
+	modePro      = "pro"
+)
+
+var (
+	klingAspectRatios = []string{"16:9", "9:16", "1:1"}
+	klingFeatures     = []Feature{FeatureText2Video, FeatureImage2Video, FeatureEndFrame, FeatureCameraControl, FeatureMotionBrush, FeatureExtension}
+
+	jimengAspectRatios = []string{"21:9", "16:9", "4:3", "1:1", "3:4", "9:16"}
+	lumaAspectRatios   = []string{"1:1", "16:9", "9:16", "4:3", "3:4", "21:9", "9:21"}
+	veoAspectRatios    = []string{"16:9", "9:16"}
+	wanxAspectRatios   = []string{"16:9", "9:16", "1:1", "4:3", "3:4"}
+)
+
+// catalog lists the models of each provider by registry name, in the order
+// the provider reports them
+var catalog = map[string][]Info{
+	"kling": {
+		{
+			Provider: "kling", Name: "kling-v1",
+			Durations: []float64{5, 10}, Resolutions: []string{"720p", "1080p"}, AspectRatios: klingAspectRatios,
+			Modes: []string{modeStandard, modePro}, Features: klingFeatures,
+			ModeDurations: map[string][]float64{modePro: {5}},
+		},
+		{
+			Provider: "kling", Name: "kling-v1-6",
+			Durations: []float64{5, 10}, Resolutions: []string{"720p", "1080p"}, AspectRatios: klingAspectRatios,
+			Modes: []string{modeStandard, modePro}, Features: append([]Feature{FeatureElements}, klingFeatures...),
+		},
+		{
+			Provider: "kling", Name: "kling-v2-master", Default: true,
+			Durations: []float64{5, 10}, Resolutions: []string{"720p", "1080p"}, AspectRatios: klingAspectRatios,
+			Modes: []string{modeStandard, modePro}, Features: klingFeatures,
+		},
+	},
+	"jimeng": {
+		{
+			Provider: "jimeng", Name: "jimeng-v1", Default: true,
+			Durations: []float64{5, 10}, Resolutions: []string{"720p"}, AspectRatios: jimengAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "jimeng", Name: "jimeng-v2",
+			Durations: []float64{5, 10}, Resolutions: []string{"720p", "1080p"}, AspectRatios: jimengAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "jimeng", Name: "seedance-1.0-lite",
+			Durations: []float64{5, 10}, Resolutions: []string{"720p", "1080p"}, AspectRatios: jimengAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "jimeng", Name: "seedance-1.0-pro",
+			Durations: []float64{5, 10}, Resolutions: []string{"480p", "720p", "1080p"}, AspectRatios: jimengAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+	},
+	"luma": {
+		{
+			Provider: "luma", Name: "ray-2", Default: true,
+			Durations: []float64{5, 9}, Resolutions: []string{"540p", "720p", "1080p"}, AspectRatios: lumaAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video, FeatureEndFrame},
+		},
+		{
+			Provider: "luma", Name: "ray-flash-2",
+			Durations: []float64{5, 9}, Resolutions: []string{"540p", "720p", "1080p"}, AspectRatios: lumaAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video, FeatureEndFrame},
+		},
+		{
+			Provider: "luma", Name: "ray-1-6",
+			Durations: []float64{5, 9}, Resolutions: []string{"540p", "720p", "1080p"}, AspectRatios: lumaAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video, FeatureEndFrame},
+		},
+	},
+	"veo": {
+		{
+			Provider: "veo", Name: "veo-2.0-generate-001", Default: true,
+			Durations: []float64{5, 6, 7, 8}, Resolutions: []string{"720p"}, AspectRatios: veoAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "veo", Name: "veo-3.0-generate-001",
+			Durations: []float64{8}, Resolutions: []string{"720p"}, AspectRatios: veoAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "veo", Name: "veo-3.0-fast-generate-001",
+			Durations: []float64{8}, Resolutions: []string{"720p"}, AspectRatios: veoAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+	},
+	"wanx": {
+		// Text-to-video models switch to their image-to-video counterpart when given an image
+		{
+			Provider: "wanx", Name: "wanx2.1-t2v-turbo", Default: true,
+			Durations: []float64{5}, Resolutions: []string{"480p", "720p"}, AspectRatios: wanxAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "wanx", Name: "wanx2.1-t2v-plus",
+			Durations: []float64{5}, Resolutions: []string{"720p"}, AspectRatios: wanxAspectRatios,
+			Features: []Feature{FeatureText2Video, FeatureImage2Video},
+		},
+		{
+			Provider: "wanx", Name: "wanx2.1-i2v-turbo",
+			Durations: []float64{5}, Resolutions: []string{"480p", "720p"},
+			Features: []Feature{FeatureImage2Video},
+		},
+		{
+			Provider: "wanx", Name: "wanx2.1-i2v-plus",
+			Durations: []float64{5}, Resolutions: []string{"720p"},
+			Features: []Feature{FeatureImage2Video},
+		},
+	},
+}
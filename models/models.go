@@ -0,0 +1,176 @@
+// Package models is the catalog of provider models: the durations, resolutions,
+// aspect ratios, modes and features each one supports. Adapters validate
+// requests against it and vidgo.Client.GetModelInfo exposes it.
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Feature is an input or operation a model supports
+type Feature string
+
+const (
+	FeatureText2Video    Feature = "text2video"
+	FeatureImage2Video   Feature = "image2video"
+	FeatureEndFrame      Feature = "end_frame" // ImageTail or a last keyframe
+	FeatureCameraControl Feature = "camera_control"
+	FeatureMotionBrush   Feature = "motion_brush"
+	FeatureElements      Feature = "elements" // Multi-image-to-video
+	FeatureExtension     Feature = "extension"
+)
+
+// Info describes one provider model
+type Info struct {
+	Provider     string    `json:"provider"` // Registry name, e.g. "kling"
+	Name         string    `json:"name"`
+	Default      bool      `json:"default,omitempty"` // Submitted when a request names no model
+	Durations    []float64 `json:"durations"`         // Clip lengths in seconds
+	Resolutions  []string  `json:"resolutions,omitempty"`
+	AspectRatios []string  `json:"aspect_ratios,omitempty"` // Empty when the video follows the input image
+	Modes        []string  `json:"modes,omitempty"`         // "std" and "pro", empty for providers without modes
+	Features     []Feature `json:"features"`
+
+	// ModeDurations narrows Durations for a mode, e.g. kling-v1 renders pro
+	// videos of 5s only
+	ModeDurations map[string][]float64 `json:"mode_durations,omitempty"`
+}
+
+// Supports reports whether the model supports feature
+func (m *Info) Supports(feature Feature) bool {
+	for _, f := range m.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsMode reports whether the model accepts mode; models without modes accept only ""
+func (m *Info) SupportsMode(mode string) bool {
+	if len(m.Modes) == 0 {
+		return mode == ""
+	}
+	for _, supported := range m.Modes {
+		if supported == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// DurationsFor returns the clip lengths the model renders in mode
+func (m *Info) DurationsFor(mode string) []float64 {
+	if durations, ok := m.ModeDurations[mode]; ok {
+		return durations
+	}
+	return m.Durations
+}
+
+// SupportsDuration reports whether the model renders clips of duration seconds in mode
+func (m *Info) SupportsDuration(mode string, duration float64) bool {
+	for _, d := range m.DurationsFor(mode) {
+		if d == duration {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsResolution reports whether the model renders resolution, e.g. "720p"
+func (m *Info) SupportsResolution(resolution string) bool {
+	for _, r := range m.Resolutions {
+		if strings.EqualFold(r, resolution) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone copies the model so callers cannot change the catalog
+func (m Info) clone() Info {
+	m.Durations = append([]float64(nil), m.Durations...)
+	m.Resolutions = append([]string(nil), m.Resolutions...)
+	m.AspectRatios = append([]string(nil), m.AspectRatios...)
+	m.Modes = append([]string(nil), m.Modes...)
+	m.Features = append([]Feature(nil), m.Features...)
+	if m.ModeDurations != nil {
+		modeDurations := make(map[string][]float64, len(m.ModeDurations))
+		for mode, durations := range m.ModeDurations {
+			modeDurations[mode] = append([]float64(nil), durations...)
+		}
+		m.ModeDurations = modeDurations
+	}
+	return m
+}
+
+// Catalog is the list of models of one provider
+type Catalog []Info
+
+// List returns the models of a provider by registry name, or nil for providers
+// without a fixed catalog such as Replicate and ComfyUI
+func List(provider string) Catalog {
+	models := catalog[strings.ToLower(provider)]
+	if models == nil {
+		return nil
+	}
+	list := make(Catalog, len(models))
+	for i, m := range models {
+		list[i] = m.clone()
+	}
+	return list
+}
+
+// Lookup returns a model of a provider; an empty name returns the default model
+func Lookup(provider, name string) (*Info, bool) {
+	return List(provider).Lookup(name)
+}
+
+// Lookup returns the named model; an empty name returns the default model
+func (c Catalog) Lookup(name string) (*Info, bool) {
+	for i := range c {
+		if c[i].Name == name || (name == "" && c[i].Default) {
+			return &c[i], true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the model names in catalog order
+func (c Catalog) Names() []string {
+	names := make([]string, len(c))
+	for i, m := range c {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Durations returns every clip length any model renders, in ascending order
+func (c Catalog) Durations() []float64 {
+	seen := make(map[float64]bool)
+	var durations []float64
+	for _, m := range c {
+		for _, d := range m.Durations {
+			if !seen[d] {
+				seen[d] = true
+				durations = append(durations, d)
+			}
+		}
+	}
+	sort.Float64s(durations)
+	return durations
+}
+
+// FormatDurations lists durations for error messages, e.g. "5s or 10s"
+func FormatDurations(durations []float64) string {
+	parts := make([]string, len(durations))
+	for i, d := range durations {
+		parts[i] = fmt.Sprintf("%gs", d)
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+}
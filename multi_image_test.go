@@ -0,0 +1,95 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/adapters/vidu"
+)
+
+func TestKlingMultiImageRequest(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t)
+
+	encoded := base64.StdEncoding.EncodeToString(testPNG(512, 512))
+	req := &GenerationRequest{
+		Prompt:   "Two cats playing",
+		Images:   []string{"https://example.com/a.png", "data:image/png;base64," + encoded},
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		Model:    "kling-v1-6",
+	}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if path := server.path(); path != "/v1/videos/multi-image2video" {
+		t.Errorf("Expected multi-image2video, got %s", path)
+	}
+	payload := server.body()
+	images, _ := payload["image_list"].([]interface{})
+	if len(images) != 2 || images[0].(map[string]interface{})["image"] != "https://example.com/a.png" ||
+		images[1].(map[string]interface{})["image"] != encoded || payload["model_name"] != "kling-v1-6" {
+		t.Errorf("Unexpected multi-image payload: %v", payload)
+	}
+
+	invalid := map[string]*GenerationRequest{
+		"too many images": {Prompt: "p", Images: []string{"a", "b", "c", "d", "e"}, Duration: 5, Width: 1280, Height: 720, Model: "kling-v1-6"},
+		"empty image":     {Prompt: "p", Images: []string{"https://example.com/a.png", ""}, Duration: 5, Width: 1280, Height: 720},
+		"with image":      {Prompt: "p", Image: "https://example.com/a.png", Images: []string{"https://example.com/b.png"}, Duration: 5, Width: 1280, Height: 720},
+	}
+	for name, req := range invalid {
+		if _, err := client.CreateGeneration(context.Background(), req); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+	if body := server.body(); body != nil {
+		t.Errorf("Expected rejected requests not to be submitted, got %v", body)
+	}
+}
+
+func TestMultiImageProviderLimits(t *testing.T) {
+	tests := []struct {
+		provider ProviderType
+		config   *ProviderConfig
+		images   int
+		valid    bool
+	}{
+		{ProviderKling, &ProviderConfig{APIKey: "ak,sk"}, 4, true},
+		{ProviderLuma, &ProviderConfig{APIKey: "luma"}, 2, false},
+	}
+	for _, tt := range tests {
+		client, err := NewClient(tt.provider, tt.config)
+		if err != nil {
+			t.Fatalf("Failed to create %s client: %v", tt.provider, err)
+		}
+		req := &GenerationRequest{Prompt: "p", Duration: 5, Width: 1280, Height: 720, Model: "kling-v1-6"}
+		for i := 0; i < tt.images; i++ {
+			req.Images = append(req.Images, "https://example.com/image.png")
+		}
+		if tt.provider != ProviderKling {
+			req.Model = ""
+		}
+		err = client.validateRequest(req)
+		if tt.valid && err != nil {
+			t.Errorf("%s with %d images: expected valid, got %v", tt.provider, tt.images, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s with %d images: expected an error", tt.provider, tt.images)
+		}
+	}
+
+	// Vidu is not wired into NewClient yet, so check its limit directly
+	provider, err := vidu.New(&adapters.ProviderConfig{APIKey: "vidu"})
+	if err != nil {
+		t.Fatalf("Failed to create Vidu provider: %v", err)
+	}
+	if err := provider.ValidateRequest(&adapters.GenerationRequest{Images: []string{"a", "b", "c"}}); err != nil {
+		t.Errorf("Expected 3 Vidu images to be valid, got %v", err)
+	}
+	if err := provider.ValidateRequest(&adapters.GenerationRequest{Images: []string{"a", "b", "c", "d"}}); err == nil {
+		t.Errorf("Expected 4 Vidu images to be rejected")
+	}
+}
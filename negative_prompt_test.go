@@ -0,0 +1,74 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNegativePrompt(t *testing.T) {
+	server := newKlingStub(t)
+	kling := server.client(t)
+
+	_, err := kling.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:         "A cat walking",
+		NegativePrompt: "blurry, watermark",
+		Duration:       5,
+		Width:          1280,
+		Height:         720,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if payload := server.body(); payload["negative_prompt"] != "blurry, watermark" {
+		t.Errorf("Expected negative_prompt to be sent, got %v", payload)
+	}
+
+	// Limits are per provider and count characters, not bytes
+	wanx, err := NewClient(ProviderWanx, &ProviderConfig{BaseURL: server.URL, APIKey: "sk-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	luma, err := NewClient(ProviderLuma, &ProviderConfig{BaseURL: server.URL, APIKey: "luma-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		client   *Client
+		negative string
+		valid    bool
+	}{
+		{"kling at limit", kling, strings.Repeat("模", 2500), true},
+		{"kling over limit", kling, strings.Repeat("a", 2501), false},
+		{"wanx at limit", wanx, strings.Repeat("a", 500), true},
+		{"wanx over limit", wanx, strings.Repeat("a", 501), false},
+		{"luma unsupported", luma, "blurry", false},
+	}
+	for _, tt := range tests {
+		err := tt.client.validateRequest(&GenerationRequest{Prompt: "A cat", NegativePrompt: tt.negative, Duration: 5, Width: 1280, Height: 720})
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.name, tt.valid, err)
+		}
+	}
+
+	// The relay adaptor forwards and validates negative_prompt
+	adaptor := NewKlingAdaptor()
+	adaptor.Init(&TaskRelayInfo{ApiKey: "test_access_key,test_secret_key"})
+	body, err := adaptor.BuildRequestBody(&VidgoSubmitReq{Prompt: "A cat", NegativePrompt: "blurry"})
+	if err != nil {
+		t.Fatalf("Failed to build request body: %v", err)
+	}
+	var relayPayload map[string]interface{}
+	json.Unmarshal(body, &relayPayload)
+	if relayPayload["negative_prompt"] != "blurry" {
+		t.Errorf("Expected negative_prompt in relay body, got %v", relayPayload["negative_prompt"])
+	}
+
+	request, _ := json.Marshal(&VidgoSubmitReq{Prompt: "A cat", NegativePrompt: strings.Repeat("a", 2501)})
+	if _, taskErr := adaptor.ValidateRequestAndSetAction(request, "generate"); taskErr == nil {
+		t.Error("Expected an overlong negative_prompt to be rejected")
+	}
+}
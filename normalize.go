@@ -0,0 +1,313 @@
+package vidgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// maxNormalizeAttempts bounds how often an image is shrunk to fit MaxBytes
+const maxNormalizeAttempts = 8
+
+// maxNormalizePixels bounds the images NormalizeImage decodes; a small file can
+// declare huge dimensions and decoding allocates 4 bytes per pixel
+const maxNormalizePixels = 8192 * 8192
+
+// jpegQualities are tried in order to fit MaxBytes before an image is shrunk
+var jpegQualities = []int{90, 75, 60}
+
+// normalizeImage applies NormalizeImage to the locally available request images.
+// It returns a copy carrying the normalized main image in ImageBytes and the
+// others as data URIs.
+func (c *Client) normalizeImage(req *GenerationRequest) (*GenerationRequest, error) {
+	if !c.config.NormalizeImages || req == nil || validateImage(req) != nil {
+		return req, nil
+	}
+
+	images := localImages(req)
+	if len(images) == 0 {
+		return req, nil
+	}
+
+	var constraints *ImageConstraints
+	if caps := c.Capabilities(); caps != nil {
+		constraints = caps.Image
+	}
+
+	prepared := *req
+	prepared.Images = append([]string(nil), req.Images...)
+	prepared.Keyframes = append([]Keyframe(nil), req.Keyframes...)
+	for _, image := range images {
+		normalized, contentType, err := NormalizeImage(image.data, constraints)
+		if err != nil {
+			return nil, &ValidationError{Field: image.field, Message: err.Error()}
+		}
+		image.replace(&prepared, normalized, contentType)
+	}
+	return &prepared, nil
+}
+
+// NormalizeImage decodes an image, applies its EXIF orientation, downscales it to fit
+// the constraints and re-encodes it in an accepted format. Re-encoding drops all metadata.
+// Images over MaxBytes are recompressed at lower JPEG quality, then shrunk, but never
+// below MinWidth x MinHeight. It returns the encoded image and its content type.
+func NormalizeImage(data []byte, constraints *ImageConstraints) ([]byte, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to decode image: %w", err)
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > maxNormalizePixels {
+		return nil, "", fmt.Errorf("image is %dx%d, maximum is %d pixels", config.Width, config.Height, maxNormalizePixels)
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to decode image: %w", err)
+	}
+
+	img := toRGBA(src)
+	if format == "jpeg" {
+		img = applyOrientation(img, exifOrientation(data))
+	}
+
+	format = normalizedFormat(format, constraints)
+
+	if constraints != nil {
+		img = fitWithin(img, constraints.MaxWidth, constraints.MaxHeight)
+	}
+
+	qualities := jpegQualities
+	if format == "png" {
+		qualities = jpegQualities[:1]
+	}
+	for attempt := 0; ; attempt++ {
+		var encoded []byte
+		for _, quality := range qualities {
+			if encoded, err = encodeImage(img, format, quality); err != nil {
+				return nil, "", err
+			}
+			if constraints == nil || constraints.MaxBytes <= 0 || int64(len(encoded)) <= constraints.MaxBytes {
+				return encoded, "image/" + format, nil
+			}
+		}
+
+		bounds := img.Bounds()
+		width, height := bounds.Dx()*4/5, bounds.Dy()*4/5
+		if attempt == maxNormalizeAttempts || width < constraints.MinWidth || height < constraints.MinHeight {
+			return nil, "", fmt.Errorf("image is still %d bytes after downscaling, maximum is %d bytes", len(encoded), constraints.MaxBytes)
+		}
+		img = resize(img, width, height)
+	}
+}
+
+// normalizedFormat keeps the source format when accepted, preferring JPEG then PNG otherwise
+func normalizedFormat(format string, constraints *ImageConstraints) string {
+	accepted := func(f string) bool {
+		if constraints == nil || len(constraints.Formats) == 0 {
+			return f == "jpeg" || f == "png"
+		}
+		for _, candidate := range constraints.Formats {
+			if candidate == f {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, f := range []string{format, "jpeg", "png"} {
+		if accepted(f) {
+			return f
+		}
+	}
+	return "jpeg"
+}
+
+// encodeImage encodes img as PNG or, at the given quality, as JPEG
+func encodeImage(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if img, ok := src.(*image.RGBA); ok && img.Bounds().Min == (image.Point{}) {
+		return img
+	}
+	bounds := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+	return img
+}
+
+// fitWithin downscales img, keeping its aspect ratio, so it fits maxWidth x maxHeight
+func fitWithin(img *image.RGBA, maxWidth, maxHeight int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	scale := 1.0
+	if maxWidth > 0 && w > maxWidth {
+		scale = float64(maxWidth) / float64(w)
+	}
+	if maxHeight > 0 && float64(h)*scale > float64(maxHeight) {
+		scale = float64(maxHeight) / float64(h)
+	}
+	if scale == 1.0 {
+		return img
+	}
+	return resize(img, int(float64(w)*scale), int(float64(h)*scale))
+}
+
+// resize scales img to width x height by averaging the covered source pixels
+func resize(img *image.RGBA, width, height int) *image.RGBA {
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	srcW, srcH := img.Bounds().Dx(), img.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, (y+1)*srcH/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, (x+1)*srcW/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := img.Pix[sy*img.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					for i := 0; i < 4; i++ {
+						sum[i] += int(row[sx*4+i])
+					}
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+			for i := 0; i < 4; i++ {
+				dst.Pix[offset+i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// applyOrientation transforms img so it displays upright for the given EXIF orientation (1-8)
+func applyOrientation(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirror horizontal
+				sx, sy = w-1-x, y
+			case 3: // rotate 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				sx, sy = x, h-1-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // rotate 90 CW
+				sx, sy = y, h-1-x
+			case 7: // transverse
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotate 90 CCW
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], img.Pix[sy*img.Stride+sx*4:sy*img.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// exifOrientation returns the EXIF orientation tag of a JPEG, or 1 if absent
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan / end of image
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag (0x0112) from the first IFD of a TIFF header
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
@@ -0,0 +1,168 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testJPEGWithOrientation encodes a width x height JPEG carrying an EXIF orientation tag.
+// The top-left pixel is red so the applied rotation can be observed.
+func testJPEGWithOrientation(width, height int, orientation byte) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	encoded := buf.Bytes()
+
+	// Big-endian TIFF header with a single IFD0 entry: orientation (SHORT, count 1)
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0, 0, 0, 0, 0}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, segment...)
+
+	return append(append([]byte{0xFF, 0xD8}, app1...), encoded[2:]...)
+}
+
+func TestNormalizeImageOrientation(t *testing.T) {
+	data := testJPEGWithOrientation(400, 300, 6)
+	if got := exifOrientation(data); got != 6 {
+		t.Fatalf("Expected orientation 6, got %d", got)
+	}
+
+	normalized, contentType, err := NormalizeImage(data, nil)
+	if err != nil {
+		t.Fatalf("Failed to normalize image: %v", err)
+	}
+	if contentType != "image/jpeg" {
+		t.Errorf("Expected image/jpeg, got %s", contentType)
+	}
+	if exifOrientation(normalized) != 1 {
+		t.Error("Expected EXIF orientation to be stripped")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(normalized))
+	if err != nil {
+		t.Fatalf("Failed to decode normalized image: %v", err)
+	}
+	if img.Bounds().Dx() != 300 || img.Bounds().Dy() != 400 {
+		t.Errorf("Expected 300x400 after rotation, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	// Rotating 90 CW moves the red top-left corner to the top-right
+	if r, g, _, _ := img.At(296, 3).RGBA(); r < 0xC000 || g > 0x4000 {
+		t.Error("Expected red pixel at the top-right corner after rotation")
+	}
+}
+
+func TestNormalizeImageDownscale(t *testing.T) {
+	constraints := &ImageConstraints{MaxWidth: 200, MaxHeight: 200, Formats: []string{"jpeg"}}
+	normalized, contentType, err := NormalizeImage(testPNG(800, 400), constraints)
+	if err != nil {
+		t.Fatalf("Failed to normalize image: %v", err)
+	}
+	if contentType != "image/jpeg" {
+		t.Errorf("Expected PNG to be re-encoded as image/jpeg, got %s", contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(normalized))
+	if err != nil {
+		t.Fatalf("Failed to decode normalized image: %v", err)
+	}
+	if config.Width != 200 || config.Height != 100 {
+		t.Errorf("Expected 200x100, got %dx%d", config.Width, config.Height)
+	}
+}
+
+func TestClientNormalizeImages(t *testing.T) {
+	server := newKlingStub(t)
+	var uploaded []byte
+	client := server.client(t, &ClientConfig{
+		Timeout:         5 * time.Second,
+		NormalizeImages: true,
+		ImageUploader: func(ctx context.Context, data []byte, contentType string) (string, error) {
+			uploaded = data
+			return "https://storage.example.com/upload.jpg", nil
+		},
+	})
+
+	req := &GenerationRequest{ImageBytes: testJPEGWithOrientation(640, 320, 8), Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(uploaded))
+	if err != nil {
+		t.Fatalf("Failed to decode uploaded image: %v", err)
+	}
+	if config.Width != 320 || config.Height != 640 {
+		t.Errorf("Expected 320x640 uploaded image, got %dx%d", config.Width, config.Height)
+	}
+	if body := server.body(); body["image"] != "https://storage.example.com/upload.jpg" {
+		t.Errorf("Expected the uploaded URL to be submitted, got %v", body["image"])
+	}
+}
+
+func TestNormalizeImageMinimum(t *testing.T) {
+	constraints := &ImageConstraints{MinWidth: 300, MinHeight: 300, MaxBytes: 10}
+	if _, _, err := NormalizeImage(testPNG(400, 400), constraints); err == nil {
+		t.Error("Expected an error instead of shrinking below the minimum size")
+	}
+}
+
+func TestClientNormalizeImageTail(t *testing.T) {
+	server := newKlingStub(t)
+	client := server.client(t, &ClientConfig{Timeout: 5 * time.Second, NormalizeImages: true})
+
+	tail := "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(testJPEGWithOrientation(640, 320, 6))
+	req := &GenerationRequest{Image: "https://example.com/a.png", ImageTail: tail, Duration: 5, Width: 512, Height: 512}
+	if _, err := client.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	payload := server.body()
+	encoded, _ := payload["image_tail"].(string)
+	data, _ := base64.StdEncoding.DecodeString(encoded)
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to decode submitted end frame: %v", err)
+	}
+	if config.Width != 320 || config.Height != 640 {
+		t.Errorf("Expected the end frame to be rotated to 320x640, got %dx%d", config.Width, config.Height)
+	}
+	if payload["image"] != "https://example.com/a.png" || req.ImageTail != tail {
+		t.Errorf("Expected the URL image and caller's request to be unchanged, got %v", payload["image"])
+	}
+}
+
+func TestNormalizeImageTooManyPixels(t *testing.T) {
+	// A PNG header declaring 100000x100000 pixels; the pixel data is never read
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	binary.BigEndian.PutUint32(ihdr[8:], 100000)
+	ihdr[12], ihdr[13] = 8, 2 // 8-bit RGB
+	data := append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d"), ihdr...)
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+
+	if _, _, err := NormalizeImage(data, nil); err == nil || !strings.Contains(err.Error(), "100000x100000") {
+		t.Errorf("Expected the image to be rejected before decoding, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// CallOption overrides client or provider settings for a single call
+type CallOption func(*callOptions)
+
+// callOptions holds the effective settings for a single call
+type callOptions struct {
+	timeout    time.Duration
+	timeoutSet bool // WithTimeout was given, so it also replaces the provider HTTP timeout
+	maxRetries int
+	retryDelay time.Duration
+	apiKey     string
+	baseURL    string
+	noCache    bool
+	header     http.Header
+	features   adapters.Features
+	via        []string
+	tokens     *adapters.TokenLifetime
+}
+
+// WithTimeout overrides the client timeout for a single call
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+		o.timeoutSet = true
+	}
+}
+
+// WithMaxRetries overrides the client retry count for a single call
+func WithMaxRetries(maxRetries int) CallOption {
+	return func(o *callOptions) {
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithHeader adds an HTTP header to the provider requests of a single call, e.g. a
+// trace ID. It can be repeated; headers the provider sets itself, such as
+// Authorization, are never replaced.
+func WithHeader(name, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(name, value)
+	}
+}
+
+// WithAPIKey overrides the provider API key for a single call.
+// It is subject to the same BYOK policy as WithCredentials.
+func WithAPIKey(apiKey string) CallOption {
+	return func(o *callOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithBaseURL overrides the provider base URL for a single call
+func WithBaseURL(baseURL string) CallOption {
+	return func(o *callOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithFeatures advertises additional X-Vidgo-Features to the provider for a single
+// call, e.g. callback support when forwarding to a downstream vidgo-based relay
+func WithFeatures(features adapters.Features) CallOption {
+	return func(o *callOptions) {
+		o.features = features
+	}
+}
+
+// WithVia sends the relay hops returned by RelayHops in the X-Vidgo-Via header
+// of a single call, so the next gateway can detect loops
+func WithVia(hops []string) CallOption {
+	return func(o *callOptions) {
+		o.via = hops
+	}
+}
+
+// callOptions resolves the effective call settings from the client config and options
+func (c *Client) callOptions(opts []CallOption) (*callOptions, error) {
+	o := &callOptions{
+		timeout:    c.config.Timeout,
+		maxRetries: c.config.MaxRetries,
+		retryDelay: c.config.RetryDelay,
+	}
+	if c.config.TokenMinTTL > 0 || c.config.TokenMaxTTL > 0 {
+		o.tokens = &adapters.TokenLifetime{Min: c.config.TokenMinTTL, Max: c.config.TokenMaxTTL}
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	if o.apiKey != "" {
+		if err := c.validateCredentials(o.apiKey); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// context attaches provider overrides, features, relay hops and token lifetimes to ctx so adapters can pick them up
+func (o *callOptions) context(ctx context.Context) context.Context {
+	if o.features != nil {
+		ctx = adapters.WithFeatures(ctx, o.features)
+	}
+	if o.via != nil {
+		ctx = adapters.WithVia(ctx, o.via)
+	}
+	if o.tokens != nil {
+		ctx = adapters.WithTokenLifetime(ctx, *o.tokens)
+	}
+	if o.apiKey == "" && o.baseURL == "" && o.header == nil && !o.timeoutSet {
+		return ctx
+	}
+	overrides := &adapters.RequestOverrides{
+		APIKey:  o.apiKey,
+		BaseURL: o.baseURL,
+		Header:  o.header,
+	}
+	if o.timeoutSet {
+		overrides.Timeout = o.timeout
+	}
+	return adapters.WithRequestOverrides(ctx, overrides)
+}
@@ -0,0 +1,173 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func TestCallOptionOverrides(t *testing.T) {
+	var issuer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			return []byte("customer_secret_key"), nil
+		})
+		if err == nil {
+			issuer, _ = token.Claims.(jwt.MapClaims)["iss"].(string)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"processing"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{
+		BaseURL: "http://127.0.0.1:0",
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.GetGeneration(context.Background(), "task-1",
+		WithBaseURL(server.URL),
+		WithAPIKey("customer_access_key,customer_secret_key"),
+		WithMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed to get generation with overrides: %v", err)
+	}
+
+	if result.Status != TaskStatusProcessing {
+		t.Errorf("Expected status '%s', got '%s'", TaskStatusProcessing, result.Status)
+	}
+	if issuer != "customer_access_key" {
+		t.Errorf("Expected token issued for 'customer_access_key', got '%s'", issuer)
+	}
+
+	// Without overrides the unreachable configured base URL is used
+	if _, err := client.GetGeneration(context.Background(), "task-1", WithMaxRetries(0), WithTimeout(time.Second)); err == nil {
+		t.Error("Expected error when calling the configured base URL")
+	}
+}
+
+func TestBYOKPolicy(t *testing.T) {
+	config := &ProviderConfig{
+		BaseURL: "http://127.0.0.1:0",
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 5 * time.Second,
+	}
+
+	client, err := NewClient(ProviderKling, config, &ClientConfig{Timeout: time.Second, DisallowBYOK: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.GetGeneration(context.Background(), "task-1", WithCredentials(&Credentials{APIKey: "ak,sk"}))
+	if !errors.Is(err, ErrBYOKNotAllowed) {
+		t.Errorf("Expected ErrBYOKNotAllowed, got %v", err)
+	}
+
+	client, err = NewClient(ProviderKling, config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.GetGeneration(context.Background(), "task-1", WithCredentials(&Credentials{APIKey: "malformed"}))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Expected validation error for malformed credentials, got %v", err)
+	}
+
+}
+
+func TestCredentialsRedacted(t *testing.T) {
+	credentials := Credentials{APIKey: "customer_access_key,customer_secret_key"}
+	request := struct {
+		Model       string
+		Credentials Credentials
+	}{Model: "kling-v1", Credentials: credentials}
+
+	var outputs []string
+	for _, v := range []interface{}{credentials, &credentials, request, &request} {
+		for _, format := range []string{"%v", "%+v", "%#v"} {
+			outputs = append(outputs, fmt.Sprintf(format, v))
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Failed to marshal %T: %v", v, err)
+		}
+		outputs = append(outputs, string(data))
+	}
+
+	for _, s := range outputs {
+		if strings.Contains(s, "customer_secret_key") || strings.Contains(s, "customer_access_key") {
+			t.Errorf("Credentials leaked in %q", s)
+		}
+		if !strings.Contains(s, "REDACTED") {
+			t.Errorf("Expected a redacted key in %q", s)
+		}
+	}
+}
+
+func TestCallOptionTimeoutAndHeader(t *testing.T) {
+	headers := make(chan http.Header, 2)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+		// The first call hangs until the client gives up on it
+		if calls.Add(1) == 1 {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	req := &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}
+
+	if _, err := client.CreateGeneration(context.Background(), req, WithMaxRetries(0)); err == nil {
+		t.Error("Expected the provider timeout to expire")
+	}
+	<-headers
+
+	_, err = client.CreateGeneration(context.Background(), req,
+		WithMaxRetries(0),
+		WithTimeout(5*time.Second),
+		WithHeader("X-Trace-Id", "trace-42"),
+		WithHeader("Authorization", "Bearer forged"),
+	)
+	if err != nil {
+		t.Fatalf("Expected WithTimeout to extend the provider timeout, got %v", err)
+	}
+	header := <-headers
+	if traceID := header.Get("X-Trace-Id"); traceID != "trace-42" {
+		t.Errorf("Expected X-Trace-Id trace-42, got %q", traceID)
+	}
+	if header.Get("Authorization") == "Bearer forged" {
+		t.Error("Expected WithHeader not to replace the provider Authorization header")
+	}
+}
@@ -0,0 +1,86 @@
+// Package pace holds the retry, backoff, request deduplication and paging loops
+// the vidgo client uses, so provider adapters built outside this repository
+// can behave the same way instead of writing their own.
+package pace
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the wait before each attempt of a repeated operation. The
+// first wait is Initial; later waits start at Interval and grow by Multiplier
+// up to Max.
+type Backoff struct {
+	Initial    time.Duration
+	Interval   time.Duration // Defaults to Initial
+	Multiplier float64       // Values up to 1 keep the interval constant
+	Max        time.Duration // Zero means unbounded
+
+	// Jitter randomizes each wait by up to this fraction either way, e.g. 0.2
+	// for ±20%, so operations started together do not run in lockstep
+	Jitter float64
+}
+
+// Constant returns a Backoff that always waits d
+func Constant(d time.Duration) Backoff {
+	return Backoff{Initial: d, Interval: d}
+}
+
+// Delay returns the wait before the given attempt (0-based), with jitter
+func (b Backoff) Delay(attempt int) time.Duration {
+	return b.jitter(b.Base(attempt))
+}
+
+// Base returns the wait before the given attempt (0-based) without jitter
+func (b Backoff) Base(attempt int) time.Duration {
+	if attempt == 0 {
+		return b.Initial
+	}
+
+	interval := float64(b.Interval)
+	if interval <= 0 {
+		interval = float64(b.Initial)
+	}
+	if b.Multiplier > 1 {
+		for i := 1; i < attempt; i++ {
+			interval *= b.Multiplier
+			if b.Max > 0 && interval >= float64(b.Max) {
+				break
+			}
+		}
+	}
+	if b.Max > 0 && interval > float64(b.Max) {
+		interval = float64(b.Max)
+	}
+	return time.Duration(interval)
+}
+
+// jitter spreads d uniformly over ±Jitter of its value
+func (b Backoff) jitter(d time.Duration) time.Duration {
+	if b.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	jitter := b.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	return time.Duration(float64(d) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// Sleep waits for d or until ctx is done, returning ctx.Err() in the latter case
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,52 @@
+package pace
+
+import "sync"
+
+// Group deduplicates concurrent calls by key: while a call for a key is in
+// flight, later callers wait for it and share its result. The zero value is
+// ready to use.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Do runs fn once for all concurrent callers with the same key. shared reports
+// whether the result came from another caller's fn.
+func (g *Group[T]) Do(key string, fn func() (T, error)) (value T, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.value, c.err, true
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+	c := &call[T]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.value, c.err = fn()
+	return c.value, c.err, false
+}
+
+// InFlight reports whether a call for key is running
+func (g *Group[T]) InFlight(key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.calls[key]
+	return ok
+}
@@ -0,0 +1,138 @@
+package pace
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{Initial: time.Minute, Interval: 10 * time.Second, Multiplier: 2, Max: 30 * time.Second}
+
+	expected := []time.Duration{time.Minute, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second}
+	for attempt, want := range expected {
+		if got := backoff.Delay(attempt); got != want {
+			t.Errorf("Expected delay %v for attempt %d, got %v", want, attempt, got)
+		}
+	}
+	if got := Constant(time.Second).Delay(5); got != time.Second {
+		t.Errorf("Expected constant delay 1s, got %v", got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	temporary := errors.New("temporary")
+	permanent := errors.New("permanent")
+	retry := Retry{
+		MaxRetries: 3,
+		Backoff:    Constant(time.Millisecond),
+		Retryable:  func(err error) bool { return errors.Is(err, temporary) },
+	}
+
+	var attempts int
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return temporary
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected success on attempt 3, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return permanent
+	})
+	if !errors.Is(err, permanent) || attempts != 1 {
+		t.Errorf("Expected permanent error after 1 attempt, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return temporary
+	})
+	if !errors.Is(err, temporary) || attempts != 4 {
+		t.Errorf("Expected temporary error after 4 attempts, got %v after %d attempts", err, attempts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	retry.Backoff = Constant(time.Hour)
+	if err := retry.Do(ctx, func(ctx context.Context) error { return temporary }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled while waiting, got %v", err)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var group Group[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = group.Do("key", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+		}(i)
+	}
+	for !group.InFlight("key") {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 call, got %d", calls.Load())
+	}
+	for i, result := range results {
+		if result != 42 {
+			t.Errorf("Expected result 42 for caller %d, got %d", i, result)
+		}
+	}
+	if group.InFlight("key") {
+		t.Error("Expected no call in flight after completion")
+	}
+}
+
+func TestPages(t *testing.T) {
+	fetch := func(ctx context.Context, cursor string) ([]int, string, error) {
+		page, _ := strconv.Atoi(cursor)
+		if page == 2 {
+			return []int{page * 2, page*2 + 1}, "", nil
+		}
+		return []int{page * 2, page*2 + 1}, strconv.Itoa(page + 1), nil
+	}
+
+	items, err := Collect(context.Background(), fetch, 0)
+	if err != nil {
+		t.Fatalf("Failed to collect pages: %v", err)
+	}
+	if len(items) != 6 || items[5] != 5 {
+		t.Errorf("Expected items 0-5, got %v", items)
+	}
+	if items, _ := Collect(context.Background(), fetch, 3); len(items) != 3 {
+		t.Errorf("Expected 3 items with a limit, got %v", items)
+	}
+
+	loop := func(ctx context.Context, cursor string) ([]int, string, error) {
+		return []int{1}, "same", nil
+	}
+	if _, err := Collect(context.Background(), loop, 0); err == nil {
+		t.Error("Expected an error for a repeated cursor")
+	}
+}
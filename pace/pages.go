@@ -0,0 +1,51 @@
+package pace
+
+import (
+	"context"
+	"fmt"
+)
+
+// PageFunc fetches the page at cursor, the empty string being the first page,
+// and returns its items and the next cursor, empty after the last page
+type PageFunc[T any] func(ctx context.Context, cursor string) (items []T, next string, err error)
+
+// Pages calls fetch page by page and passes every item to yield until the last
+// page, an error, or yield returning false. Cursors that repeat fail rather than
+// loop forever.
+func Pages[T any](ctx context.Context, fetch PageFunc[T], yield func(item T) bool) error {
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, next, err := fetch(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if !yield(item) {
+				return nil
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		if seen[next] {
+			return fmt.Errorf("pagination cursor %q repeated", next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+// Collect fetches every page and returns all items. A positive limit stops
+// after that many items.
+func Collect[T any](ctx context.Context, fetch PageFunc[T], limit int) ([]T, error) {
+	var all []T
+	err := Pages(ctx, fetch, func(item T) bool {
+		all = append(all, item)
+		return limit <= 0 || len(all) < limit
+	})
+	return all, err
+}
@@ -0,0 +1,47 @@
+package pace
+
+import (
+	"context"
+	"time"
+)
+
+// Retry runs an operation until it succeeds, fails with a non-retryable error
+// or runs out of retries
+type Retry struct {
+	MaxRetries int     // Retries after the first attempt
+	Backoff    Backoff // Attempt 0 is the wait before the first retry
+
+	// Retryable reports whether an error is worth retrying; nil retries every error
+	Retryable func(err error) bool
+
+	// OnRetry, if set, is called with the failed attempt (1-based), its error and
+	// the wait before the next one
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// Do calls fn until it returns nil or r gives up, and returns the last error.
+// Waiting between attempts stops when ctx is done.
+func (r Retry) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	var lastErr error
+	for i := 0; i <= r.MaxRetries; i++ {
+		if i > 0 {
+			wait := r.Backoff.Delay(i - 1)
+			if r.OnRetry != nil {
+				r.OnRetry(i, lastErr, wait)
+			}
+			if err := Sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if r.Retryable != nil && !r.Retryable(err) {
+			break
+		}
+	}
+	return lastErr
+}
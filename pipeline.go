@@ -0,0 +1,561 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PipelineStage names a stage of a Pipeline
+type PipelineStage string
+
+const (
+	PipelineStageSubmit      PipelineStage = "submit"
+	PipelineStageWait        PipelineStage = "wait"
+	PipelineStagePostprocess PipelineStage = "postprocess"
+	PipelineStageArchive     PipelineStage = "archive"
+	PipelineStageNotify      PipelineStage = "notify"
+)
+
+// pipelineStages lists the stages in the order they run
+var pipelineStages = []PipelineStage{
+	PipelineStageSubmit,
+	PipelineStageWait,
+	PipelineStagePostprocess,
+	PipelineStageArchive,
+	PipelineStageNotify,
+}
+
+// PipelineHook runs custom logic before or after a stage, e.g. creating an
+// invoice line once the result is archived. An error fails the stage.
+type PipelineHook func(ctx context.Context, stage PipelineStage, run *PipelineResult) error
+
+// PipelineCheckpoint records the progress of a run once its task is submitted
+type PipelineCheckpoint struct {
+	TaskID    string             `json:"task_id"`
+	Request   *GenerationRequest `json:"request"`
+	Completed PipelineStage      `json:"completed"` // Last stage that finished
+	Result    *TaskResult        `json:"result,omitempty"`
+	UpdatedAt time.Time          `json:"updated_at"`
+
+	// LeaseToken is the fencing token of the lease the run held, see Pipeline.Lease
+	LeaseToken int64 `json:"lease_token,omitempty"`
+
+	// ReplayOf is the task whose request the run replays, see Pipeline.Replay
+	ReplayOf string `json:"replay_of,omitempty"`
+
+	// Version is the schema version of a saved checkpoint, see PipelineCheckpointVersion
+	Version int `json:"version,omitempty"`
+}
+
+// PipelineStore persists pipeline checkpoints so a run can be resumed after a
+// crash. Implementations must be safe for concurrent use.
+type PipelineStore interface {
+	SaveCheckpoint(checkpoint *PipelineCheckpoint) error
+	LoadCheckpoint(taskID string) (*PipelineCheckpoint, bool, error)
+}
+
+// MemoryPipelineStore is an in-process PipelineStore and PipelineLeaseStore
+type MemoryPipelineStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]PipelineCheckpoint
+	leases      map[string]PipelineLease
+	token       int64 // Last fencing token granted
+}
+
+// NewMemoryPipelineStore creates an empty in-memory pipeline store
+func NewMemoryPipelineStore() *MemoryPipelineStore {
+	return &MemoryPipelineStore{
+		checkpoints: make(map[string]PipelineCheckpoint),
+		leases:      make(map[string]PipelineLease),
+	}
+}
+
+// SaveCheckpoint stores a copy of checkpoint. While the task is leased, only
+// checkpoints carrying the token of the current lease are accepted.
+func (s *MemoryPipelineStore) SaveCheckpoint(checkpoint *PipelineCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lease, leased := s.leases[checkpoint.TaskID]
+	if (leased || checkpoint.LeaseToken != 0) && lease.Token != checkpoint.LeaseToken {
+		return fmt.Errorf("%w: checkpoint of %s has a stale lease token", ErrLeaseLost, checkpoint.TaskID)
+	}
+	s.checkpoints[checkpoint.TaskID] = *checkpoint
+	return nil
+}
+
+// LoadCheckpoint returns a copy of the checkpoint of a task
+func (s *MemoryPipelineStore) LoadCheckpoint(taskID string) (*PipelineCheckpoint, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoint, ok := s.checkpoints[taskID]
+	if !ok {
+		return nil, false, nil
+	}
+	return &checkpoint, true, nil
+}
+
+// FilePipelineStore is a PipelineStore keeping one JSON file per task in Dir, so
+// a run can be resumed or replayed by another process. Request.ImageBytes is
+// saved as a data URI in Request.Image. It implements PipelineLeaseStore with a
+// lease file per task, so replicas sharing Dir, e.g. over NFS, can take over
+// each other's runs.
+type FilePipelineStore struct {
+	Dir string
+}
+
+// NewFilePipelineStore creates a pipeline store in dir, creating it if needed
+func NewFilePipelineStore(dir string) (*FilePipelineStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create pipeline store: %w", err)
+	}
+	return &FilePipelineStore{Dir: dir}, nil
+}
+
+// SaveCheckpoint writes checkpoint at the current version, replacing the
+// previous one of its task atomically. It fails with ErrLeaseLost when the
+// checkpoint's LeaseToken is not the token of the task's current lease.
+func (s *FilePipelineStore) SaveCheckpoint(checkpoint *PipelineCheckpoint) error {
+	unlock, err := s.lock(checkpoint.TaskID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	lease, err := s.readLease(checkpoint.TaskID)
+	if err != nil {
+		return err
+	}
+	var token int64
+	if lease != nil && lease.Owner != "" {
+		token = lease.Token
+	}
+	if (token != 0 || checkpoint.LeaseToken != 0) && token != checkpoint.LeaseToken {
+		return fmt.Errorf("%w: checkpoint of %s has a stale lease token", ErrLeaseLost, checkpoint.TaskID)
+	}
+	return s.writeCheckpoint(checkpoint)
+}
+
+// writeCheckpoint writes checkpoint at the current version without checking its lease
+func (s *FilePipelineStore) writeCheckpoint(checkpoint *PipelineCheckpoint) error {
+	saved := *checkpoint
+	saved.Version = PipelineCheckpointVersion
+	if req := checkpoint.Request; req != nil && len(req.ImageBytes) > 0 {
+		request := *req
+		request.Image = "data:" + http.DetectContentType(req.ImageBytes) + ";base64," + base64.StdEncoding.EncodeToString(req.ImageBytes)
+		request.ImageBytes = nil
+		saved.Request = &request
+	}
+	data, err := json.Marshal(&saved)
+	if err != nil {
+		return fmt.Errorf("failed to encode pipeline checkpoint: %w", err)
+	}
+	if err := writeFileAtomic(s.path(checkpoint.TaskID), data); err != nil {
+		return fmt.Errorf("failed to save pipeline checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads the checkpoint of a task, migrating one of an older version
+func (s *FilePipelineStore) LoadCheckpoint(taskID string) (*PipelineCheckpoint, bool, error) {
+	data, err := os.ReadFile(s.path(taskID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load pipeline checkpoint: %w", err)
+	}
+
+	checkpoint, _, err := decodeCheckpoint(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode pipeline checkpoint: %w", err)
+	}
+	return checkpoint, true, nil
+}
+
+// path returns the file of a task; task IDs such as Veo operation names contain
+// slashes, so they are base64url encoded
+func (s *FilePipelineStore) path(taskID string) string {
+	return s.file(taskID, ".json")
+}
+
+// file returns the file of a task with the given extension
+func (s *FilePipelineStore) file(taskID, ext string) string {
+	return filepath.Join(s.Dir, base64.RawURLEncoding.EncodeToString([]byte(taskID))+ext)
+}
+
+// WaitOptions configures the wait stage of a Pipeline
+type WaitOptions struct {
+	PollInterval time.Duration // Passed to WaitForCompletion, zero uses the adaptive policy
+	Timeout      time.Duration // Maximum time to wait, zero waits until the context is done
+}
+
+// Postprocessor transforms a succeeded result, e.g. trimming or watermarking the
+// video, and returns the result to pass on (typically with a new URL)
+type Postprocessor func(ctx context.Context, result *TaskResult) (*TaskResult, error)
+
+// Notifier announces the outcome of a pipeline run
+type Notifier interface {
+	Notify(ctx context.Context, run *PipelineResult) error
+}
+
+// PipelineResult is the outcome of a pipeline run. Stage is the last stage that
+// ran; when Err is set it is the stage that failed.
+type PipelineResult struct {
+	TaskID string        `json:"task_id,omitempty"`
+	Result *TaskResult   `json:"result,omitempty"`
+	Stage  PipelineStage `json:"stage"`
+	Err    error         `json:"-"`
+
+	// ReplayOf is the task whose request the run replays, see Pipeline.Replay
+	ReplayOf string `json:"replay_of,omitempty"`
+
+	lease *PipelineLease // Held while the run is leased
+}
+
+// Pipeline composes submit → wait → postprocess → archive → notify. Stages other
+// than submit and wait are optional; build a pipeline once and Run it per request.
+type Pipeline struct {
+	client         *Client
+	wait           WaitOptions
+	postprocessors []Postprocessor
+	archive        ResultArchive
+	notifiers      []Notifier
+	store          PipelineStore
+	before         map[PipelineStage][]PipelineHook
+	after          map[PipelineStage][]PipelineHook
+	leaseOwner     string
+	leaseTTL       time.Duration
+	leaseErr       error
+}
+
+// NewPipeline creates a pipeline that submits and waits with client
+func NewPipeline(client *Client) *Pipeline {
+	return &Pipeline{
+		client: client,
+		before: make(map[PipelineStage][]PipelineHook),
+		after:  make(map[PipelineStage][]PipelineHook),
+	}
+}
+
+// Wait configures how the pipeline waits for the task to finish
+func (p *Pipeline) Wait(opts WaitOptions) *Pipeline {
+	p.wait = opts
+	return p
+}
+
+// Postprocess appends steps run in order on succeeded results
+func (p *Pipeline) Postprocess(steps ...Postprocessor) *Pipeline {
+	p.postprocessors = append(p.postprocessors, steps...)
+	return p
+}
+
+// ArchiveTo stores the final result in store
+func (p *Pipeline) ArchiveTo(store ResultArchive) *Pipeline {
+	p.archive = store
+	return p
+}
+
+// Notify appends notifiers called with the outcome of every run, including failures
+func (p *Pipeline) Notify(notifiers ...Notifier) *Pipeline {
+	p.notifiers = append(p.notifiers, notifiers...)
+	return p
+}
+
+// CheckpointTo saves the progress of every run in store, so it can be resumed
+func (p *Pipeline) CheckpointTo(store PipelineStore) *Pipeline {
+	p.store = store
+	return p
+}
+
+// Before adds a hook run before stage
+func (p *Pipeline) Before(stage PipelineStage, hook PipelineHook) *Pipeline {
+	p.before[stage] = append(p.before[stage], hook)
+	return p
+}
+
+// After adds a hook run after stage succeeds
+func (p *Pipeline) After(stage PipelineStage, hook PipelineHook) *Pipeline {
+	p.after[stage] = append(p.after[stage], hook)
+	return p
+}
+
+// Run submits req and takes the task through the configured stages. A failed
+// task skips postprocessing but is still archived and notified.
+func (p *Pipeline) Run(ctx context.Context, req *GenerationRequest) *PipelineResult {
+	run := &PipelineResult{}
+	p.runFrom(ctx, req, run, 0)
+	return run
+}
+
+// Resume continues the run of a task from the stage after the last one its
+// checkpoint recorded, e.g. re-running the archive without regenerating the
+// video. A finished run returns its stored result. A leased pipeline fails with
+// ErrLeaseHeld while another owner runs the task.
+func (p *Pipeline) Resume(ctx context.Context, taskID string) *PipelineResult {
+	run := &PipelineResult{TaskID: taskID}
+	if p.store == nil {
+		run.Err = fmt.Errorf("%w: pipeline has no checkpoint store", ErrInvalidConfiguration)
+		return run
+	}
+
+	ctx, stop, err := p.acquireLease(ctx, run)
+	if err != nil {
+		run.Err = err
+		return run
+	}
+	defer stop()
+
+	checkpoint, ok, err := p.store.LoadCheckpoint(taskID)
+	switch {
+	case err != nil:
+		run.Err = err
+		return run
+	case !ok:
+		run.Err = &TaskNotFoundError{TaskID: taskID, Message: "no pipeline checkpoint"}
+		return run
+	}
+
+	run.Result = checkpoint.Result
+	run.Stage = checkpoint.Completed
+	run.ReplayOf = checkpoint.ReplayOf
+	if checkpoint.Completed == PipelineStageNotify {
+		return run
+	}
+	for i, stage := range pipelineStages {
+		if stage == checkpoint.Completed {
+			p.runFrom(ctx, checkpoint.Request, run, i+1)
+			break
+		}
+	}
+	return run
+}
+
+// checkpoint records that stage completed, if a store is configured
+func (p *Pipeline) checkpoint(req *GenerationRequest, run *PipelineResult, stage PipelineStage) error {
+	if p.store == nil {
+		return nil
+	}
+	return p.store.SaveCheckpoint(&PipelineCheckpoint{
+		TaskID:    run.TaskID,
+		Request:   req,
+		Completed: stage,
+		Result:    run.Result,
+		UpdatedAt: time.Now(),
+
+		LeaseToken: run.leaseToken(),
+		ReplayOf:   run.ReplayOf,
+	})
+}
+
+// leaseToken returns the fencing token of the lease run holds, or 0
+func (run *PipelineResult) leaseToken() int64 {
+	if run.lease == nil {
+		return 0
+	}
+	return run.lease.Token
+}
+
+// runFrom runs the stages from pipelineStages[start] on, then notifies. A
+// leased pipeline claims the task once it is submitted; a run that loses its
+// lease stops with ErrLeaseLost and leaves notifying to the new owner.
+func (p *Pipeline) runFrom(ctx context.Context, req *GenerationRequest, run *PipelineResult, start int) {
+	stop := func() {}
+	defer func() { stop() }()
+
+	// 读入本地图片，使检查点保存图片本身而不是只在本机有效的路径；
+	// 读取失败时原样提交，由提交阶段报错
+	if start == 0 {
+		if loaded, err := loadImageFile(req); err == nil {
+			req = loaded
+		}
+	}
+
+	for _, stage := range pipelineStages[start:] {
+		if stage == PipelineStageNotify || !p.enabled(stage, run) {
+			continue
+		}
+		run.Stage = stage
+		if err := p.runStage(ctx, stage, req, run); err != nil {
+			run.Err = err
+			break
+		}
+		if stage == PipelineStageSubmit {
+			var err error
+			if ctx, stop, err = p.acquireLease(ctx, run); err != nil {
+				run.Err = err
+				break
+			}
+		}
+		if err := p.checkpoint(req, run, stage); err != nil {
+			run.Err = err
+			break
+		}
+	}
+
+	if cause := context.Cause(ctx); errors.Is(cause, ErrLeaseLost) && !errors.Is(run.Err, ErrLeaseLost) {
+		run.Err = cause
+	}
+	if errors.Is(run.Err, ErrLeaseLost) {
+		return
+	}
+	if len(p.notifiers) > 0 {
+		p.notify(ctx, req, run)
+	}
+}
+
+// enabled reports whether a stage is configured and applies to run
+func (p *Pipeline) enabled(stage PipelineStage, run *PipelineResult) bool {
+	switch stage {
+	case PipelineStagePostprocess:
+		return len(p.postprocessors) > 0 && run.Result.Status == TaskStatusSucceeded
+	case PipelineStageArchive:
+		return p.archive != nil
+	case PipelineStageNotify:
+		return len(p.notifiers) > 0
+	}
+	return true
+}
+
+// runStage runs one stage between its before and after hooks
+func (p *Pipeline) runStage(ctx context.Context, stage PipelineStage, req *GenerationRequest, run *PipelineResult) error {
+	for _, hook := range p.before[stage] {
+		if err := hook(ctx, stage, run); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch stage {
+	case PipelineStageSubmit:
+		var resp *GenerationResponse
+		if resp, err = p.client.CreateGeneration(ctx, req); err == nil {
+			run.TaskID = resp.TaskID
+		}
+	case PipelineStageWait:
+		waitCtx := ctx
+		if p.wait.Timeout > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(ctx, p.wait.Timeout)
+			defer cancel()
+		}
+		run.Result, err = p.client.WaitForCompletion(waitCtx, run.TaskID, p.wait.PollInterval)
+	case PipelineStagePostprocess:
+		for _, step := range p.postprocessors {
+			var result *TaskResult
+			if result, err = step(ctx, run.Result); err != nil {
+				break
+			}
+			if result != nil {
+				run.Result = result
+			}
+		}
+	case PipelineStageArchive:
+		err = p.archive.Archive(run.Result)
+	case PipelineStageNotify:
+		var errs []error
+		for _, notifier := range p.notifiers {
+			if err := notifier.Notify(ctx, run); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		err = errors.Join(errs...)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range p.after[stage] {
+		if err := hook(ctx, stage, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify runs the notify stage. Its errors are added to run.Err without changing
+// the stage a failed run stopped at.
+func (p *Pipeline) notify(ctx context.Context, req *GenerationRequest, run *PipelineResult) {
+	failed := run.Err != nil
+	stage := run.Stage
+
+	err := p.runStage(ctx, PipelineStageNotify, req, run)
+	switch {
+	case failed:
+		run.Stage = stage
+	case err != nil:
+		run.Err = err
+	default:
+		run.Stage = PipelineStageNotify
+		if err := p.checkpoint(req, run, PipelineStageNotify); err != nil {
+			run.Err = err
+		}
+	}
+}
+
+// WebhookNotifier posts each pipeline outcome as JSON: {"text", "task_id", "stage",
+// "status", "url", "error"}. The "text" summary makes it usable as a Slack
+// incoming webhook.
+type WebhookNotifier struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// Notify posts the outcome of run
+func (n *WebhookNotifier) Notify(ctx context.Context, run *PipelineResult) error {
+	payload := map[string]interface{}{"task_id": run.TaskID, "stage": run.Stage}
+	if run.Result != nil {
+		payload["status"] = run.Result.Status
+		payload["url"] = run.Result.URL
+	}
+	if run.Err != nil {
+		payload["error"] = run.Err.Error()
+		payload["text"] = fmt.Sprintf("vidgo task %s failed at %s: %v", run.TaskID, run.Stage, run.Err)
+	} else if run.Result != nil && run.Result.Status != TaskStatusSucceeded {
+		payload["text"] = fmt.Sprintf("vidgo task %s %s", run.TaskID, run.Result.Status)
+	} else {
+		payload["text"] = fmt.Sprintf("vidgo task %s is ready: %s", run.TaskID, run.Result.URL)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("notification error %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PipelineLease is an owner's claim on the run of a task. Token is a fencing
+// token that grows with every grant, so a store can reject the checkpoints of
+// an owner whose lease was taken over while it was stalled.
+type PipelineLease struct {
+	TaskID  string    `json:"task_id"`
+	Owner   string    `json:"owner"`
+	Token   int64     `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// PipelineLeaseStore is a PipelineStore that hands the run of each task to one
+// owner at a time, so replicas sharing the store can take over the in-flight
+// runs of a replica that died without polling a task twice. SaveCheckpoint must
+// fail with ErrLeaseLost for a checkpoint whose LeaseToken is not the token of
+// the task's current lease.
+type PipelineLeaseStore interface {
+	PipelineStore
+
+	// AcquireLease grants owner the run of a task for ttl. It fails with
+	// ErrLeaseHeld while another owner holds an unexpired lease.
+	AcquireLease(taskID, owner string, ttl time.Duration) (*PipelineLease, error)
+
+	// RenewLease extends lease by ttl. It fails with ErrLeaseLost once the
+	// lease expired and was taken over.
+	RenewLease(lease *PipelineLease, ttl time.Duration) error
+
+	// ReleaseLease ends lease when its run stops
+	ReleaseLease(lease *PipelineLease) error
+
+	// ExpiredLeases returns the tasks whose lease expired without being released
+	ExpiredLeases() ([]string, error)
+}
+
+// AcquireLease grants owner the run of a task for ttl
+func (s *MemoryPipelineStore) AcquireLease(taskID, owner string, ttl time.Duration) (*PipelineLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if lease, ok := s.leases[taskID]; ok && lease.Owner != owner && now.Before(lease.Expires) {
+		return nil, fmt.Errorf("%w: %s holds %s until %s", ErrLeaseHeld, lease.Owner, taskID, lease.Expires.Format(time.RFC3339))
+	}
+	s.token++
+	lease := PipelineLease{TaskID: taskID, Owner: owner, Token: s.token, Expires: now.Add(ttl)}
+	s.leases[taskID] = lease
+	return &lease, nil
+}
+
+// RenewLease extends lease by ttl
+func (s *MemoryPipelineStore) RenewLease(lease *PipelineLease, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.leases[lease.TaskID]
+	if !ok || current.Token != lease.Token {
+		return fmt.Errorf("%w: %s", ErrLeaseLost, lease.TaskID)
+	}
+	current.Expires = time.Now().Add(ttl)
+	s.leases[lease.TaskID] = current
+	lease.Expires = current.Expires
+	return nil
+}
+
+// ReleaseLease ends lease; a lease that was taken over is left to its new owner
+func (s *MemoryPipelineStore) ReleaseLease(lease *PipelineLease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.leases[lease.TaskID]; ok && current.Token == lease.Token {
+		delete(s.leases, lease.TaskID)
+	}
+	return nil
+}
+
+// ExpiredLeases returns the tasks whose lease expired without being released
+func (s *MemoryPipelineStore) ExpiredLeases() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var taskIDs []string
+	for taskID, lease := range s.leases {
+		if !now.Before(lease.Expires) {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	return taskIDs, nil
+}
+
+// fileLockStale is how old a task lock file must be before it is taken to be
+// left behind by a process that died while holding it
+const fileLockStale = 10 * time.Second
+
+// AcquireLease grants owner the run of a task for ttl. Tokens keep growing
+// across releases, as the lease file of a task is never removed.
+func (s *FilePipelineStore) AcquireLease(taskID, owner string, ttl time.Duration) (*PipelineLease, error) {
+	unlock, err := s.lock(taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	current, err := s.readLease(taskID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var token int64
+	if current != nil {
+		if current.Owner != "" && current.Owner != owner && now.Before(current.Expires) {
+			return nil, fmt.Errorf("%w: %s holds %s until %s", ErrLeaseHeld, current.Owner, taskID, current.Expires.Format(time.RFC3339))
+		}
+		token = current.Token
+	}
+	lease := &PipelineLease{TaskID: taskID, Owner: owner, Token: token + 1, Expires: now.Add(ttl)}
+	if err := s.writeLease(lease); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// RenewLease extends lease by ttl
+func (s *FilePipelineStore) RenewLease(lease *PipelineLease, ttl time.Duration) error {
+	unlock, err := s.lock(lease.TaskID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := s.readLease(lease.TaskID)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Owner == "" || current.Token != lease.Token {
+		return fmt.Errorf("%w: %s", ErrLeaseLost, lease.TaskID)
+	}
+	current.Expires = time.Now().Add(ttl)
+	if err := s.writeLease(current); err != nil {
+		return err
+	}
+	lease.Expires = current.Expires
+	return nil
+}
+
+// ReleaseLease ends lease, keeping its token in the lease file; a lease that
+// was taken over is left to its new owner
+func (s *FilePipelineStore) ReleaseLease(lease *PipelineLease) error {
+	unlock, err := s.lock(lease.TaskID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := s.readLease(lease.TaskID)
+	if err != nil || current == nil || current.Token != lease.Token {
+		return err
+	}
+	current.Owner = ""
+	current.Expires = time.Time{}
+	return s.writeLease(current)
+}
+
+// ExpiredLeases returns the tasks whose lease expired without being released
+func (s *FilePipelineStore) ExpiredLeases() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline store: %w", err)
+	}
+	now := time.Now()
+	var taskIDs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".lease" {
+			continue
+		}
+		taskID, err := base64.RawURLEncoding.DecodeString(strings.TrimSuffix(name, ".lease"))
+		if err != nil {
+			continue
+		}
+		lease, err := s.readLease(string(taskID))
+		if err != nil {
+			return nil, err
+		}
+		if lease != nil && lease.Owner != "" && !now.Before(lease.Expires) {
+			taskIDs = append(taskIDs, lease.TaskID)
+		}
+	}
+	return taskIDs, nil
+}
+
+// readLease returns the lease of a task, nil when it was never leased
+func (s *FilePipelineStore) readLease(taskID string) (*PipelineLease, error) {
+	data, err := os.ReadFile(s.file(taskID, ".lease"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline lease: %w", err)
+	}
+	var lease PipelineLease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil, fmt.Errorf("failed to decode pipeline lease: %w", err)
+	}
+	return &lease, nil
+}
+
+// writeLease replaces the lease file of a task atomically
+func (s *FilePipelineStore) writeLease(lease *PipelineLease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("failed to encode pipeline lease: %w", err)
+	}
+	if err := writeFileAtomic(s.file(lease.TaskID, ".lease"), data); err != nil {
+		return fmt.Errorf("failed to save pipeline lease: %w", err)
+	}
+	return nil
+}
+
+// lock serializes the lease and checkpoint updates of a task across processes
+// with a lock file created with O_EXCL, and returns the function releasing it
+func (s *FilePipelineStore) lock(taskID string) (func(), error) {
+	path := filepath.Join(s.Dir, "."+filepath.Base(s.file(taskID, ".lock")))
+	deadline := time.Now().Add(2 * fileLockStale)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock pipeline task: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > fileLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock pipeline task %s: %s is held", taskID, path)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Lease makes every run claim its task in the checkpoint store as owner, e.g.
+// the hostname of the replica, renewing the claim every ttl/3 while it runs.
+// The store must implement PipelineLeaseStore and ttl must be positive;
+// otherwise runs fail with ErrInvalidConfiguration.
+func (p *Pipeline) Lease(owner string, ttl time.Duration) *Pipeline {
+	p.leaseOwner = owner
+	p.leaseTTL = ttl
+	p.leaseErr = nil
+	if ttl <= 0 {
+		p.leaseErr = fmt.Errorf("%w: lease ttl must be positive, got %s", ErrInvalidConfiguration, ttl)
+	}
+	return p
+}
+
+// TakeOver resumes the runs whose lease expired, e.g. those of a replica that
+// died, and returns their outcomes. Runs another replica claims first are skipped.
+func (p *Pipeline) TakeOver(ctx context.Context) ([]*PipelineResult, error) {
+	store, err := p.leaseStore()
+	if err != nil {
+		return nil, err
+	}
+	taskIDs, err := store.ExpiredLeases()
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*PipelineResult
+	for _, taskID := range taskIDs {
+		run := p.Resume(ctx, taskID)
+		if errors.Is(run.Err, ErrLeaseHeld) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// leaseStore returns the checkpoint store as a PipelineLeaseStore
+func (p *Pipeline) leaseStore() (PipelineLeaseStore, error) {
+	if p.leaseErr != nil {
+		return nil, p.leaseErr
+	}
+	store, ok := p.store.(PipelineLeaseStore)
+	if !ok {
+		return nil, fmt.Errorf("%w: leasing pipeline runs needs a PipelineLeaseStore", ErrInvalidConfiguration)
+	}
+	return store, nil
+}
+
+// acquireLease claims the task of run and keeps renewing the claim until the
+// returned stop is called. Losing the lease cancels the returned context with
+// ErrLeaseLost as its cause. Pipelines without a lease owner do nothing.
+func (p *Pipeline) acquireLease(ctx context.Context, run *PipelineResult) (context.Context, func(), error) {
+	noop := func() {}
+	if p.leaseOwner == "" {
+		return ctx, noop, nil
+	}
+	store, err := p.leaseStore()
+	if err != nil {
+		return ctx, noop, err
+	}
+	lease, err := store.AcquireLease(run.TaskID, p.leaseOwner, p.leaseTTL)
+	if err != nil {
+		return ctx, noop, err
+	}
+	run.lease = lease
+
+	leaseCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(max(p.leaseTTL/3, time.Nanosecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.RenewLease(lease, p.leaseTTL); err != nil {
+					cancel(err)
+					return
+				}
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		if !errors.Is(context.Cause(leaseCtx), ErrLeaseLost) {
+			store.ReleaseLease(lease)
+		}
+		cancel(nil)
+	}
+	return leaseCtx, stop, nil
+}
@@ -0,0 +1,165 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+// stalledLeaseStore is a MemoryPipelineStore whose leases cannot be renewed, as
+// when a replica stalls past its lease
+type stalledLeaseStore struct {
+	*MemoryPipelineStore
+}
+
+func (s stalledLeaseStore) RenewLease(lease *PipelineLease, ttl time.Duration) error {
+	return ErrLeaseLost
+}
+
+// notifierFunc adapts a function to a Notifier
+type notifierFunc func(ctx context.Context, run *PipelineResult) error
+
+func (f notifierFunc) Notify(ctx context.Context, run *PipelineResult) error {
+	return f(ctx, run)
+}
+
+func TestPipelineLeaseTakeOver(t *testing.T) {
+	memory := NewMemoryPipelineStore()
+	testPipelineLeaseTakeOver(t, memory, memory)
+
+	// Replicas sharing a directory each open their own file store
+	dir := t.TempDir()
+	a, err := NewFilePipelineStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	b, err := NewFilePipelineStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	testPipelineLeaseTakeOver(t, a, b)
+}
+
+// testPipelineLeaseTakeOver has replica b take over the run of replica a,
+// each reaching the shared checkpoints through its own store
+func testPipelineLeaseTakeOver(t *testing.T, store, replicaStore PipelineLeaseStore) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	// Replica a submits a task, then dies before its lease expires
+	req := &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}
+	resp, err := client.CreateGeneration(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	lease, err := store.AcquireLease(resp.TaskID, "a", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to acquire lease: %v", err)
+	}
+	stale := &PipelineCheckpoint{TaskID: resp.TaskID, Request: req, Completed: PipelineStageSubmit, LeaseToken: lease.Token}
+	if err := store.SaveCheckpoint(stale); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	archive := NewMemoryArchive()
+	replica := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		ArchiveTo(archive).
+		CheckpointTo(replicaStore).
+		Lease("b", time.Minute)
+	if run := replica.Resume(ctx, resp.TaskID); !errors.Is(run.Err, ErrLeaseHeld) {
+		t.Errorf("Expected ErrLeaseHeld while a holds the lease, got %v", run.Err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	runs, err := replica.TakeOver(ctx)
+	if err != nil {
+		t.Fatalf("Failed to take over: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Err != nil || runs[0].TaskID != resp.TaskID || runs[0].Stage != PipelineStageArchive {
+		t.Fatalf("Expected the run of a to be finished by b, got %+v", runs)
+	}
+	if _, ok := archive.Archived(resp.TaskID); !ok {
+		t.Error("Expected the taken over run to be archived")
+	}
+
+	// a wakes up: its checkpoints are fenced off
+	stale.Completed = PipelineStageWait
+	if err := store.SaveCheckpoint(stale); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Expected ErrLeaseLost for a stale lease token, got %v", err)
+	}
+	if expired, _ := store.ExpiredLeases(); len(expired) != 0 {
+		t.Errorf("Expected finished runs to release their lease, got %v", expired)
+	}
+	if runs, _ := replica.TakeOver(ctx); len(runs) != 0 {
+		t.Errorf("Expected nothing left to take over, got %+v", runs)
+	}
+	if again, err := store.AcquireLease(resp.TaskID, "a", time.Minute); err != nil || again.Token <= lease.Token+1 {
+		t.Errorf("Expected a released lease to be granted with a newer token than %d, got %+v (%v)", lease.Token+1, again, err)
+	}
+}
+
+func TestPipelineLeaseRenewal(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+	req := &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}
+
+	// A run outliving its ttl keeps its lease by renewing it
+	store := NewMemoryPipelineStore()
+	other := NewPipeline(client).CheckpointTo(store).Lease("b", time.Minute)
+	var contended error
+	run := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		CheckpointTo(store).
+		Lease("a", 30*time.Millisecond).
+		After(PipelineStageWait, func(ctx context.Context, stage PipelineStage, run *PipelineResult) error {
+			time.Sleep(100 * time.Millisecond)
+			contended = other.Resume(ctx, run.TaskID).Err
+			return nil
+		}).
+		Run(ctx, req)
+	if run.Err != nil {
+		t.Fatalf("Expected the renewed run to finish, got %s (%v)", run.Stage, run.Err)
+	}
+	if !errors.Is(contended, ErrLeaseHeld) {
+		t.Errorf("Expected ErrLeaseHeld while the run is renewed, got %v", contended)
+	}
+
+	// A run that cannot renew stops without notifying
+	notified := false
+	lost := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		CheckpointTo(stalledLeaseStore{NewMemoryPipelineStore()}).
+		Lease("a", 30*time.Millisecond).
+		After(PipelineStageWait, func(ctx context.Context, stage PipelineStage, run *PipelineResult) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Notify(notifierFunc(func(ctx context.Context, run *PipelineResult) error {
+			notified = true
+			return nil
+		})).
+		Run(ctx, req)
+	if !errors.Is(lost.Err, ErrLeaseLost) || notified {
+		t.Errorf("Expected ErrLeaseLost without a notification, got %v (notified=%v)", lost.Err, notified)
+	}
+
+	plain := struct{ PipelineStore }{NewMemoryPipelineStore()}
+	if run := NewPipeline(client).CheckpointTo(plain).Lease("a", time.Minute).Run(ctx, req); !errors.Is(run.Err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration for a store without leases, got %v", run.Err)
+	}
+
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if run := NewPipeline(client).CheckpointTo(NewMemoryPipelineStore()).Lease("a", ttl).Run(ctx, req); !errors.Is(run.Err, ErrInvalidConfiguration) {
+			t.Errorf("Expected ErrInvalidConfiguration for ttl %s, got %v", ttl, run.Err)
+		}
+	}
+	if _, err := NewPipeline(client).CheckpointTo(NewMemoryPipelineStore()).Lease("a", 0).TakeOver(ctx); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration from TakeOver, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package vidgo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PipelineCheckpointVersion is the schema version of the checkpoints
+// FilePipelineStore saves. Checkpoints of older versions are migrated when
+// loaded; FilePipelineStore.Migrate rewrites them in place.
+const PipelineCheckpointVersion = 2
+
+// checkpointMigrations upgrade a decoded checkpoint by one version each:
+// checkpointMigrations[0] turns version 1 into version 2 and so on. Checkpoints
+// saved before versioning have no version field and are version 1.
+var checkpointMigrations = []func(checkpoint map[string]json.RawMessage) error{
+	// 1 → 2: lease_token and replay_of were added; version 1 checkpoints had
+	// neither lease nor lineage, which their zero values express
+	func(checkpoint map[string]json.RawMessage) error { return nil },
+}
+
+// decodeCheckpoint decodes a saved checkpoint, migrating it to the current
+// version. It reports whether the checkpoint was saved by an older version.
+func decodeCheckpoint(data []byte) (*PipelineCheckpoint, bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, false, err
+	}
+
+	version := 1
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, false, fmt.Errorf("invalid version: %w", err)
+		}
+	}
+	if version < 1 || version > PipelineCheckpointVersion {
+		return nil, false, fmt.Errorf("unsupported checkpoint version %d, this vidgo reads up to %d", version, PipelineCheckpointVersion)
+	}
+
+	outdated := version < PipelineCheckpointVersion
+	if outdated {
+		for _, migrate := range checkpointMigrations[version-1:] {
+			if err := migrate(fields); err != nil {
+				return nil, false, fmt.Errorf("failed to migrate checkpoint from version %d: %w", version, err)
+			}
+		}
+		fields["version"] = json.RawMessage(fmt.Sprint(PipelineCheckpointVersion))
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, err
+	}
+	var checkpoint PipelineCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, false, err
+	}
+	return &checkpoint, outdated, nil
+}
+
+// PipelineStoreReport is the outcome of FilePipelineStore.Check
+type PipelineStoreReport struct {
+	Checkpoints int              `json:"checkpoints"`        // Readable checkpoints
+	Outdated    []string         `json:"outdated,omitempty"` // Task IDs of checkpoints of older versions, see Migrate
+	Corrupt     map[string]error `json:"-"`                  // Unreadable checkpoints by file name
+}
+
+// Check reads every checkpoint in the store, e.g. on startup, and reports
+// those saved by older versions and those that cannot be read: malformed
+// files, checkpoints of a newer version and files not named after their task.
+func (s *FilePipelineStore) Check() (*PipelineStoreReport, error) {
+	report := &PipelineStoreReport{Corrupt: make(map[string]error)}
+	err := s.walk(func(name string, checkpoint *PipelineCheckpoint, outdated bool, err error) error {
+		switch {
+		case err != nil:
+			report.Corrupt[name] = err
+		case outdated:
+			report.Checkpoints++
+			report.Outdated = append(report.Outdated, checkpoint.TaskID)
+		default:
+			report.Checkpoints++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Migrate rewrites the checkpoints of older versions at the current version and
+// returns how many it rewrote. Unreadable checkpoints are left alone; use Check
+// to find them.
+func (s *FilePipelineStore) Migrate() (int, error) {
+	migrated := 0
+	err := s.walk(func(name string, checkpoint *PipelineCheckpoint, outdated bool, err error) error {
+		if err != nil || !outdated {
+			return nil
+		}
+		unlock, err := s.lock(checkpoint.TaskID)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		if err := s.writeCheckpoint(checkpoint); err != nil {
+			return err
+		}
+		migrated++
+		return nil
+	})
+	return migrated, err
+}
+
+// walk decodes every checkpoint file in the store
+func (s *FilePipelineStore) walk(fn func(name string, checkpoint *PipelineCheckpoint, outdated bool, err error) error) error {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read pipeline store: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		// Skips the temporary files of writes in progress
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		checkpoint, outdated, err := s.readCheckpointFile(name)
+		if err := fn(name, checkpoint, outdated, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCheckpointFile decodes a checkpoint file and checks it belongs to the task it is named after
+func (s *FilePipelineStore) readCheckpointFile(name string) (*PipelineCheckpoint, bool, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		return nil, false, err
+	}
+	checkpoint, outdated, err := decodeCheckpoint(data)
+	if err != nil {
+		return nil, false, err
+	}
+	taskID, err := base64.RawURLEncoding.DecodeString(strings.TrimSuffix(name, ".json"))
+	if err != nil || string(taskID) != checkpoint.TaskID {
+		return nil, false, fmt.Errorf("file does not belong to task %q", checkpoint.TaskID)
+	}
+	return checkpoint, outdated, nil
+}
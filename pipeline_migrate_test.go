@@ -0,0 +1,53 @@
+package vidgo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFilePipelineStoreMigrate(t *testing.T) {
+	store, err := NewFilePipelineStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	write := func(taskID, data string) {
+		if err := os.WriteFile(store.path(taskID), []byte(data), 0o644); err != nil {
+			t.Fatalf("Failed to write checkpoint: %v", err)
+		}
+	}
+	// A checkpoint saved before versioning, and files Check must flag
+	legacy := "projects/p/operations/op-1"
+	write(legacy, `{"task_id":"projects/p/operations/op-1","request":{"prompt":"A cat","duration":5,"width":1280,"height":720},"completed":"wait","updated_at":"2025-01-01T00:00:00Z"}`)
+	write("task-newer", `{"task_id":"task-newer","completed":"wait","version":99}`)
+	write("task-truncated", `{"task_id":`)
+	write("task-misnamed", `{"task_id":"task-other","completed":"wait","version":2}`)
+
+	report, err := store.Check()
+	if err != nil {
+		t.Fatalf("Failed to check store: %v", err)
+	}
+	if report.Checkpoints != 1 || len(report.Outdated) != 1 || report.Outdated[0] != legacy || len(report.Corrupt) != 3 {
+		t.Errorf("Expected one outdated and three corrupt checkpoints, got %+v", report)
+	}
+
+	checkpoint, ok, err := store.LoadCheckpoint(legacy)
+	if err != nil || !ok || checkpoint.Version != PipelineCheckpointVersion || checkpoint.Completed != PipelineStageWait || checkpoint.Request.Prompt != "A cat" {
+		t.Errorf("Expected the legacy checkpoint to load at the current version, got %+v (%v)", checkpoint, err)
+	}
+	if _, _, err := store.LoadCheckpoint("task-newer"); err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("Expected a checkpoint of a newer version to be rejected, got %v", err)
+	}
+
+	migrated, err := store.Migrate()
+	if err != nil || migrated != 1 {
+		t.Fatalf("Expected one checkpoint migrated, got %d (%v)", migrated, err)
+	}
+	if data, _ := os.ReadFile(store.path(legacy)); !strings.Contains(string(data), fmt.Sprintf(`"version":%d`, PipelineCheckpointVersion)) {
+		t.Errorf("Expected the migrated file to record its version, got %s", data)
+	}
+	if report, _ := store.Check(); len(report.Outdated) != 0 || report.Checkpoints != 1 {
+		t.Errorf("Expected nothing left to migrate, got %+v", report)
+	}
+}
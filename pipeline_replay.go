@@ -0,0 +1,54 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+)
+
+// Replay submits the request of a checkpointed task again as a new run, e.g. to
+// retry a failed or poor generation with another model or seed. override, if
+// set, changes a copy of the stored request before it is submitted; the
+// original ClientTaskID is dropped since it names the original task. The new
+// run records the task it replays in PipelineResult.ReplayOf and its
+// checkpoints. To replay on another provider, call Replay on a pipeline with a
+// client for that provider and the same checkpoint store.
+func (p *Pipeline) Replay(ctx context.Context, taskID string, override func(req *GenerationRequest)) *PipelineResult {
+	run := &PipelineResult{ReplayOf: taskID}
+	if p.store == nil {
+		run.Err = fmt.Errorf("%w: pipeline has no checkpoint store", ErrInvalidConfiguration)
+		return run
+	}
+
+	checkpoint, ok, err := p.store.LoadCheckpoint(taskID)
+	switch {
+	case err != nil:
+		run.Err = err
+		return run
+	case !ok || checkpoint.Request == nil:
+		run.Err = &TaskNotFoundError{TaskID: taskID, Message: "no pipeline checkpoint"}
+		return run
+	}
+
+	req := cloneRequest(checkpoint.Request)
+	req.ClientTaskID = ""
+	if override != nil {
+		override(req)
+	}
+	p.runFrom(ctx, req, run, 0)
+	return run
+}
+
+// cloneRequest copies req so changing the copy's slices and metadata leaves req as is
+func cloneRequest(req *GenerationRequest) *GenerationRequest {
+	clone := *req
+	clone.Images = append([]string(nil), req.Images...)
+	clone.Keyframes = append([]Keyframe(nil), req.Keyframes...)
+	clone.ImageBytes = append([]byte(nil), req.ImageBytes...)
+	if req.Metadata != nil {
+		clone.Metadata = make(map[string]interface{}, len(req.Metadata))
+		for k, v := range req.Metadata {
+			clone.Metadata[k] = v
+		}
+	}
+	return &clone
+}
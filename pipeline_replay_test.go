@@ -0,0 +1,76 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestPipelineReplay(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	checkpoints, err := NewFilePipelineStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	pipeline := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		CheckpointTo(checkpoints)
+
+	seed := 1
+	original := &GenerationRequest{
+		Prompt:       "A cat",
+		ImageBytes:   testPNG(640, 360),
+		Duration:     5,
+		Width:        1280,
+		Height:       720,
+		Seed:         &seed,
+		ClientTaskID: "order-1",
+		Metadata:     map[string]interface{}{"cfg_scale": 0.5},
+	}
+	run := pipeline.Run(ctx, original)
+	if run.Err != nil {
+		t.Fatalf("Pipeline failed at %s: %v", run.Stage, run.Err)
+	}
+
+	replayed := pipeline.Replay(ctx, run.TaskID, func(req *GenerationRequest) {
+		reseed := 2
+		req.Seed = &reseed
+		req.Model = "kling-v1-6"
+		req.Metadata["cfg_scale"] = 0.8
+	})
+	if replayed.Err != nil {
+		t.Fatalf("Replay failed at %s: %v", replayed.Stage, replayed.Err)
+	}
+	if replayed.TaskID == run.TaskID || replayed.ReplayOf != run.TaskID {
+		t.Errorf("Expected a new task replaying %s, got %+v", run.TaskID, replayed)
+	}
+
+	checkpoint, ok, err := checkpoints.LoadCheckpoint(replayed.TaskID)
+	if err != nil || !ok {
+		t.Fatalf("Expected a checkpoint of the replay, got %v", err)
+	}
+	req := checkpoint.Request
+	if checkpoint.ReplayOf != run.TaskID || req.Prompt != "A cat" || *req.Seed != 2 || req.Model != "kling-v1-6" || req.ClientTaskID != "" {
+		t.Errorf("Expected the overridden request with its lineage, got %+v (%+v)", checkpoint, req)
+	}
+	if !strings.HasPrefix(req.Image, "data:image/png;base64,") {
+		t.Errorf("Expected the image bytes to be kept, got %.40q", req.Image)
+	}
+	if stored, _, _ := checkpoints.LoadCheckpoint(run.TaskID); stored.Request.Metadata["cfg_scale"] != 0.5 || *stored.Request.Seed != 1 {
+		t.Errorf("Expected the original request to be unchanged, got %+v", stored.Request)
+	}
+	if resumed := pipeline.Resume(ctx, replayed.TaskID); resumed.ReplayOf != run.TaskID {
+		t.Errorf("Expected resumed replays to keep their lineage, got %q", resumed.ReplayOf)
+	}
+
+	if missing := pipeline.Replay(ctx, "unknown", nil); !errors.Is(missing.Err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", missing.Err)
+	}
+}
@@ -0,0 +1,201 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestPipelineRun(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var notifications []map[string]interface{}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		notifications = append(notifications, payload)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	watermark := func(ctx context.Context, result *TaskResult) (*TaskResult, error) {
+		processed := *result
+		processed.URL = result.URL + "?watermarked"
+		return &processed, nil
+	}
+	store := NewMemoryArchive()
+	pipeline := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond, Timeout: 5 * time.Second}).
+		Postprocess(watermark).
+		ArchiveTo(store).
+		Notify(&WebhookNotifier{URL: hook.URL})
+
+	run := pipeline.Run(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if run.Err != nil {
+		t.Fatalf("Pipeline failed at %s: %v", run.Stage, run.Err)
+	}
+	if run.Stage != PipelineStageNotify || !strings.HasSuffix(run.Result.URL, "?watermarked") {
+		t.Errorf("Unexpected pipeline result: %+v", run)
+	}
+	if archived, ok := store.Archived(run.TaskID); !ok || archived.URL != run.Result.URL {
+		t.Errorf("Expected the postprocessed result to be archived, got %+v", archived)
+	}
+
+	// A failing stage stops the pipeline but is still notified
+	failing := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		Postprocess(func(ctx context.Context, result *TaskResult) (*TaskResult, error) {
+			return nil, errors.New("trim failed")
+		}).
+		ArchiveTo(store).
+		Notify(&WebhookNotifier{URL: hook.URL})
+	run = failing.Run(context.Background(), &GenerationRequest{Prompt: "A dog", Duration: 5, Width: 1280, Height: 720})
+	if run.Err == nil || run.Stage != PipelineStagePostprocess {
+		t.Errorf("Expected a postprocess failure, got %s (%v)", run.Stage, run.Err)
+	}
+	if _, ok := store.Archived(run.TaskID); ok {
+		t.Error("Expected a failed run not to be archived")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notifications) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(notifications))
+	}
+	if notifications[0]["stage"] != "archive" || notifications[1]["error"] != "trim failed" {
+		t.Errorf("Unexpected notifications: %v", notifications)
+	}
+}
+
+// flakyArchive fails its first Archive call
+type flakyArchive struct {
+	*MemoryArchive
+	failed bool
+}
+
+func (a *flakyArchive) Archive(result *TaskResult) error {
+	if !a.failed {
+		a.failed = true
+		return errors.New("storage unavailable")
+	}
+	return a.MemoryArchive.Archive(result)
+}
+
+func TestPipelineResumeAndHooks(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+
+	postprocessed, invoiced := 0, 0
+	store := &flakyArchive{MemoryArchive: NewMemoryArchive()}
+	checkpoints := NewMemoryPipelineStore()
+	pipeline := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		Postprocess(func(ctx context.Context, result *TaskResult) (*TaskResult, error) {
+			postprocessed++
+			return result, nil
+		}).
+		ArchiveTo(store).
+		CheckpointTo(checkpoints).
+		After(PipelineStageArchive, func(ctx context.Context, stage PipelineStage, run *PipelineResult) error {
+			invoiced++
+			return nil
+		})
+
+	ctx := context.Background()
+	run := pipeline.Run(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if run.Err == nil || run.Stage != PipelineStageArchive {
+		t.Fatalf("Expected the archive stage to fail, got %s (%v)", run.Stage, run.Err)
+	}
+	if checkpoint, ok, _ := checkpoints.LoadCheckpoint(run.TaskID); !ok || checkpoint.Completed != PipelineStagePostprocess {
+		t.Fatalf("Expected a postprocess checkpoint, got %+v", checkpoint)
+	}
+
+	tasks := fake.Tasks()
+	resumed := pipeline.Resume(ctx, run.TaskID)
+	if resumed.Err != nil || resumed.Stage != PipelineStageArchive {
+		t.Fatalf("Expected the resumed run to finish, got %s (%v)", resumed.Stage, resumed.Err)
+	}
+	if fake.Tasks() != tasks || postprocessed != 1 {
+		t.Errorf("Expected resume to skip finished stages, got %d new tasks and %d postprocess runs", fake.Tasks()-tasks, postprocessed)
+	}
+	if _, ok := store.Archived(run.TaskID); !ok || invoiced != 1 {
+		t.Errorf("Expected the result archived and invoiced once, got invoiced=%d", invoiced)
+	}
+
+	if again := pipeline.Resume(ctx, run.TaskID); again.Err != nil || invoiced != 1 {
+		t.Errorf("Expected resuming a finished run to do nothing, got %v, invoiced=%d", again.Err, invoiced)
+	}
+	if missing := pipeline.Resume(ctx, "unknown"); !errors.Is(missing.Err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound, got %v", missing.Err)
+	}
+
+	// A before hook can veto a stage
+	vetoed := NewPipeline(client).Before(PipelineStageSubmit, func(ctx context.Context, stage PipelineStage, run *PipelineResult) error {
+		return errors.New("budget exceeded")
+	})
+	if run := vetoed.Run(ctx, &GenerationRequest{Prompt: "A dog", Duration: 5, Width: 1280, Height: 720}); run.Err == nil || run.TaskID != "" {
+		t.Errorf("Expected the before hook to stop the submission, got %+v", run)
+	}
+}
+
+func TestFilePipelineStoreResume(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+
+	dir := filepath.Join(t.TempDir(), "checkpoints")
+	checkpoints, err := NewFilePipelineStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	archive := &flakyArchive{MemoryArchive: NewMemoryArchive()}
+	ctx := context.Background()
+	run := NewPipeline(client).
+		Wait(WaitOptions{PollInterval: time.Millisecond}).
+		ArchiveTo(archive).
+		CheckpointTo(checkpoints).
+		Run(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if run.Err == nil || run.Stage != PipelineStageArchive {
+		t.Fatalf("Expected the archive stage to fail, got %s (%v)", run.Stage, run.Err)
+	}
+
+	// A new process resumes from the files alone
+	reopened, err := NewFilePipelineStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	tasks := fake.Tasks()
+	resumed := NewPipeline(client).ArchiveTo(archive).CheckpointTo(reopened).Resume(ctx, run.TaskID)
+	if resumed.Err != nil || resumed.Stage != PipelineStageArchive {
+		t.Fatalf("Expected the resumed run to finish, got %s (%v)", resumed.Stage, resumed.Err)
+	}
+	if fake.Tasks() != tasks {
+		t.Errorf("Expected resume not to submit again, got %d new tasks", fake.Tasks()-tasks)
+	}
+	if archived, ok := archive.Archived(run.TaskID); !ok || archived.URL == "" {
+		t.Errorf("Expected the stored result to be archived, got %+v", archived)
+	}
+	if checkpoint, ok, err := reopened.LoadCheckpoint(run.TaskID); err != nil || !ok || checkpoint.Completed != PipelineStageArchive || checkpoint.Request.Prompt != "A cat" {
+		t.Errorf("Expected an archive checkpoint with the request, got %+v (%v)", checkpoint, err)
+	}
+	if _, ok, err := reopened.LoadCheckpoint("projects/p/operations/unknown"); ok || err != nil {
+		t.Errorf("Expected no checkpoint for an unknown task, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package vidgo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxPlannerSamples bounds the latency history kept per provider/model
+const maxPlannerSamples = 1000
+
+// Price describes how a model is billed
+type Price struct {
+	PerSecond     float64 `json:"per_second"`
+	ProMultiplier float64 `json:"pro_multiplier,omitempty"` // Applied when the request resolves to pro mode
+	Currency      string  `json:"currency,omitempty"`
+}
+
+// LatencyEstimate is a predicted latency distribution
+type LatencyEstimate struct {
+	P50     time.Duration `json:"p50"`
+	P90     time.Duration `json:"p90"`
+	P99     time.Duration `json:"p99"`
+	Samples int           `json:"samples"`
+}
+
+// Estimate is the predicted outcome of a request that has not been submitted
+type Estimate struct {
+	Provider ProviderType    `json:"provider"`
+	Model    string          `json:"model"`
+	Latency  LatencyEstimate `json:"latency"`
+	Cost     float64         `json:"cost"`
+	Currency string          `json:"currency,omitempty"`
+}
+
+// Planner estimates generation latency and cost from historical stats without submitting tasks.
+// It is safe for concurrent use.
+type Planner struct {
+	mu      sync.RWMutex
+	prices  map[string]Price
+	samples map[string][]time.Duration
+}
+
+// NewPlanner creates a planner with a price table keyed by model name
+func NewPlanner(prices map[string]Price) *Planner {
+	p := &Planner{
+		prices:  make(map[string]Price, len(prices)),
+		samples: make(map[string][]time.Duration),
+	}
+	for model, price := range prices {
+		p.prices[model] = price
+	}
+	return p
+}
+
+// Record adds an observed end-to-end latency for a provider/model
+func (p *Planner) Record(provider ProviderType, model string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := plannerKey(provider, model)
+	samples := append(p.samples[key], latency)
+	if len(samples) > maxPlannerSamples {
+		samples = samples[len(samples)-maxPlannerSamples:]
+	}
+	p.samples[key] = samples
+}
+
+// Estimate predicts latency and cost for a request on the given provider
+func (p *Planner) Estimate(provider ProviderType, req *GenerationRequest) (*Estimate, error) {
+	if req == nil {
+		return nil, &ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+	if req.Duration <= 0 {
+		return nil, &ValidationError{Field: "duration", Message: "duration must be positive"}
+	}
+
+	p.mu.RLock()
+	samples := append([]time.Duration(nil), p.samples[plannerKey(provider, req.Model)]...)
+	price, hasPrice := p.prices[req.Model]
+	p.mu.RUnlock()
+
+	estimate := &Estimate{
+		Provider: provider,
+		Model:    req.Model,
+		Latency:  latencyEstimate(samples),
+	}
+
+	if hasPrice {
+		cost := price.PerSecond * req.Duration
+		if requestMode(req) == ModePro && price.ProMultiplier > 0 {
+			cost *= price.ProMultiplier
+		}
+		estimate.Cost = cost
+		estimate.Currency = price.Currency
+	}
+
+	return estimate, nil
+}
+
+// latencyEstimate computes percentiles over the samples
+func latencyEstimate(samples []time.Duration) LatencyEstimate {
+	if len(samples) == 0 {
+		return LatencyEstimate{}
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	percentile := func(q float64) time.Duration {
+		return samples[int(q*float64(len(samples)-1))]
+	}
+
+	return LatencyEstimate{
+		P50:     percentile(0.50),
+		P90:     percentile(0.90),
+		P99:     percentile(0.99),
+		Samples: len(samples),
+	}
+}
+
+// plannerKey builds the stats key for a provider/model
+func plannerKey(provider ProviderType, model string) string {
+	return string(provider) + "/" + model
+}
@@ -0,0 +1,48 @@
+package vidgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlannerEstimate(t *testing.T) {
+	planner := NewPlanner(map[string]Price{
+		"kling-v2-master": {PerSecond: 0.2, ProMultiplier: 2, Currency: "USD"},
+	})
+
+	for i := 1; i <= 10; i++ {
+		planner.Record(ProviderKling, "kling-v2-master", time.Duration(i)*time.Minute)
+	}
+
+	estimate, err := planner.Estimate(ProviderKling, &GenerationRequest{
+		Prompt:   "Test prompt",
+		Duration: 10,
+		Model:    "kling-v2-master",
+		Metadata: map[string]interface{}{"mode": "pro"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to estimate: %v", err)
+	}
+
+	if estimate.Latency.Samples != 10 {
+		t.Errorf("Expected 10 samples, got %d", estimate.Latency.Samples)
+	}
+	if estimate.Latency.P50 != 5*time.Minute {
+		t.Errorf("Expected p50 of 5m, got %v", estimate.Latency.P50)
+	}
+	if estimate.Latency.P99 != 9*time.Minute {
+		t.Errorf("Expected p99 of 9m, got %v", estimate.Latency.P99)
+	}
+	if estimate.Cost != 4 || estimate.Currency != "USD" {
+		t.Errorf("Expected cost 4 USD, got %v %s", estimate.Cost, estimate.Currency)
+	}
+
+	// Unknown models have no history and no price
+	estimate, err = planner.Estimate(ProviderKling, &GenerationRequest{Prompt: "Test prompt", Duration: 5, Model: "kling-v1"})
+	if err != nil {
+		t.Fatalf("Failed to estimate: %v", err)
+	}
+	if estimate.Latency.Samples != 0 || estimate.Cost != 0 {
+		t.Errorf("Expected empty estimate for unknown model, got %+v", estimate)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build go1.23
+
+package vidgo
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// PollOptions configures PollGeneration
+type PollOptions struct {
+	// Interval between polls. When zero, the adaptive PollPolicy for Model is used.
+	Interval time.Duration
+
+	// Model selects the adaptive polling policy, defaults to the model the task
+	// was submitted with
+	Model string
+
+	// Policy overrides the client's polling policy
+	Policy *PollPolicy
+}
+
+// PollGeneration returns an iterator that polls a generation task and yields
+// every observed result. Iteration ends after a terminal status, after an error
+// has been yielded, or when the caller breaks out of the loop.
+//
+//	for result, err := range client.PollGeneration(ctx, taskID, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(result.Status)
+//	}
+func (c *Client) PollGeneration(ctx context.Context, taskID string, opts *PollOptions) iter.Seq2[*TaskResult, error] {
+	var policy *PollPolicy
+	switch {
+	case opts != nil && opts.Interval > 0:
+		policy = &PollPolicy{Interval: opts.Interval}
+	case opts != nil && opts.Policy != nil:
+		policy = opts.Policy
+	case opts != nil && opts.Model != "":
+		policy = c.pollPolicy(opts.Model)
+	}
+
+	return func(yield func(*TaskResult, error) bool) {
+		_, err := c.poll(ctx, taskID, poller{policy: policy, onUpdate: func(result *TaskResult) bool {
+			return yield(result, nil)
+		}})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
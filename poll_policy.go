@@ -0,0 +1,176 @@
+package vidgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/feitianbubu/vidgo/pace"
+)
+
+// PollPolicy controls how often a task is polled. The first poll happens after
+// InitialDelay; the interval then grows by Multiplier up to MaxInterval.
+type PollPolicy struct {
+	InitialDelay time.Duration
+	Interval     time.Duration
+	Multiplier   float64
+	MaxInterval  time.Duration
+
+	// Jitter randomizes each delay by up to this fraction either way, e.g. 0.2
+	// for ±20%, so tasks submitted together do not poll in lockstep
+	Jitter float64
+}
+
+// DefaultPollPolicy returns the policy used when nothing more specific is known
+func DefaultPollPolicy() *PollPolicy {
+	return &PollPolicy{
+		InitialDelay: 5 * time.Second,
+		Interval:     5 * time.Second,
+		Multiplier:   1.5,
+		MaxInterval:  30 * time.Second,
+		Jitter:       0.2,
+	}
+}
+
+// Delay returns the wait before the given poll attempt (0-based)
+func (p *PollPolicy) Delay(attempt int) time.Duration {
+	return p.Backoff().Delay(attempt)
+}
+
+// Backoff returns the policy as a pace.Backoff
+func (p *PollPolicy) Backoff() pace.Backoff {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return pace.Backoff{
+		Initial:    p.InitialDelay,
+		Interval:   interval,
+		Multiplier: p.Multiplier,
+		Max:        p.MaxInterval,
+		Jitter:     p.Jitter,
+	}
+}
+
+// PollPolicy derives a polling policy from the recorded latencies of a provider/model.
+// It reports false when there is no history.
+func (p *Planner) PollPolicy(provider ProviderType, model string) (*PollPolicy, bool) {
+	p.mu.RLock()
+	samples := append([]time.Duration(nil), p.samples[plannerKey(provider, model)]...)
+	p.mu.RUnlock()
+
+	latency := latencyEstimate(samples)
+	if latency.Samples == 0 {
+		return nil, false
+	}
+
+	interval := latency.P50 / 20
+	if interval < 2*time.Second {
+		interval = 2 * time.Second
+	}
+	maxInterval := latency.P90 / 10
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+
+	return &PollPolicy{
+		InitialDelay: latency.P50 / 2,
+		Interval:     interval,
+		Multiplier:   1.5,
+		MaxInterval:  maxInterval,
+		Jitter:       0.2,
+	}, true
+}
+
+// pollPolicy resolves the polling policy for a model: an explicit per-model policy,
+// then one derived from the planner's history, then the configured default.
+func (c *Client) pollPolicy(model string) *PollPolicy {
+	if policy, ok := c.config.PollPolicies[model]; ok && model != "" && policy != nil {
+		return policy
+	}
+
+	if c.config.Planner != nil {
+		provider := c.registryName()
+		if policy, ok := c.config.Planner.PollPolicy(provider, model); ok {
+			return policy
+		}
+	}
+
+	if policy, ok := c.config.PollPolicies[""]; ok && policy != nil {
+		return policy
+	}
+	return DefaultPollPolicy()
+}
+
+// taskPollPolicy resolves the polling policy for a task from the model it was
+// submitted with, falling back to the default policy for unknown tasks
+func (c *Client) taskPollPolicy(taskID string) *PollPolicy {
+	return c.pollPolicy(c.metrics.model(taskID))
+}
+
+// poller configures poll
+type poller struct {
+	// policy paces the polls, defaults to taskPollPolicy
+	policy *PollPolicy
+
+	// immediate polls once right away instead of after the policy's initial delay
+	immediate bool
+
+	// deadline, when it fires after the first poll, returns the latest result
+	deadline <-chan time.Time
+
+	// budget, when set, is waited on before each poll to share a rate limit
+	budget <-chan time.Time
+
+	// onUpdate sees every polled result; returning false stops polling
+	onUpdate func(result *TaskResult) bool
+}
+
+// poll polls a task until it reaches a terminal status and returns the latest
+// result it observed along with any error. When ctx is done the failure is
+// recorded in the task's timeline and ctx.Err() is returned.
+func (c *Client) poll(ctx context.Context, taskID string, p poller, opts ...CallOption) (*TaskResult, error) {
+	policy := p.policy
+	if policy == nil {
+		policy = c.taskPollPolicy(taskID)
+	}
+	delay := policy.Delay(0)
+	if p.immediate {
+		delay = 0
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var last *TaskResult
+	var deadline <-chan time.Time
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			c.recordTimeline(taskID, failureEvent(ctx.Err(), 0))
+			return last, ctx.Err()
+		case <-deadline:
+			return last, nil
+		case <-timer.C:
+		}
+		if p.budget != nil {
+			select {
+			case <-ctx.Done():
+				c.recordTimeline(taskID, failureEvent(ctx.Err(), 0))
+				return last, ctx.Err()
+			case <-p.budget:
+			}
+		}
+
+		result, err := c.GetGeneration(ctx, taskID, opts...)
+		if err != nil {
+			return last, err
+		}
+		last, deadline = result, p.deadline
+		if p.onUpdate != nil && !p.onUpdate(result) {
+			return result, nil
+		}
+		if !taskPending(result.Status) {
+			return result, nil
+		}
+		timer.Reset(policy.Delay(attempt))
+	}
+}
@@ -0,0 +1,98 @@
+package vidgo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPollPolicyDelay(t *testing.T) {
+	policy := &PollPolicy{
+		InitialDelay: time.Minute,
+		Interval:     10 * time.Second,
+		Multiplier:   2,
+		MaxInterval:  30 * time.Second,
+	}
+
+	expected := []time.Duration{time.Minute, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second}
+	for attempt, want := range expected {
+		if got := policy.Delay(attempt); got != want {
+			t.Errorf("Expected delay %v for attempt %d, got %v", want, attempt, got)
+		}
+	}
+}
+
+func TestClientPollPolicyResolution(t *testing.T) {
+	planner := NewPlanner(nil)
+	for i := 0; i < 10; i++ {
+		planner.Record(ProviderKling, "kling-v2-master", 4*time.Minute)
+	}
+
+	explicit := &PollPolicy{Interval: time.Second}
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"}, &ClientConfig{
+		Timeout:      time.Second,
+		PollPolicies: map[string]*PollPolicy{"kling-v1": explicit},
+		Planner:      planner,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.pollPolicy("kling-v1") != explicit {
+		t.Error("Expected explicit per-model policy")
+	}
+	if policy := client.pollPolicy("kling-v2-master"); policy.InitialDelay != 2*time.Minute {
+		t.Errorf("Expected planner-derived initial delay of 2m, got %v", policy.InitialDelay)
+	}
+	if policy := client.pollPolicy("kling-v1-6"); policy.Interval != DefaultPollPolicy().Interval {
+		t.Errorf("Expected default policy, got %+v", policy)
+	}
+}
+
+func TestPollPolicyJitter(t *testing.T) {
+	policy := &PollPolicy{Interval: 10 * time.Second, Multiplier: 2, MaxInterval: 30 * time.Second, Jitter: 0.2}
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		delay := policy.Delay(3)
+		if delay < 24*time.Second || delay > 36*time.Second {
+			t.Fatalf("Expected delay within 20%% of 30s, got %v", delay)
+		}
+		seen[delay] = true
+	}
+	if len(seen) < 2 {
+		t.Error("Expected jittered delays to vary")
+	}
+}
+
+func TestPollFollowsTaskModel(t *testing.T) {
+	fast := &PollPolicy{InitialDelay: 10 * time.Millisecond, Interval: 10 * time.Millisecond}
+	config := DefaultClientConfig()
+	config.PollPolicies = map[string]*PollPolicy{"": {InitialDelay: time.Hour}, "fast": fast}
+	client := NewClientWithProvider(&sloProvider{status: TaskStatusSucceeded}, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "a cat", Model: "fast", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if policy := client.taskPollPolicy(resp.TaskID); policy != fast {
+		t.Fatalf("Expected the policy of the submitted model, got %+v", policy)
+	}
+
+	updates, err := client.WatchGeneration(ctx, resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to watch generation: %v", err)
+	}
+	var last TaskUpdate
+	for update := range updates {
+		last = update
+	}
+	if last.Status != TaskStatusSucceeded || last.Err != nil {
+		t.Errorf("Expected the watch to follow the fast policy to success, got %+v", last)
+	}
+	if client.taskPollPolicy(resp.TaskID) == fast {
+		t.Error("Expected the task model to be forgotten once finished")
+	}
+}
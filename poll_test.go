@@ -0,0 +1,63 @@
+//go:build go1.23
+
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPollGeneration(t *testing.T) {
+	statuses := []string{"submitted", "processing", "succeed"}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[calls]
+		if calls < len(statuses)-1 {
+			calls++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"` + status + `"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var seen []TaskStatus
+	for result, err := range client.PollGeneration(context.Background(), "task-1", &PollOptions{Interval: time.Millisecond}) {
+		if err != nil {
+			t.Fatalf("Unexpected poll error: %v", err)
+		}
+		seen = append(seen, result.Status)
+	}
+
+	expected := []TaskStatus{TaskStatusQueued, TaskStatusProcessing, TaskStatusSucceeded}
+	if len(seen) != len(expected) {
+		t.Fatalf("Expected statuses %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Errorf("Expected status %d to be '%s', got '%s'", i, expected[i], seen[i])
+		}
+	}
+
+	// Breaking early must stop polling
+	calls = 0
+	count := 0
+	for range client.PollGeneration(context.Background(), "task-1", &PollOptions{Interval: time.Millisecond}) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expected a single iteration after break, got %d", count)
+	}
+}
@@ -0,0 +1,181 @@
+package vidgo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for DecodeConfig
+	_ "image/jpeg" // register JPEG for DecodeConfig
+	_ "image/png"  // register PNG for DecodeConfig
+	"strings"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Capabilities describes what a provider supports
+type Capabilities = adapters.Capabilities
+
+// ImageConstraints describes the input images a provider accepts
+type ImageConstraints = adapters.ImageConstraints
+
+// Combination is one valid choice of model, mode, duration and aspect ratio
+type Combination = adapters.Combination
+
+// CapabilityMatrix lists the valid combinations of a provider, see Capabilities.Matrix
+type CapabilityMatrix = adapters.CapabilityMatrix
+
+// Capabilities returns the capabilities of the current provider, or nil if unknown
+func (c *Client) Capabilities() *Capabilities {
+	if provider, ok := c.current().(adapters.CapabilitiesProvider); ok {
+		return provider.Capabilities()
+	}
+	return nil
+}
+
+// preflightImage checks the locally available images of a request against the
+// provider constraints. URL images are skipped since they are fetched by the provider.
+func (c *Client) preflightImage(req *GenerationRequest) error {
+	caps := c.Capabilities()
+	if caps == nil || caps.Image == nil {
+		return nil
+	}
+
+	for _, image := range localImages(req) {
+		if err := checkImage(image.data, caps.Image, image.field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PreflightImage validates image data against constraints by decoding only its header
+func PreflightImage(data []byte, constraints *ImageConstraints) error {
+	return checkImage(data, constraints, "image")
+}
+
+// checkImage implements PreflightImage, reporting errors under field, e.g. "image_tail.size"
+func checkImage(data []byte, constraints *ImageConstraints, field string) error {
+	if constraints == nil {
+		return nil
+	}
+
+	if constraints.MaxBytes > 0 && int64(len(data)) > constraints.MaxBytes {
+		return &ValidationError{
+			Field:   field + ".size",
+			Message: fmt.Sprintf("image is %d bytes, maximum is %d bytes", len(data), constraints.MaxBytes),
+		}
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return &ValidationError{Field: field + ".format", Message: "unable to decode image header: " + err.Error()}
+	}
+
+	if len(constraints.Formats) > 0 {
+		supported := false
+		for _, f := range constraints.Formats {
+			if f == format {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return &ValidationError{
+				Field:   field + ".format",
+				Message: fmt.Sprintf("image format %s is not supported, expected one of %s", format, strings.Join(constraints.Formats, ", ")),
+			}
+		}
+	}
+
+	if config.Width < constraints.MinWidth || config.Height < constraints.MinHeight {
+		return &ValidationError{
+			Field:   field + ".resolution",
+			Message: fmt.Sprintf("image is %dx%d, minimum is %dx%d", config.Width, config.Height, constraints.MinWidth, constraints.MinHeight),
+		}
+	}
+	if (constraints.MaxWidth > 0 && config.Width > constraints.MaxWidth) || (constraints.MaxHeight > 0 && config.Height > constraints.MaxHeight) {
+		return &ValidationError{
+			Field:   field + ".resolution",
+			Message: fmt.Sprintf("image is %dx%d, maximum is %dx%d", config.Width, config.Height, constraints.MaxWidth, constraints.MaxHeight),
+		}
+	}
+
+	if config.Height > 0 {
+		ratio := float64(config.Width) / float64(config.Height)
+		if (constraints.MinAspectRatio > 0 && ratio < constraints.MinAspectRatio) || (constraints.MaxAspectRatio > 0 && ratio > constraints.MaxAspectRatio) {
+			return &ValidationError{
+				Field:   field + ".aspect_ratio",
+				Message: fmt.Sprintf("image aspect ratio %.2f is outside %.2f-%.2f", ratio, constraints.MinAspectRatio, constraints.MaxAspectRatio),
+			}
+		}
+	}
+
+	return nil
+}
+
+// localImage is a locally available request image and the field it came from
+type localImage struct {
+	field string
+	data  []byte
+
+	// replace stores a re-encoded image in the field of a request copy
+	replace func(req *GenerationRequest, data []byte, contentType string)
+}
+
+// localImages returns the request images that are not URLs: the main image, the
+// end frame, reference images and keyframes
+func localImages(req *GenerationRequest) []localImage {
+	var images []localImage
+	if data, ok := localImageData(req); ok {
+		images = append(images, localImage{"image", data, func(req *GenerationRequest, data []byte, _ string) {
+			req.Image = ""
+			req.ImageBytes = data
+		}})
+	}
+	if data, ok := decodeLocalImage(req.ImageTail); ok {
+		images = append(images, localImage{"image_tail", data, func(req *GenerationRequest, data []byte, contentType string) {
+			req.ImageTail = dataURI(data, contentType)
+		}})
+	}
+	for i, image := range req.Images {
+		if data, ok := decodeLocalImage(image); ok {
+			images = append(images, localImage{fmt.Sprintf("images[%d]", i), data, func(req *GenerationRequest, data []byte, contentType string) {
+				req.Images[i] = dataURI(data, contentType)
+			}})
+		}
+	}
+	for i, keyframe := range req.Keyframes {
+		if data, ok := decodeLocalImage(keyframe.Image); ok {
+			images = append(images, localImage{fmt.Sprintf("keyframes[%d]", i), data, func(req *GenerationRequest, data []byte, contentType string) {
+				req.Keyframes[i].Image = dataURI(data, contentType)
+			}})
+		}
+	}
+	return images
+}
+
+// dataURI encodes image data as a data URI
+func dataURI(data []byte, contentType string) string {
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// localImageData returns the image bytes when the image is not a URL
+func localImageData(req *GenerationRequest) ([]byte, bool) {
+	if len(req.ImageBytes) > 0 {
+		return req.ImageBytes, true
+	}
+	return decodeLocalImage(req.Image)
+}
+
+// decodeLocalImage decodes a data URI or Base64 image, reporting false for URLs
+func decodeLocalImage(image string) ([]byte, bool) {
+	if data, _, ok := adapters.DecodeDataURI(image); ok {
+		return data, true
+	}
+	if image == "" || strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(image)
+	return data, err == nil
+}
@@ -0,0 +1,58 @@
+package vidgo
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestPreflightImage(t *testing.T) {
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		image []byte
+		field string
+	}{
+		{"valid", testPNG(512, 512), ""},
+		{"too small", testPNG(200, 512), "image.resolution"},
+		{"extreme aspect", testPNG(1600, 400), "image.aspect_ratio"},
+		{"not an image", []byte("plain text"), "image.format"},
+	}
+
+	for _, tt := range tests {
+		req := &GenerationRequest{ImageBytes: tt.image, Duration: 5, Width: 512, Height: 512}
+		err := client.validateRequest(req)
+
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", tt.name, err)
+			}
+			continue
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+			t.Errorf("%s: expected validation error for '%s', got %v", tt.name, tt.field, err)
+		}
+	}
+
+	var validationErr *ValidationError
+	tail := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(200, 512))
+	if err := client.validateRequest(&GenerationRequest{Image: "https://example.com/a.png", ImageTail: tail, Duration: 5, Width: 512, Height: 512}); !errors.As(err, &validationErr) || validationErr.Field != "image_tail.resolution" {
+		t.Errorf("Expected image_tail.resolution validation error, got %v", err)
+	}
+
+	big := &ImageConstraints{MaxBytes: 10}
+	if err := PreflightImage(testPNG(512, 512), big); err == nil {
+		t.Error("Image over MaxBytes should return error")
+	}
+
+	// URL images are fetched by the provider and skipped
+	if err := client.validateRequest(&GenerationRequest{Image: "https://example.com/a.png", Duration: 5, Width: 512, Height: 512}); err != nil {
+		t.Errorf("URL image should skip preflight, got %v", err)
+	}
+}
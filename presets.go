@@ -0,0 +1,43 @@
+package vidgo
+
+import "github.com/feitianbubu/vidgo/adapters"
+
+// SizePreset maps a size keyword such as "portrait" or "reel" to provider dimensions
+type SizePreset = adapters.SizePreset
+
+// RegisterSizePreset adds or replaces a size keyword for a provider.
+// An empty provider registers a default used by all providers.
+func RegisterSizePreset(provider ProviderType, name string, preset SizePreset) {
+	adapters.RegisterSizePreset(string(provider), name, preset)
+}
+
+// UnregisterSizePreset removes a size keyword registered for a provider
+func UnregisterSizePreset(provider ProviderType, name string) {
+	adapters.UnregisterSizePreset(string(provider), name)
+}
+
+// LookupSizePreset resolves a size keyword for a provider
+func LookupSizePreset(provider ProviderType, name string) (SizePreset, bool) {
+	return adapters.LookupSizePreset(string(provider), name)
+}
+
+// SizePresets returns all size keywords available to a provider
+func SizePresets(provider ProviderType) map[string]SizePreset {
+	return adapters.SizePresets(string(provider))
+}
+
+// resolveSize fills Width/Height from the Size keyword, returning a copy when it changes
+func (c *Client) resolveSize(req *GenerationRequest) (*GenerationRequest, error) {
+	if req == nil || req.Size == "" || (req.Width > 0 && req.Height > 0) {
+		return req, nil
+	}
+
+	preset, ok := LookupSizePreset(c.registryName(), req.Size)
+	if !ok {
+		return nil, &ValidationError{Field: "size", Message: "unknown size preset: " + req.Size}
+	}
+
+	resolved := *req
+	resolved.Width, resolved.Height = preset.Width, preset.Height
+	return &resolved, nil
+}
@@ -20,6 +20,44 @@ type Provider interface {
 	ValidateRequest(req *GenerationRequest) error
 }
 
+// Extender is implemented by providers that can extend generated videos
+type Extender interface {
+	ExtendGeneration(ctx context.Context, req *ExtendRequest) (*GenerationResponse, error)
+}
+
+// LipSyncer is implemented by providers that can lip-sync videos
+type LipSyncer interface {
+	CreateLipSync(ctx context.Context, req *LipSyncRequest) (*GenerationResponse, error)
+}
+
+// EffectGenerator is implemented by providers that offer preset video effects
+type EffectGenerator interface {
+	CreateEffect(ctx context.Context, req *EffectRequest) (*GenerationResponse, error)
+	SupportedEffects(model string) []string
+}
+
+// AccountInfoProvider is implemented by providers that can report their account quota
+type AccountInfoProvider interface {
+	GetQuota(ctx context.Context) (*Quota, error)
+}
+
+// ClientTaskLookup is implemented by providers that can find a task by the
+// GenerationRequest.ClientTaskID it was submitted with
+type ClientTaskLookup interface {
+	GetGenerationByClientID(ctx context.Context, clientTaskID string) (*TaskResult, error)
+}
+
+// Canceler is implemented by providers that can cancel unfinished tasks
+type Canceler interface {
+	CancelGeneration(ctx context.Context, taskID string) error
+}
+
+// BatchTaskGetter is implemented by providers that can fetch many tasks in a few
+// requests; tasks missing from the returned map are fetched one by one
+type BatchTaskGetter interface {
+	GetGenerations(ctx context.Context, taskIDs []string) (map[string]*TaskResult, error)
+}
+
 // ProviderFactory creates provider instances
 type ProviderFactory interface {
 	CreateProvider(providerType ProviderType, config *ProviderConfig) (Provider, error)
@@ -0,0 +1,31 @@
+package vidgo
+
+import (
+	"github.com/feitianbubu/vidgo/adapters"
+
+	// Built-in providers register themselves with the adapters registry
+	_ "github.com/feitianbubu/vidgo/adapters/comfyui"
+	_ "github.com/feitianbubu/vidgo/adapters/jimeng"
+	_ "github.com/feitianbubu/vidgo/adapters/kling"
+	_ "github.com/feitianbubu/vidgo/adapters/luma"
+	_ "github.com/feitianbubu/vidgo/adapters/replicate"
+	_ "github.com/feitianbubu/vidgo/adapters/veo"
+	_ "github.com/feitianbubu/vidgo/adapters/wanx"
+)
+
+// RegisterProvider makes a provider available to NewClient. Providers with heavy
+// SDK dependencies can live in their own Go module and call it from init, so
+// only programs that import them pull those dependencies in.
+func RegisterProvider(provider ProviderType, factory adapters.Factory) {
+	adapters.Register(string(provider), factory)
+}
+
+// RegisteredProviders returns the provider types NewClient can create
+func RegisteredProviders() []ProviderType {
+	names := adapters.Registered()
+	providers := make([]ProviderType, len(names))
+	for i, name := range names {
+		providers[i] = ProviderType(name)
+	}
+	return providers
+}
@@ -0,0 +1,36 @@
+package vidgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/feitianbubu/vidgo/adapters/vidu"
+)
+
+func TestRegisterProvider(t *testing.T) {
+	registered := RegisteredProviders()
+	if len(registered) != 7 || registered[0] != ProviderComfyUI {
+		t.Errorf("Expected the 7 built-in providers in sorted order, got %v", registered)
+	}
+
+	custom := ProviderType("registry-test")
+	if _, err := NewClient(custom, &ProviderConfig{}); !errors.Is(err, ErrUnsupportedProvider) {
+		t.Errorf("Expected ErrUnsupportedProvider before registration, got %v", err)
+	}
+
+	RegisterProvider(custom, vidu.New)
+	client, err := NewClient(custom, &ProviderConfig{})
+	if err != nil {
+		t.Fatalf("Failed to create client for registered provider: %v", err)
+	}
+	if client.GetProviderName() != "Vidu" {
+		t.Errorf("Expected provider name Vidu, got %s", client.GetProviderName())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a provider twice to panic")
+		}
+	}()
+	RegisterProvider(custom, vidu.New)
+}
@@ -0,0 +1,238 @@
+package vidgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// dispatchWindow is the period DispatchRate is averaged over
+	dispatchWindow = time.Minute
+
+	// pendingTTL drops submitted tasks that are never polled to completion
+	pendingTTL = 24 * time.Hour
+)
+
+// QueueMetrics is a point-in-time view of the work a client holds, for
+// autoscaling the workers that drive it
+type QueueMetrics struct {
+	Provider         ProviderType `json:"provider"`
+	Queued           int          `json:"queued"`                      // Batch and bulk requests waiting for a submission slot
+	InFlight         int          `json:"in_flight"`                   // Provider calls in progress
+	Pending          int          `json:"pending"`                     // Submitted tasks not yet seen finished
+	DispatchRate     float64      `json:"dispatch_rate"`               // Submissions per second over the last minute
+	ConcurrencyLimit int          `json:"concurrency_limit,omitempty"` // ClientConfig.ProviderConcurrency
+	Utilization      float64      `json:"utilization,omitempty"`       // Pending / ConcurrencyLimit
+	SLO              *SLOStatus   `json:"slo,omitempty"`               // Evaluation of ClientConfig.SLO, nil without one
+}
+
+// Backlog is the work waiting on the provider or on a submission slot
+func (m QueueMetrics) Backlog() int {
+	return m.Queued + m.Pending
+}
+
+// queueMetrics counts the client's backlog; the zero value is ready to use
+type queueMetrics struct {
+	queued   atomic.Int64
+	inFlight atomic.Int64
+
+	mu         sync.Mutex
+	pending    map[string]pendingTask
+	dispatches []time.Time
+}
+
+// pendingTask is a submitted task not yet seen finished
+type pendingTask struct {
+	submitted time.Time
+	model     string // Requested model, selects the PollPolicy
+}
+
+// dispatched records a submitted task
+func (m *queueMetrics) dispatched(taskID, model string) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending == nil {
+		m.pending = make(map[string]pendingTask)
+	}
+	m.pending[taskID] = pendingTask{submitted: now, model: model}
+	m.dispatches = append(m.prune(now), now)
+}
+
+// reused records a pending task shared from the ResultCache; it counts towards
+// Pending but not DispatchRate, since nothing was submitted
+func (m *queueMetrics) reused(taskID, model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending == nil {
+		m.pending = make(map[string]pendingTask)
+	}
+	if _, ok := m.pending[taskID]; !ok {
+		m.pending[taskID] = pendingTask{submitted: time.Now(), model: model}
+	}
+}
+
+// finished records that a task no longer runs at the provider and returns when
+// it was submitted, if it was still pending
+func (m *queueMetrics) finished(taskID string) (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, ok := m.pending[taskID]
+	delete(m.pending, taskID)
+	return task.submitted, ok
+}
+
+// model returns the requested model of a pending task, or "" if it is unknown
+func (m *queueMetrics) model(taskID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.pending[taskID].model
+}
+
+// prune drops dispatches outside the window and expired pending tasks; the caller holds mu
+func (m *queueMetrics) prune(now time.Time) []time.Time {
+	for taskID, task := range m.pending {
+		if now.Sub(task.submitted) > pendingTTL {
+			delete(m.pending, taskID)
+		}
+	}
+
+	cutoff := now.Add(-dispatchWindow)
+	i := 0
+	for i < len(m.dispatches) && !m.dispatches[i].After(cutoff) {
+		i++
+	}
+	return m.dispatches[i:]
+}
+
+// QueueMetrics returns the client's current backlog
+func (c *Client) QueueMetrics() QueueMetrics {
+	m := &c.metrics
+	m.mu.Lock()
+	m.dispatches = m.prune(time.Now())
+	pending, dispatches := len(m.pending), len(m.dispatches)
+	m.mu.Unlock()
+
+	provider := c.providerType
+	if provider == "" {
+		provider = c.registryName()
+	}
+
+	metrics := QueueMetrics{
+		Provider:         provider,
+		Queued:           int(m.queued.Load()),
+		InFlight:         int(m.inFlight.Load()),
+		Pending:          pending,
+		DispatchRate:     float64(dispatches) / dispatchWindow.Seconds(),
+		ConcurrencyLimit: c.config.ProviderConcurrency,
+		SLO:              c.sloStatus(),
+	}
+	if metrics.ConcurrencyLimit > 0 {
+		metrics.Utilization = float64(pending) / float64(metrics.ConcurrencyLimit)
+	}
+	return metrics
+}
+
+// Metric names served by MetricsHandler
+const (
+	MetricBacklog                = "vidgo_backlog"
+	MetricQueued                 = "vidgo_queued"
+	MetricInFlight               = "vidgo_in_flight"
+	MetricPending                = "vidgo_pending"
+	MetricDispatchRate           = "vidgo_dispatch_rate"
+	MetricConcurrencyUtilization = "vidgo_concurrency_utilization"
+	MetricSLOCompliance          = "vidgo_slo_compliance"
+	MetricSLOErrorBudget         = "vidgo_slo_error_budget_remaining"
+	MetricSLOBurnRate            = "vidgo_slo_burn_rate"
+)
+
+// ExternalMetricValueList is the Kubernetes external.metrics.k8s.io/v1beta1 list format
+type ExternalMetricValueList struct {
+	Kind       string                `json:"kind"`
+	APIVersion string                `json:"apiVersion"`
+	Metadata   struct{}              `json:"metadata"`
+	Items      []ExternalMetricValue `json:"items"`
+}
+
+// ExternalMetricValue is one metric sample; Value is a Kubernetes quantity such as "12" or "750m"
+type ExternalMetricValue struct {
+	MetricName   string            `json:"metricName"`
+	MetricLabels map[string]string `json:"metricLabels"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Value        string            `json:"value"`
+}
+
+// MetricsHandler serves the QueueMetrics of clients in the Kubernetes external
+// metrics format, for a KEDA metrics-api scaler or an HPA external metrics
+// adapter. The metric and provider query parameters narrow the list, e.g.
+// ?metric=vidgo_backlog&provider=kling.
+func MetricsHandler(clients ...*Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metric, provider := r.URL.Query().Get("metric"), r.URL.Query().Get("provider")
+
+		list := ExternalMetricValueList{Kind: "ExternalMetricValueList", APIVersion: "external.metrics.k8s.io/v1beta1", Items: []ExternalMetricValue{}}
+		now := time.Now()
+		for _, client := range clients {
+			metrics := client.QueueMetrics()
+			if provider != "" && string(metrics.Provider) != provider {
+				continue
+			}
+
+			values := []metricSample{
+				{MetricBacklog, float64(metrics.Backlog())},
+				{MetricQueued, float64(metrics.Queued)},
+				{MetricInFlight, float64(metrics.InFlight)},
+				{MetricPending, float64(metrics.Pending)},
+				{MetricDispatchRate, metrics.DispatchRate},
+			}
+			if metrics.ConcurrencyLimit > 0 {
+				values = append(values, metricSample{MetricConcurrencyUtilization, metrics.Utilization})
+			}
+			if slo := metrics.SLO; slo != nil {
+				values = append(values,
+					metricSample{MetricSLOCompliance, slo.Compliance},
+					metricSample{MetricSLOErrorBudget, slo.ErrorBudgetRemaining},
+					metricSample{MetricSLOBurnRate, slo.BurnRate},
+				)
+			}
+
+			for _, v := range values {
+				if metric != "" && v.name != metric {
+					continue
+				}
+				list.Items = append(list.Items, ExternalMetricValue{
+					MetricName:   v.name,
+					MetricLabels: map[string]string{"provider": string(metrics.Provider)},
+					Timestamp:    now,
+					Value:        quantity(v.value),
+				})
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list)
+	})
+}
+
+type metricSample struct {
+	name  string
+	value float64
+}
+
+// quantity formats v as a Kubernetes quantity, using milli-units for fractions
+func quantity(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%dm", int64(math.Round(v*1000)))
+}
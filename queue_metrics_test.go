@@ -0,0 +1,50 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestQueueMetrics(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{Timeout: 5 * time.Second, ProviderConcurrency: 4})
+	ctx := context.Background()
+	req := &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720}
+
+	batch := client.CreateGenerations(ctx, []*GenerationRequest{req, req, req}, BatchOptions{})
+	if err := batch.Err(); err != nil {
+		t.Fatalf("Failed to submit batch: %v", err)
+	}
+
+	metrics := client.QueueMetrics()
+	if metrics.Provider != ProviderKling || metrics.Pending != 3 || metrics.Queued != 0 || metrics.InFlight != 0 {
+		t.Errorf("Expected 3 pending kling tasks and nothing queued, got %+v", metrics)
+	}
+	if metrics.DispatchRate != 3/dispatchWindow.Seconds() || metrics.Utilization != 0.75 {
+		t.Errorf("Expected 3 dispatches in the window and 75%% utilization, got %+v", metrics)
+	}
+	if metrics.SLO != nil {
+		t.Errorf("Expected no SLO status without an SLO tracker, got %+v", metrics.SLO)
+	}
+
+	if _, err := client.WaitForCompletion(ctx, batch.Items[0].Response.TaskID, time.Millisecond); err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+	if metrics := client.QueueMetrics(); metrics.Pending != 2 || metrics.Backlog() != 2 {
+		t.Errorf("Expected 2 pending tasks after one finished, got %+v", metrics)
+	}
+
+	recorder := httptest.NewRecorder()
+	MetricsHandler(client).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics?metric=vidgo_concurrency_utilization", nil))
+	var list ExternalMetricValueList
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Value != "500m" || list.Items[0].MetricLabels["provider"] != "kling" {
+		t.Errorf("Expected a single 500m utilization sample for kling, got %+v", list.Items)
+	}
+}
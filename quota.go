@@ -0,0 +1,36 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Quota is the remaining balance of a provider account
+type Quota = adapters.Quota
+
+// ResourcePack is a prepaid credit package on a provider account
+type ResourcePack = adapters.ResourcePack
+
+// GetQuota returns the provider account's remaining credits and resource packs,
+// so operators can alert on a low balance before submitting tasks
+func (c *Client) GetQuota(ctx context.Context, opts ...CallOption) (*Quota, error) {
+	info, ok := c.current().(AccountInfoProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not report account quota", ErrUnsupportedOperation, c.current().Name())
+	}
+
+	o, err := c.callOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var quota *Quota
+	err = c.withRetry(c.captureContext(ctx, "get_quota", ""), o, func(ctx context.Context) error {
+		var err error
+		quota, err = info.GetQuota(ctx)
+		return err
+	})
+	return quota, err
+}
@@ -0,0 +1,54 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestGetQuota(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	fake.ResourcePacks = []fakekling.ResourcePack{
+		{ID: "pack-1", Name: "trial", Total: 1, Remaining: 1},
+		{ID: "pack-2", Name: "monthly", Total: 100, Remaining: 40},
+	}
+	client := newFakeKlingClient(t, fake, &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	quota, err := client.GetQuota(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get quota: %v", err)
+	}
+	if quota.Remaining != 41 || len(quota.Packs) != 2 {
+		t.Errorf("Expected 41 units in 2 packs, got %v in %d", quota.Remaining, len(quota.Packs))
+	}
+
+	if _, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	quota, err = client.GetQuota(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get quota: %v", err)
+	}
+	if quota.Remaining != 40 || quota.Packs[0].Status != "runOut" || quota.Packs[1].Status != "online" {
+		t.Errorf("Expected the trial pack to run out, got %+v", quota.Packs)
+	}
+
+	exhausted := fakekling.New("ak", "sk")
+	exhausted.ResourcePacks = nil
+	broke := newFakeKlingClient(t, exhausted)
+	if _, err := broke.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}); err == nil {
+		t.Error("Expected submissions to fail without resource packs")
+	}
+
+	luma, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "luma-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := luma.GetQuota(ctx); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RaceOptions configures Race
+type RaceOptions struct {
+	// Accept decides whether a finished result wins the race. When nil, the
+	// first succeeded result that meets MinScores wins.
+	Accept func(provider ProviderType, result *TaskResult) bool
+
+	// Evaluators score each succeeded result before it is judged, in addition to
+	// the evaluators of its client
+	Evaluators []Evaluator
+
+	// MinScores, when Accept is nil, are the lowest scores by evaluator name a
+	// winner must have, e.g. {"sharpness": 0.5}. A missing score does not win.
+	MinScores map[string]float64
+
+	// PollInterval is passed to WaitForCompletion, zero uses the adaptive policy
+	PollInterval time.Duration
+
+	// SubmitTimeout bounds each submission, defaults to DefaultRaceSubmitTimeout.
+	// Submissions are not cut short when a winner is found, so a task created
+	// by a late submission is known and can be canceled.
+	SubmitTimeout time.Duration
+}
+
+// DefaultRaceSubmitTimeout is the default RaceOptions.SubmitTimeout
+const DefaultRaceSubmitTimeout = time.Minute
+
+// RaceResult is the outcome of Race
+type RaceResult struct {
+	Provider ProviderType
+	TaskID   string
+	Result   *TaskResult
+
+	// Errors holds why each losing provider did not win, if it finished before the winner
+	Errors map[ProviderType]error
+
+	// CancelErrors holds the losers whose unfinished tasks could not be canceled.
+	// Providers that do not support canceling are left out.
+	CancelErrors map[ProviderType]error
+}
+
+// raceEntry is a single finished contestant
+type raceEntry struct {
+	provider ProviderType
+	taskID   string
+	result   *TaskResult
+	err      error
+}
+
+// running reports whether the entry's task was submitted but may not have finished
+func (e raceEntry) running() bool {
+	return e.taskID != "" && (e.result == nil || taskPending(e.result.Status))
+}
+
+// Race submits a request to several providers concurrently and returns the first
+// acceptable result. The remaining polls are cancelled once a winner is found,
+// submissions in flight are awaited, and the losers' unfinished tasks are
+// canceled with CancellationSuperseded where the provider supports it.
+// reqs is keyed by provider and each provider needs a client in clients.
+func Race(ctx context.Context, clients map[ProviderType]*Client, reqs map[ProviderType]*GenerationRequest, opts *RaceOptions) (*RaceResult, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("no requests to race")
+	}
+	for provider := range reqs {
+		if clients[provider] == nil {
+			return nil, fmt.Errorf("no client for provider: %s", provider)
+		}
+	}
+	if opts == nil {
+		opts = &RaceOptions{}
+	}
+	accept := opts.Accept
+	if accept == nil {
+		accept = func(_ ProviderType, result *TaskResult) bool {
+			if result.Status != TaskStatusSucceeded {
+				return false
+			}
+			for name, min := range opts.MinScores {
+				if score, ok := result.Scores[name]; !ok || score < min {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	entries := make(chan raceEntry, len(reqs))
+	var wg sync.WaitGroup
+	for provider, req := range reqs {
+		wg.Add(1)
+		go func(provider ProviderType, client *Client, req *GenerationRequest) {
+			defer wg.Done()
+			entries <- runRaceEntry(raceCtx, provider, client, req, opts)
+		}(provider, clients[provider], req)
+	}
+	go func() {
+		wg.Wait()
+		close(entries)
+	}()
+
+	errs := make(map[ProviderType]error)
+	var losers []raceEntry
+	for entry := range entries {
+		if entry.err == nil && accept(entry.provider, entry.result) {
+			cancel()
+			// Collect the contestants still running, which stop promptly now or
+			// once their submission is answered
+			for loser := range entries {
+				losers = append(losers, loser)
+			}
+			result := &RaceResult{Provider: entry.provider, TaskID: entry.taskID, Result: entry.result, Errors: errs}
+			result.CancelErrors = cancelRaceLosers(context.WithoutCancel(ctx), clients, losers)
+			return result, nil
+		}
+
+		losers = append(losers, entry)
+		if entry.err != nil {
+			errs[entry.provider] = entry.err
+		} else {
+			errs[entry.provider] = fmt.Errorf("result not accepted: %s", entry.result.Status)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, &RaceError{Errors: errs}
+}
+
+// runRaceEntry submits a request, waits for it to finish and scores it. The
+// submission outlives ctx, so a task the provider creates after the race was
+// decided is still returned for cancellation.
+func runRaceEntry(ctx context.Context, provider ProviderType, client *Client, req *GenerationRequest, opts *RaceOptions) raceEntry {
+	entry := raceEntry{provider: provider}
+
+	timeout := opts.SubmitTimeout
+	if timeout <= 0 {
+		timeout = DefaultRaceSubmitTimeout
+	}
+	submitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	resp, err := client.CreateGeneration(submitCtx, req)
+	cancel()
+	if err != nil {
+		entry.err = err
+		return entry
+	}
+	entry.taskID = resp.TaskID
+	if err := ctx.Err(); err != nil {
+		entry.err = err
+		return entry
+	}
+
+	entry.result, entry.err = client.WaitForCompletion(ctx, resp.TaskID, opts.PollInterval)
+	if entry.err == nil {
+		if err := client.evaluate(ctx, entry.result, opts.Evaluators); err != nil && client.config.Debug {
+			fmt.Printf("Evaluation failed: %v\n", err)
+		}
+	}
+	return entry
+}
+
+// cancelRaceLosers cancels the unfinished tasks of losers concurrently, returning
+// the failures other than ErrUnsupportedOperation
+func cancelRaceLosers(ctx context.Context, clients map[ProviderType]*Client, losers []raceEntry) map[ProviderType]error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs map[ProviderType]error
+	)
+	for _, loser := range losers {
+		if !loser.running() {
+			continue
+		}
+		wg.Add(1)
+		go func(loser raceEntry) {
+			defer wg.Done()
+			err := clients[loser.provider].CancelGeneration(ctx, loser.taskID, CancellationSuperseded)
+			if err == nil || errors.Is(err, ErrUnsupportedOperation) {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if errs == nil {
+				errs = make(map[ProviderType]error)
+			}
+			errs[loser.provider] = err
+		}(loser)
+	}
+	wg.Wait()
+	return errs
+}
+
+// RaceError is returned when no provider produced an acceptable result
+type RaceError struct {
+	Errors map[ProviderType]error
+}
+
+func (e *RaceError) Error() string {
+	return fmt.Sprintf("no acceptable result from %d providers", len(e.Errors))
+}
@@ -0,0 +1,148 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRace(t *testing.T) {
+	kling := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"kling-1"}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"kling-1","status":"succeed","task_result":{"videos":[{"url":"https://cdn.example.com/kling.mp4"}]}}}`))
+	}))
+	defer kling.Close()
+
+	slowCancelled := make(chan struct{})
+	var canceled atomic.Value
+	wanx := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/cancel") {
+			canceled.Store(r.URL.Path)
+			w.Write([]byte(`{"output":{"task_id":"wanx-1","task_status":"CANCELED"}}`))
+			return
+		}
+		if r.Method == "POST" {
+			w.Write([]byte(`{"output":{"task_id":"wanx-1","task_status":"PENDING"}}`))
+			return
+		}
+		<-r.Context().Done()
+		close(slowCancelled)
+	}))
+	defer wanx.Close()
+
+	klingClient, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: kling.URL, APIKey: "test_access_key,test_secret_key", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	wanxClient, err := NewClient(ProviderWanx, &ProviderConfig{BaseURL: wanx.URL, APIKey: "sk-test", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	clients := map[ProviderType]*Client{ProviderKling: klingClient, ProviderWanx: wanxClient}
+
+	newRequests := func() map[ProviderType]*GenerationRequest {
+		return map[ProviderType]*GenerationRequest{
+			ProviderKling: {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+			ProviderWanx:  {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+		}
+	}
+
+	result, err := Race(context.Background(), clients, newRequests(), &RaceOptions{PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Race failed: %v", err)
+	}
+	if result.Provider != ProviderKling || result.TaskID != "kling-1" || result.Result.URL != "https://cdn.example.com/kling.mp4" {
+		t.Errorf("Unexpected race result: %+v", result)
+	}
+
+	select {
+	case <-slowCancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the losing provider to be cancelled")
+	}
+	if path, _ := canceled.Load().(string); path != "/api/v1/tasks/wanx-1/cancel" || result.CancelErrors != nil {
+		t.Errorf("Expected the losing task to be canceled, got %q and %v", path, result.CancelErrors)
+	}
+	if value, ok := wanxClient.cancellations.Load("wanx-1"); !ok || value.(*Cancellation).Reason != CancellationSuperseded {
+		t.Errorf("Expected the loser to be canceled as superseded, got %v", value)
+	}
+
+	// Nothing is acceptable: the race reports every provider's failure
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	reqs := newRequests()
+	delete(reqs, ProviderWanx)
+	_, err = Race(ctx, clients, reqs, &RaceOptions{
+		PollInterval: 10 * time.Millisecond,
+		Accept:       func(ProviderType, *TaskResult) bool { return false },
+	})
+	var raceErr *RaceError
+	if !errors.As(err, &raceErr) || raceErr.Errors[ProviderKling] == nil {
+		t.Errorf("Expected RaceError with kling failure, got %v", err)
+	}
+
+	if _, err := Race(ctx, map[ProviderType]*Client{}, reqs, nil); err == nil {
+		t.Error("Missing client should return error")
+	}
+}
+
+// slowSubmitProvider is a custom provider whose submissions take delay and
+// create a task even when the caller gave up waiting
+type slowSubmitProvider struct {
+	sloProvider
+	delay    time.Duration
+	canceled chan string
+}
+
+func (p *slowSubmitProvider) Name() string { return "wanx" }
+
+func (p *slowSubmitProvider) CreateGeneration(ctx context.Context, req *GenerationRequest) (*GenerationResponse, error) {
+	time.Sleep(p.delay)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &GenerationResponse{TaskID: "slow-1", Status: TaskStatusQueued}, nil
+}
+
+func (p *slowSubmitProvider) CancelGeneration(ctx context.Context, taskID string) error {
+	p.canceled <- taskID
+	return nil
+}
+
+func TestRaceCancelsLateSubmissions(t *testing.T) {
+	slow := &slowSubmitProvider{sloProvider: sloProvider{status: TaskStatusProcessing}, delay: 50 * time.Millisecond, canceled: make(chan string, 1)}
+	clients := map[ProviderType]*Client{
+		ProviderKling: NewClientWithProvider(&sloProvider{status: TaskStatusSucceeded}, nil),
+		ProviderWanx:  NewClientWithProvider(slow, nil),
+	}
+	reqs := map[ProviderType]*GenerationRequest{
+		ProviderKling: {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+		ProviderWanx:  {Prompt: "A cat", Duration: 5, Width: 1280, Height: 720},
+	}
+
+	result, err := Race(context.Background(), clients, reqs, &RaceOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Race failed: %v", err)
+	}
+	if result.Provider != ProviderKling || result.CancelErrors != nil {
+		t.Errorf("Expected kling to win without cancel errors, got %+v", result)
+	}
+	select {
+	case taskID := <-slow.canceled:
+		if taskID != "slow-1" {
+			t.Errorf("Expected the late task slow-1 to be canceled, got %s", taskID)
+		}
+	default:
+		t.Error("Expected the task of the submission in flight to be canceled")
+	}
+}
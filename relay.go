@@ -0,0 +1,29 @@
+package vidgo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// MaxRelayHops bounds the vidgo gateways a request may be relayed through
+const MaxRelayHops = 8
+
+// RelayHops checks an incoming request that gateway is about to forward to
+// another vidgo gateway, e.g. an edge gateway forwarding to a central one
+// through a Kling-compatible endpoint, and returns the hops to pass on with
+// WithVia. It fails with ErrRelayLoop when the request already went through
+// gateway or through MaxRelayHops gateways.
+func RelayHops(r *http.Request, gateway string) ([]string, error) {
+	hops := adapters.ParseVia(r.Header.Get(adapters.ViaHeader))
+	for _, hop := range hops {
+		if hop == gateway {
+			return nil, fmt.Errorf("%w: %s already relayed the request via %v", ErrRelayLoop, gateway, hops)
+		}
+	}
+	if len(hops) >= MaxRelayHops {
+		return nil, fmt.Errorf("%w: the request was relayed %d times", ErrRelayLoop, len(hops))
+	}
+	return append(hops, gateway), nil
+}
@@ -0,0 +1,56 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+func TestRelayHops(t *testing.T) {
+	var via string
+	central := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		via = r.Header.Get(adapters.ViaHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"central-1"}}`))
+	}))
+	defer central.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: central.URL, APIKey: "ak,sk", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// The edge gateway forwards a request another gateway relayed to it
+	incoming := httptest.NewRequest("POST", "/v1/videos/text2video", nil)
+	incoming.Header.Set(adapters.ViaHeader, "office")
+	hops, err := RelayHops(incoming, "edge")
+	if err != nil {
+		t.Fatalf("Failed to relay: %v", err)
+	}
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}, WithVia(hops))
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if via != "office, edge" || resp.TaskID != "central-1" {
+		t.Errorf("Expected the hops and task ID to cross the relay, got via %q and task %s", via, resp.TaskID)
+	}
+
+	// Calls without relay hops send none
+	if _, err := client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720}); err != nil || via != "" {
+		t.Errorf("Expected no relay hops, got %q (%v)", via, err)
+	}
+
+	incoming.Header.Set(adapters.ViaHeader, "edge, central")
+	if _, err := RelayHops(incoming, "edge"); !errors.Is(err, ErrRelayLoop) {
+		t.Errorf("Expected ErrRelayLoop for a request edge already relayed, got %v", err)
+	}
+	incoming.Header.Set(adapters.ViaHeader, "g1, g2, g3, g4, g5, g6, g7, g8")
+	if _, err := RelayHops(incoming, "edge"); !errors.Is(err, ErrRelayLoop) {
+		t.Errorf("Expected ErrRelayLoop after %d hops, got %v", MaxRelayHops, err)
+	}
+}
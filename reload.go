@@ -0,0 +1,241 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// ReloadEvent audits a provider configuration reload. Changes names the fields
+// that differ, such as "api_key" or "extra.location", never their values.
+type ReloadEvent struct {
+	Time     time.Time    `json:"time"`
+	Provider ProviderType `json:"provider"`
+	Changes  []string     `json:"changes,omitempty"`
+	Err      error        `json:"-"`
+}
+
+// ConfigSource loads the current provider configuration, e.g. from a file or a secret store
+type ConfigSource func(ctx context.Context) (*ProviderConfig, error)
+
+// FileConfigSource reads a JSON encoded ProviderConfig from path. The timeout
+// is a duration string such as "30s" or a number of seconds.
+func FileConfigSource(path string) ConfigSource {
+	return func(ctx context.Context) (*ProviderConfig, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read provider config: %w", err)
+		}
+		var file struct {
+			ProviderConfig
+			Timeout json.RawMessage `json:"timeout"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode %s: %v", ErrInvalidConfiguration, path, err)
+		}
+		config := file.ProviderConfig
+		if config.Timeout, err = parseConfigTimeout(file.Timeout); err != nil {
+			return nil, fmt.Errorf("%w: invalid timeout in %s: %v", ErrInvalidConfiguration, path, err)
+		}
+		return &config, nil
+	}
+}
+
+// parseConfigTimeout parses a duration string or a number of seconds
+func parseConfigTimeout(raw json.RawMessage) (time.Duration, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, err
+	}
+	switch value := value.(type) {
+	case string:
+		return time.ParseDuration(value)
+	case float64:
+		return time.Duration(value * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("expected a duration string or seconds, got %s", raw)
+	}
+}
+
+// current returns the provider serving new calls
+func (c *Client) current() Provider {
+	c.providerMu.RLock()
+	defer c.providerMu.RUnlock()
+	return c.provider
+}
+
+// Reload replaces the provider with one built from config, e.g. after a key
+// rotation. Calls already in flight finish on the old provider and tasks
+// submitted before the reload can still be polled. An invalid config leaves
+// the client unchanged. Only clients created with NewClient can reload.
+func (c *Client) Reload(config *ProviderConfig) error {
+	event := ReloadEvent{Time: time.Now(), Provider: c.providerType}
+	err := c.reload(config, &event)
+	if err != nil {
+		event.Err = err
+	}
+	if c.config.OnReload != nil && (err != nil || len(event.Changes) > 0) {
+		c.config.OnReload(event)
+	}
+	return err
+}
+
+func (c *Client) reload(config *ProviderConfig, event *ReloadEvent) error {
+	if c.providerType == "" {
+		return fmt.Errorf("%w: a client with a custom provider cannot reload its configuration", ErrInvalidConfiguration)
+	}
+	if config == nil {
+		return fmt.Errorf("%w: provider config cannot be nil", ErrInvalidConfiguration)
+	}
+
+	c.providerMu.RLock()
+	event.Changes = diffProviderConfig(c.providerConfig, config)
+	c.providerMu.RUnlock()
+	if len(event.Changes) == 0 {
+		return nil
+	}
+
+	provider, err := createProvider(c.providerType, config)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+
+	c.providerMu.Lock()
+	if wrapper, ok := provider.(*adapterWrapper); ok {
+		wrapper.inheritTaskState(c.provider)
+	}
+	c.provider = provider
+	c.providerConfig = cloneProviderConfig(config)
+	c.providerMu.Unlock()
+
+	c.RefreshModels()
+	if c.config.Prewarm {
+		go c.warmOnce()
+	}
+	return nil
+}
+
+// Watch reloads the configuration from source every time trigger fires, until
+// ctx is done. Failed reloads keep the current configuration and are reported
+// through ClientConfig.OnReload.
+func (c *Client) Watch(ctx context.Context, source ConfigSource, trigger <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-trigger:
+			if !ok {
+				return
+			}
+			config, err := source(ctx)
+			if err != nil {
+				if c.config.OnReload != nil {
+					c.config.OnReload(ReloadEvent{Time: time.Now(), Provider: c.providerType, Err: err})
+				}
+				continue
+			}
+			c.Reload(config)
+		}
+	}
+}
+
+// ReloadSignals returns a channel that fires whenever the process receives one
+// of sigs, SIGHUP by default, until ctx is done. Use it as the Watch trigger.
+func ReloadSignals(ctx context.Context, sigs ...os.Signal) <-chan struct{} {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+
+	trigger := make(chan struct{}, 1)
+	go func() {
+		defer signal.Stop(received)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-received:
+				select {
+				case trigger <- struct{}{}:
+				default: // A reload is already pending
+				}
+			}
+		}
+	}()
+	return trigger
+}
+
+// diffProviderConfig lists the names of the fields that differ between two configurations
+func diffProviderConfig(old, new *ProviderConfig) []string {
+	if old == nil {
+		old = &ProviderConfig{}
+	}
+
+	var changes []string
+	add := func(name string, changed bool) {
+		if changed {
+			changes = append(changes, name)
+		}
+	}
+	add("base_url", old.BaseURL != new.BaseURL)
+	add("api_key", old.APIKey != new.APIKey)
+	add("secret_key", old.SecretKey != new.SecretKey)
+	add("timeout", old.Timeout != new.Timeout)
+	add("retry_count", old.RetryCount != new.RetryCount)
+	add("allow_unknown_extra", old.AllowUnknownExtra != new.AllowUnknownExtra)
+	add("api_version", old.APIVersion != new.APIVersion)
+	add("environment", old.Environment != new.Environment)
+	add("region", old.Region != new.Region)
+	changes = append(changes, diffStringMap("extra", old.Extra, new.Extra)...)
+	changes = append(changes, diffStringMap("endpoints", old.Endpoints, new.Endpoints)...)
+	return changes
+}
+
+// diffStringMap lists the keys added, removed or changed between two maps as prefix.key
+func diffStringMap(prefix string, old, new map[string]string) []string {
+	var keys []string
+	for key, value := range old {
+		if newValue, ok := new[key]; !ok || newValue != value {
+			keys = append(keys, prefix+"."+key)
+		}
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, prefix+"."+key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cloneProviderConfig copies config so later changes by the caller are detected by Reload
+func cloneProviderConfig(config *ProviderConfig) *ProviderConfig {
+	if config == nil {
+		return nil
+	}
+	clone := *config
+	clone.Extra = cloneStringMap(config.Extra)
+	clone.Endpoints = cloneStringMap(config.Endpoints)
+	return &clone
+}
+
+func cloneStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
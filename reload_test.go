@@ -0,0 +1,188 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestClientReloadKeepsInFlightTasks(t *testing.T) {
+	fake := fakekling.New("ak", "sk")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	var events []ReloadEvent
+	config := &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}
+	client, err := NewClient(ProviderKling, config, &ClientConfig{
+		Timeout:  5 * time.Second,
+		OnReload: func(event ReloadEvent) { events = append(events, event) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	// An effects task is polled on its own path, which only the old provider knows
+	resp, err := client.CreateEffect(ctx, &EffectRequest{Effect: "squish", Images: []string{"https://example.com/cat.png"}})
+	if err != nil {
+		t.Fatalf("Failed to create effect: %v", err)
+	}
+
+	// Changing the caller's config is not applied until Reload
+	config.Timeout = 10 * time.Second
+	config.APIVersion = "v1"
+	if err := client.Reload(config); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if len(events) != 1 || !reflect.DeepEqual(events[0].Changes, []string{"timeout", "api_version"}) {
+		t.Fatalf("Expected one event for timeout and api_version, got %+v", events)
+	}
+
+	result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to poll a task submitted before the reload: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded {
+		t.Errorf("Expected succeeded, got %s", result.Status)
+	}
+
+	// Reloading an unchanged config is a no-op
+	if err := client.Reload(config); err != nil || len(events) != 1 {
+		t.Errorf("Expected no event for an unchanged config, got %d (%v)", len(events), err)
+	}
+
+	// An invalid config is rejected and audited, the client keeps working
+	if err := client.Reload(&ProviderConfig{BaseURL: server.URL, APIKey: "no-secret"}); err == nil {
+		t.Error("Expected an invalid config to be rejected")
+	}
+	if len(events) != 2 || events[1].Err == nil {
+		t.Errorf("Expected a failed reload event, got %+v", events)
+	}
+	if _, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A dog", Duration: 5, Width: 1280, Height: 720}); err != nil {
+		t.Errorf("Expected the client to keep its provider, got %v", err)
+	}
+
+	custom := NewClientWithProvider(client.current())
+	if err := custom.Reload(config); err == nil {
+		t.Error("Expected a client with a custom provider to refuse reloads")
+	}
+}
+
+func TestClientWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kling.json")
+	os.WriteFile(path, []byte(`{"base_url":"http://127.0.0.1:1","api_key":"ak,sk"}`), 0600)
+
+	var mu sync.Mutex
+	var events []ReloadEvent
+	done := make(chan struct{}, 2)
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: "http://127.0.0.1:1", APIKey: "ak,sk"}, &ClientConfig{
+		Timeout: time.Second,
+		OnReload: func(event ReloadEvent) {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+			done <- struct{}{}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trigger := make(chan struct{})
+	go client.Watch(ctx, FileConfigSource(path), trigger)
+
+	os.WriteFile(path, []byte(`{"base_url":"http://127.0.0.1:1","api_key":"ak2,sk2","extra":{}}`), 0600)
+	trigger <- struct{}{}
+	<-done
+
+	os.WriteFile(path, []byte(`not json`), 0600)
+	trigger <- struct{}{}
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if !reflect.DeepEqual(events[0].Changes, []string{"api_key"}) || events[0].Err != nil {
+		t.Errorf("Expected an api_key change, got %+v", events[0])
+	}
+	if events[1].Err == nil {
+		t.Errorf("Expected a source error, got %+v", events[1])
+	}
+}
+
+func TestClientReloadSharesTaskState(t *testing.T) {
+	var image2video atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+		case strings.HasPrefix(r.URL.Path, "/v1/videos/image2video/"):
+			image2video.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":1203,"message":"task not found"}`))
+		default:
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1","task_status":"processing"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	old := client.current()
+	if err := client.Reload(&ProviderConfig{BaseURL: server.URL, APIKey: "ak2,sk2"}); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+
+	// A submission still running on the old provider when the reload happened
+	ctx := context.Background()
+	if _, err := old.CreateGeneration(ctx, &GenerationRequest{Prompt: "A dog", Duration: 5, Width: 1280, Height: 720}); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if _, err := client.GetGeneration(ctx, "task-1"); err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if n := image2video.Load(); n != 0 {
+		t.Errorf("Expected the new provider to know the task type, got %d image2video lookups", n)
+	}
+}
+
+func TestFileConfigSourceTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kling.json")
+	for content, want := range map[string]time.Duration{
+		`{"api_key":"ak,sk","timeout":"1m30s"}`: 90 * time.Second,
+		`{"api_key":"ak,sk","timeout":45}`:      45 * time.Second,
+		`{"api_key":"ak,sk"}`:                   0,
+	} {
+		os.WriteFile(path, []byte(content), 0600)
+		config, err := FileConfigSource(path)(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", content, err)
+		}
+		if config.Timeout != want || config.APIKey != "ak,sk" {
+			t.Errorf("Expected timeout %s from %s, got %+v", want, content, config)
+		}
+	}
+
+	os.WriteFile(path, []byte(`{"api_key":"ak,sk","timeout":"soon"}`), 0600)
+	if _, err := FileConfigSource(path)(context.Background()); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration for an invalid timeout, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+	"github.com/feitianbubu/vidgo/adapters/replicate"
+)
+
+func TestReplicateProvider(t *testing.T) {
+	var submitted map[string]interface{}
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer r8_test" {
+			t.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			paths = append(paths, r.URL.Path)
+			json.NewDecoder(r.Body).Decode(&submitted)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"pred-1","status":"starting"}`))
+			return
+		}
+		if r.URL.Path != "/v1/predictions/pred-1" {
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"pred-1","status":"succeeded","output":["https://replicate.delivery/out.mp4"]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderReplicate, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "r8_test",
+		Timeout: 5 * time.Second,
+		Extra:   map[string]string{"webhook": "https://example.com/hooks/replicate"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat",
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		Model:    "genmoai/mochi-1",
+		Metadata: map[string]interface{}{"input": map[string]interface{}{"num_frames": 163}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	input, _ := submitted["input"].(map[string]interface{})
+	if input["prompt"] != "A cat" || input["num_frames"] != 163.0 || submitted["webhook"] != "https://example.com/hooks/replicate" {
+		t.Errorf("Unexpected submitted payload: %v", submitted)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Model: "tencent/hunyuan-video:6c9132aee14409cd6568d030453f1ba50f5f3412b844fe67f78a9eb62d55664f"})
+	if err != nil {
+		t.Fatalf("Failed to create versioned generation: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/v1/models/genmoai/mochi-1/predictions" || paths[1] != "/v1/predictions" || submitted["version"] == nil {
+		t.Errorf("Unexpected prediction endpoints: %v, payload %v", paths, submitted)
+	}
+
+	result, err := client.GetGeneration(context.Background(), resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "https://replicate.delivery/out.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	webhook, err := replicate.ParseWebhook([]byte(`{"id":"pred-1","status":"failed","error":"CUDA out of memory"}`))
+	if err != nil {
+		t.Fatalf("Failed to parse webhook: %v", err)
+	}
+	if webhook.Status != adapters.TaskStatusFailed || webhook.Error == nil || webhook.Error.Message != "CUDA out of memory" {
+		t.Errorf("Unexpected webhook result: %+v", webhook)
+	}
+
+	if err := client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Model: "mochi"}); err == nil {
+		t.Error("Model without owner should return error")
+	}
+}
@@ -0,0 +1,92 @@
+package vidgo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Data residency requirements, see GenerationRequest.Residency
+const (
+	ResidencyCNOnly = "cn-only" // Only providers in mainland China
+	ResidencyNonCN  = "non-cn"  // Only providers outside mainland China
+)
+
+// Provider regions, see ProviderConfig.Region
+const (
+	RegionCN     = "cn"
+	RegionGlobal = "global"
+)
+
+// defaultRegions are the regions of the built-in providers' default endpoints.
+// Providers missing here, and any custom BaseURL, have no known region until
+// ProviderConfig.Region sets one.
+var defaultRegions = map[ProviderType]string{
+	ProviderJimeng:    RegionCN,
+	ProviderWanx:      RegionCN,
+	ProviderLuma:      RegionGlobal,
+	ProviderReplicate: RegionGlobal,
+}
+
+// ResidencyEvent audits the residency check of a request tagged with
+// GenerationRequest.Residency, see ClientConfig.OnResidency
+type ResidencyEvent struct {
+	Time      time.Time    `json:"time"`
+	Provider  ProviderType `json:"provider"`
+	Region    string       `json:"region,omitempty"` // Empty when the endpoint's region is unknown
+	Residency string       `json:"residency"`
+	Allowed   bool         `json:"allowed"`
+}
+
+// Region returns the region of the client's provider endpoint, or "" if unknown
+func (c *Client) Region() string {
+	c.providerMu.RLock()
+	config := c.providerConfig
+	c.providerMu.RUnlock()
+
+	if config != nil && config.Region != "" {
+		return config.Region
+	}
+	if config != nil && config.BaseURL != "" {
+		return ""
+	}
+	return defaultRegions[c.registryName()]
+}
+
+// checkResidency rejects a request whose residency the provider region does not
+// satisfy. An unknown region satisfies no residency.
+func (c *Client) checkResidency(req *GenerationRequest) error {
+	if req.Residency == "" {
+		return nil
+	}
+	var want string
+	switch req.Residency {
+	case ResidencyCNOnly:
+		want = RegionCN
+	case ResidencyNonCN:
+		want = RegionGlobal
+	default:
+		return &ValidationError{Field: "residency", Message: fmt.Sprintf("must be %s or %s", ResidencyCNOnly, ResidencyNonCN)}
+	}
+
+	region := c.Region()
+	event := ResidencyEvent{Time: time.Now(), Provider: c.providerType, Region: region, Residency: req.Residency, Allowed: region == want}
+	if c.config.OnResidency != nil {
+		c.config.OnResidency(event)
+	}
+	if event.Allowed {
+		return nil
+	}
+	if region == "" {
+		return fmt.Errorf("%w: %s requires a known region, set ProviderConfig.Region for %s", ErrResidencyNotAllowed, req.Residency, c.providerType)
+	}
+	return fmt.Errorf("%w: %s request sent to %s in region %s", ErrResidencyNotAllowed, req.Residency, c.providerType, region)
+}
+
+// validateRegion checks ProviderConfig.Region
+func validateRegion(region string) error {
+	switch region {
+	case "", RegionCN, RegionGlobal:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown region %q, expected %s or %s", ErrInvalidConfiguration, region, RegionCN, RegionGlobal)
+}
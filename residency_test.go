@@ -0,0 +1,80 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResidency(t *testing.T) {
+	stub := newKlingStub(t)
+	var events []ResidencyEvent
+	audit := &ClientConfig{Timeout: 5 * time.Second, OnResidency: func(event ResidencyEvent) {
+		events = append(events, event)
+	}}
+	ctx := context.Background()
+	req := func(residency string) *GenerationRequest {
+		return &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Residency: residency}
+	}
+
+	// A custom endpoint has no known region until one is configured
+	unknown := stub.client(t, audit)
+	if _, err := unknown.CreateGeneration(ctx, req(ResidencyCNOnly)); !errors.Is(err, ErrResidencyNotAllowed) {
+		t.Errorf("Expected ErrResidencyNotAllowed for an unknown region, got %v", err)
+	}
+	if _, err := unknown.CreateGeneration(ctx, req("")); err != nil {
+		t.Errorf("Expected untagged requests to go anywhere, got %v", err)
+	}
+
+	cn, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: stub.URL, APIKey: "ak,sk", Region: RegionCN}, audit)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := cn.CreateGeneration(ctx, req(ResidencyCNOnly)); err != nil {
+		t.Errorf("Expected a cn-only request to reach a cn provider, got %v", err)
+	}
+	if _, err := cn.CreateGeneration(ctx, req(ResidencyNonCN)); !errors.Is(err, ErrResidencyNotAllowed) {
+		t.Errorf("Expected ErrResidencyNotAllowed for a non-cn request, got %v", err)
+	}
+	if submissions := stub.submissions(); len(submissions) != 2 {
+		t.Errorf("Expected only the allowed requests to be submitted, got %d", len(submissions))
+	}
+
+	var validationErr *ValidationError
+	if _, err := cn.CreateGeneration(ctx, req("eu-only")); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for an unknown residency, got %v", err)
+	}
+
+	expected := []ResidencyEvent{
+		{Provider: ProviderKling, Residency: ResidencyCNOnly},
+		{Provider: ProviderKling, Region: RegionCN, Residency: ResidencyCNOnly, Allowed: true},
+		{Provider: ProviderKling, Region: RegionCN, Residency: ResidencyNonCN},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d audit events, got %+v", len(expected), events)
+	}
+	for i, want := range expected {
+		got := events[i]
+		got.Time = time.Time{}
+		if got != want {
+			t.Errorf("Expected audit event %+v, got %+v", want, got)
+		}
+	}
+
+	// Default endpoints have a known region
+	luma, err := NewClient(ProviderLuma, &ProviderConfig{APIKey: "luma-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if region := luma.Region(); region != RegionGlobal {
+		t.Errorf("Expected Luma in %s, got %q", RegionGlobal, region)
+	}
+	if _, err := luma.CreateGeneration(ctx, req(ResidencyCNOnly)); !errors.Is(err, ErrResidencyNotAllowed) {
+		t.Errorf("Expected ErrResidencyNotAllowed for Luma, got %v", err)
+	}
+
+	if _, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Region: "eu"}); !errors.Is(err, ErrInvalidConfiguration) {
+		t.Errorf("Expected ErrInvalidConfiguration for an unknown region, got %v", err)
+	}
+}
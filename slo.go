@@ -0,0 +1,156 @@
+package vidgo
+
+import (
+	"sync"
+	"time"
+)
+
+// SLO describes a service level objective, e.g. 95% of tasks succeed within 5 minutes
+type SLO struct {
+	Objective float64       `json:"objective"` // Target ratio of good tasks, e.g. 0.95
+	Threshold time.Duration `json:"threshold"` // A task is good if it succeeds within this time
+	Window    time.Duration `json:"window"`    // Evaluation window, defaults to 1 hour
+
+	// BurnRateAlert is the burn rate above which OnBurnRate is called, defaults to 2
+	BurnRateAlert float64 `json:"burn_rate_alert,omitempty"`
+
+	// AlertCooldown is the minimum time between two alerts for a provider,
+	// defaults to 10 minutes
+	AlertCooldown time.Duration `json:"alert_cooldown,omitempty"`
+}
+
+// SLOStatus is the current evaluation of an SLO for a provider
+type SLOStatus struct {
+	Provider             ProviderType `json:"provider"`
+	Total                int          `json:"total"`
+	Good                 int          `json:"good"`
+	Compliance           float64      `json:"compliance"`
+	ErrorBudgetRemaining float64      `json:"error_budget_remaining"` // 1 is untouched, <0 is exhausted
+	BurnRate             float64      `json:"burn_rate"`              // 1 consumes the budget exactly over the window
+	Met                  bool         `json:"met"`
+}
+
+// sloOutcome is a single recorded task outcome
+type sloOutcome struct {
+	at   time.Time
+	good bool
+}
+
+// SLOTracker continuously evaluates task outcomes against an SLO per provider.
+// It is safe for concurrent use.
+type SLOTracker struct {
+	slo SLO
+
+	// OnBurnRate is called when a recorded outcome pushes the burn rate above
+	// SLO.BurnRateAlert. It fires once per crossing: it is called again only after
+	// the burn rate has dropped back to the threshold and SLO.AlertCooldown has passed.
+	OnBurnRate func(status SLOStatus)
+
+	mu       sync.Mutex
+	outcomes map[ProviderType][]sloOutcome
+	alerts   map[ProviderType]*sloAlert
+}
+
+// sloAlert is the alert state of a provider
+type sloAlert struct {
+	firing bool
+	last   time.Time
+}
+
+// NewSLOTracker creates a tracker for the given SLO
+func NewSLOTracker(slo SLO) *SLOTracker {
+	if slo.Window <= 0 {
+		slo.Window = time.Hour
+	}
+	if slo.BurnRateAlert <= 0 {
+		slo.BurnRateAlert = 2
+	}
+	if slo.AlertCooldown <= 0 {
+		slo.AlertCooldown = 10 * time.Minute
+	}
+	return &SLOTracker{
+		slo:      slo,
+		outcomes: make(map[ProviderType][]sloOutcome),
+		alerts:   make(map[ProviderType]*sloAlert),
+	}
+}
+
+// Record adds a finished task outcome for a provider
+func (t *SLOTracker) Record(provider ProviderType, status TaskStatus, latency time.Duration) {
+	good := status == TaskStatusSucceeded && (t.slo.Threshold <= 0 || latency <= t.slo.Threshold)
+
+	t.mu.Lock()
+	now := time.Now()
+	t.outcomes[provider] = append(t.prune(provider, now), sloOutcome{at: now, good: good})
+	current := t.evaluate(provider)
+	alert := t.alert(provider, current.BurnRate, now)
+	t.mu.Unlock()
+
+	if alert && t.OnBurnRate != nil {
+		t.OnBurnRate(current)
+	}
+}
+
+// alert reports whether burnRate starts a new alert for a provider; the caller holds mu
+func (t *SLOTracker) alert(provider ProviderType, burnRate float64, now time.Time) bool {
+	state := t.alerts[provider]
+	if state == nil {
+		state = &sloAlert{}
+		t.alerts[provider] = state
+	}
+
+	if burnRate <= t.slo.BurnRateAlert {
+		state.firing = false
+		return false
+	}
+	if state.firing || (!state.last.IsZero() && now.Sub(state.last) < t.slo.AlertCooldown) {
+		return false
+	}
+	state.firing = true
+	state.last = now
+	return true
+}
+
+// Status returns the current SLO evaluation for a provider
+func (t *SLOTracker) Status(provider ProviderType) SLOStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.outcomes[provider] = t.prune(provider, time.Now())
+	return t.evaluate(provider)
+}
+
+// prune drops outcomes outside the evaluation window
+func (t *SLOTracker) prune(provider ProviderType, now time.Time) []sloOutcome {
+	outcomes := t.outcomes[provider]
+	cutoff := now.Add(-t.slo.Window)
+	i := 0
+	for i < len(outcomes) && outcomes[i].at.Before(cutoff) {
+		i++
+	}
+	return outcomes[i:]
+}
+
+// evaluate computes the SLO status over the recorded outcomes
+func (t *SLOTracker) evaluate(provider ProviderType) SLOStatus {
+	status := SLOStatus{Provider: provider, Compliance: 1, ErrorBudgetRemaining: 1, Met: true}
+
+	for _, outcome := range t.outcomes[provider] {
+		status.Total++
+		if outcome.good {
+			status.Good++
+		}
+	}
+	if status.Total == 0 {
+		return status
+	}
+
+	status.Compliance = float64(status.Good) / float64(status.Total)
+	budget := 1 - t.slo.Objective
+	if budget > 0 {
+		status.BurnRate = (1 - status.Compliance) / budget
+		status.ErrorBudgetRemaining = 1 - status.BurnRate
+	}
+	status.Met = status.Compliance >= t.slo.Objective
+	return status
+}
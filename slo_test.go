@@ -0,0 +1,161 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSLOTracker(t *testing.T) {
+	tracker := NewSLOTracker(SLO{Objective: 0.95, Threshold: 5 * time.Minute})
+
+	var alerts []SLOStatus
+	tracker.OnBurnRate = func(status SLOStatus) {
+		alerts = append(alerts, status)
+	}
+
+	for i := 0; i < 9; i++ {
+		tracker.Record(ProviderKling, TaskStatusSucceeded, time.Minute)
+	}
+	status := tracker.Status(ProviderKling)
+	if !status.Met || status.Compliance != 1 {
+		t.Errorf("Expected SLO to be met, got %+v", status)
+	}
+
+	// A slow success and a failure both consume error budget
+	tracker.Record(ProviderKling, TaskStatusSucceeded, 10*time.Minute)
+	tracker.Record(ProviderKling, TaskStatusFailed, time.Minute)
+
+	status = tracker.Status(ProviderKling)
+	if status.Total != 11 || status.Good != 9 {
+		t.Errorf("Expected 9 of 11 good tasks, got %d of %d", status.Good, status.Total)
+	}
+	if status.Met {
+		t.Errorf("Expected SLO to be violated, got %+v", status)
+	}
+	if status.ErrorBudgetRemaining >= 0 {
+		t.Errorf("Expected exhausted error budget, got %v", status.ErrorBudgetRemaining)
+	}
+	if len(alerts) != 1 {
+		t.Errorf("Expected one burn rate alert, got %d", len(alerts))
+	}
+
+	// Further failures while the alert is firing do not alert again
+	tracker.Record(ProviderKling, TaskStatusFailed, time.Minute)
+	if len(alerts) != 1 {
+		t.Errorf("Expected no repeated alert while firing, got %d", len(alerts))
+	}
+
+	if other := tracker.Status(ProviderVidu); other.Total != 0 || !other.Met {
+		t.Errorf("Expected empty status for untracked provider, got %+v", other)
+	}
+}
+
+func TestSLOTrackerAlertCooldown(t *testing.T) {
+	tracker := NewSLOTracker(SLO{Objective: 0.5, BurnRateAlert: 1, AlertCooldown: time.Hour})
+	var alerts int
+	tracker.OnBurnRate = func(status SLOStatus) { alerts++ }
+
+	// The burn rate crosses the threshold, recovers and crosses again within the cooldown
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	for i := 0; i < 3; i++ {
+		tracker.Record(ProviderKling, TaskStatusSucceeded, 0)
+	}
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	if alerts != 1 {
+		t.Errorf("Expected one alert within the cooldown, got %d", alerts)
+	}
+
+	tracker = NewSLOTracker(SLO{Objective: 0.5, BurnRateAlert: 1, AlertCooldown: time.Nanosecond})
+	tracker.OnBurnRate = func(status SLOStatus) { alerts++ }
+	alerts = 0
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	for i := 0; i < 3; i++ {
+		tracker.Record(ProviderKling, TaskStatusSucceeded, 0)
+	}
+	time.Sleep(time.Millisecond)
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	tracker.Record(ProviderKling, TaskStatusFailed, 0)
+	if alerts != 2 {
+		t.Errorf("Expected a second alert after recovery and cooldown, got %d", alerts)
+	}
+}
+
+// sloProvider is a custom provider whose tasks finish with status
+type sloProvider struct {
+	createErr error
+	status    TaskStatus
+}
+
+func (p *sloProvider) Name() string { return "kling" }
+
+func (p *sloProvider) CreateGeneration(ctx context.Context, req *GenerationRequest) (*GenerationResponse, error) {
+	if p.createErr != nil {
+		return nil, p.createErr
+	}
+	return &GenerationResponse{TaskID: "task-1", Status: TaskStatusQueued}, nil
+}
+
+func (p *sloProvider) GetGeneration(ctx context.Context, taskID string) (*TaskResult, error) {
+	return &TaskResult{TaskID: taskID, Status: p.status}, nil
+}
+
+func (p *sloProvider) SupportedModels() []string { return nil }
+
+func (p *sloProvider) ValidateRequest(req *GenerationRequest) error { return nil }
+
+func TestClientRecordsSLO(t *testing.T) {
+	tracker := NewSLOTracker(SLO{Objective: 0.75})
+	provider := &sloProvider{status: TaskStatusProcessing}
+	config := DefaultClientConfig()
+	config.SLO = tracker
+	config.MaxRetries = 0
+	client := NewClientWithProvider(provider, config)
+	ctx := context.Background()
+
+	if _, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "a cat", Duration: 5, Width: 1280, Height: 720}); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	client.GetGeneration(ctx, "task-1")
+	if status := tracker.Status(ProviderKling); status.Total != 0 {
+		t.Errorf("Expected no outcome for a running task, got %+v", status)
+	}
+
+	provider.status = TaskStatusSucceeded
+	client.GetGeneration(ctx, "task-1")
+	client.GetGeneration(ctx, "task-1")
+	if status := tracker.Status(ProviderKling); status.Total != 1 || status.Good != 1 {
+		t.Errorf("Expected one good outcome, got %+v", status)
+	}
+
+	// Provider failures of CreateGeneration count against the SLO, invalid requests do not
+	provider.createErr = &APIError{Code: 400, Message: "bad prompt"}
+	client.CreateGeneration(ctx, &GenerationRequest{Prompt: "a cat", Duration: 5, Width: 1280, Height: 720})
+	provider.createErr = &APIError{Code: 503, Message: "overloaded"}
+	client.CreateGeneration(ctx, &GenerationRequest{Prompt: "a cat", Duration: 5, Width: 1280, Height: 720})
+	if status := tracker.Status(ProviderKling); status.Total != 2 || status.Good != 1 {
+		t.Errorf("Expected one failed create, got %+v", status)
+	}
+
+	// The evaluation is served with the queue metrics, the metrics handler and the config snapshot
+	if slo := client.QueueMetrics().SLO; slo == nil || slo.Total != 2 || slo.BurnRate != 2 || slo.Met {
+		t.Errorf("Expected a burn rate of 2 in the queue metrics, got %+v", slo)
+	}
+	recorder := httptest.NewRecorder()
+	MetricsHandler(client).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics?metric="+MetricSLOBurnRate, nil))
+	var list ExternalMetricValueList
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Value != "2" {
+		t.Errorf("Expected a single burn rate sample of 2, got %+v", list.Items)
+	}
+	if slo := client.ConfigSnapshot(true).SLO; slo == nil || slo.Compliance != 0.5 {
+		t.Errorf("Expected 50%% compliance in the config snapshot, got %+v", slo)
+	}
+}
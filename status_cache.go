@@ -0,0 +1,152 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/feitianbubu/vidgo/pace"
+)
+
+// StatusCache serves task status reads with stale-while-revalidate semantics:
+// results younger than the fresh window are returned as is, older ones are
+// returned immediately while a single background poll refreshes them, and ones
+// older than maxAge are fetched synchronously. Finished tasks are never
+// revalidated. It suits high-traffic status endpoints; a client polling with
+// WaitForCompletion sees the same status until its entry goes stale. It is safe
+// for concurrent use and may be shared by several clients.
+type StatusCache struct {
+	fresh  time.Duration
+	maxAge time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*statusEntry // keyed by provider name and task ID
+	misses    pace.Group[*TaskResult]
+	lastSweep time.Time
+}
+
+type statusEntry struct {
+	result       *TaskResult
+	fetched      time.Time
+	revalidating bool
+}
+
+// NewStatusCache creates a cache that revalidates entries older than fresh and
+// stops serving them after maxAge, which defaults to ten times fresh
+func NewStatusCache(fresh, maxAge time.Duration) *StatusCache {
+	if maxAge < fresh {
+		maxAge = 10 * fresh
+	}
+	return &StatusCache{fresh: fresh, maxAge: maxAge, entries: make(map[string]*statusEntry)}
+}
+
+// get returns a copy of the cached result for key, and whether the caller should
+// revalidate it in the background. It returns nil when the entry is missing or
+// too old to serve.
+func (s *StatusCache) get(key string) (*TaskResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	age := time.Since(entry.fetched)
+	if age >= s.maxAge {
+		delete(s.entries, key)
+		return nil, false
+	}
+
+	result := *entry.result
+	revalidate := age >= s.fresh && taskPending(result.Status) && !entry.revalidating
+	if revalidate {
+		entry.revalidating = true
+	}
+	return &result, revalidate
+}
+
+// fetch fetches and stores a missing entry, sharing one fetch between concurrent callers
+func (s *StatusCache) fetch(key string, fn func() (*TaskResult, error)) (*TaskResult, error) {
+	result, err, _ := s.misses.Do(key, func() (*TaskResult, error) {
+		result, err := fn()
+		if err == nil {
+			s.put(key, result)
+		}
+		return result, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	copied := *result
+	return &copied, nil
+}
+
+// put stores a copy of result for key
+func (s *StatusCache) put(key string, result *TaskResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	stored := *result
+	s.entries[key] = &statusEntry{result: &stored, fetched: now}
+}
+
+// sweep drops entries too old to serve, at most once per maxAge so puts stay
+// cheap; the caller holds mu
+func (s *StatusCache) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.maxAge {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if now.Sub(entry.fetched) >= s.maxAge {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// forget drops the entry for key
+func (s *StatusCache) forget(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// failed ends a revalidation that returned err, forgetting tasks the provider no longer knows
+func (s *StatusCache) failed(key string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if errors.Is(err, ErrTaskNotFound) {
+		delete(s.entries, key)
+	} else if entry, ok := s.entries[key]; ok {
+		entry.revalidating = false
+	}
+}
+
+// statusCache returns the status cache to use for a call, or nil. Like the
+// ResultCache it is bypassed by WithoutCache, customer-supplied credentials and
+// WithBaseURL.
+func (c *Client) statusCache(o *callOptions) *StatusCache {
+	if c.config.StatusCache == nil || o.noCache || o.apiKey != "" || o.baseURL != "" {
+		return nil
+	}
+	return c.config.StatusCache
+}
+
+// revalidate refreshes a stale status cache entry in the background
+func (c *Client) revalidate(ctx context.Context, statuses *StatusCache, key, taskID string, o *callOptions) {
+	result, err := c.getGeneration(ctx, taskID, o)
+	if err != nil {
+		statuses.failed(key, err)
+		if c.config.Debug {
+			fmt.Printf("Status revalidation of %s failed: %v\n", taskID, err)
+		}
+		return
+	}
+	statuses.put(key, result)
+}
@@ -0,0 +1,85 @@
+package vidgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestStatusCache(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{
+		Timeout:     5 * time.Second,
+		StatusCache: NewStatusCache(50*time.Millisecond, time.Minute),
+	})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	first, err := client.GetGeneration(ctx, resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	// Fresh entries are served without polling the provider, which would advance the task
+	cached, err := client.GetGeneration(ctx, resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if cached.Status != first.Status {
+		t.Errorf("Expected cached status %s, got %s", first.Status, cached.Status)
+	}
+
+	// Stale entries are served immediately and refreshed in the background
+	time.Sleep(60 * time.Millisecond)
+	stale, err := client.GetGeneration(ctx, resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if stale.Status != first.Status {
+		t.Errorf("Expected stale status %s, got %s", first.Status, stale.Status)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		refreshed, err := client.GetGeneration(ctx, resp.TaskID)
+		if err != nil {
+			t.Fatalf("Failed to get generation: %v", err)
+		}
+		if refreshed.Status == TaskStatusProcessing {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the background refresh to store processing, got %s", refreshed.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// WithoutCache bypasses the status cache
+	direct, err := client.GetGeneration(ctx, resp.TaskID, WithoutCache())
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if direct.Status != TaskStatusSucceeded {
+		t.Errorf("Expected succeeded from the provider, got %s", direct.Status)
+	}
+}
+
+func TestStatusCacheSweep(t *testing.T) {
+	cache := NewStatusCache(time.Millisecond, 20*time.Millisecond)
+	cache.put("kling:task-1", &TaskResult{TaskID: "task-1", Status: TaskStatusProcessing})
+	cache.put("kling:task-2", &TaskResult{TaskID: "task-2", Status: TaskStatusProcessing})
+
+	// Expired entries are swept by a put once maxAge passed since the last sweep
+	time.Sleep(30 * time.Millisecond)
+	cache.put("kling:task-3", &TaskResult{TaskID: "task-3", Status: TaskStatusProcessing})
+	cache.mu.Lock()
+	_, kept := cache.entries["kling:task-3"]
+	entries := len(cache.entries)
+	cache.mu.Unlock()
+	if entries != 1 || !kept {
+		t.Errorf("Expected only the new entry after the sweep, got %d entries", entries)
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GCS stores objects in a Google Cloud Storage bucket through the JSON API's
+// media upload
+type GCS struct {
+	Bucket string
+
+	// Token returns an OAuth access token with the devstorage.read_write or
+	// cloud-platform scope, e.g. from golang.org/x/oauth2/google
+	Token func(ctx context.Context) (string, error)
+
+	// Endpoint defaults to https://storage.googleapis.com
+	Endpoint string
+
+	// PublicURL, e.g. a CDN in front of the bucket, prefixes returned URLs
+	// instead of https://storage.googleapis.com/<Bucket>
+	PublicURL string
+
+	Client *http.Client // Defaults to http.DefaultClient
+}
+
+// Put uploads r as key and returns the object's URL
+func (g *GCS) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	if g.Bucket == "" || g.Token == nil {
+		return "", fmt.Errorf("gcs storage requires Bucket and Token")
+	}
+	body, length, err := sized(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read object: %w", err)
+	}
+	token, err := g.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(g.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	query := url.Values{"uploadType": {"media"}, "name": {key}}
+	uploadURL := endpoint + "/upload/storage/v1/b/" + url.PathEscape(g.Bucket) + "/o?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType(key))
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if err := upload(g.Client, req); err != nil {
+		return "", err
+	}
+	if g.PublicURL != "" {
+		return publicURL(g.PublicURL, key), nil
+	}
+	return publicURL("https://storage.googleapis.com/"+g.Bucket, key), nil
+}
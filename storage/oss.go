@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OSS stores objects in an Alibaba Cloud OSS bucket, signing requests with the
+// OSS header signature
+type OSS struct {
+	Endpoint        string // e.g. "https://oss-cn-hangzhou.aliyuncs.com"
+	Bucket          string
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string // Optional, for STS credentials
+
+	// PublicURL, e.g. a CDN in front of the bucket, prefixes returned URLs
+	// instead of the object URL
+	PublicURL string
+
+	Client *http.Client // Defaults to http.DefaultClient
+
+	now func() time.Time
+}
+
+// Put uploads r as key and returns the object's URL
+func (o *OSS) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	if o.Endpoint == "" || o.Bucket == "" || o.AccessKeyID == "" || o.AccessKeySecret == "" {
+		return "", fmt.Errorf("oss storage requires Endpoint, Bucket, AccessKeyID and AccessKeySecret")
+	}
+	body, length, err := sized(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read object: %w", err)
+	}
+
+	u, err := url.Parse(o.Endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid oss endpoint %q", o.Endpoint)
+	}
+	u.Host = o.Bucket + "." + u.Host
+	u.Path = "/" + key
+	objectURL := u.String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType(key))
+	if o.SecurityToken != "" {
+		req.Header.Set("X-Oss-Security-Token", o.SecurityToken)
+	}
+
+	now := time.Now
+	if o.now != nil {
+		now = o.now
+	}
+	req.Header.Set("Date", now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Authorization", "OSS "+o.AccessKeyID+":"+o.signature(req, key))
+
+	if err := upload(o.Client, req); err != nil {
+		return "", err
+	}
+	if o.PublicURL != "" {
+		return publicURL(o.PublicURL, key), nil
+	}
+	return objectURL, nil
+}
+
+// signature computes the OSS V1 signature of req
+func (o *OSS) signature(req *http.Request, key string) string {
+	var ossHeaders []string
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-oss-") {
+			ossHeaders = append(ossHeaders, lower+":"+strings.TrimSpace(req.Header.Get(name)))
+		}
+	}
+	sort.Strings(ossHeaders)
+
+	var stringToSign strings.Builder
+	stringToSign.WriteString(req.Method + "\n")
+	stringToSign.WriteString(req.Header.Get("Content-MD5") + "\n")
+	stringToSign.WriteString(req.Header.Get("Content-Type") + "\n")
+	stringToSign.WriteString(req.Header.Get("Date") + "\n")
+	for _, header := range ossHeaders {
+		stringToSign.WriteString(header + "\n")
+	}
+	stringToSign.WriteString("/" + o.Bucket + "/" + key)
+
+	mac := hmac.New(sha1.New, []byte(o.AccessKeySecret))
+	mac.Write([]byte(stringToSign.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
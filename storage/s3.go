@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unsignedPayload skips hashing the body in the SigV4 signature, so uploads can stream
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3 stores objects in an Amazon S3 bucket, or any S3-compatible service,
+// signing requests with AWS Signature Version 4
+type S3 struct {
+	Region       string // e.g. "us-east-1"
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string // Optional, for temporary credentials
+
+	// Endpoint defaults to https://s3.<Region>.amazonaws.com; set it for
+	// S3-compatible services such as MinIO or Cloudflare R2
+	Endpoint string
+	// PathStyle addresses the bucket as <Endpoint>/<Bucket> instead of as a subdomain
+	PathStyle bool
+
+	// PublicURL, e.g. a CDN in front of the bucket, prefixes returned URLs
+	// instead of the object URL
+	PublicURL string
+
+	Client *http.Client // Defaults to http.DefaultClient
+
+	now func() time.Time
+}
+
+// Put uploads r as key and returns the object's URL
+func (s *S3) Put(ctx context.Context, key string, r io.Reader) (string, error) {
+	if s.Bucket == "" || s.Region == "" || s.AccessKey == "" || s.SecretKey == "" {
+		return "", fmt.Errorf("s3 storage requires Region, Bucket, AccessKey and SecretKey")
+	}
+	body, length, err := sized(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read object: %w", err)
+	}
+
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType(key))
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	s.sign(req, now())
+
+	if err := upload(s.Client, req); err != nil {
+		return "", err
+	}
+	if s.PublicURL != "" {
+		return publicURL(s.PublicURL, key), nil
+	}
+	return objectURL, nil
+}
+
+// objectURL returns the URL of key in the bucket
+func (s *S3) objectURL(key string) (string, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+	prefix := strings.TrimSuffix(u.Path, "/")
+	if s.PathStyle {
+		prefix += "/" + s.Bucket
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+	}
+	// SigV4 signs the path with every byte outside the unreserved set escaped
+	u.Path = prefix + "/" + key
+	u.RawPath = awsEscape(prefix) + "/" + awsEscape(key)
+	return u.String(), nil
+}
+
+// awsEscape percent-encodes a path the way SigV4 canonicalizes it, keeping slashes
+func awsEscape(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sign adds SigV4 headers to req; the payload is unsigned unless req already
+// carries its X-Amz-Content-Sha256
+func (s *S3) sign(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	shortDate := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = unsignedPayload
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signed := []string{"host"}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			signed = append(signed, lower)
+		}
+	}
+	sort.Strings(signed)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := shortDate + "/" + s.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), shortDate)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.AccessKey+"/"+scope+
+		", SignedHeaders="+strings.Join(signed, ";")+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
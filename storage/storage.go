@@ -0,0 +1,92 @@
+// Package storage uploads generated videos to object storage so they outlive
+// the provider's result URLs. S3, OSS and GCS implement vidgo.Storage for
+// Client.ArchiveResult using plain HTTP requests, without the cloud SDKs.
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// videoTypes covers video extensions missing from mime's built-in table
+var videoTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+}
+
+// contentType returns the media type for an object key from its extension
+func contentType(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if t, ok := videoTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// sized returns r with its remaining length. Files and readers reporting Len
+// are streamed; other readers are buffered in memory.
+func sized(r io.Reader) (io.Reader, int64, error) {
+	switch v := r.(type) {
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, 0, err
+		}
+		return v, info.Size() - offset, nil
+	case interface{ Len() int }:
+		return r, int64(v.Len()), nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// escapePath percent-encodes each segment of an object key
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// publicURL joins a public base URL and an object key
+func publicURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + escapePath(key)
+}
+
+// upload sends a prepared upload request and checks the response status
+func upload(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("upload failed with HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package vidgo
+
+import (
+	"encoding/json"
+	"net/url"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// ConfigSnapshot is the effective configuration of a client, with defaults
+// applied, for attaching to support requests
+type ConfigSnapshot struct {
+	SDKVersion     string          `json:"sdk_version"`
+	GoVersion      string          `json:"go_version"`
+	OS             string          `json:"os"`
+	Arch           string          `json:"arch"`
+	Time           time.Time       `json:"time"`
+	Redacted       bool            `json:"redacted"`
+	Provider       ProviderType    `json:"provider,omitempty"` // Empty for clients with a custom provider
+	ProviderName   string          `json:"provider_name"`
+	ProviderConfig *ProviderConfig `json:"provider_config,omitempty"`
+	Client         ClientSnapshot  `json:"client"`
+	Models         []string        `json:"models,omitempty"`
+	Capabilities   *Capabilities   `json:"capabilities,omitempty"`
+	Features       []string        `json:"features,omitempty"` // Negotiated with the provider, see PeerFeatures
+	SLO            *SLOStatus      `json:"slo,omitempty"`      // Current evaluation of ClientConfig.SLO
+}
+
+// ClientSnapshot is a ClientConfig with defaults applied. Components that hold
+// state or code, such as the Planner or OnReload, are listed by name in Enabled.
+type ClientSnapshot struct {
+	Timeout             time.Duration          `json:"timeout"`
+	MaxRetries          int                    `json:"max_retries"`
+	RetryDelay          time.Duration          `json:"retry_delay"`
+	Debug               bool                   `json:"debug"`
+	DisallowBYOK        bool                   `json:"disallow_byok"`
+	PollPolicy          *PollPolicy            `json:"poll_policy"` // Used for models without a specific policy
+	PollPolicies        map[string]*PollPolicy `json:"poll_policies,omitempty"`
+	NormalizeImages     bool                   `json:"normalize_images"`
+	Prewarm             bool                   `json:"prewarm"`
+	WarmInterval        time.Duration          `json:"warm_interval,omitempty"`
+	DownloadTimeout     time.Duration          `json:"download_timeout"`
+	DownloadReadTimeout time.Duration          `json:"download_read_timeout,omitempty"`
+	TokenMinTTL         time.Duration          `json:"token_min_ttl,omitempty"`
+	TokenMaxTTL         time.Duration          `json:"token_max_ttl,omitempty"`
+	Enabled             []string               `json:"enabled,omitempty"`
+}
+
+// redactedValue replaces secrets in redacted snapshots
+const redactedValue = "REDACTED"
+
+// DumpConfig returns the effective configuration of the client as indented
+// JSON. With redacted, credentials, secret-looking Extra values and URL
+// passwords are masked so the output can be shared.
+func (c *Client) DumpConfig(redacted bool) ([]byte, error) {
+	return json.MarshalIndent(c.ConfigSnapshot(redacted), "", "  ")
+}
+
+// ConfigSnapshot returns the effective configuration of the client, see DumpConfig
+func (c *Client) ConfigSnapshot(redacted bool) *ConfigSnapshot {
+	c.providerMu.RLock()
+	provider, providerConfig := c.provider, cloneProviderConfig(c.providerConfig)
+	c.providerMu.RUnlock()
+
+	if reporter, ok := provider.(adapters.ConfigReporter); ok && providerConfig != nil {
+		if effective := reporter.EffectiveConfig(); effective != nil {
+			providerConfig.BaseURL = effective.BaseURL
+			providerConfig.Timeout = effective.Timeout
+		}
+	}
+	if providerConfig != nil && redacted {
+		redactProviderConfig(providerConfig)
+	}
+
+	snapshot := &ConfigSnapshot{
+		SDKVersion:     adapters.SDKVersion,
+		GoVersion:      runtime.Version(),
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		Time:           time.Now(),
+		Redacted:       redacted,
+		Provider:       c.providerType,
+		ProviderName:   c.GetProviderName(),
+		ProviderConfig: providerConfig,
+		Client:         c.clientSnapshot(),
+		Models:         c.GetSupportedModels(),
+		Capabilities:   c.Capabilities(),
+		SLO:            c.sloStatus(),
+	}
+	for name := range c.PeerFeatures() {
+		snapshot.Features = append(snapshot.Features, name)
+	}
+	sort.Strings(snapshot.Features)
+	return snapshot
+}
+
+// clientSnapshot applies the ClientConfig defaults the client uses at call time
+func (c *Client) clientSnapshot() ClientSnapshot {
+	config := c.config
+	snapshot := ClientSnapshot{
+		Timeout:             config.Timeout,
+		MaxRetries:          config.MaxRetries,
+		RetryDelay:          config.RetryDelay,
+		Debug:               config.Debug,
+		DisallowBYOK:        config.DisallowBYOK,
+		PollPolicy:          c.pollPolicy(""),
+		PollPolicies:        config.PollPolicies,
+		NormalizeImages:     config.NormalizeImages,
+		Prewarm:             config.Prewarm,
+		WarmInterval:        config.WarmInterval,
+		DownloadTimeout:     config.DownloadTimeout,
+		DownloadReadTimeout: config.DownloadReadTimeout,
+		TokenMinTTL:         config.TokenMinTTL,
+		TokenMaxTTL:         config.TokenMaxTTL,
+	}
+	if snapshot.DownloadTimeout == 0 {
+		snapshot.DownloadTimeout = config.Timeout
+	}
+	if snapshot.TokenMaxTTL > 0 && snapshot.TokenMinTTL == 0 {
+		snapshot.TokenMinTTL = time.Minute
+	}
+
+	enabled := func(name string, set bool) {
+		if set {
+			snapshot.Enabled = append(snapshot.Enabled, name)
+		}
+	}
+	enabled("planner", config.Planner != nil)
+	enabled("timelines", config.Timelines != nil)
+	enabled("image_uploader", config.ImageUploader != nil)
+	enabled("upgrade_policy", config.UpgradePolicy != nil)
+	enabled("result_cache", config.ResultCache != nil)
+	enabled("evaluators", len(config.Evaluators) > 0)
+	enabled("archive", config.Archive != nil)
+	enabled("on_reload", config.OnReload != nil)
+	enabled("on_capabilities_changed", config.OnCapabilitiesChanged != nil)
+	enabled("capture", config.Capture != nil)
+	enabled("telemetry", c.telemetry() != nil)
+	return snapshot
+}
+
+// redactProviderConfig masks the credentials in a copy of a provider configuration
+func redactProviderConfig(config *ProviderConfig) {
+	if config.APIKey != "" {
+		config.APIKey = redactedValue
+	}
+	if config.SecretKey != "" {
+		config.SecretKey = redactedValue
+	}
+	config.BaseURL = redactURL(config.BaseURL)
+	for key, value := range config.Extra {
+		if secretKey(key) {
+			config.Extra[key] = redactedValue
+		} else {
+			config.Extra[key] = redactURL(value)
+		}
+	}
+}
+
+// secretKey reports whether an Extra key names a credential, e.g. "api_key" or
+// "access_token" but not "token_refresh_margin"
+func secretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range []string{"key", "secret", "token", "password"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL masks the password of a URL with user info and leaves other values as they are
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+	return u.String()
+}
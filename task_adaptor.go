@@ -1,11 +1,16 @@
 package vidgo
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/feitianbubu/vidgo/adapters"
 )
 
-// TaskAdaptorInterface defines the interface for task-based video generation
+// TaskAdaptorInterface defines the interface for task-based video generation.
+// Submit and Fetch return typed results; the HTTP-shaped steps of
+// HTTPTaskAdaptor remain for relays that forward the raw provider response.
 type TaskAdaptorInterface interface {
 	// Init initializes the adaptor with relay information
 	Init(info *TaskRelayInfo)
@@ -13,6 +18,24 @@ type TaskAdaptorInterface interface {
 	// ValidateRequestAndSetAction validates the request and sets the action
 	ValidateRequestAndSetAction(requestBody []byte, action string) (*VidgoSubmitReq, *TaskAdaptorError)
 
+	// Submit creates a task for a validated request. Errors are *TaskAdaptorError.
+	Submit(ctx context.Context, vidgoRequest *VidgoSubmitReq) (*GenerationResponse, error)
+
+	// Fetch returns the status of a task. Errors are *TaskAdaptorError.
+	Fetch(ctx context.Context, taskID string) (*TaskResult, error)
+
+	// GetModelList returns the list of supported models
+	GetModelList() []string
+
+	// GetChannelName returns the channel name
+	GetChannelName() string
+
+	HTTPTaskAdaptor
+}
+
+// HTTPTaskAdaptor is the HTTP-shaped workflow driven by ProcessVideoGeneration
+// and ProcessTaskFetch
+type HTTPTaskAdaptor interface {
 	// BuildRequestURL builds the request URL for the video generation API
 	BuildRequestURL(info *TaskRelayInfo) (string, error)
 
@@ -30,50 +53,24 @@ type TaskAdaptorInterface interface {
 
 	// FetchTask fetches the status of a video generation task
 	FetchTask(baseUrl, key string, taskID string) (*http.Response, error)
-
-	// GetModelList returns the list of supported models
-	GetModelList() []string
-
-	// GetChannelName returns the channel name
-	GetChannelName() string
 }
 
-// TaskAdaptor is a factory that creates vendor-specific adaptors
+// TaskAdaptor is a factory that creates vendor-specific adaptors.
+// ProcessVideoGeneration and ProcessTaskFetch are safe for concurrent use; the
+// delegate methods share one stateful adaptor and must not be used concurrently.
 type TaskAdaptor struct {
 	vendor string
 	impl   TaskAdaptorInterface
 }
 
 // TaskRelayInfo contains information needed for task relay
-type TaskRelayInfo struct {
-	ChannelType int
-	BaseUrl     string
-	ApiKey      string
-	Action      string
-}
+type TaskRelayInfo = adapters.TaskRelayInfo
 
 // TaskAdaptorError represents an error in task processing
-type TaskAdaptorError struct {
-	StatusCode int    `json:"status_code"`
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	LocalError bool   `json:"local_error"`
-}
-
-func (e *TaskAdaptorError) Error() string {
-	return e.Message
-}
+type TaskAdaptorError = adapters.TaskAdaptorError
 
 // VidgoSubmitReq represents a video generation request
-type VidgoSubmitReq struct {
-	Prompt   string                 `json:"prompt"`
-	Model    string                 `json:"model,omitempty"`
-	Mode     string                 `json:"mode,omitempty"`  // Mode: "std" or "pro", defaults to "std"
-	Image    string                 `json:"image,omitempty"` // Image URL for image-to-video
-	Size     string                 `json:"size,omitempty"`
-	Duration int                    `json:"duration,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
-}
+type VidgoSubmitReq = adapters.VidgoSubmitReq
 
 // TaskResponse represents a generic task response
 type TaskResponse[T any] struct {
@@ -93,46 +90,27 @@ func NewTaskAdaptor() *TaskAdaptor {
 
 // NewTaskAdaptorWithVendor creates a new TaskAdaptor with specified vendor
 func NewTaskAdaptorWithVendor(vendor string) *TaskAdaptor {
-	var impl TaskAdaptorInterface
-
-	switch vendor {
-	case "kling":
-		impl = NewKlingAdaptor()
-	default:
-		impl = NewKlingAdaptor() // Default to Kling
-	}
-
-	return &TaskAdaptor{
-		vendor: vendor,
-		impl:   impl,
-	}
+	a := &TaskAdaptor{vendor: vendor}
+	a.impl = a.newImpl()
+	return a
 }
 
 // ===== High-level workflow methods =====
 
 // ProcessVideoGeneration handles the complete video generation workflow
 func (a *TaskAdaptor) ProcessVideoGeneration(info *TaskRelayInfo, requestBody []byte) (taskID string, responseData []byte, taskErr *TaskAdaptorError) {
-	// Ensure impl is initialized
-	if a.impl == nil {
-		switch a.vendor {
-		case "kling":
-			a.impl = NewKlingAdaptor()
-		default:
-			a.impl = NewKlingAdaptor()
-		}
-	}
-
-	// Initialize the vendor-specific adaptor
-	a.impl.Init(info)
+	// Use a per-call adaptor so concurrent relays never share credentials
+	impl := a.newImpl()
+	impl.Init(info)
 
 	// Validate request and set action
-	vidgoRequest, taskErr := a.impl.ValidateRequestAndSetAction(requestBody, info.Action)
+	vidgoRequest, taskErr := impl.ValidateRequestAndSetAction(requestBody, info.Action)
 	if taskErr != nil {
 		return
 	}
 
 	// Build request URL
-	requestUrl, err := a.impl.BuildRequestURL(info)
+	requestUrl, err := impl.BuildRequestURL(info)
 	if err != nil {
 		taskErr = &TaskAdaptorError{
 			StatusCode: 500,
@@ -144,10 +122,10 @@ func (a *TaskAdaptor) ProcessVideoGeneration(info *TaskRelayInfo, requestBody []
 	}
 
 	// Build headers
-	headers := a.impl.BuildRequestHeader(info)
+	headers := impl.BuildRequestHeader(info)
 
 	// Build request body
-	requestBodyBytes, err := a.impl.BuildRequestBody(vidgoRequest)
+	requestBodyBytes, err := impl.BuildRequestBody(vidgoRequest)
 	if err != nil {
 		taskErr = &TaskAdaptorError{
 			StatusCode: 500,
@@ -159,10 +137,10 @@ func (a *TaskAdaptor) ProcessVideoGeneration(info *TaskRelayInfo, requestBody []
 	}
 
 	// Make the request
-	resp, err := a.impl.DoRequest(requestUrl, headers, requestBodyBytes)
+	resp, err := impl.DoRequest(requestUrl, headers, requestBodyBytes)
 	if err != nil {
 		taskErr = &TaskAdaptorError{
-			StatusCode: 500,
+			StatusCode: http.StatusBadGateway,
 			Code:       "request_failed",
 			Message:    err.Error(),
 			LocalError: true,
@@ -172,41 +150,44 @@ func (a *TaskAdaptor) ProcessVideoGeneration(info *TaskRelayInfo, requestBody []
 	defer resp.Body.Close()
 
 	// Process response
-	return a.impl.DoResponse(resp)
+	return impl.DoResponse(resp)
 }
 
 // ProcessTaskFetch handles the complete task status fetch workflow
 func (a *TaskAdaptor) ProcessTaskFetch(info *TaskRelayInfo, taskID string) (*http.Response, error) {
-	// Ensure impl is initialized
-	if a.impl == nil {
-		switch a.vendor {
-		case "kling":
-			a.impl = NewKlingAdaptor()
-		default:
-			a.impl = NewKlingAdaptor()
-		}
-	}
-
-	// Initialize the vendor-specific adaptor
-	a.impl.Init(info)
+	// Use a per-call adaptor so concurrent relays never share credentials
+	impl := a.newImpl()
+	impl.Init(info)
 
 	// Fetch task status
-	return a.impl.FetchTask(info.BaseUrl, info.ApiKey, taskID)
+	return impl.FetchTask(info.BaseUrl, info.ApiKey, taskID)
+}
+
+// SubmitTask validates a relay request and creates its task, returning the typed
+// response instead of the provider body. Errors are *TaskAdaptorError.
+func (a *TaskAdaptor) SubmitTask(ctx context.Context, info *TaskRelayInfo, requestBody []byte) (*GenerationResponse, error) {
+	impl := a.newImpl()
+	impl.Init(info)
+
+	vidgoRequest, taskErr := impl.ValidateRequestAndSetAction(requestBody, info.Action)
+	if taskErr != nil {
+		return nil, taskErr
+	}
+	return impl.Submit(ctx, vidgoRequest)
+}
+
+// FetchTaskResult returns the typed status of a task. Errors are *TaskAdaptorError.
+func (a *TaskAdaptor) FetchTaskResult(ctx context.Context, info *TaskRelayInfo, taskID string) (*TaskResult, error) {
+	impl := a.newImpl()
+	impl.Init(info)
+	return impl.Fetch(ctx, taskID)
 }
 
 // ===== Delegate methods for backward compatibility =====
 
 // Delegate all methods to the implementation
 func (a *TaskAdaptor) Init(info *TaskRelayInfo) {
-	// Ensure impl is initialized before calling Init
-	if a.impl == nil {
-		switch a.vendor {
-		case "kling":
-			a.impl = NewKlingAdaptor()
-		default:
-			a.impl = NewKlingAdaptor()
-		}
-	}
+	a.ensureImpl()
 	a.impl.Init(info)
 }
 
@@ -215,6 +196,16 @@ func (a *TaskAdaptor) ValidateRequestAndSetAction(requestBody []byte, action str
 	return a.impl.ValidateRequestAndSetAction(requestBody, action)
 }
 
+func (a *TaskAdaptor) Submit(ctx context.Context, vidgoRequest *VidgoSubmitReq) (*GenerationResponse, error) {
+	a.ensureImpl()
+	return a.impl.Submit(ctx, vidgoRequest)
+}
+
+func (a *TaskAdaptor) Fetch(ctx context.Context, taskID string) (*TaskResult, error) {
+	a.ensureImpl()
+	return a.impl.Fetch(ctx, taskID)
+}
+
 func (a *TaskAdaptor) BuildRequestURL(info *TaskRelayInfo) (string, error) {
 	a.ensureImpl()
 	return a.impl.BuildRequestURL(info)
@@ -258,12 +249,17 @@ func (a *TaskAdaptor) GetChannelName() string {
 // ensureImpl ensures that the implementation is initialized
 func (a *TaskAdaptor) ensureImpl() {
 	if a.impl == nil {
-		switch a.vendor {
-		case "kling":
-			a.impl = NewKlingAdaptor()
-		default:
-			a.impl = NewKlingAdaptor()
-		}
+		a.impl = a.newImpl()
+	}
+}
+
+// newImpl creates a fresh vendor-specific adaptor
+func (a *TaskAdaptor) newImpl() TaskAdaptorInterface {
+	switch a.vendor {
+	case "kling":
+		return &KlingAdaptor{}
+	default:
+		return &KlingAdaptor{} // Default to Kling
 	}
 }
 
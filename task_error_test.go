@@ -0,0 +1,49 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestKlingFailureReason(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat " + fakekling.FailMarker, Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+	if result.Status != TaskStatusFailed || result.Error == nil || result.Error.Message != "generation failed" {
+		t.Errorf("Expected failed task with task_status_msg as the error, got %+v", result)
+	}
+}
+
+func TestFailureWithoutReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"gen-1","state":"failed"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderLuma, &ProviderConfig{BaseURL: server.URL, APIKey: "test-key", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.GetGeneration(context.Background(), "gen-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Error == nil || result.Error.Message != "task failed" {
+		t.Errorf("Expected a default error message for a failed task, got %+v", result.Error)
+	}
+}
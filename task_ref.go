@@ -0,0 +1,133 @@
+package vidgo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// taskRefPrefix marks task IDs that encode a TaskRef
+const taskRefPrefix = "vgt_"
+
+// TaskRef identifies a relayed task together with the channel it was submitted
+// through, so a status fetch can be routed without extra headers
+type TaskRef struct {
+	Vendor    string `json:"vendor"`     // TaskAdaptor vendor, e.g. "kling"
+	ChannelID int    `json:"channel_id"` // The gateway's channel, see TaskRelayInfo.ChannelID
+	TaskID    string `json:"task_id"`    // The provider's task ID
+}
+
+// String encodes the ref as an opaque task ID that relays can hand to callers
+// instead of the provider task ID; ParseTaskRef decodes it
+func (r TaskRef) String() string {
+	raw := r.Vendor + ":" + strconv.Itoa(r.ChannelID) + ":" + r.TaskID
+	return taskRefPrefix + base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTaskRef decodes a task ID produced by TaskRef.String. It reports false
+// for plain provider task IDs.
+func ParseTaskRef(id string) (TaskRef, bool) {
+	encoded, ok := strings.CutPrefix(id, taskRefPrefix)
+	if !ok {
+		return TaskRef{}, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return TaskRef{}, false
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return TaskRef{}, false
+	}
+	channelID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return TaskRef{}, false
+	}
+	return TaskRef{Vendor: parts[0], ChannelID: channelID, TaskID: parts[2]}, true
+}
+
+// TaskRefStore remembers which channel submitted a task, e.g. backed by the
+// gateway's task table. Implementations must be safe for concurrent use.
+type TaskRefStore interface {
+	SaveTaskRef(ref TaskRef) error
+	// LoadTaskRef returns the ref of a provider task ID, or false if it is unknown
+	LoadTaskRef(taskID string) (TaskRef, bool, error)
+}
+
+// MemoryTaskRefStore is an in-process TaskRefStore
+type MemoryTaskRefStore struct {
+	mu   sync.RWMutex
+	refs map[string]TaskRef
+}
+
+// NewMemoryTaskRefStore creates an empty in-memory store
+func NewMemoryTaskRefStore() *MemoryTaskRefStore {
+	return &MemoryTaskRefStore{refs: make(map[string]TaskRef)}
+}
+
+// SaveTaskRef records ref by its provider task ID
+func (s *MemoryTaskRefStore) SaveTaskRef(ref TaskRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refs[ref.TaskID] = ref
+	return nil
+}
+
+// LoadTaskRef returns the ref recorded for a provider task ID
+func (s *MemoryTaskRefStore) LoadTaskRef(taskID string) (TaskRef, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ref, ok := s.refs[taskID]
+	return ref, ok, nil
+}
+
+// ChannelLookup returns the relay info of a gateway channel: its type, base URL
+// and API key
+type ChannelLookup func(channelID int) (*TaskRelayInfo, error)
+
+// TaskResolver routes status fetches to the channel that submitted the task.
+// Task IDs encoding a TaskRef resolve directly; plain provider task IDs are
+// looked up in Store.
+type TaskResolver struct {
+	Channels ChannelLookup
+	Store    TaskRefStore // Optional when callers only use TaskRef IDs
+}
+
+// Resolve returns the ref and channel relay info of a task ID. Unknown tasks
+// fail with ErrTaskNotFound.
+func (r *TaskResolver) Resolve(id string) (TaskRef, *TaskRelayInfo, error) {
+	ref, ok := ParseTaskRef(id)
+	if !ok && r.Store != nil {
+		var err error
+		if ref, ok, err = r.Store.LoadTaskRef(id); err != nil {
+			return TaskRef{}, nil, fmt.Errorf("failed to load task ref: %w", err)
+		}
+	}
+	if !ok {
+		return TaskRef{}, nil, &TaskNotFoundError{TaskID: id, Message: "no channel is known for this task"}
+	}
+
+	if r.Channels == nil {
+		return TaskRef{}, nil, fmt.Errorf("%w: task resolver has no channel lookup", ErrInvalidConfiguration)
+	}
+	info, err := r.Channels(ref.ChannelID)
+	if err != nil {
+		return TaskRef{}, nil, fmt.Errorf("failed to look up channel %d: %w", ref.ChannelID, err)
+	}
+	return ref, info, nil
+}
+
+// FetchTask resolves a task ID and fetches its status from the provider through
+// the vendor's TaskAdaptor
+func (r *TaskResolver) FetchTask(id string) (*http.Response, error) {
+	ref, info, err := r.Resolve(id)
+	if err != nil {
+		return nil, err
+	}
+	return NewTaskAdaptorWithVendor(ref.Vendor).ProcessTaskFetch(info, ref.TaskID)
+}
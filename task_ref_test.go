@@ -0,0 +1,73 @@
+package vidgo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestTaskRef(t *testing.T) {
+	ref := TaskRef{Vendor: "kling", ChannelID: 12, TaskID: "task:with:colons"}
+	id := ref.String()
+	if !strings.HasPrefix(id, "vgt_") {
+		t.Errorf("Expected a vgt_ task ID, got %s", id)
+	}
+	if parsed, ok := ParseTaskRef(id); !ok || parsed != ref {
+		t.Errorf("Expected %+v to round-trip, got %+v", ref, parsed)
+	}
+	for _, id := range []string{"831922345719271433", "vgt_not-base64!", "vgt_" + "a2xpbmc"} {
+		if _, ok := ParseTaskRef(id); ok {
+			t.Errorf("Expected %s not to parse as a TaskRef", id)
+		}
+	}
+}
+
+func TestTaskResolver(t *testing.T) {
+	server := httptest.NewServer(fakekling.New("ak", "sk"))
+	defer server.Close()
+
+	channels := map[int]*TaskRelayInfo{7: {ChannelID: 7, BaseUrl: server.URL, ApiKey: "ak,sk", Action: "generate"}}
+	store := NewMemoryTaskRefStore()
+	resolver := &TaskResolver{
+		Channels: func(channelID int) (*TaskRelayInfo, error) {
+			if info, ok := channels[channelID]; ok {
+				return info, nil
+			}
+			return nil, fmt.Errorf("unknown channel")
+		},
+		Store: store,
+	}
+
+	info := channels[7]
+	taskID, _, taskErr := NewTaskAdaptor().ProcessVideoGeneration(info, []byte(`{"prompt":"A cat walking","duration":5,"image":"https://example.com/cat.png"}`))
+	if taskErr != nil {
+		t.Fatalf("Failed to submit: %v", taskErr)
+	}
+	ref := TaskRef{Vendor: "kling", ChannelID: info.ChannelID, TaskID: taskID}
+	store.SaveTaskRef(ref)
+
+	for _, id := range []string{taskID, ref.String()} {
+		resp, err := resolver.FetchTask(id)
+		if err != nil {
+			t.Fatalf("Failed to fetch %s: %v", id, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), taskID) {
+			t.Errorf("Expected task %s from %s, got %d %s", taskID, id, resp.StatusCode, body)
+		}
+	}
+
+	if _, err := resolver.FetchTask("unknown-task"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for an unknown task, got %v", err)
+	}
+	if _, _, err := resolver.Resolve(TaskRef{Vendor: "kling", ChannelID: 8, TaskID: taskID}.String()); err == nil {
+		t.Error("Expected an error for an unknown channel")
+	}
+}
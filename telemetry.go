@@ -0,0 +1,247 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// TelemetryConfig configures opt-in usage reporting
+type TelemetryConfig struct {
+	// Endpoint receives TelemetryReport JSON documents by POST
+	Endpoint string
+
+	// Interval between reports, defaults to one hour
+	Interval time.Duration
+
+	// HTTPClient sends the reports, defaults to a client with a 10 second timeout
+	HTTPClient *http.Client
+}
+
+// TelemetryReport is the anonymized aggregate usage sent to the endpoint. It holds
+// counts only: no prompts, task IDs, credentials or error messages.
+type TelemetryReport struct {
+	InstanceID  string                   `json:"instance_id"` // random per Telemetry, not tied to the host
+	SDKVersion  string                   `json:"sdk_version"`
+	GoVersion   string                   `json:"go_version"`
+	OS          string                   `json:"os"`
+	Arch        string                   `json:"arch"`
+	PeriodStart time.Time                `json:"period_start"`
+	PeriodEnd   time.Time                `json:"period_end"`
+	Providers   map[string]ProviderUsage `json:"providers"`
+}
+
+// ProviderUsage counts the provider calls made during a report period
+type ProviderUsage struct {
+	Calls      int            `json:"calls"`
+	Errors     int            `json:"errors"`
+	ErrorRate  float64        `json:"error_rate"`
+	ErrorCodes map[string]int `json:"error_codes,omitempty"` // keyed by ErrorDetail codes, e.g. "rate_limited"
+}
+
+// Telemetry aggregates usage across clients and reports it periodically. It is
+// disabled unless set in ClientConfig.Telemetry or with SetDefaultTelemetry, and
+// is safe for concurrent use.
+type Telemetry struct {
+	config     TelemetryConfig
+	instanceID string
+
+	mu        sync.Mutex
+	start     time.Time
+	providers map[string]*ProviderUsage
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTelemetry starts reporting to config.Endpoint every config.Interval
+func NewTelemetry(config TelemetryConfig) (*Telemetry, error) {
+	if !strings.HasPrefix(config.Endpoint, "http://") && !strings.HasPrefix(config.Endpoint, "https://") {
+		return nil, fmt.Errorf("%w: telemetry endpoint must be an http(s) URL", ErrInvalidConfiguration)
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	t := &Telemetry{
+		config:     config,
+		instanceID: hex.EncodeToString(id),
+		start:      time.Now(),
+		providers:  make(map[string]*ProviderUsage),
+		stop:       make(chan struct{}),
+	}
+	go t.loop()
+	return t, nil
+}
+
+var (
+	defaultTelemetryMu sync.RWMutex
+	defaultTelemetry   *Telemetry
+)
+
+// SetDefaultTelemetry enables t for every client whose ClientConfig.Telemetry is
+// nil, so a service can opt in once at startup. Pass nil to disable it again.
+func SetDefaultTelemetry(t *Telemetry) {
+	defaultTelemetryMu.Lock()
+	defaultTelemetry = t
+	defaultTelemetryMu.Unlock()
+}
+
+// telemetry returns the client's telemetry, falling back to the default
+func (c *Client) telemetry() *Telemetry {
+	if c.config.Telemetry != nil {
+		return c.config.Telemetry
+	}
+	defaultTelemetryMu.RLock()
+	defer defaultTelemetryMu.RUnlock()
+	return defaultTelemetry
+}
+
+// Record counts a provider call and its error, if any
+func (t *Telemetry) Record(provider string, err error) {
+	provider = strings.ToLower(provider)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage, ok := t.providers[provider]
+	if !ok {
+		usage = &ProviderUsage{}
+		t.providers[provider] = usage
+	}
+	usage.Calls++
+	if err != nil {
+		usage.Errors++
+		if usage.ErrorCodes == nil {
+			usage.ErrorCodes = make(map[string]int)
+		}
+		usage.ErrorCodes[errorCode(err)]++
+	}
+}
+
+// Snapshot returns the usage aggregated since the last successful report
+func (t *Telemetry) Snapshot() TelemetryReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := TelemetryReport{
+		InstanceID:  t.instanceID,
+		SDKVersion:  adapters.SDKVersion,
+		GoVersion:   runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		PeriodStart: t.start,
+		PeriodEnd:   time.Now(),
+		Providers:   make(map[string]ProviderUsage, len(t.providers)),
+	}
+	for name, usage := range t.providers {
+		u := *usage
+		if u.Calls > 0 {
+			u.ErrorRate = float64(u.Errors) / float64(u.Calls)
+		}
+		if usage.ErrorCodes != nil {
+			u.ErrorCodes = make(map[string]int, len(usage.ErrorCodes))
+			for code, n := range usage.ErrorCodes {
+				u.ErrorCodes[code] = n
+			}
+		}
+		report.Providers[name] = u
+	}
+	return report
+}
+
+// Flush sends the current report and starts a new period. Nothing is sent when
+// no calls were recorded; on failure the counts are kept for the next report.
+func (t *Telemetry) Flush(ctx context.Context) error {
+	report := t.Snapshot()
+	if len(report.Providers) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", t.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNetworkError, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return &APIError{Code: resp.StatusCode, Message: "telemetry endpoint rejected the report"}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subtract(report)
+	t.start = report.PeriodEnd
+	return nil
+}
+
+// subtract removes reported counts, keeping calls recorded during the upload
+func (t *Telemetry) subtract(report TelemetryReport) {
+	for name, reported := range report.Providers {
+		usage := t.providers[name]
+		usage.Calls -= reported.Calls
+		usage.Errors -= reported.Errors
+		for code, n := range reported.ErrorCodes {
+			if usage.ErrorCodes[code] -= n; usage.ErrorCodes[code] == 0 {
+				delete(usage.ErrorCodes, code)
+			}
+		}
+		if usage.Calls == 0 {
+			delete(t.providers, name)
+		}
+	}
+}
+
+// loop reports every interval until Close is called
+func (t *Telemetry) loop() {
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), t.config.Interval)
+			t.Flush(ctx)
+			cancel()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// Close stops periodic reporting and sends the remaining usage
+func (t *Telemetry) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.stop)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = t.Flush(ctx)
+	})
+	return err
+}
@@ -0,0 +1,102 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestTelemetryReportsAggregateUsage(t *testing.T) {
+	var mu sync.Mutex
+	var reports []TelemetryReport
+	var raw []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var report TelemetryReport
+		json.Unmarshal(body, &report)
+		mu.Lock()
+		reports = append(reports, report)
+		raw = append(raw, string(body))
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	fake := fakekling.New("ak", "sk")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	telemetry, err := NewTelemetry(TelemetryConfig{Endpoint: collector.URL, Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create telemetry: %v", err)
+	}
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second, Telemetry: telemetry})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A secret prompt", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	client.GetGeneration(ctx, resp.TaskID)
+	client.GetGeneration(ctx, "missing-task")
+
+	if err := telemetry.Close(); err != nil {
+		t.Fatalf("Failed to flush telemetry: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(reports))
+	}
+	usage := reports[0].Providers["kling"]
+	if usage.Calls != 3 || usage.Errors != 1 || usage.ErrorCodes["task_not_found"] != 1 {
+		t.Errorf("Unexpected Kling usage: %+v", usage)
+	}
+	if reports[0].SDKVersion == "" || reports[0].InstanceID == "" {
+		t.Errorf("Expected SDK version and instance ID, got %+v", reports[0])
+	}
+	if strings.Contains(raw[0], "secret") || strings.Contains(raw[0], resp.TaskID) {
+		t.Errorf("Report leaks request data: %s", raw[0])
+	}
+
+	// Empty periods are not reported
+	if err := telemetry.Flush(ctx); err != nil || len(reports) != 1 {
+		t.Errorf("Expected no report for an empty period, got %d (%v)", len(reports), err)
+	}
+}
+
+func TestTelemetryDisabledByDefault(t *testing.T) {
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if client.telemetry() != nil {
+		t.Error("Expected telemetry to be disabled by default")
+	}
+
+	telemetry, err := NewTelemetry(TelemetryConfig{Endpoint: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatalf("Failed to create telemetry: %v", err)
+	}
+	defer telemetry.Close()
+	SetDefaultTelemetry(telemetry)
+	defer SetDefaultTelemetry(nil)
+	if client.telemetry() != telemetry {
+		t.Error("Expected the default telemetry to be used")
+	}
+
+	if _, err := NewTelemetry(TelemetryConfig{}); err == nil {
+		t.Error("Expected an error without endpoint")
+	}
+}
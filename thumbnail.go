@@ -0,0 +1,525 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Decodes Motion JPEG keyframes
+	"image/png"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Thumbnail sources, from best to worst
+const (
+	ThumbnailProvider    = "provider"    // The provider's cover image
+	ThumbnailEmbedded    = "embedded"    // Cover art embedded in the MP4
+	ThumbnailKeyframe    = "keyframe"    // The first keyframe of a Motion JPEG or PNG track, read in pure Go
+	ThumbnailFrame       = "frame"       // The first frame, extracted with ffmpeg
+	ThumbnailPlaceholder = "placeholder" // A blank gray image in the video's aspect ratio, not a frame
+)
+
+// maxPosterVideoSize bounds how much of a video is read to find its moov box
+// when the server ignores range requests
+const maxPosterVideoSize = 100 << 20
+
+// maxMoovSize bounds the moov box read for embedded cover art
+const maxMoovSize = 16 << 20
+
+// maxTopLevelBoxes bounds the top-level MP4 boxes skipped looking for moov
+const maxTopLevelBoxes = 64
+
+// maxKeyframeSize bounds the sample read for a keyframe thumbnail
+const maxKeyframeSize = 8 << 20
+
+// stillImageFormats are the MP4 sample formats whose samples are images the
+// standard library decodes, so a keyframe is a frame without ffmpeg
+var stillImageFormats = map[string]bool{"jpeg": true, "mjpa": true, "png ": true}
+
+// maxPlaceholderWidth bounds the width of placeholder thumbnails
+const maxPlaceholderWidth = 640
+
+// ffmpegPath is the ffmpeg binary used to extract frames, looked up in PATH
+var ffmpegPath = "ffmpeg"
+
+// Thumbnail is a poster image of a finished video
+type Thumbnail struct {
+	Data        []byte `json:"-"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	Source      string `json:"source"` // ThumbnailProvider, ThumbnailEmbedded, ThumbnailKeyframe, ThumbnailFrame or ThumbnailPlaceholder
+}
+
+// Thumbnail returns a poster image of a succeeded task. It prefers the provider's
+// cover image, then cover art embedded in the MP4, then the first keyframe of a
+// Motion JPEG or PNG track, then the first frame when ffmpeg is installed, and
+// otherwise returns a blank placeholder in the video's aspect ratio, so callers
+// always get an image. H.264 and H.265 frames are not decoded without ffmpeg.
+// Only the MP4's moov box and keyframe are fetched, with range requests when
+// the server supports them.
+func (c *Client) Thumbnail(ctx context.Context, result *TaskResult) (*Thumbnail, error) {
+	if result == nil || result.Status != TaskStatusSucceeded {
+		return nil, fmt.Errorf("task has not succeeded")
+	}
+
+	for _, asset := range result.Assets {
+		if asset.Kind != AssetThumbnail {
+			continue
+		}
+		var buf bytes.Buffer
+		target := &downloadTarget{w: &buf, reset: func() error {
+			buf.Reset()
+			return nil
+		}}
+		if err := c.download(ctx, asset.URL, target); err == nil {
+			return newThumbnail(buf.Bytes(), ThumbnailProvider), nil
+		} else if c.config.Debug {
+			fmt.Printf("Failed to download thumbnail of task %s: %v\n", result.TaskID, err)
+		}
+	}
+
+	var width, height int
+	if result.Metadata != nil {
+		width, height = result.Metadata.Width, result.Metadata.Height
+	}
+	if result.URL == "" {
+		return placeholderThumbnail(width, height)
+	}
+
+	if moov, err := c.fetchMoov(ctx, result.URL); err == nil {
+		poster := parseMoovPoster(moov)
+		if poster.cover != nil {
+			return newThumbnail(poster.cover, ThumbnailEmbedded), nil
+		}
+		if poster.keyframe != nil {
+			if frame, err := c.fetchKeyframe(ctx, result.URL, *poster.keyframe); err == nil {
+				return frame, nil
+			} else if c.config.Debug {
+				fmt.Printf("Failed to read the keyframe of task %s for a thumbnail: %v\n", result.TaskID, err)
+			}
+		}
+		if poster.width > 0 && poster.height > 0 {
+			width, height = poster.width, poster.height
+		}
+	} else if c.config.Debug {
+		fmt.Printf("Failed to read the MP4 header of task %s for a thumbnail: %v\n", result.TaskID, err)
+	}
+
+	if frame, err := extractFrame(ctx, result.URL); err == nil {
+		return newThumbnail(frame, ThumbnailFrame), nil
+	} else if c.config.Debug {
+		fmt.Printf("Failed to extract a frame of task %s: %v\n", result.TaskID, err)
+	}
+	return placeholderThumbnail(width, height)
+}
+
+// fetchKeyframe reads a still-image sample of the MP4 at url and checks it decodes
+func (c *Client) fetchKeyframe(ctx context.Context, url string, sample mp4Sample) (*Thumbnail, error) {
+	if sample.size > maxKeyframeSize {
+		return nil, fmt.Errorf("keyframe of %d bytes is too large", sample.size)
+	}
+	var data []byte
+	if video, _, ok := adapters.DecodeDataURI(url); ok {
+		if sample.offset+sample.size > int64(len(video)) {
+			return nil, fmt.Errorf("keyframe is past the end of the video")
+		}
+		data = video[sample.offset : sample.offset+sample.size]
+	} else {
+		var err error
+		if data, err = c.readRange(ctx, url, sample.offset, sample.size); err != nil {
+			return nil, err
+		}
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("undecodable %q keyframe: %w", sample.format, err)
+	}
+	return newThumbnail(data, ThumbnailKeyframe), nil
+}
+
+// extractFrame decodes the first frame of the video at url into a PNG with ffmpeg
+func extractFrame(ctx context.Context, url string) ([]byte, error) {
+	path, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "-v", "error", "-i", url, "-frames:v", "1", "-f", "image2pipe", "-vcodec", "png", "-")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("ffmpeg returned no frame")
+	}
+	return stdout.Bytes(), nil
+}
+
+// fetchMoov returns the body of the moov box of the MP4 at url. Top-level boxes
+// before it, such as the media data, are skipped by reading only box headers
+// with range requests; servers that ignore ranges are read sequentially up to
+// maxPosterVideoSize.
+func (c *Client) fetchMoov(ctx context.Context, url string) ([]byte, error) {
+	if data, _, ok := adapters.DecodeDataURI(url); ok {
+		if moov, ok := mp4Box(data, "moov"); ok {
+			return moov, nil
+		}
+		return nil, fmt.Errorf("no moov box")
+	}
+
+	resp, err := c.getRange(ctx, url, 0, 16)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		defer resp.Body.Close()
+		return readMoov(io.LimitReader(resp.Body, maxPosterVideoSize))
+	}
+	header, err := readRangeBody(resp, 0, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	var offset int64
+	for i := 0; i < maxTopLevelBoxes; i++ {
+		size, headerSize, boxType, err := readBoxHeader(bytes.NewReader(header))
+		if err != nil {
+			return nil, err
+		}
+		if boxType == "moov" {
+			length := size - headerSize
+			if size == 0 {
+				length = -1 // The box extends to the end of the file
+			} else if length > maxMoovSize {
+				return nil, fmt.Errorf("moov box of %d bytes is too large", size)
+			}
+			return c.readRange(ctx, url, offset+headerSize, length)
+		}
+		if size == 0 {
+			return nil, fmt.Errorf("no moov box")
+		}
+
+		offset += size
+		if header, err = c.readRange(ctx, url, offset, 16); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("no moov box in the first %d boxes", maxTopLevelBoxes)
+}
+
+// readMoov reads top-level boxes from r until the moov box and returns its body
+func readMoov(r io.Reader) ([]byte, error) {
+	for i := 0; i < maxTopLevelBoxes; i++ {
+		size, headerSize, boxType, err := readBoxHeader(r)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case boxType == "moov" && size == 0:
+			return readCapped(r, maxMoovSize)
+		case boxType == "moov":
+			if size-headerSize > maxMoovSize {
+				return nil, fmt.Errorf("moov box of %d bytes is too large", size)
+			}
+			moov := make([]byte, size-headerSize)
+			if _, err := io.ReadFull(r, moov); err != nil {
+				return nil, fmt.Errorf("truncated moov box: %w", err)
+			}
+			return moov, nil
+		case size == 0:
+			return nil, fmt.Errorf("no moov box")
+		}
+		if _, err := io.CopyN(io.Discard, r, size-headerSize); err != nil {
+			return nil, fmt.Errorf("no moov box")
+		}
+	}
+	return nil, fmt.Errorf("no moov box in the first %d boxes", maxTopLevelBoxes)
+}
+
+// readBoxHeader reads an MP4 box header, returning the box size (0 when the box
+// extends to the end of the file), the header size and the box type
+func readBoxHeader(r io.Reader) (int64, int64, string, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header[:8]); err != nil {
+		return 0, 0, "", fmt.Errorf("no moov box")
+	}
+	size, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+	if size == 1 {
+		if _, err := io.ReadFull(r, header[8:]); err != nil {
+			return 0, 0, "", fmt.Errorf("truncated box header")
+		}
+		size, headerSize = int64(binary.BigEndian.Uint64(header[8:])), 16
+	}
+	if size != 0 && size < headerSize {
+		return 0, 0, "", fmt.Errorf("malformed %q box", header[4:8])
+	}
+	return size, headerSize, string(header[4:8]), nil
+}
+
+// readRange reads length bytes of url from offset, or up to maxMoovSize bytes
+// to the end when length is negative
+func (c *Client) readRange(ctx context.Context, url string, offset, length int64) ([]byte, error) {
+	resp, err := c.getRange(ctx, url, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return readRangeBody(resp, offset, length)
+}
+
+// getRange requests length bytes of url from offset, to the end when length is negative
+func (c *Client) getRange(ctx context.Context, url string, offset, length int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if length < 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	}
+	return c.httpClient().Do(req)
+}
+
+// readRangeBody reads the body of a range response, which must start at offset
+func readRangeBody(resp *http.Response, offset, length int64) ([]byte, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return nil, fmt.Errorf("no moov box")
+	}
+	if resp.StatusCode != http.StatusPartialContent || !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+		return nil, fmt.Errorf("unexpected range response: HTTP %d %s", resp.StatusCode, resp.Header.Get("Content-Range"))
+	}
+	if length < 0 {
+		return readCapped(resp.Body, maxMoovSize)
+	}
+	return readCapped(resp.Body, length)
+}
+
+// readCapped reads r to the end, failing beyond max bytes
+func readCapped(r io.Reader, max int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, fmt.Errorf("box is larger than %d bytes", max)
+	}
+	return data, nil
+}
+
+// newThumbnail describes image data, decoding only its header
+func newThumbnail(data []byte, source string) *Thumbnail {
+	thumbnail := &Thumbnail{Data: data, ContentType: http.DetectContentType(data), Source: source}
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		thumbnail.Width, thumbnail.Height = config.Width, config.Height
+	}
+	return thumbnail
+}
+
+// placeholderThumbnail encodes a blank PNG in the aspect ratio of width x height,
+// 16:9 when unknown
+func placeholderThumbnail(width, height int) (*Thumbnail, error) {
+	if width <= 0 || height <= 0 {
+		width, height = 1280, 720
+	}
+	if width > maxPlaceholderWidth {
+		width, height = maxPlaceholderWidth, max(height*maxPlaceholderWidth/width, 1)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{Y: 0x20}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode placeholder thumbnail: %w", err)
+	}
+	return &Thumbnail{Data: buf.Bytes(), ContentType: "image/png", Width: width, Height: height, Source: ThumbnailPlaceholder}, nil
+}
+
+// mp4Poster is what parseMoovPoster finds in an MP4
+type mp4Poster struct {
+	cover         []byte     // moov/udta/meta/ilst/covr image, nil when absent
+	width, height int        // Display size of the first video track
+	keyframe      *mp4Sample // First keyframe of a still-image video track, nil when absent
+}
+
+// mp4Sample locates a sample in an MP4 file
+type mp4Sample struct {
+	format       string // Sample entry format of the track, e.g. "jpeg"
+	offset, size int64
+}
+
+// parseMoovPoster reads the embedded cover art and the video track size from
+// the body of a moov box. Missing or malformed boxes leave the fields empty.
+func parseMoovPoster(moov []byte) mp4Poster {
+	var poster mp4Poster
+	for _, trak := range mp4Boxes(moov, "trak") {
+		if tkhd, ok := mp4Box(trak, "tkhd"); ok {
+			if poster.width, poster.height = tkhdSize(tkhd); poster.width > 0 {
+				break
+			}
+		}
+	}
+
+	for _, trak := range mp4Boxes(moov, "trak") {
+		if sample, ok := trackKeyframe(trak); ok && stillImageFormats[sample.format] {
+			poster.keyframe = &sample
+			break
+		}
+	}
+
+	box, ok := mp4Box(moov, "udta")
+	if ok {
+		box, ok = mp4Box(box, "meta")
+	}
+	if ok && len(box) >= 4 {
+		box, ok = mp4Box(box[4:], "ilst") // Skip the full box version and flags
+	}
+	if ok {
+		box, ok = mp4Box(box, "covr")
+	}
+	if ok {
+		box, ok = mp4Box(box, "data")
+	}
+	if ok && len(box) > 8 {
+		poster.cover = box[8:] // Skip the type indicator and locale
+	}
+	return poster
+}
+
+// tkhdSize reads the 16.16 fixed-point width and height of a track header box
+func tkhdSize(body []byte) (int, int) {
+	offset := 76 // version 0: 4 flags + 20 times and IDs + 8 reserved + 8 layer to volume + 36 matrix
+	if len(body) > 0 && body[0] == 1 {
+		offset = 88 // version 1 uses 64-bit times
+	}
+	if len(body) < offset+8 {
+		return 0, 0
+	}
+	return int(binary.BigEndian.Uint32(body[offset:]) >> 16), int(binary.BigEndian.Uint32(body[offset+4:]) >> 16)
+}
+
+// trackKeyframe locates the first sync sample of a video track from its sample
+// tables: stss gives its number, stsc its chunk, stco or co64 the chunk offset
+// and stsz the sizes of the samples before it in the chunk
+func trackKeyframe(trak []byte) (mp4Sample, bool) {
+	var sample mp4Sample
+	mdia, ok := mp4Box(trak, "mdia")
+	if !ok {
+		return sample, false
+	}
+	if hdlr, ok := mp4Box(mdia, "hdlr"); !ok || len(hdlr) < 12 || string(hdlr[8:12]) != "vide" {
+		return sample, false
+	}
+	minf, ok := mp4Box(mdia, "minf")
+	if !ok {
+		return sample, false
+	}
+	stbl, ok := mp4Box(minf, "stbl")
+	if !ok {
+		return sample, false
+	}
+	stsd, ok := mp4Box(stbl, "stsd")
+	if !ok || len(stsd) < 16 {
+		return sample, false
+	}
+	sample.format = string(stsd[12:16])
+
+	// Without stss every sample is a sync sample
+	number := uint32(1)
+	if stss, ok := mp4Box(stbl, "stss"); ok {
+		if len(stss) < 12 || binary.BigEndian.Uint32(stss[4:]) == 0 {
+			return sample, false
+		}
+		number = binary.BigEndian.Uint32(stss[8:])
+	}
+
+	stsz, ok := mp4Box(stbl, "stsz")
+	if !ok || len(stsz) < 12 || number == 0 || number > binary.BigEndian.Uint32(stsz[8:]) {
+		return sample, false
+	}
+	sampleSize := func(n uint32) (int64, bool) {
+		if size := binary.BigEndian.Uint32(stsz[4:]); size != 0 {
+			return int64(size), true
+		}
+		at := 12 + 4*int(n-1)
+		if len(stsz) < at+4 {
+			return 0, false
+		}
+		return int64(binary.BigEndian.Uint32(stsz[at:])), true
+	}
+
+	chunk, first, ok := sampleChunk(stbl, number)
+	if !ok {
+		return sample, false
+	}
+	if sample.offset, ok = chunkOffset(stbl, chunk); !ok {
+		return sample, false
+	}
+	for n := first; n < number; n++ {
+		size, ok := sampleSize(n)
+		if !ok {
+			return sample, false
+		}
+		sample.offset += size
+	}
+	sample.size, ok = sampleSize(number)
+	return sample, ok
+}
+
+// sampleChunk returns the 1-based chunk holding a sample and the number of the
+// chunk's first sample, from the stsc runs of chunks with the same sample count
+func sampleChunk(stbl []byte, number uint32) (uint32, uint32, bool) {
+	stsc, ok := mp4Box(stbl, "stsc")
+	if !ok || len(stsc) < 8 {
+		return 0, 0, false
+	}
+	count := int(binary.BigEndian.Uint32(stsc[4:]))
+	if len(stsc) < 8+12*count {
+		return 0, 0, false
+	}
+	before := uint32(0) // Samples in the runs before the current one
+	for i := 0; i < count; i++ {
+		entry := stsc[8+12*i:]
+		firstChunk, perChunk := binary.BigEndian.Uint32(entry), binary.BigEndian.Uint32(entry[4:])
+		if perChunk == 0 {
+			return 0, 0, false
+		}
+		if i+1 < count {
+			chunks := binary.BigEndian.Uint32(stsc[8+12*(i+1):]) - firstChunk
+			if number-1 >= before+chunks*perChunk {
+				before += chunks * perChunk
+				continue
+			}
+		}
+		index := (number - 1 - before) / perChunk
+		return firstChunk + index, before + index*perChunk + 1, true
+	}
+	return 0, 0, false
+}
+
+// chunkOffset returns the file offset of a 1-based chunk from stco or co64
+func chunkOffset(stbl []byte, chunk uint32) (int64, bool) {
+	if stco, ok := mp4Box(stbl, "stco"); ok {
+		at := 8 + 4*int(chunk-1)
+		if len(stco) < at+4 {
+			return 0, false
+		}
+		return int64(binary.BigEndian.Uint32(stco[at:])), true
+	}
+	if co64, ok := mp4Box(stbl, "co64"); ok {
+		at := 8 + 8*int(chunk-1)
+		if len(co64) < at+8 {
+			return 0, false
+		}
+		return int64(binary.BigEndian.Uint64(co64[at:])), true
+	}
+	return 0, false
+}
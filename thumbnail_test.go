@@ -0,0 +1,243 @@
+package vidgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testBox encodes an MP4 box of the given type around the concatenated children
+func testBox(boxType string, children ...[]byte) []byte {
+	var body []byte
+	for _, child := range children {
+		body = append(body, child...)
+	}
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(box, boxType...), body...)
+}
+
+// testCoverMP4 encodes the boxes of an MP4 with a width x height video track and,
+// when set, embedded cover art
+func testCoverMP4(width, height int, cover []byte) []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], uint32(height)<<16)
+	moov := [][]byte{testBox("trak", testBox("tkhd", tkhd))}
+	if cover != nil {
+		data := append([]byte{0, 0, 0, 14, 0, 0, 0, 0}, cover...)
+		meta := append(make([]byte, 4), testBox("ilst", testBox("covr", testBox("data", data)))...)
+		moov = append(moov, testBox("udta", testBox("meta", meta)))
+	}
+	return append(testBox("ftyp", []byte("isom")), testBox("moov", moov...)...)
+}
+
+// testKeyframeMP4 encodes an MP4 whose Motion JPEG track starts with a sample
+// that is not a keyframe, followed by the keyframe
+func testKeyframeMP4(keyframe []byte) []byte {
+	u32 := func(values ...uint32) []byte {
+		var b []byte
+		for _, v := range values {
+			b = binary.BigEndian.AppendUint32(b, v)
+		}
+		return b
+	}
+	ftyp := testBox("ftyp", []byte("isom"))
+	mdat := testBox("mdat", append(make([]byte, 10), keyframe...))
+
+	hdlr := append(u32(0, 0), []byte("vide")...)
+	stsd := append(u32(0, 1, 16), []byte("jpeg")...)
+	stbl := testBox("stbl",
+		testBox("stsd", stsd),
+		testBox("stss", u32(0, 1, 2)),
+		testBox("stsz", u32(0, 0, 2, 10, uint32(len(keyframe)))),
+		testBox("stsc", u32(0, 1, 1, 2, 1)),
+		testBox("stco", u32(0, 1, uint32(len(ftyp)+8))),
+	)
+	trak := testBox("trak", testBox("mdia", testBox("hdlr", hdlr), testBox("minf", stbl)))
+	return append(append(ftyp, mdat...), testBox("moov", trak)...)
+}
+
+// countingWriter counts the bytes written to a response
+type countingWriter struct {
+	http.ResponseWriter
+	n *int64
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	atomic.AddInt64(w.n, int64(len(p)))
+	return w.ResponseWriter.Write(p)
+}
+
+// withoutFFmpeg hides ffmpeg from the test
+func withoutFFmpeg(t *testing.T) {
+	path := ffmpegPath
+	ffmpegPath = filepath.Join(t.TempDir(), "no-ffmpeg")
+	t.Cleanup(func() { ffmpegPath = path })
+}
+
+func TestThumbnail(t *testing.T) {
+	withoutFFmpeg(t)
+
+	cover := testPNG(64, 36)
+	// The media data comes before the moov box, as in videos without faststart
+	late := testCoverMP4(1920, 1080, cover)
+	late = append(append(late[:12:12], testBox("mdat", make([]byte, 4<<20))...), late[12:]...)
+	files := map[string][]byte{
+		"/cover.png":    testPNG(320, 180),
+		"/embedded.mp4": testCoverMP4(1920, 1080, cover),
+		"/plain.mp4":    testCoverMP4(720, 1280, nil),
+		"/late.mp4":     late,
+	}
+	var served int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w = countingWriter{w, &served}
+		if r.URL.Query().Has("ranges") {
+			http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(data))
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		result *TaskResult
+		source string
+		width  int
+		height int
+	}{
+		{"provider cover", &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/plain.mp4", Assets: []ResultAsset{
+			{URL: server.URL + "/plain.mp4", Kind: AssetVideo},
+			{URL: server.URL + "/cover.png", Kind: AssetThumbnail},
+		}}, ThumbnailProvider, 320, 180},
+		{"embedded cover", &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/embedded.mp4"}, ThumbnailEmbedded, 64, 36},
+		{"late cover", &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/late.mp4"}, ThumbnailEmbedded, 64, 36},
+		{"inline video", &TaskResult{Status: TaskStatusSucceeded, URL: "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(files["/plain.mp4"])}, ThumbnailPlaceholder, 640, 1137},
+		{"track size", &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/plain.mp4"}, ThumbnailPlaceholder, 640, 1137},
+		{"missing video", &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/missing.mp4", Metadata: &Metadata{Width: 1280, Height: 1280}}, ThumbnailPlaceholder, 640, 640},
+	}
+	for _, tt := range tests {
+		thumbnail, err := client.Thumbnail(ctx, tt.result)
+		if err != nil {
+			t.Errorf("%s: failed to get thumbnail: %v", tt.name, err)
+			continue
+		}
+		if thumbnail.Source != tt.source || thumbnail.Width != tt.width || thumbnail.Height != tt.height {
+			t.Errorf("%s: expected %s %dx%d, got %s %dx%d", tt.name, tt.source, tt.width, tt.height, thumbnail.Source, thumbnail.Width, thumbnail.Height)
+		}
+		if thumbnail.ContentType != "image/png" || len(thumbnail.Data) == 0 {
+			t.Errorf("%s: expected PNG data, got %s with %d bytes", tt.name, thumbnail.ContentType, len(thumbnail.Data))
+		}
+	}
+
+	if _, err := client.Thumbnail(ctx, &TaskResult{Status: TaskStatusProcessing}); err == nil {
+		t.Error("Expected an error for an unfinished task")
+	}
+
+	// With range support the media data is skipped
+	atomic.StoreInt64(&served, 0)
+	thumbnail, err := client.Thumbnail(ctx, &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/late.mp4?ranges=1"})
+	if err != nil || thumbnail.Source != ThumbnailEmbedded {
+		t.Fatalf("Expected the embedded cover, got %+v (%v)", thumbnail, err)
+	}
+	if n := atomic.LoadInt64(&served); n > 64<<10 {
+		t.Errorf("Expected only the MP4 header to be read, got %d bytes", n)
+	}
+}
+
+// fakeFFmpeg replaces ffmpeg with a script that outputs frame
+func fakeFFmpeg(t *testing.T, frame []byte) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake ffmpeg is a shell script")
+	}
+	dir := t.TempDir()
+	framePath := filepath.Join(dir, "frame.png")
+	if err := os.WriteFile(framePath, frame, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "ffmpeg")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat "+framePath+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := ffmpegPath
+	ffmpegPath = script
+	t.Cleanup(func() { ffmpegPath = path })
+}
+
+func TestThumbnailFrame(t *testing.T) {
+	fakeFFmpeg(t, testPNG(48, 27))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testCoverMP4(1920, 1080, nil))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	thumbnail, err := client.Thumbnail(context.Background(), &TaskResult{Status: TaskStatusSucceeded, URL: server.URL + "/v.mp4"})
+	if err != nil {
+		t.Fatalf("Failed to get thumbnail: %v", err)
+	}
+	if thumbnail.Source != ThumbnailFrame || thumbnail.Width != 48 || thumbnail.Height != 27 {
+		t.Errorf("Expected a 48x27 frame, got %s %dx%d", thumbnail.Source, thumbnail.Width, thumbnail.Height)
+	}
+}
+
+func TestThumbnailKeyframe(t *testing.T) {
+	withoutFFmpeg(t)
+
+	var frame bytes.Buffer
+	if err := jpeg.Encode(&frame, image.NewGray(image.Rect(0, 0, 96, 54)), nil); err != nil {
+		t.Fatal(err)
+	}
+	video := testKeyframeMP4(frame.Bytes())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(video))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	for _, url := range []string{server.URL + "/v.mp4", "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(video)} {
+		thumbnail, err := client.Thumbnail(context.Background(), &TaskResult{Status: TaskStatusSucceeded, URL: url})
+		if err != nil {
+			t.Fatalf("Failed to get thumbnail: %v", err)
+		}
+		if thumbnail.Source != ThumbnailKeyframe || thumbnail.ContentType != "image/jpeg" || thumbnail.Width != 96 || thumbnail.Height != 54 {
+			t.Errorf("Expected a 96x54 JPEG keyframe, got %s %s %dx%d", thumbnail.Source, thumbnail.ContentType, thumbnail.Width, thumbnail.Height)
+		}
+	}
+
+	// A track whose samples are not images falls back to the placeholder
+	h264 := bytes.Replace(video, []byte("jpeg"), []byte("avc1"), 1)
+	thumbnail, err := client.Thumbnail(context.Background(), &TaskResult{Status: TaskStatusSucceeded, URL: "data:video/mp4;base64," + base64.StdEncoding.EncodeToString(h264)})
+	if err != nil || thumbnail.Source != ThumbnailPlaceholder {
+		t.Errorf("Expected a placeholder for an H.264 track without ffmpeg, got %+v (%v)", thumbnail, err)
+	}
+}
@@ -0,0 +1,219 @@
+package vidgo
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TimelineEventType identifies a step in a task's lifecycle
+type TimelineEventType string
+
+const (
+	TimelineEventSubmitted     TimelineEventType = "submitted"
+	TimelineEventPolled        TimelineEventType = "polled"
+	TimelineEventStatusChanged TimelineEventType = "status_changed"
+	TimelineEventError         TimelineEventType = "error"
+)
+
+// TimelineEvent is a single entry in a task's history
+type TimelineEvent struct {
+	Type    TimelineEventType `json:"type"`
+	Status  TaskStatus        `json:"status,omitempty"`
+	Time    time.Time         `json:"time"`
+	Message string            `json:"message,omitempty"`
+
+	// Duration is the time the step took, e.g. a poll request or a download.
+	// For collapsed events it is the total over all of them.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Count is the number of identical consecutive events collapsed into this
+	// one, e.g. the polls of a long-running task, and LastTime is when the
+	// last of them happened. Both are zero for a single event.
+	Count    int       `json:"count,omitempty"`
+	LastTime time.Time `json:"last_time,omitempty"`
+}
+
+// occurrences returns the number of events collapsed into the event
+func (e TimelineEvent) occurrences() int {
+	if e.Count > 1 {
+		return e.Count
+	}
+	return 1
+}
+
+// end returns when the last of the events collapsed into the event happened
+func (e TimelineEvent) end() time.Time {
+	if !e.LastTime.IsZero() {
+		return e.LastTime
+	}
+	return e.Time
+}
+
+// Defaults bounding a TimelineStore
+const (
+	DefaultTimelineMaxTasks  = 10000
+	DefaultTimelineRetention = time.Hour
+)
+
+// TimelineStore keeps the state transition history of tasks in memory.
+// It is safe for concurrent use.
+type TimelineStore struct {
+	// MaxTasks caps the number of timelines kept; recording a new task beyond
+	// it evicts the oldest one. Zero keeps every task.
+	MaxTasks int
+
+	// Retention is how long a timeline is kept after its task finished, counted
+	// from the last event recorded once it did. Zero keeps finished tasks.
+	Retention time.Duration
+
+	mu        sync.RWMutex
+	timelines map[string]*timeline
+	order     *list.List // Task IDs, oldest first
+	lastSweep time.Time
+}
+
+// timeline is the history of a task and its place in the eviction order
+type timeline struct {
+	events   []TimelineEvent
+	element  *list.Element
+	finished time.Time // When the last event after the task finished was recorded
+}
+
+// NewTimelineStore creates an empty timeline store keeping at most
+// DefaultTimelineMaxTasks tasks, each for DefaultTimelineRetention after it
+// finished. Change MaxTasks and Retention before use to bound it differently.
+func NewTimelineStore() *TimelineStore {
+	return &TimelineStore{
+		MaxTasks:  DefaultTimelineMaxTasks,
+		Retention: DefaultTimelineRetention,
+		timelines: make(map[string]*timeline),
+		order:     list.New(),
+	}
+}
+
+// Record appends an event to a task's timeline. A status_changed event is
+// added automatically when a polled status differs from the last known one.
+// An event repeating the previous one, such as another poll with the same
+// status, is collapsed into it so timelines stay short however long a task runs.
+func (s *TimelineStore) Record(taskID string, event TimelineEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	t, ok := s.timelines[taskID]
+	if !ok {
+		for s.MaxTasks > 0 && len(s.timelines) >= s.MaxTasks {
+			s.remove(s.order.Front().Value.(string))
+		}
+		t = &timeline{element: s.order.PushBack(taskID)}
+		s.timelines[taskID] = t
+	}
+	if !t.finished.IsZero() || (event.Status != "" && !taskPending(event.Status)) {
+		t.finished = now
+	}
+
+	events := t.events
+	if n := len(events); n > 0 && repeats(events[n-1], event) {
+		last := &events[n-1]
+		last.Count = last.occurrences() + 1
+		last.LastTime = event.Time
+		last.Duration += event.Duration
+		return
+	}
+	if event.Type == TimelineEventPolled && event.Status != "" && event.Status != lastStatus(events) {
+		events = append(events, TimelineEvent{
+			Type:   TimelineEventStatusChanged,
+			Status: event.Status,
+			Time:   event.Time,
+		})
+	}
+	t.events = append(events, event)
+}
+
+// Get returns a copy of a task's timeline
+func (s *TimelineStore) Get(taskID string) []TimelineEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.timelines[taskID]
+	if !ok {
+		return nil
+	}
+	return append([]TimelineEvent(nil), t.events...)
+}
+
+// Len returns the number of tasks with a timeline
+func (s *TimelineStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.timelines)
+}
+
+// Delete removes a task's timeline
+func (s *TimelineStore) Delete(taskID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(taskID)
+}
+
+// sweep evicts the timelines of tasks finished more than Retention ago, at most
+// once per Retention so recording stays cheap; the caller holds mu
+func (s *TimelineStore) sweep(now time.Time) {
+	if s.Retention <= 0 || now.Sub(s.lastSweep) < s.Retention {
+		return
+	}
+	s.lastSweep = now
+	cutoff := now.Add(-s.Retention)
+	for taskID, t := range s.timelines {
+		if !t.finished.IsZero() && t.finished.Before(cutoff) {
+			s.remove(taskID)
+		}
+	}
+}
+
+// remove drops a task's timeline; the caller holds mu
+func (s *TimelineStore) remove(taskID string) {
+	if t, ok := s.timelines[taskID]; ok {
+		s.order.Remove(t.element)
+		delete(s.timelines, taskID)
+	}
+}
+
+// repeats reports whether event is a repetition of previous that can be collapsed into it
+func repeats(previous, event TimelineEvent) bool {
+	return previous.Type == event.Type && previous.Type != TimelineEventSubmitted &&
+		previous.Status == event.Status && previous.Message == event.Message
+}
+
+// lastStatus returns the most recent status recorded in events
+func lastStatus(events []TimelineEvent) TaskStatus {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Status != "" {
+			return events[i].Status
+		}
+	}
+	return ""
+}
+
+// GetTimeline returns the recorded history of a task, or nil if timelines are not enabled
+func (c *Client) GetTimeline(taskID string) []TimelineEvent {
+	if c.config.Timelines == nil {
+		return nil
+	}
+	return c.config.Timelines.Get(taskID)
+}
+
+// recordTimeline records an event if timelines are enabled
+func (c *Client) recordTimeline(taskID string, event TimelineEvent) {
+	if c.config.Timelines != nil && taskID != "" {
+		c.config.Timelines.Record(taskID, event)
+	}
+}
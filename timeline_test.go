@@ -0,0 +1,99 @@
+package vidgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineStore(t *testing.T) {
+	store := NewTimelineStore()
+	store.Record("task-1", TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusQueued})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusProcessing})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusSucceeded})
+
+	expected := []TimelineEventType{
+		TimelineEventSubmitted,
+		TimelineEventPolled,
+		TimelineEventStatusChanged,
+		TimelineEventPolled,
+		TimelineEventStatusChanged,
+		TimelineEventPolled,
+	}
+
+	events := store.Get("task-1")
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, want := range expected {
+		if events[i].Type != want {
+			t.Errorf("Expected event %d to be '%s', got '%s'", i, want, events[i].Type)
+		}
+		if events[i].Time.IsZero() {
+			t.Errorf("Expected event %d to have a timestamp", i)
+		}
+	}
+
+	store.Delete("task-1")
+	if len(store.Get("task-1")) != 0 {
+		t.Error("Expected timeline to be deleted")
+	}
+}
+
+func TestTimelineStoreCollapsesRepeats(t *testing.T) {
+	store := NewTimelineStore()
+	start := time.Now()
+	store.Record("task-1", TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued, Time: start})
+	for i := 1; i <= 100; i++ {
+		store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusProcessing, Time: start.Add(time.Duration(i) * time.Second), Duration: 10 * time.Millisecond})
+	}
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusSucceeded, Time: start.Add(101 * time.Second)})
+
+	events := store.Get("task-1")
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events, got %d: %+v", len(events), events)
+	}
+	polls := events[2]
+	if polls.Count != 100 || !polls.Time.Equal(start.Add(time.Second)) || !polls.LastTime.Equal(start.Add(100*time.Second)) {
+		t.Errorf("Expected 100 polls from 1s to 100s, got %+v", polls)
+	}
+	if polls.Duration != time.Second {
+		t.Errorf("Expected 1s of polling, got %v", polls.Duration)
+	}
+
+	timing := TimelineTiming(events)
+	if timing.Polls != 101 || timing.PollOverhead != time.Second {
+		t.Errorf("Expected 101 polls taking 1s, got %d taking %v", timing.Polls, timing.PollOverhead)
+	}
+	if timing.QueueWait != time.Second || timing.Processing != 100*time.Second || timing.Total != 101*time.Second {
+		t.Errorf("Unexpected timing: %+v", timing)
+	}
+}
+
+func TestTimelineStoreEviction(t *testing.T) {
+	store := NewTimelineStore()
+	store.MaxTasks = 2
+	for _, taskID := range []string{"task-1", "task-2", "task-3"} {
+		store.Record(taskID, TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued})
+	}
+	if store.Len() != 2 || store.Get("task-1") != nil || store.Get("task-3") == nil {
+		t.Errorf("Expected the oldest task to be evicted beyond MaxTasks, got %d tasks", store.Len())
+	}
+
+	// Finished tasks are evicted once Retention passed, running ones are kept
+	store = NewTimelineStore()
+	store.Retention = 10 * time.Millisecond
+	store.Record("finished", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusSucceeded})
+	store.Record("running", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusProcessing})
+	time.Sleep(20 * time.Millisecond)
+	store.Record("new", TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued})
+	if store.Get("finished") != nil {
+		t.Error("Expected the finished task to be evicted after Retention")
+	}
+	if store.Get("running") == nil || store.Len() != 2 {
+		t.Errorf("Expected the running and new tasks to be kept, got %d tasks", store.Len())
+	}
+	if stats := store.Stats(); stats.Tasks != 2 {
+		t.Errorf("Expected stats over the 2 kept tasks, got %d", stats.Tasks)
+	}
+}
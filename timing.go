@@ -0,0 +1,165 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Timeline events recording where the time of a task is spent
+const (
+	TimelineEventDownloaded TimelineEventType = "downloaded"
+	TimelineEventArchived   TimelineEventType = "archived"
+	TimelineEventTimedOut   TimelineEventType = "timed_out"
+	TimelineEventCanceled   TimelineEventType = "canceled"
+)
+
+// TaskTiming breaks a task's time down by phase. Provider phases are measured
+// between the polls that observed them, so they are accurate to the poll interval.
+type TaskTiming struct {
+	QueueWait    time.Duration `json:"queue_wait"`    // Submitted until the provider reported processing
+	Processing   time.Duration `json:"processing"`    // Processing until a terminal status
+	PollOverhead time.Duration `json:"poll_overhead"` // Time spent in status requests
+	Polls        int           `json:"polls"`
+	Download     time.Duration `json:"download"`
+	Archive      time.Duration `json:"archive"`
+	Total        time.Duration `json:"total"` // Submitted until the last event
+	TimedOut     int           `json:"timed_out"`
+	Canceled     int           `json:"canceled"`
+}
+
+// TimelineTiming summarizes the phases of a task from its timeline
+func TimelineTiming(events []TimelineEvent) TaskTiming {
+	var timing TaskTiming
+	if len(events) == 0 {
+		return timing
+	}
+
+	start := events[0].Time
+	var processing, finished time.Time
+	if events[0].Status == TaskStatusProcessing {
+		processing = start
+	}
+	for _, event := range events {
+		switch event.Type {
+		case TimelineEventStatusChanged:
+			if event.Status == TaskStatusProcessing && processing.IsZero() {
+				processing = event.Time
+			} else if !taskPending(event.Status) && finished.IsZero() {
+				finished = event.Time
+			}
+		case TimelineEventPolled:
+			timing.Polls += event.occurrences()
+			timing.PollOverhead += event.Duration
+		case TimelineEventDownloaded:
+			timing.Download += event.Duration
+		case TimelineEventArchived:
+			timing.Archive += event.Duration
+		case TimelineEventTimedOut:
+			timing.TimedOut += event.occurrences()
+		case TimelineEventCanceled:
+			timing.Canceled += event.occurrences()
+		}
+	}
+
+	switch {
+	case !processing.IsZero():
+		timing.QueueWait = processing.Sub(start)
+		if !finished.IsZero() {
+			timing.Processing = finished.Sub(processing)
+		}
+	case !finished.IsZero():
+		// The provider went straight from queued to a terminal status
+		timing.QueueWait = finished.Sub(start)
+	}
+	timing.Total = events[len(events)-1].end().Sub(start)
+	return timing
+}
+
+// TimingStats summarizes the phases of every task in a TimelineStore
+type TimingStats struct {
+	Tasks        int             `json:"tasks"`
+	QueueWait    LatencyEstimate `json:"queue_wait"`
+	Processing   LatencyEstimate `json:"processing"`
+	PollOverhead LatencyEstimate `json:"poll_overhead"`
+	Download     LatencyEstimate `json:"download"`
+	Archive      LatencyEstimate `json:"archive"`
+	TimedOut     int             `json:"timed_out"`
+	Canceled     int             `json:"canceled"`
+}
+
+// Stats computes phase percentiles over the stored timelines. Phases a task did
+// not reach, such as a download that never happened, are not sampled.
+func (s *TimelineStore) Stats() TimingStats {
+	s.mu.RLock()
+	timings := make([]TaskTiming, 0, len(s.timelines))
+	for _, t := range s.timelines {
+		timings = append(timings, TimelineTiming(t.events))
+	}
+	s.mu.RUnlock()
+
+	stats := TimingStats{Tasks: len(timings)}
+	var queueWait, processing, pollOverhead, download, archive []time.Duration
+	for _, timing := range timings {
+		if timing.QueueWait > 0 {
+			queueWait = append(queueWait, timing.QueueWait)
+		}
+		if timing.Processing > 0 {
+			processing = append(processing, timing.Processing)
+		}
+		if timing.Polls > 0 {
+			pollOverhead = append(pollOverhead, timing.PollOverhead)
+		}
+		if timing.Download > 0 {
+			download = append(download, timing.Download)
+		}
+		if timing.Archive > 0 {
+			archive = append(archive, timing.Archive)
+		}
+		stats.TimedOut += timing.TimedOut
+		stats.Canceled += timing.Canceled
+	}
+	stats.QueueWait = latencyEstimate(queueWait)
+	stats.Processing = latencyEstimate(processing)
+	stats.PollOverhead = latencyEstimate(pollOverhead)
+	stats.Download = latencyEstimate(download)
+	stats.Archive = latencyEstimate(archive)
+	return stats
+}
+
+// GetTaskTiming returns the phase breakdown of a task, or zero if timelines are not enabled
+func (c *Client) GetTaskTiming(taskID string) TaskTiming {
+	return TimelineTiming(c.GetTimeline(taskID))
+}
+
+// TimingStats returns phase percentiles over all recorded tasks, or zero if
+// timelines are not enabled
+func (c *Client) TimingStats() TimingStats {
+	if c.config.Timelines == nil {
+		return TimingStats{}
+	}
+	return c.config.Timelines.Stats()
+}
+
+// failureEvent records err after elapsed, telling a timeout or cancellation
+// of the caller's context apart from a provider error
+func failureEvent(err error, elapsed time.Duration) TimelineEvent {
+	event := TimelineEvent{Type: TimelineEventError, Message: err.Error(), Duration: elapsed}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		event.Type = TimelineEventTimedOut
+	case errors.Is(err, context.Canceled):
+		event.Type = TimelineEventCanceled
+	}
+	return event
+}
+
+// recordDownload records a finished or failed download of a task's video
+func (c *Client) recordDownload(taskID string, size int, elapsed time.Duration, err error) {
+	if err != nil {
+		c.recordTimeline(taskID, failureEvent(err, elapsed))
+		return
+	}
+	c.recordTimeline(taskID, TimelineEvent{Type: TimelineEventDownloaded, Duration: elapsed, Message: fmt.Sprintf("%d bytes", size)})
+}
@@ -0,0 +1,81 @@
+package vidgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestTimelineTiming(t *testing.T) {
+	start := time.Now()
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	store := NewTimelineStore()
+	store.Record("task-1", TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued, Time: at(0)})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusQueued, Time: at(10), Duration: time.Second})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusProcessing, Time: at(20), Duration: time.Second})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventPolled, Status: TaskStatusSucceeded, Time: at(60), Duration: 2 * time.Second})
+	store.Record("task-1", TimelineEvent{Type: TimelineEventDownloaded, Time: at(65), Duration: 5 * time.Second})
+
+	timing := TimelineTiming(store.Get("task-1"))
+	expected := TaskTiming{
+		QueueWait:    20 * time.Second,
+		Processing:   40 * time.Second,
+		PollOverhead: 4 * time.Second,
+		Polls:        3,
+		Download:     5 * time.Second,
+		Total:        65 * time.Second,
+	}
+	if timing != expected {
+		t.Errorf("Expected timing %+v, got %+v", expected, timing)
+	}
+
+	store.Record("task-2", TimelineEvent{Type: TimelineEventSubmitted, Status: TaskStatusQueued, Time: at(0)})
+	store.Record("task-2", TimelineEvent{Type: TimelineEventTimedOut, Time: at(30)})
+
+	stats := store.Stats()
+	if stats.Tasks != 2 || stats.TimedOut != 1 || stats.Processing.Samples != 1 || stats.Processing.P50 != 40*time.Second {
+		t.Errorf("Expected 2 tasks, 1 timeout and one 40s processing sample, got %+v", stats)
+	}
+}
+
+func TestClientTiming(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{Timeout: 5 * time.Second, Timelines: NewTimelineStore()})
+	ctx := context.Background()
+	req := &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720}
+
+	resp, err := client.CreateGeneration(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err := client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+	if _, err := client.Download(ctx, result); err != nil {
+		t.Fatalf("Failed to download video: %v", err)
+	}
+
+	timing := client.GetTaskTiming(resp.TaskID)
+	if timing.Polls != 3 || timing.PollOverhead <= 0 || timing.Download <= 0 {
+		t.Errorf("Expected 3 timed polls and a timed download, got %+v", timing)
+	}
+
+	resp, err = client.CreateGeneration(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.WaitForCompletion(canceled, resp.TaskID, time.Millisecond); err == nil {
+		t.Fatal("Expected a canceled wait to fail")
+	}
+	if timing := client.GetTaskTiming(resp.TaskID); timing.Canceled != 1 {
+		t.Errorf("Expected 1 cancellation, got %+v", timing)
+	}
+	if stats := client.TimingStats(); stats.Tasks != 2 || stats.Canceled != 1 {
+		t.Errorf("Expected 2 tasks and 1 cancellation, got %+v", stats)
+	}
+}
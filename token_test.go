@@ -0,0 +1,116 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenTTL(t *testing.T) {
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"processing"}}`))
+	}))
+	defer server.Close()
+
+	// tokenTTL returns the exp claim of the token sent for one status call, relative to now
+	tokenTTL := func(config *ClientConfig) time.Duration {
+		client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, config)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, err := client.GetGeneration(context.Background(), "task-1"); err != nil {
+			t.Fatalf("Failed to get generation: %v", err)
+		}
+
+		parts := strings.Split(token, ".")
+		if len(parts) != 3 {
+			t.Fatalf("Expected a JWT, got %q", token)
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatalf("Failed to decode JWT payload: %v", err)
+		}
+		var claims struct {
+			Exp int64 `json:"exp"`
+		}
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			t.Fatalf("Failed to parse JWT claims: %v", err)
+		}
+		return time.Until(time.Unix(claims.Exp, 0)).Round(time.Minute)
+	}
+
+	tests := []struct {
+		name     string
+		config   *ClientConfig
+		expected time.Duration
+	}{
+		{"default", &ClientConfig{Timeout: 5 * time.Second}, 30 * time.Minute},
+		{"deadline below min", &ClientConfig{Timeout: 5 * time.Second, TokenMinTTL: 2 * time.Minute, TokenMaxTTL: 10 * time.Minute}, 2 * time.Minute},
+		{"deadline within bounds", &ClientConfig{Timeout: 5 * time.Minute, TokenMaxTTL: 10 * time.Minute}, 5 * time.Minute},
+		{"deadline above max", &ClientConfig{Timeout: time.Hour, TokenMaxTTL: 10 * time.Minute}, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if ttl := tokenTTL(tt.config); ttl != tt.expected {
+			t.Errorf("%s: expected token TTL %v, got %v", tt.name, tt.expected, ttl)
+		}
+	}
+}
+
+func TestTokenCache(t *testing.T) {
+	var mu sync.Mutex
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"processing"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "test_access_key,test_secret_key"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetGeneration(ctx, "task-1")
+		}()
+	}
+	wg.Wait()
+
+	// A token signed a second later would carry a different exp claim
+	time.Sleep(1100 * time.Millisecond)
+	client.GetGeneration(ctx, "task-1")
+	client.GetGeneration(ctx, "task-1", WithAPIKey("other_access_key,other_secret_key"))
+
+	if len(tokens) != 10 {
+		t.Fatalf("Expected 10 requests, got %d", len(tokens))
+	}
+	for i, token := range tokens[:9] {
+		if token != tokens[0] {
+			t.Errorf("Expected request %d to reuse the cached token", i)
+		}
+	}
+	if tokens[9] == tokens[0] {
+		t.Error("Expected a per-call API key to use its own token")
+	}
+
+	if _, err := NewClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk", Extra: map[string]string{"token_refresh_margin": "soon"}}); err == nil {
+		t.Error("Expected an invalid token_refresh_margin to be rejected")
+	}
+}
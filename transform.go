@@ -0,0 +1,63 @@
+package vidgo
+
+import (
+	"sync"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// RegisterRequestTransformer registers a hook that is executed just before the
+// provider-native payload is marshaled. It allows setting provider fields that
+// vidgo does not model yet without forking the adapter.
+//
+// For Kling the payload is a *kling.KlingGenerationRequest (SDK client) or a
+// *KlingRequest (task adaptor); the returned value is what gets marshaled.
+func RegisterRequestTransformer(provider ProviderType, fn func(providerPayload any) any) {
+	adapters.RegisterRequestTransformer(string(provider), fn)
+}
+
+// ClearRequestTransformers removes all request transformers registered for the provider
+func ClearRequestTransformers(provider ProviderType) {
+	adapters.ClearRequestTransformers(string(provider))
+}
+
+// ResponseTransformer reads the raw provider response and enriches the parsed TaskResult
+type ResponseTransformer func(rawResponse []byte, result *TaskResult)
+
+var (
+	responseMu           sync.RWMutex
+	responseTransformers = map[ProviderType][]ResponseTransformer{}
+)
+
+// RegisterResponseTransformer registers a hook that runs after a provider task
+// response has been parsed. Hooks can extract custom metadata or flags into
+// TaskResult.Extra without changing adapter code.
+func RegisterResponseTransformer(provider ProviderType, fn ResponseTransformer) {
+	if fn == nil {
+		return
+	}
+
+	responseMu.Lock()
+	defer responseMu.Unlock()
+
+	responseTransformers[provider] = append(responseTransformers[provider], fn)
+}
+
+// ClearResponseTransformers removes all response transformers registered for the provider
+func ClearResponseTransformers(provider ProviderType) {
+	responseMu.Lock()
+	defer responseMu.Unlock()
+
+	delete(responseTransformers, provider)
+}
+
+// transformResponse applies all response transformers registered for the provider
+func transformResponse(provider ProviderType, rawResponse []byte, result *TaskResult) {
+	responseMu.RLock()
+	fns := responseTransformers[provider]
+	responseMu.RUnlock()
+
+	for _, fn := range fns {
+		fn(rawResponse, result)
+	}
+}
@@ -0,0 +1,83 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestTransformer(t *testing.T) {
+	defer ClearRequestTransformers(ProviderKling)
+
+	RegisterRequestTransformer(ProviderKling, func(payload any) any {
+		req, ok := payload.(*KlingRequest)
+		if !ok {
+			t.Fatalf("Expected *KlingRequest payload, got %T", payload)
+		}
+		return map[string]interface{}{
+			"prompt":     req.Prompt,
+			"beta_param": "on",
+		}
+	})
+
+	adaptor := NewKlingAdaptor()
+	adaptor.Init(&TaskRelayInfo{ApiKey: "ak,sk"})
+
+	body, err := adaptor.BuildRequestBody(&VidgoSubmitReq{Prompt: "Test prompt"})
+	if err != nil {
+		t.Fatalf("Failed to build request body: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Failed to decode request body: %v", err)
+	}
+
+	if payload["beta_param"] != "on" {
+		t.Errorf("Expected beta_param 'on', got '%v'", payload["beta_param"])
+	}
+	if payload["prompt"] != "Test prompt" {
+		t.Errorf("Expected prompt 'Test prompt', got '%v'", payload["prompt"])
+	}
+}
+
+func TestResponseTransformer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"ok","data":{"id":"task-1","status":"succeed","watermark":true,` +
+			`"task_result":{"videos":[{"id":"v1","url":"https://cdn.example.com/v1.mp4","duration":"5"}]}}}`))
+	}))
+	defer server.Close()
+
+	defer ClearResponseTransformers(ProviderKling)
+	RegisterResponseTransformer(ProviderKling, func(raw []byte, result *TaskResult) {
+		if strings.Contains(string(raw), `"watermark":true`) {
+			result.Extra = map[string]interface{}{"watermark": true}
+		}
+	})
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.GetGeneration(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	if result.Status != TaskStatusSucceeded {
+		t.Errorf("Expected status '%s', got '%s'", TaskStatusSucceeded, result.Status)
+	}
+	if result.Extra["watermark"] != true {
+		t.Errorf("Expected watermark flag in Extra, got %v", result.Extra)
+	}
+}
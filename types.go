@@ -1,6 +1,10 @@
 package vidgo
 
-import "time"
+import (
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
 
 // TaskStatus represents the status of a video generation task
 type TaskStatus string
@@ -29,26 +33,89 @@ const (
 	QualityLevelHigh     QualityLevel = "high"
 )
 
+// Generation modes, Kling only
+const (
+	ModeStandard = adapters.ModeStandard
+	ModePro      = adapters.ModePro
+)
+
 // GenerationRequest represents a video generation request
 type GenerationRequest struct {
 	Prompt         string                 `json:"prompt,omitempty"`
-	Image          string                 `json:"image,omitempty"`
+	NegativePrompt string                 `json:"negative_prompt,omitempty"` // Content to avoid, see provider limits
+	Image          string                 `json:"image,omitempty"`           // URL, Base64 or data URI
+	ImageTail      string                 `json:"image_tail,omitempty"`      // End frame, URL, Base64 or data URI
+	Images         []string               `json:"images,omitempty"`          // Reference images for multi-image to video, see provider limits
+	Keyframes      []Keyframe             `json:"keyframes,omitempty"`       // Images at given times, Kling and Luma take the first and last frame
+	ImageBytes     []byte                 `json:"-"`                         // Raw image data, mutually exclusive with Image
+	ImageFile      string                 `json:"-"`                         // Local image path, read into ImageBytes before submission
 	Style          string                 `json:"style,omitempty"`
+	Mode           string                 `json:"mode,omitempty"` // ModeStandard or ModePro, QualityLevelHigh implies pro; replaces metadata.mode
 	Duration       float64                `json:"duration"`
 	FPS            int                    `json:"fps,omitempty"`
 	Width          int                    `json:"width"`
 	Height         int                    `json:"height"`
+	Size           string                 `json:"size,omitempty"` // Size preset keyword, used when Width/Height are not set
 	ResponseFormat ResponseFormat         `json:"response_format,omitempty"`
 	QualityLevel   QualityLevel           `json:"quality_level,omitempty"`
 	Seed           *int                   `json:"seed,omitempty"`
+	GuidanceScale  *float64               `json:"guidance_scale,omitempty"` // Prompt adherence in [0, 1], Kling cfg_scale
 	Model          string                 `json:"model,omitempty"`
+	CameraControl  *CameraControl         `json:"camera_control,omitempty"` // Camera movement, Kling only
+	MotionBrush    *MotionBrush           `json:"motion_brush,omitempty"`   // Static/dynamic masks, Kling image-to-video only
+	CallbackURL    string                 `json:"callback_url,omitempty"`   // Called by the provider on task completion, Kling and Replicate only
+	ClientTaskID   string                 `json:"client_task_id,omitempty"` // Caller's own ID for the task, see GetGenerationByClientID; Kling only
+	Watermark      WatermarkPolicy        `json:"watermark,omitempty"`      // Visible watermark, see provider support
+	Residency      string                 `json:"residency,omitempty"`      // ResidencyCNOnly or ResidencyNonCN, see ProviderConfig.Region
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Keyframe is an image the video shows at a point in time
+type Keyframe = adapters.Keyframe
+
+// WatermarkPolicy controls the visible watermark on generated videos
+type WatermarkPolicy = adapters.WatermarkPolicy
+
+// Watermark policies
+const (
+	WatermarkDefault = adapters.WatermarkDefault
+	WatermarkNone    = adapters.WatermarkNone
+	WatermarkVisible = adapters.WatermarkVisible
+)
+
+// CameraControl describes the camera movement of a generated video
+type CameraControl = adapters.CameraControl
+
+// CameraConfig sets a simple camera movement
+type CameraConfig = adapters.CameraConfig
+
+// CameraControlType selects a simple or preset camera movement
+type CameraControlType = adapters.CameraControlType
+
+// Camera movement types
+const (
+	CameraControlSimple           = adapters.CameraControlSimple
+	CameraControlDownBack         = adapters.CameraControlDownBack
+	CameraControlForwardUp        = adapters.CameraControlForwardUp
+	CameraControlRightTurnForward = adapters.CameraControlRightTurnForward
+	CameraControlLeftTurnForward  = adapters.CameraControlLeftTurnForward
+)
+
+// MotionBrush marks regions of the input image that stay still or move along trajectories
+type MotionBrush = adapters.MotionBrush
+
+// DynamicMask moves the masked region along a trajectory
+type DynamicMask = adapters.DynamicMask
+
+// TrajectoryPoint is a pixel position in the input image
+type TrajectoryPoint = adapters.TrajectoryPoint
+
 // GenerationResponse represents the response from creating a generation task
 type GenerationResponse struct {
-	TaskID string     `json:"task_id"`
-	Status TaskStatus `json:"status"`
+	TaskID   string     `json:"task_id"`
+	Status   TaskStatus `json:"status"`
+	Warnings []string   `json:"warnings,omitempty"`
+	Cached   bool       `json:"cached,omitempty"` // Reused from the ResultCache instead of submitted
 }
 
 // TaskResult represents the result of a video generation task
@@ -58,7 +125,76 @@ type TaskResult struct {
 	URL      string     `json:"url,omitempty"`
 	Format   string     `json:"format,omitempty"`
 	Metadata *Metadata  `json:"metadata,omitempty"`
-	Error    *TaskError `json:"error,omitempty"`
+	Error    *TaskError `json:"error,omitempty"` // Always set when Status is failed
+
+	// ClientTaskID is the GenerationRequest.ClientTaskID the task was submitted with
+	ClientTaskID string `json:"client_task_id,omitempty"`
+
+	// Assets lists the output files, URL first, with the time each URL expires
+	Assets []ResultAsset `json:"assets,omitempty"`
+
+	// Videos lists every generated video; URL is the first one's
+	Videos []VideoOutput `json:"videos,omitempty"`
+
+	// B64Data holds the base64-encoded video of a succeeded task submitted with
+	// ResponseFormatB64JSON, up to ClientConfig.MaxB64Size; URL is still set
+	B64Data string `json:"b64_data,omitempty"`
+
+	// Cancellation says why a canceled task was canceled. Canceled tasks are
+	// reported as failed; see CancelGeneration.
+	Cancellation *Cancellation `json:"cancellation,omitempty"`
+
+	// Usage is what the task was billed, when the provider reports it
+	Usage *Usage `json:"usage,omitempty"`
+
+	// Extra holds values attached by response transformers
+	Extra map[string]interface{} `json:"extra,omitempty"`
+
+	// Scores holds quality scores by evaluator name, see Evaluator
+	Scores map[string]float64 `json:"scores,omitempty"`
+}
+
+// Cancellation records why and when a task was canceled
+type Cancellation = adapters.Cancellation
+
+// CancellationReason says why a task was canceled, see CancellationReason.UserInitiated
+type CancellationReason = adapters.CancellationReason
+
+// Cancellation reasons
+const (
+	CancellationUser       = adapters.CancellationUser
+	CancellationBudget     = adapters.CancellationBudget
+	CancellationTimeout    = adapters.CancellationTimeout
+	CancellationExpired    = adapters.CancellationExpired
+	CancellationProvider   = adapters.CancellationProvider
+	CancellationSuperseded = adapters.CancellationSuperseded
+)
+
+// ResultAsset is one output file of a task, see ResultAsset.IsExpired
+type ResultAsset = adapters.ResultAsset
+
+// VideoOutput is one video generated by a task, see TaskResult.Videos
+type VideoOutput = adapters.VideoOutput
+
+// Usage is the billing information a provider reports for a task
+type Usage = adapters.Usage
+
+// Asset kinds
+const (
+	AssetVideo     = adapters.AssetVideo
+	AssetThumbnail = adapters.AssetThumbnail
+)
+
+// ExpiresAt returns the earliest expiry of the result's assets, or false when
+// none is known. Download or archive the outputs before then.
+func (r *TaskResult) ExpiresAt() (time.Time, bool) {
+	var earliest time.Time
+	for _, asset := range r.Assets {
+		if asset.ExpiresAt != nil && (earliest.IsZero() || asset.ExpiresAt.Before(earliest)) {
+			earliest = *asset.ExpiresAt
+		}
+	}
+	return earliest, !earliest.IsZero()
 }
 
 // Metadata contains video metadata information
@@ -85,13 +221,43 @@ type ProviderConfig struct {
 	Timeout    time.Duration     `json:"timeout"`
 	RetryCount int               `json:"retry_count"`
 	Extra      map[string]string `json:"extra,omitempty"`
+
+	// AllowUnknownExtra accepts Extra keys the provider does not define, which are otherwise rejected
+	AllowUnknownExtra bool `json:"allow_unknown_extra,omitempty"`
+
+	// APIVersion selects version-aware payload building for providers whose fields changed over time
+	APIVersion string `json:"api_version,omitempty"`
+
+	// Endpoints overrides endpoint path templates keyed by task type ("*" applies to all).
+	// Templates may use the {version} and {task_type} placeholders.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+
+	// Environment is EnvironmentProduction (the default) or EnvironmentSandbox, which
+	// sends every call to BaseURL, e.g. a provider test endpoint or a mock server
+	Environment string `json:"environment,omitempty"`
+
+	// Region is RegionCN or RegionGlobal, where the endpoint processes requests.
+	// It defaults to the region of the provider's default endpoint, if known,
+	// and is needed to send requests with a Residency to a custom BaseURL.
+	Region string `json:"region,omitempty"`
 }
 
+// Provider environments, see ProviderConfig.Environment
+const (
+	EnvironmentProduction = "production"
+	EnvironmentSandbox    = "sandbox"
+)
+
 // ProviderType represents different video generation providers
 type ProviderType string
 
 const (
-	ProviderKling  ProviderType = "kling"
-	ProviderJimeng ProviderType = "jimeng"
-	ProviderVidu   ProviderType = "vidu"
+	ProviderKling     ProviderType = "kling"
+	ProviderJimeng    ProviderType = "jimeng"
+	ProviderVidu      ProviderType = "vidu"
+	ProviderLuma      ProviderType = "luma"
+	ProviderVeo       ProviderType = "veo"
+	ProviderWanx      ProviderType = "wanx"
+	ProviderReplicate ProviderType = "replicate"
+	ProviderComfyUI   ProviderType = "comfyui"
 )
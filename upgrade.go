@@ -0,0 +1,144 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// TimelineEventUpgraded records that a submission was retried in a higher tier
+const TimelineEventUpgraded TimelineEventType = "upgraded"
+
+// UpgradePolicy retries std-mode submissions rejected for capacity reasons in pro
+// mode or on fallback models. Upgrades are reported in GenerationResponse.Warnings
+// and recorded on the task timeline.
+type UpgradePolicy struct {
+	// ProMode retries the same model with metadata.mode set to "pro" first
+	ProMode bool
+
+	// Models are fallback models tried in order, keeping the original mode
+	Models []string
+
+	// MaxCost skips candidates whose Planner estimate exceeds it; 0 means no cap
+	MaxCost float64
+
+	// IsCapacityError reports whether an error warrants an upgrade; defaults to IsCapacityError
+	IsCapacityError func(error) bool
+}
+
+// capacityErrorMarkers are provider error fragments that indicate a capacity shortage,
+// e.g. Kling's 1303 (parallel task limit) and 5003 (server busy)
+var capacityErrorMarkers = []string{"API error 1303", "API error 5003", "capacity", "overloaded"}
+
+// IsCapacityError reports whether an error indicates the provider is out of capacity
+func IsCapacityError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.Code == 429 || apiErr.Code == 503) {
+		return true
+	}
+	if errors.Is(err, ErrRateLimitExceeded) {
+		return true
+	}
+
+	message := err.Error()
+	for _, marker := range capacityErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// upgrade resubmits a request rejected with cause according to the UpgradePolicy.
+// It returns cause unchanged when no upgrade applies or every candidate fails.
+func (c *Client) upgrade(ctx context.Context, o *callOptions, req *GenerationRequest, cause error) (*GenerationResponse, error) {
+	policy := c.config.UpgradePolicy
+	if policy == nil || requestMode(req) != "std" {
+		return nil, cause
+	}
+
+	isCapacityError := policy.IsCapacityError
+	if isCapacityError == nil {
+		isCapacityError = IsCapacityError
+	}
+	if !isCapacityError(cause) {
+		return nil, cause
+	}
+
+	for _, candidate := range policy.candidates(req) {
+		if c.current().ValidateRequest(candidate) != nil || !c.withinCostCap(candidate, policy.MaxCost) {
+			continue
+		}
+
+		var resp *GenerationResponse
+		err := c.withRetry(c.captureContext(ctx, "create_generation", ""), o, func(ctx context.Context) error {
+			var err error
+			resp, err = c.current().CreateGeneration(ctx, candidate)
+			return err
+		})
+		if err != nil {
+			if isCapacityError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		warning := fmt.Sprintf("upgraded from %s to %s after capacity error: %v", describeTier(req), describeTier(candidate), cause)
+		resp.Warnings = append(resp.Warnings, warning)
+		c.recordTimeline(resp.TaskID, TimelineEvent{Type: TimelineEventUpgraded, Status: resp.Status, Message: warning})
+		return resp, nil
+	}
+
+	return nil, cause
+}
+
+// candidates builds the upgraded requests in the order they are tried
+func (p *UpgradePolicy) candidates(req *GenerationRequest) []*GenerationRequest {
+	var candidates []*GenerationRequest
+	if p.ProMode {
+		candidate := *req
+		candidate.Mode = ModePro
+		candidates = append(candidates, &candidate)
+	}
+	for _, model := range p.Models {
+		if model == req.Model {
+			continue
+		}
+		candidate := *req
+		candidate.Model = model
+		candidates = append(candidates, &candidate)
+	}
+	return candidates
+}
+
+// withinCostCap reports whether the Planner estimate of req is within maxCost
+func (c *Client) withinCostCap(req *GenerationRequest, maxCost float64) bool {
+	if maxCost <= 0 || c.config.Planner == nil {
+		return true
+	}
+	estimate, err := c.config.Planner.Estimate(c.registryName(), req)
+	return err == nil && estimate.Cost <= maxCost
+}
+
+// requestMode returns the resolved generation mode, defaulting to "std"
+func requestMode(req *GenerationRequest) string {
+	if mode := adapters.ResolveMode(toAdapterRequest(req)); mode != "" {
+		return mode
+	}
+	return ModeStandard
+}
+
+func describeTier(req *GenerationRequest) string {
+	model := req.Model
+	if model == "" {
+		model = "default model"
+	}
+	return model + "/" + requestMode(req)
+}
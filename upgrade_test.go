@@ -0,0 +1,70 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpgradePolicy(t *testing.T) {
+	server := newKlingStub(t)
+	server.reply = func(r *http.Request, body map[string]interface{}) string {
+		if body["mode"] == "std" {
+			return `{"code":1303,"message":"parallel task over resource pack limit"}`
+		}
+		return ""
+	}
+	modes := func() string {
+		var modes []string
+		for _, body := range server.submissions() {
+			modes = append(modes, fmt.Sprintf("%v/%v", body["mode"], body["model_name"]))
+		}
+		return strings.Join(modes, ",")
+	}
+	req := &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Model: "kling-v1"}
+
+	client := server.client(t)
+	if _, err := client.CreateGeneration(context.Background(), req); err == nil || !IsCapacityError(err) {
+		t.Fatalf("Expected capacity error without upgrade policy, got %v", err)
+	}
+	modes()
+
+	timelines := NewTimelineStore()
+	client = server.client(t, &ClientConfig{
+		Timeout:       5 * time.Second,
+		Timelines:     timelines,
+		Planner:       NewPlanner(map[string]Price{"kling-v1": {PerSecond: 0.1, ProMultiplier: 3.5}, "kling-v1-6": {PerSecond: 0.1}}),
+		UpgradePolicy: &UpgradePolicy{ProMode: true, Models: []string{"kling-v1-6"}, MaxCost: 1},
+	})
+
+	// Pro mode costs 1.75 and exceeds the cap, so the fallback model is used (and rejected in std)
+	if _, err := client.CreateGeneration(context.Background(), req); err == nil {
+		t.Fatal("Expected capacity error when the only affordable candidate is also rejected")
+	}
+	if submitted := modes(); submitted != "std/kling-v1,std/kling-v1-6" {
+		t.Errorf("Unexpected submissions: %v", submitted)
+	}
+
+	client.config.UpgradePolicy.MaxCost = 0
+	resp, err := client.CreateGeneration(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected upgrade to succeed, got %v", err)
+	}
+	if submitted := modes(); submitted != "std/kling-v1,pro/kling-v1" {
+		t.Errorf("Unexpected submissions: %v", submitted)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "kling-v1/std to kling-v1/pro") {
+		t.Errorf("Expected upgrade warning, got %v", resp.Warnings)
+	}
+	if req.Metadata != nil {
+		t.Error("Upgrade should not modify the original request")
+	}
+
+	events := timelines.Get(resp.TaskID)
+	if len(events) == 0 || events[0].Type != TimelineEventUpgraded {
+		t.Errorf("Expected upgraded timeline event, got %+v", events)
+	}
+}
@@ -0,0 +1,55 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTaskResultUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/videos/text2video/kling-1":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"kling-1","task_status":"succeed","final_unit_deduction":"10","task_result":{"videos":[
+				{"id":"v-1","url":"https://cdn.example.com/1.mp4","duration":"5.1"}
+			]}}}`))
+		case "/v1/videos/text2video/kling-2":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"kling-2","task_status":"processing"}}`))
+		case "/api/v1/tasks/wanx-1":
+			w.Write([]byte(`{"request_id":"r-1","output":{"task_id":"wanx-1","task_status":"SUCCEEDED","video_url":"https://cdn.example.com/w.mp4"},"usage":{"video_duration":5,"video_count":1,"video_ratio":"1280*720"}}`))
+		case "/v1/predictions/rep-1":
+			w.Write([]byte(`{"id":"rep-1","status":"succeeded","output":"https://cdn.example.com/r.mp4","metrics":{"predict_time":42.5}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	get := func(provider ProviderType, apiKey, taskID string) *TaskResult {
+		client, err := NewClient(provider, &ProviderConfig{BaseURL: server.URL, APIKey: apiKey, Timeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("Failed to create %s client: %v", provider, err)
+		}
+		result, err := client.GetGeneration(context.Background(), taskID)
+		if err != nil {
+			t.Fatalf("Failed to get %s generation: %v", provider, err)
+		}
+		return result
+	}
+
+	if usage := get(ProviderKling, "ak,sk", "kling-1").Usage; usage == nil || usage.Credits != 10 || usage.BilledSeconds != 5.1 {
+		t.Errorf("Expected 10 Kling credits for 5.1 seconds, got %+v", usage)
+	}
+	if usage := get(ProviderKling, "ak,sk", "kling-2").Usage; usage != nil {
+		t.Errorf("Expected no usage for a running Kling task, got %+v", usage)
+	}
+	if usage := get(ProviderWanx, "sk-test", "wanx-1").Usage; usage == nil || usage.BilledSeconds != 5 {
+		t.Errorf("Expected 5 billed Wanx seconds, got %+v", usage)
+	}
+	if usage := get(ProviderReplicate, "r8_test", "rep-1").Usage; usage == nil || usage.BilledSeconds != 42.5 {
+		t.Errorf("Expected 42.5 billed Replicate seconds, got %+v", usage)
+	}
+}
@@ -0,0 +1,98 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultVariantSizes are the size presets CreateVariants submits by default:
+// 16:9 for feeds and players, 9:16 for short-video platforms
+var DefaultVariantSizes = []string{"landscape", "portrait"}
+
+// VariantOptions configures CreateVariants
+type VariantOptions struct {
+	// Sizes are size preset keywords (see RegisterSizePreset), one submission
+	// each; defaults to DefaultVariantSizes
+	Sizes []string
+
+	Batch BatchOptions
+}
+
+// VariantGroup tracks the tasks generating the size variants of one brief
+type VariantGroup struct {
+	Sizes []string     `json:"sizes"`
+	Batch *BatchResult `json:"batch"` // One item per size, in Sizes order
+
+	// Results holds the final result per size, filled by WaitForVariants
+	Results map[string]*TaskResult `json:"results,omitempty"`
+}
+
+// TaskID returns the task generating a size, or false if its submission failed
+func (g *VariantGroup) TaskID(size string) (string, bool) {
+	for i, s := range g.Sizes {
+		if s == size && g.Batch.Items[i].Response != nil {
+			return g.Batch.Items[i].Response.TaskID, true
+		}
+	}
+	return "", false
+}
+
+// TaskIDs returns the tasks of all submitted variants
+func (g *VariantGroup) TaskIDs() []string {
+	var taskIDs []string
+	for _, item := range g.Batch.Succeeded() {
+		taskIDs = append(taskIDs, item.Response.TaskID)
+	}
+	return taskIDs
+}
+
+// CreateVariants submits one generation per size variant of req, e.g. a 16:9
+// and a 9:16 video from the same brief. Each variant is a copy of req with
+// Width and Height cleared and Size set to the variant keyword, resolved for the
+// client's provider. A ClientTaskID gets the size appended to stay unique. The
+// group is returned even when some submissions fail; the error is Batch.Err().
+func (c *Client) CreateVariants(ctx context.Context, req *GenerationRequest, opts *VariantOptions) (*VariantGroup, error) {
+	if req == nil {
+		return nil, &ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+	sizes := DefaultVariantSizes
+	var batch BatchOptions
+	if opts != nil {
+		if len(opts.Sizes) > 0 {
+			sizes = opts.Sizes
+		}
+		batch = opts.Batch
+	}
+
+	seen := make(map[string]bool, len(sizes))
+	reqs := make([]*GenerationRequest, len(sizes))
+	for i, size := range sizes {
+		if size == "" || seen[size] {
+			return nil, &ValidationError{Field: fmt.Sprintf("sizes[%d]", i), Message: "sizes must be distinct preset keywords"}
+		}
+		seen[size] = true
+
+		variant := *req
+		variant.Width, variant.Height, variant.Size = 0, 0, size
+		if req.ClientTaskID != "" {
+			variant.ClientTaskID = req.ClientTaskID + "-" + size
+		}
+		reqs[i] = &variant
+	}
+
+	group := &VariantGroup{Sizes: append([]string(nil), sizes...), Batch: c.CreateGenerations(ctx, reqs, batch)}
+	return group, group.Batch.Err()
+}
+
+// WaitForVariants waits for every submitted variant of a group with WaitForAll
+// and stores the results by size in group.Results
+func (c *Client) WaitForVariants(ctx context.Context, group *VariantGroup, opts *WaitAllOptions) error {
+	results, err := c.WaitForAll(ctx, group.TaskIDs(), opts)
+	group.Results = make(map[string]*TaskResult, len(results))
+	for _, size := range group.Sizes {
+		if taskID, ok := group.TaskID(size); ok && results[taskID] != nil {
+			group.Results[size] = results[taskID]
+		}
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestCreateVariants(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	req := &GenerationRequest{Prompt: "A cat surfing", Duration: 5, Width: 1280, Height: 720, ClientTaskID: "job-1"}
+	group, err := client.CreateVariants(ctx, req, nil)
+	if err != nil {
+		t.Fatalf("Failed to create variants: %v", err)
+	}
+	if len(group.TaskIDs()) != 2 {
+		t.Fatalf("Expected 2 variant tasks, got %v", group.TaskIDs())
+	}
+	portrait := group.Batch.Items[1].Request
+	if portrait.Size != "portrait" || portrait.Width != 0 || portrait.ClientTaskID != "job-1-portrait" {
+		t.Errorf("Expected a portrait variant with its own client task ID, got %+v", portrait)
+	}
+	if req.Width != 1280 || req.ClientTaskID != "job-1" {
+		t.Errorf("Expected the brief to be left unchanged, got %+v", req)
+	}
+
+	if err := client.WaitForVariants(ctx, group, &WaitAllOptions{PollsPerSecond: 1000, Policy: &PollPolicy{Interval: time.Millisecond}}); err != nil {
+		t.Fatalf("Failed to wait for variants: %v", err)
+	}
+	for _, size := range DefaultVariantSizes {
+		if result := group.Results[size]; result == nil || result.Status != TaskStatusSucceeded {
+			t.Errorf("Expected a succeeded %s variant, got %+v", size, result)
+		}
+	}
+
+	group, err = client.CreateVariants(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5}, &VariantOptions{Sizes: []string{"landscape", "widescreen"}})
+	if err == nil || len(group.TaskIDs()) != 1 || len(group.Batch.ValidationFailures()) != 1 {
+		t.Errorf("Expected the unknown size to fail validation, got %v", err)
+	}
+	var validationErr *ValidationError
+	if _, err := client.CreateVariants(ctx, req, &VariantOptions{Sizes: []string{"portrait", "portrait"}}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected validation error for duplicate sizes, got %v", err)
+	}
+}
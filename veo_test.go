@@ -0,0 +1,154 @@
+package vidgo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVeoProvider(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	const operation = "projects/my-project/locations/us-central1/publishers/google/models/veo-2.0-generate-001/operations/op-1"
+	var tokenRequests int32
+	var submitted veoPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			atomic.AddInt32(&tokenRequests, 1)
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.Form.Get("assertion") == "" {
+				t.Errorf("Unexpected token request: %v", r.Form)
+			}
+			w.Write([]byte(`{"access_token":"ya29.test","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer ya29.test" {
+			t.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/v1/projects/my-project/locations/us-central1/publishers/google/models/veo-2.0-generate-001:predictLongRunning":
+			json.NewDecoder(r.Body).Decode(&submitted)
+			w.Write([]byte(`{"name":"` + operation + `"}`))
+		case "/v1/projects/my-project/locations/us-central1/publishers/google/models/veo-2.0-generate-001:fetchPredictOperation":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["operationName"] != operation {
+				t.Errorf("Expected operation name %s, got %s", operation, body["operationName"])
+			}
+			w.Write([]byte(`{"name":"` + operation + `","done":true,"response":{"videos":[{"gcsUri":"gs://bucket/out/sample_0.mp4","mimeType":"video/mp4"}]}}`))
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	account, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "my-project",
+		"private_key":  string(keyPEM),
+		"client_email": "vidgo@my-project.iam.gserviceaccount.com",
+		"token_uri":    server.URL + "/token",
+	})
+
+	client, err := NewClient(ProviderVeo, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  string(account),
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:     "A cat",
+		Duration:   8,
+		Width:      720,
+		Height:     1280,
+		ImageBytes: testPNG(64, 64),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if resp.TaskID != operation {
+		t.Errorf("Expected operation name as task ID, got %s", resp.TaskID)
+	}
+	if submitted.Parameters.AspectRatio != "9:16" || submitted.Parameters.DurationSeconds != 8 {
+		t.Errorf("Unexpected parameters: %+v", submitted.Parameters)
+	}
+	if len(submitted.Instances) != 1 || submitted.Instances[0].Image.MimeType != "image/png" || submitted.Instances[0].Image.BytesBase64Encoded == "" {
+		t.Errorf("Expected inline PNG image, got %+v", submitted.Instances)
+	}
+
+	result, err := client.GetGeneration(context.Background(), resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "gs://bucket/out/sample_0.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("Expected access token to be cached, got %d token requests", n)
+	}
+
+	err = client.validateRequest(&GenerationRequest{Prompt: "A cat", Duration: 8, Width: 1280, Height: 720, Image: "https://example.com/a.png"})
+	if err == nil || !strings.Contains(err.Error(), "gs://") {
+		t.Errorf("Expected HTTP image URL to be rejected, got %v", err)
+	}
+
+	// Uploaded images are validated and sent as Cloud Storage URIs
+	uploadURL := "https://storage.googleapis.com/bucket/in/a.png"
+	uploading, err := NewClient(ProviderVeo, &ProviderConfig{BaseURL: server.URL, APIKey: string(account)}, &ClientConfig{
+		Timeout:       5 * time.Second,
+		ImageUploader: func(ctx context.Context, data []byte, contentType string) (string, error) { return uploadURL, nil },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	req := &GenerationRequest{Prompt: "A cat", Duration: 8, Width: 1280, Height: 720, ImageBytes: testPNG(64, 64)}
+	submitted = veoPayload{}
+	if _, err := uploading.CreateGeneration(context.Background(), req); err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if image := submitted.Instances[0].Image; image.GcsURI != "gs://bucket/in/a.png" || image.BytesBase64Encoded != "" {
+		t.Errorf("Expected the upload as a gcsUri, got %+v", image)
+	}
+
+	uploadURL = "https://cdn.example.com/a.png"
+	submitted = veoPayload{}
+	if _, err := uploading.CreateGeneration(context.Background(), req); err == nil || !strings.Contains(err.Error(), "gs://") {
+		t.Errorf("Expected an upload outside Cloud Storage to be rejected, got %v", err)
+	}
+	if len(submitted.Instances) != 0 {
+		t.Errorf("Expected nothing to be submitted, got %+v", submitted)
+	}
+}
+
+// veoPayload mirrors the fields of the Veo request used in assertions
+type veoPayload struct {
+	Instances []struct {
+		Image struct {
+			BytesBase64Encoded string `json:"bytesBase64Encoded"`
+			GcsURI             string `json:"gcsUri"`
+			MimeType           string `json:"mimeType"`
+		} `json:"image"`
+	} `json:"instances"`
+	Parameters struct {
+		AspectRatio     string `json:"aspectRatio"`
+		DurationSeconds int    `json:"durationSeconds"`
+	} `json:"parameters"`
+}
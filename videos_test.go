@@ -0,0 +1,73 @@
+package vidgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestTaskResultVideos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/videos/text2video":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1"}}`))
+		case "/v1/videos/text2video/task-1":
+			w.Write([]byte(`{"code":0,"message":"ok","data":{"task_id":"task-1","task_status":"succeed","task_result":{"videos":[
+				{"id":"v-1","url":"https://cdn.example.com/1.mp4","duration":"5.1"},
+				{"id":"v-2","url":"https://cdn.example.com/2.mp4","watermark_url":"https://cdn.example.com/2-wm.mp4","duration":"5.0"}
+			]}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderKling, &ProviderConfig{BaseURL: server.URL, APIKey: "ak,sk", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err := client.GetGeneration(ctx, resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+
+	if len(result.Videos) != 2 || result.URL != result.Videos[0].URL {
+		t.Fatalf("Expected two videos with URL as the first, got %+v", result.Videos)
+	}
+	second := result.Videos[1]
+	if second.ID != "v-2" || second.Duration != 5 || second.Format != "mp4" || second.WatermarkURL != "https://cdn.example.com/2-wm.mp4" {
+		t.Errorf("Unexpected second video: %+v", second)
+	}
+	if len(result.Assets) != 2 || result.Assets[1].URL != second.URL {
+		t.Errorf("Expected an asset per video, got %+v", result.Assets)
+	}
+
+	fake := httptest.NewServer(fakekling.New("ak", "sk"))
+	defer fake.Close()
+	client, err = NewClient(ProviderKling, &ProviderConfig{BaseURL: fake.URL, APIKey: "ak,sk"}, &ClientConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	resp, err = client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	result, err = client.WaitForCompletion(ctx, resp.TaskID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for completion: %v", err)
+	}
+	if len(result.Videos) != 1 || result.Videos[0].URL != result.URL {
+		t.Errorf("Expected the single video to be listed, got %+v", result.Videos)
+	}
+}
@@ -0,0 +1,171 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WaitAllOptions configures WaitForAll
+type WaitAllOptions struct {
+	// PollsPerSecond is the poll budget shared by all tasks, defaults to 10
+	PollsPerSecond float64
+
+	// Policy overrides the PollPolicy of each task's model
+	Policy *PollPolicy
+}
+
+// WaitForAll polls many tasks concurrently until each reaches a terminal status
+// and returns their results by task ID. Polls of all tasks share one rate budget
+// so a large batch does not exceed the provider's rate limit; providers with a
+// batch lookup (BatchTaskGetter) poll all pending tasks in one round per tick
+// instead. A task whose poll fails is dropped from polling and its error joined
+// into the returned error. When ctx is done the latest observed result of every
+// task is returned with ctx.Err().
+func (c *Client) WaitForAll(ctx context.Context, taskIDs []string, opts *WaitAllOptions) (map[string]*TaskResult, error) {
+	var ids []string
+	seen := make(map[string]bool, len(taskIDs))
+	for i, taskID := range taskIDs {
+		if taskID == "" {
+			return nil, &ValidationError{Field: fmt.Sprintf("task_ids[%d]", i), Message: "task ID cannot be empty"}
+		}
+		if !seen[taskID] {
+			seen[taskID] = true
+			ids = append(ids, taskID)
+		}
+	}
+
+	rate := 10.0
+	var policy *PollPolicy
+	if opts != nil && opts.PollsPerSecond > 0 {
+		rate = opts.PollsPerSecond
+	}
+	if opts != nil && opts.Policy != nil {
+		policy = opts.Policy
+	}
+	budget := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer budget.Stop()
+
+	var results map[string]*TaskResult
+	var errs map[string]error
+	if c.supportsBatchLookup() && len(ids) > 1 {
+		results, errs = c.waitBatched(ctx, ids, policy, budget.C)
+	} else {
+		results, errs = c.waitEach(ctx, ids, policy, budget.C)
+	}
+
+	for taskID, result := range results {
+		if result == nil {
+			delete(results, taskID)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, joinTaskErrors(ids, errs)
+}
+
+// waitEach polls every task in its own goroutine for WaitForAll
+func (c *Client) waitEach(ctx context.Context, ids []string, policy *PollPolicy, budget <-chan time.Time) (map[string]*TaskResult, map[string]error) {
+	var mu sync.Mutex
+	results := make(map[string]*TaskResult, len(ids))
+	errs := make(map[string]error)
+
+	var wg sync.WaitGroup
+	for _, taskID := range ids {
+		wg.Add(1)
+		go func(taskID string) {
+			defer wg.Done()
+			result, err := c.waitForOne(ctx, taskID, policy, budget)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[taskID] = result
+			if err != nil {
+				errs[taskID] = err
+			}
+		}(taskID)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// waitBatched polls all pending tasks with GetGenerations for WaitForAll, taking
+// a tick from budget before each round. Without a policy, rounds follow the
+// PollPolicy of the first task's model.
+func (c *Client) waitBatched(ctx context.Context, ids []string, policy *PollPolicy, budget <-chan time.Time) (map[string]*TaskResult, map[string]error) {
+	results := make(map[string]*TaskResult, len(ids))
+	errs := make(map[string]error)
+	pending := ids
+
+	o, err := c.callOptions(nil)
+	if err != nil {
+		for _, taskID := range ids {
+			errs[taskID] = err
+		}
+		return results, errs
+	}
+
+	if policy == nil {
+		policy = c.taskPollPolicy(ids[0])
+	}
+	timer := time.NewTimer(policy.Delay(0))
+	defer timer.Stop()
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			select {
+			case <-ctx.Done():
+			case <-budget:
+			}
+		}
+		if ctx.Err() != nil {
+			for _, taskID := range pending {
+				c.recordTimeline(taskID, failureEvent(ctx.Err(), 0))
+			}
+			return results, errs
+		}
+
+		polled, failed := c.getGenerations(ctx, pending, o, nil)
+		var next []string
+		for _, taskID := range pending {
+			if err, ok := failed[taskID]; ok {
+				errs[taskID] = err
+				continue
+			}
+			result := polled[taskID]
+			results[taskID] = result
+			if taskPending(result.Status) {
+				next = append(next, taskID)
+			} else {
+				c.evaluateFinished(ctx, result)
+			}
+		}
+		pending = next
+		timer.Reset(policy.Delay(attempt))
+	}
+	return results, errs
+}
+
+// waitForOne polls one task for WaitForAll, taking a tick from budget before
+// each poll. It returns the latest result it observed along with any error.
+func (c *Client) waitForOne(ctx context.Context, taskID string, policy *PollPolicy, budget <-chan time.Time) (*TaskResult, error) {
+	result, err := c.poll(ctx, taskID, poller{policy: policy, budget: budget})
+	if err == nil {
+		c.evaluateFinished(ctx, result)
+	}
+	return result, err
+}
+
+// evaluateFinished scores a finished result with the configured Evaluators if it succeeded
+func (c *Client) evaluateFinished(ctx context.Context, result *TaskResult) {
+	if result.Status != TaskStatusSucceeded {
+		return
+	}
+	if err := c.Evaluate(ctx, result); err != nil && c.config.Debug {
+		fmt.Printf("Evaluation failed: %v\n", err)
+	}
+}
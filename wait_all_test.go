@@ -0,0 +1,60 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestWaitForAll(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	var taskIDs []string
+	for _, prompt := range []string{"A cat", "A dog", "A bird [fail]"} {
+		resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: prompt, Duration: 5, Width: 1280, Height: 720})
+		if err != nil {
+			t.Fatalf("Failed to create generation: %v", err)
+		}
+		taskIDs = append(taskIDs, resp.TaskID)
+	}
+
+	policy := &PollPolicy{Interval: time.Millisecond}
+	results, err := client.WaitForAll(ctx, append(taskIDs, taskIDs[0]), &WaitAllOptions{PollsPerSecond: 1000, Policy: policy})
+	if err != nil {
+		t.Fatalf("Failed to wait for tasks: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[taskIDs[0]].Status != TaskStatusSucceeded || results[taskIDs[2]].Status != TaskStatusFailed {
+		t.Errorf("Expected succeeded and failed tasks, got %s and %s", results[taskIDs[0]].Status, results[taskIDs[2]].Status)
+	}
+
+	results, err = client.WaitForAll(ctx, []string{taskIDs[0], "missing-task"}, &WaitAllOptions{PollsPerSecond: 1000, Policy: policy})
+	if !errors.Is(err, ErrTaskNotFound) || results[taskIDs[0]] == nil {
+		t.Errorf("Expected ErrTaskNotFound alongside the other result, got %v and %v", err, results)
+	}
+
+	// A budget of 5 polls per second allows about one poll before the deadline
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A fish", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	results, err = client.WaitForAll(ctx, []string{resp.TaskID}, &WaitAllOptions{PollsPerSecond: 5, Policy: policy})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if result := results[resp.TaskID]; result == nil || !taskPending(result.Status) {
+		t.Errorf("Expected a partial pending result, got %+v", result)
+	}
+
+	if _, err := client.WaitForAll(context.Background(), []string{""}, nil); err == nil {
+		t.Error("Expected an empty task ID to be rejected")
+	}
+}
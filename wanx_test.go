@@ -0,0 +1,137 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWanxProvider(t *testing.T) {
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v1/services/aigc/video-generation/video-synthesis":
+			if r.Header.Get("X-DashScope-Async") != "enable" {
+				t.Error("Expected X-DashScope-Async: enable header")
+			}
+			json.NewDecoder(r.Body).Decode(&submitted)
+			w.Write([]byte(`{"request_id":"r-1","output":{"task_id":"task-1","task_status":"PENDING"}}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/tasks/task-1":
+			w.Write([]byte(`{"request_id":"r-2","output":{"task_id":"task-1","task_status":"SUCCEEDED","video_url":"https://cdn.example.com/wanx.mp4"}}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/tasks/task-2":
+			w.Write([]byte(`{"request_id":"r-3","output":{"task_id":"task-2","task_status":"FAILED","code":"DataInspectionFailed","message":"Input data may contain inappropriate content."}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderWanx, &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "sk-test",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat",
+		Duration: 5,
+		Width:    720,
+		Height:   1280,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+	if resp.TaskID != "task-1" || resp.Status != TaskStatusQueued {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	params, _ := submitted["parameters"].(map[string]interface{})
+	if submitted["model"] != "wanx2.1-t2v-turbo" || params["size"] != "720*1280" {
+		t.Errorf("Unexpected submitted payload: %v", submitted)
+	}
+
+	_, err = client.CreateGeneration(context.Background(), &GenerationRequest{
+		Prompt:   "A cat",
+		Image:    "https://example.com/cat.png",
+		Duration: 5,
+		Width:    1280,
+		Height:   720,
+		Model:    "wanx2.1-t2v-plus",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create image generation: %v", err)
+	}
+	input, _ := submitted["input"].(map[string]interface{})
+	params, _ = submitted["parameters"].(map[string]interface{})
+	if submitted["model"] != "wanx2.1-i2v-plus" || input["img_url"] != "https://example.com/cat.png" || params["resolution"] != "720P" {
+		t.Errorf("Unexpected image-to-video payload: %v", submitted)
+	}
+
+	result, err := client.GetGeneration(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusSucceeded || result.URL != "https://cdn.example.com/wanx.mp4" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	result, err = client.GetGeneration(context.Background(), "task-2")
+	if err != nil {
+		t.Fatalf("Failed to get generation: %v", err)
+	}
+	if result.Status != TaskStatusFailed || result.Error == nil || result.Error.Message == "" {
+		t.Errorf("Expected failed result with error, got %+v", result)
+	}
+}
+
+func TestWanxResolutionClamp(t *testing.T) {
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submitted = nil
+		json.NewDecoder(r.Body).Decode(&submitted)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"request_id":"r-1","output":{"task_id":"task-1","task_status":"PENDING"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ProviderWanx, &ProviderConfig{BaseURL: server.URL, APIKey: "sk-test", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		req   *GenerationRequest
+		param string
+		want  string
+	}{
+		{"turbo renders 480P", &GenerationRequest{Model: "wanx2.1-t2v-turbo", Image: "https://example.com/cat.png", Width: 1280, Height: 720, QualityLevel: QualityLevelLow}, "resolution", "480P"},
+		{"plus clamps to 720P", &GenerationRequest{Model: "wanx2.1-t2v-plus", Image: "https://example.com/cat.png", Width: 1280, Height: 720, QualityLevel: QualityLevelLow}, "resolution", "720P"},
+		{"plus clamps a small image to 720P", &GenerationRequest{Model: "wanx2.1-i2v-plus", Image: "https://example.com/cat.png", Width: 832, Height: 480}, "resolution", "720P"},
+		{"turbo renders a 480p size", &GenerationRequest{Model: "wanx2.1-t2v-turbo", Width: 832, Height: 480}, "size", "832*480"},
+		{"plus clamps to a 720p size", &GenerationRequest{Model: "wanx2.1-t2v-plus", Width: 832, Height: 480}, "size", "1280*720"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Prompt = "A cat"
+			tt.req.Duration = 5
+			if _, err := client.CreateGeneration(context.Background(), tt.req); err != nil {
+				t.Fatalf("Failed to create generation: %v", err)
+			}
+			params, _ := submitted["parameters"].(map[string]interface{})
+			if params[tt.param] != tt.want {
+				t.Errorf("Expected %s %s, got %v", tt.param, tt.want, params)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package vidgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/feitianbubu/vidgo/adapters"
+)
+
+// Warm establishes provider connections and auth tokens so the first request does
+// not pay handshake and token costs. Providers without warm-up support are a no-op.
+func (c *Client) Warm(ctx context.Context) error {
+	if warmer, ok := c.current().(adapters.Warmer); ok {
+		return warmer.Warm(ctx)
+	}
+	return nil
+}
+
+// prewarm runs the configured warm-up once and starts the background refresher
+func (c *Client) prewarm() {
+	if !c.config.Prewarm {
+		return
+	}
+
+	c.warmOnce()
+
+	if c.config.WarmInterval > 0 {
+		c.stop = make(chan struct{})
+		go c.keepWarm(c.config.WarmInterval)
+	}
+}
+
+// keepWarm re-warms the provider every interval until Close is called
+func (c *Client) keepWarm(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.warmOnce()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// warmOnce warms the provider within the client timeout; failures only affect latency
+func (c *Client) warmOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	if err := c.Warm(ctx); err != nil && c.config.Debug {
+		fmt.Printf("Warm-up failed: %v\n", err)
+	}
+}
+
+// Close stops background work started by the client, such as the warm-up refresher
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.stop != nil {
+			close(c.stop)
+		}
+	})
+	return nil
+}
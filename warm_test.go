@@ -0,0 +1,53 @@
+package vidgo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPrewarm(t *testing.T) {
+	var heads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			atomic.AddInt32(&heads, 1)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	providerConfig := &ProviderConfig{
+		BaseURL: server.URL,
+		APIKey:  "test_access_key,test_secret_key",
+		Timeout: 5 * time.Second,
+	}
+
+	client, err := NewClient(ProviderKling, providerConfig, &ClientConfig{Timeout: 5 * time.Second, Prewarm: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if n := atomic.LoadInt32(&heads); n != 1 {
+		t.Errorf("Expected one warm-up request on NewClient, got %d", n)
+	}
+	client.Close()
+
+	atomic.StoreInt32(&heads, 0)
+	client, err = NewClient(ProviderKling, providerConfig, &ClientConfig{Timeout: 5 * time.Second, Prewarm: true, WarmInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	client.Close()
+	client.Close()
+
+	warmed := atomic.LoadInt32(&heads)
+	if warmed < 2 {
+		t.Errorf("Expected background re-warming, got %d warm-up requests", warmed)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := atomic.LoadInt32(&heads); n > warmed+1 {
+		t.Errorf("Expected re-warming to stop after Close, got %d more requests", n-warmed)
+	}
+}
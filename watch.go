@@ -0,0 +1,58 @@
+package vidgo
+
+import "context"
+
+// TaskUpdate is a status transition of a watched task. The final update of a
+// failed watch carries Err instead of a Result.
+type TaskUpdate struct {
+	TaskID   string      `json:"task_id"`
+	Status   TaskStatus  `json:"status,omitempty"`
+	Previous TaskStatus  `json:"previous,omitempty"` // Empty for the first update
+	Result   *TaskResult `json:"result,omitempty"`
+	Err      error       `json:"-"`
+}
+
+// WatchGeneration polls a task with the PollPolicy of its model and sends an update
+// whenever its status changes. The channel is closed after a terminal status,
+// after an update carrying an error, or when ctx is done.
+//
+//	updates, err := client.WatchGeneration(ctx, taskID)
+//	for update := range updates {
+//		fmt.Println(update.Previous, "->", update.Status)
+//	}
+func (c *Client) WatchGeneration(ctx context.Context, taskID string, opts ...CallOption) (<-chan TaskUpdate, error) {
+	if taskID == "" {
+		return nil, &ValidationError{Field: "task_id", Message: "task ID cannot be empty"}
+	}
+	if _, err := c.callOptions(opts); err != nil {
+		return nil, err
+	}
+
+	updates := make(chan TaskUpdate, 1)
+	go func() {
+		defer close(updates)
+
+		send := func(update TaskUpdate) bool {
+			select {
+			case updates <- update:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var previous TaskStatus
+		_, err := c.poll(ctx, taskID, poller{onUpdate: func(result *TaskResult) bool {
+			if result.Status == previous {
+				return true
+			}
+			update := TaskUpdate{TaskID: taskID, Status: result.Status, Previous: previous, Result: result}
+			previous = result.Status
+			return send(update)
+		}}, opts...)
+		if err != nil && ctx.Err() == nil {
+			send(TaskUpdate{TaskID: taskID, Previous: previous, Err: err})
+		}
+	}()
+	return updates, nil
+}
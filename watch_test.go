@@ -0,0 +1,64 @@
+package vidgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/feitianbubu/vidgo/fakekling"
+)
+
+func TestWatchGeneration(t *testing.T) {
+	client := newFakeKlingClient(t, fakekling.New("ak", "sk"), &ClientConfig{
+		Timeout:      5 * time.Second,
+		PollPolicies: map[string]*PollPolicy{"": {Interval: time.Millisecond}},
+	})
+	ctx := context.Background()
+
+	resp, err := client.CreateGeneration(ctx, &GenerationRequest{Prompt: "A cat walking", Duration: 5, Width: 1280, Height: 720})
+	if err != nil {
+		t.Fatalf("Failed to create generation: %v", err)
+	}
+
+	updates, err := client.WatchGeneration(ctx, resp.TaskID)
+	if err != nil {
+		t.Fatalf("Failed to watch generation: %v", err)
+	}
+	var transitions []TaskStatus
+	for update := range updates {
+		if update.Err != nil {
+			t.Fatalf("Unexpected watch error: %v", update.Err)
+		}
+		if len(transitions) > 0 && update.Previous != transitions[len(transitions)-1] {
+			t.Errorf("Expected previous status %s, got %s", transitions[len(transitions)-1], update.Previous)
+		}
+		transitions = append(transitions, update.Status)
+	}
+
+	expected := []TaskStatus{TaskStatusQueued, TaskStatusProcessing, TaskStatusSucceeded}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Expected transition %d to be %s, got %s", i, expected[i], transitions[i])
+		}
+	}
+
+	updates, err = client.WatchGeneration(ctx, "missing-task")
+	if err != nil {
+		t.Fatalf("Failed to watch generation: %v", err)
+	}
+	update, ok := <-updates
+	if !ok || !errors.Is(update.Err, ErrTaskNotFound) {
+		t.Errorf("Expected a task-not-found update, got %+v", update)
+	}
+	if _, ok := <-updates; ok {
+		t.Error("Expected the channel to close after an error")
+	}
+
+	if _, err := client.WatchGeneration(ctx, ""); err == nil {
+		t.Error("Expected an empty task ID to be rejected")
+	}
+}
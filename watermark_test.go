@@ -0,0 +1,73 @@
+package vidgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWatermarkPolicy(t *testing.T) {
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submitted = nil
+		json.NewDecoder(r.Body).Decode(&submitted)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("Action") {
+		case "CVSync2AsyncSubmitTask":
+			w.Write([]byte(`{"code":10000,"message":"Success","data":{"task_id":"7392"}}`))
+		default:
+			w.Write([]byte(`{"request_id":"r-1","output":{"task_id":"task-1","task_status":"PENDING"}}`))
+		}
+	}))
+	defer server.Close()
+
+	newClient := func(provider ProviderType, config *ProviderConfig) *Client {
+		config.BaseURL = server.URL
+		config.Timeout = 5 * time.Second
+		client, err := NewClient(provider, config)
+		if err != nil {
+			t.Fatalf("Failed to create %s client: %v", provider, err)
+		}
+		return client
+	}
+	req := func(policy WatermarkPolicy) *GenerationRequest {
+		return &GenerationRequest{Prompt: "A cat", Duration: 5, Width: 1280, Height: 720, Watermark: policy}
+	}
+	ctx := context.Background()
+
+	wanx := newClient(ProviderWanx, &ProviderConfig{APIKey: "sk-test"})
+	if _, err := wanx.CreateGeneration(ctx, req(WatermarkNone)); err != nil {
+		t.Fatalf("Failed to create Wanx generation: %v", err)
+	}
+	if parameters, _ := submitted["parameters"].(map[string]interface{}); parameters["watermark"] != false {
+		t.Errorf("Expected Wanx parameters.watermark false, got %v", submitted["parameters"])
+	}
+	if _, err := wanx.CreateGeneration(ctx, req(WatermarkDefault)); err != nil {
+		t.Fatalf("Failed to create Wanx generation: %v", err)
+	}
+	if parameters, _ := submitted["parameters"].(map[string]interface{}); parameters["watermark"] != nil {
+		t.Errorf("Expected no Wanx watermark parameter by default, got %v", parameters["watermark"])
+	}
+
+	jimeng := newClient(ProviderJimeng, &ProviderConfig{APIKey: "test_access_key", SecretKey: "test_secret_key"})
+	if _, err := jimeng.CreateGeneration(ctx, req(WatermarkVisible)); err != nil {
+		t.Fatalf("Failed to create Jimeng generation: %v", err)
+	}
+	if logo, _ := submitted["logo_info"].(map[string]interface{}); logo["add_logo"] != true {
+		t.Errorf("Expected Jimeng logo_info.add_logo true, got %v", submitted["logo_info"])
+	}
+
+	kling := newClient(ProviderKling, &ProviderConfig{APIKey: "ak,sk"})
+	if _, err := kling.CreateGeneration(ctx, req(WatermarkVisible)); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Expected ErrInvalidRequest for a visible Kling watermark, got %v", err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := wanx.CreateGeneration(ctx, req("faint")); !errors.As(err, &validationErr) || validationErr.Field != "watermark" {
+		t.Errorf("Expected watermark validation error, got %v", err)
+	}
+}